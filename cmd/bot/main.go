@@ -2,18 +2,38 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Users' time zones must resolve even on hosts without a zoneinfo database
 
 	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/infrastructure/filesystem"
+	httpserver "dutch-learning-bot/internal/infrastructure/http"
 	"dutch-learning-bot/internal/infrastructure/persistence"
 	"dutch-learning-bot/internal/infrastructure/telegram"
+	"dutch-learning-bot/internal/infrastructure/tts"
 	"dutch-learning-bot/internal/interfaces/telegram/handlers"
 )
 
+// defaultMinVocabularySize is the answer plus the three distractors a multiple-choice question needs
+const defaultMinVocabularySize = 4
+
+// checkVocabularySize reports an error when fewer than minimum active words are available (0 disables the check)
+func checkVocabularySize(count, minimum int) error {
+	if minimum > 0 && count < minimum {
+		return fmt.Errorf("only %d active words loaded, but at least %d are needed to build multiple-choice questions; "+
+			"add words to vocabulary.json or lower MIN_VOCABULARY_SIZE", count, minimum)
+	}
+	return nil
+}
+
 func main() {
 	// Get bot token from environment variable
 	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
@@ -46,26 +66,117 @@ func main() {
 		log.Fatalf("Failed to load vocabulary: %v", err)
 	}
 
-	err = vocabularyRepo.SaveBatch(context.Background(), vocabulary)
+	added, skipped, err := vocabularyRepo.SaveBatch(context.Background(), vocabulary)
 	if err != nil {
 		log.Fatalf("Failed to populate vocabulary: %v", err)
 	}
+	log.Printf("Vocabulary loaded: added %d new words, skipped %d already present", added, skipped)
 
-	// Load and populate grammar tips
-	grammarLoader := filesystem.NewGrammarLoader()
-	grammarTips, err := grammarLoader.LoadFromFile("grammar_tips.json")
+	// Refuse to start with too few words to build multiple-choice questions
+	minVocabularySize := defaultMinVocabularySize
+	if size := os.Getenv("MIN_VOCABULARY_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil && n >= 0 {
+			minVocabularySize = n
+		} else {
+			log.Printf("Warning: invalid MIN_VOCABULARY_SIZE %q, using default %d", size, minVocabularySize)
+		}
+	}
+	activeWords, err := vocabularyRepo.FindAll(context.Background())
 	if err != nil {
-		log.Fatalf("Failed to load grammar tips: %v", err)
+		log.Fatalf("Failed to count vocabulary: %v", err)
+	}
+	if err := checkVocabularySize(len(activeWords), minVocabularySize); err != nil {
+		log.Fatalf("Invalid vocabulary: %v", err)
 	}
 
-	err = grammarRepo.SaveBatch(context.Background(), grammarTips)
+	// Load and populate grammar tips (optional - the bot works without them)
+	grammarLoader := filesystem.NewGrammarLoader()
+	grammarTips, err := grammarLoader.LoadFromFile("grammar_tips.json")
 	if err != nil {
-		log.Fatalf("Failed to populate grammar tips: %v", err)
+		log.Printf("Warning: Failed to load grammar tips, grammar tips will be disabled: %v", err)
+	} else if len(grammarTips) == 0 {
+		log.Printf("Warning: No grammar tips found, grammar tips will be disabled")
+	} else if err := grammarRepo.SaveBatch(context.Background(), grammarTips); err != nil {
+		log.Printf("Warning: Failed to populate grammar tips, grammar tips will be disabled: %v", err)
 	}
 
 	// Initialize use cases
-	userUseCase := usecases.NewUserUseCase(userRepo, preferencesRepo)
-	learningUseCase := usecases.NewLearningUseCase(learningRepo, vocabularyRepo, userRepo, grammarRepo, preferencesRepo)
+	userConfig := usecases.DefaultUserConfig()
+	if interval := os.Getenv("DEFAULT_REMINDER_INTERVAL"); interval != "" {
+		if n, err := strconv.Atoi(interval); err == nil && n > 0 {
+			userConfig.DefaultReminderInterval = n
+		} else {
+			log.Printf("Warning: invalid DEFAULT_REMINDER_INTERVAL %q, using default %d", interval, userConfig.DefaultReminderInterval)
+		}
+	}
+	if ttl := os.Getenv("PREFERENCES_CACHE_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil && d >= 0 {
+			userConfig.PreferencesCacheTTL = d
+		} else {
+			log.Printf("Warning: invalid PREFERENCES_CACHE_TTL %q, using default %v", ttl, userConfig.PreferencesCacheTTL)
+		}
+	}
+	userUseCase := usecases.NewUserUseCase(userRepo, preferencesRepo, userConfig)
+	// Share the (possibly cached) preferences repository so every write invalidates the cache
+	preferencesRepo = userUseCase.PreferencesRepository()
+	learningConfig := usecases.DefaultLearningConfig()
+	if questionTimeout := os.Getenv("QUESTION_TIMEOUT"); questionTimeout != "" {
+		if d, err := time.ParseDuration(questionTimeout); err == nil && d >= 0 {
+			learningConfig.QuestionTimeout = d
+		} else {
+			log.Printf("Warning: invalid QUESTION_TIMEOUT %q, question timeout disabled", questionTimeout)
+		}
+	}
+	if window := os.Getenv("RECENT_REVIEW_WINDOW"); window != "" {
+		if d, err := time.ParseDuration(window); err == nil && d >= 0 {
+			learningConfig.RecentReviewWindow = d
+		} else {
+			log.Printf("Warning: invalid RECENT_REVIEW_WINDOW %q, using default %v", window, learningConfig.RecentReviewWindow)
+		}
+	}
+	if breakOnRecent := os.Getenv("BREAK_ON_RECENT_ONLY"); breakOnRecent != "" {
+		if b, err := strconv.ParseBool(breakOnRecent); err == nil {
+			learningConfig.BreakOnRecentOnly = b
+		} else {
+			log.Printf("Warning: invalid BREAK_ON_RECENT_ONLY %q, ignoring", breakOnRecent)
+		}
+	}
+	if fast := os.Getenv("FAST_ANSWER_THRESHOLD"); fast != "" {
+		if d, err := time.ParseDuration(fast); err == nil && d > 0 {
+			learningConfig.FastAnswerThreshold = d
+		} else {
+			log.Printf("Warning: invalid FAST_ANSWER_THRESHOLD %q, using default %v", fast, learningConfig.FastAnswerThreshold)
+		}
+	}
+	if reviews := os.Getenv("RECOGNITION_FIRST_REVIEWS"); reviews != "" {
+		if n, err := strconv.Atoi(reviews); err == nil && n >= 0 {
+			learningConfig.RecognitionFirstReviews = n
+		} else {
+			log.Printf("Warning: invalid RECOGNITION_FIRST_REVIEWS %q, using default %d", reviews, learningConfig.RecognitionFirstReviews)
+		}
+	}
+	if boost := os.Getenv("CONFIDENCE_BOOST"); boost != "" {
+		if f, err := strconv.ParseFloat(boost, 64); err == nil && f >= 0 && f < 1 {
+			learningConfig.ConfidenceBoost = f
+		} else {
+			log.Printf("Warning: invalid CONFIDENCE_BOOST %q, using default %v", boost, learningConfig.ConfidenceBoost)
+		}
+	}
+	if prune := os.Getenv("PRUNE_MISSING_WORD_PROGRESS"); prune != "" {
+		if b, err := strconv.ParseBool(prune); err == nil {
+			learningConfig.PruneMissingWordProgress = b
+		} else {
+			log.Printf("Warning: invalid PRUNE_MISSING_WORD_PROGRESS %q, ignoring", prune)
+		}
+	}
+	if threshold := os.Getenv("REPORT_ARCHIVE_THRESHOLD"); threshold != "" {
+		if n, err := strconv.Atoi(threshold); err == nil && n >= 0 {
+			learningConfig.ReportArchiveThreshold = n
+		} else {
+			log.Printf("Warning: invalid REPORT_ARCHIVE_THRESHOLD %q, using default %d", threshold, learningConfig.ReportArchiveThreshold)
+		}
+	}
+	learningUseCase := usecases.NewLearningUseCase(learningRepo, vocabularyRepo, userRepo, grammarRepo, preferencesRepo, learningConfig)
 
 	// Initialize Telegram bot
 	bot, err := telegram.NewBot(botToken)
@@ -80,10 +191,83 @@ func main() {
 	}
 
 	// Initialize reminder service
-	reminderUseCase := usecases.NewReminderUseCase(bot, userRepo, learningRepo, preferencesRepo, nil)
+	reminderConfig := usecases.DefaultReminderConfig()
+	if concurrency := os.Getenv("REMINDER_CONCURRENCY"); concurrency != "" {
+		if n, err := strconv.Atoi(concurrency); err == nil && n > 0 {
+			reminderConfig.MaxConcurrentReminders = n
+		} else {
+			log.Printf("Warning: invalid REMINDER_CONCURRENCY %q, using default %d", concurrency, reminderConfig.MaxConcurrentReminders)
+		}
+	}
+	if templateFile := os.Getenv("REMINDER_TEMPLATE_FILE"); templateFile != "" {
+		templateText, err := os.ReadFile(templateFile)
+		if err != nil {
+			log.Fatalf("Failed to read reminder template: %v", err)
+		}
+		reminderConfig.MessageTemplate = string(templateText)
+	}
+	if err := reminderConfig.Validate(); err != nil {
+		log.Fatalf("Invalid reminder configuration: %v", err)
+	}
+	reminderUseCase := usecases.NewReminderUseCase(bot, userRepo, learningRepo, preferencesRepo, reminderConfig)
 
 	// Initialize handler
-	handler := handlers.NewBotHandler(bot, userUseCase, learningUseCase, preferencesRepo)
+	handlerConfig := handlers.DefaultHandlerConfig()
+	for _, idStr := range strings.Split(os.Getenv("ADMIN_TELEGRAM_IDS"), ",") {
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Printf("Warning: ignoring invalid admin Telegram ID %q", idStr)
+			continue
+		}
+		handlerConfig.AdminTelegramIDs = append(handlerConfig.AdminTelegramIDs, user.TelegramID(id))
+	}
+	if confirmRestart := os.Getenv("CONFIRM_SESSION_RESTART"); confirmRestart != "" {
+		if b, err := strconv.ParseBool(confirmRestart); err == nil {
+			handlerConfig.ConfirmSessionRestart = b
+		} else {
+			log.Printf("Warning: invalid CONFIRM_SESSION_RESTART %q, ignoring", confirmRestart)
+		}
+	}
+	if concurrent := os.Getenv("MAX_CONCURRENT_REVIEWS"); concurrent != "" {
+		if n, err := strconv.Atoi(concurrent); err == nil && n > 0 {
+			handlerConfig.MaxConcurrentReviews = n
+		} else {
+			log.Printf("Warning: invalid MAX_CONCURRENT_REVIEWS %q, using default %d", concurrent, handlerConfig.MaxConcurrentReviews)
+		}
+	}
+	if pending := os.Getenv("MAX_PENDING_REVIEWS_PER_USER"); pending != "" {
+		if n, err := strconv.Atoi(pending); err == nil && n > 0 {
+			handlerConfig.MaxPendingReviewsPerUser = n
+		} else {
+			log.Printf("Warning: invalid MAX_PENDING_REVIEWS_PER_USER %q, using default %d", pending, handlerConfig.MaxPendingReviewsPerUser)
+		}
+	}
+	// Pronunciation audio is optional; without an endpoint the Listen button is hidden
+	if endpoint := os.Getenv("TTS_ENDPOINT"); endpoint != "" {
+		language := os.Getenv("TTS_LANGUAGE")
+		if language == "" {
+			language = "nl"
+		}
+		cacheDir := os.Getenv("TTS_CACHE_DIR")
+		if cacheDir == "" {
+			cacheDir = "tts_cache"
+		}
+		format := os.Getenv("TTS_AUDIO_FORMAT")
+		if format == "" {
+			format = "mp3"
+		}
+		speech, err := tts.NewCache(tts.NewHTTPClient(endpoint, language), cacheDir, format)
+		if err != nil {
+			log.Printf("Warning: Failed to set up text-to-speech, pronunciation will be disabled: %v", err)
+		} else {
+			handlerConfig.Speech = speech
+		}
+	}
+	handler := handlers.NewBotHandler(bot, userUseCase, learningUseCase, reminderUseCase, preferencesRepo, handlerConfig)
 
 	// Start bot
 	log.Printf("Starting Dutch Learning Bot...")
@@ -95,6 +279,22 @@ func main() {
 	// Start reminder service in background
 	go reminderUseCase.StartReminderService(ctx)
 
+	// Start optional database maintenance in background
+	maintenanceConfig := persistence.DefaultMaintenanceConfig()
+	if interval := os.Getenv("DB_MAINTENANCE_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d >= 0 {
+			maintenanceConfig.Interval = d
+		} else {
+			log.Printf("Warning: invalid DB_MAINTENANCE_INTERVAL %q, database maintenance disabled", interval)
+		}
+	}
+	go persistence.StartMaintenance(ctx, db, maintenanceConfig)
+
+	// Start optional health and metrics endpoints in background
+	if httpAddr := os.Getenv("HTTP_ADDR"); httpAddr != "" {
+		go httpserver.NewServer(httpAddr, db, reminderUseCase).Start(ctx)
+	}
+
 	go func() {
 		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
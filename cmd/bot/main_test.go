@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestCheckVocabularySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		minimum int
+		wantErr bool
+	}{
+		{"enough words", 10, defaultMinVocabularySize, false},
+		{"exactly the minimum", 4, defaultMinVocabularySize, false},
+		{"too few words", 3, defaultMinVocabularySize, true},
+		{"empty vocabulary", 0, defaultMinVocabularySize, true},
+		{"check disabled", 0, 0, false},
+		{"custom minimum", 20, 50, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVocabularySize(tt.count, tt.minimum)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkVocabularySize(%d, %d) error = %v, wantErr %v", tt.count, tt.minimum, err, tt.wantErr)
+			}
+		})
+	}
+}
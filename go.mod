@@ -5,4 +5,6 @@ go 1.21
 require (
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/mattn/go-sqlite3 v1.14.17
+	golang.org/x/sync v0.11.0
+	golang.org/x/text v0.14.0
 )
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// UserDataExport is a portable snapshot of a user's learning data
+type UserDataExport struct {
+	ExportedAt time.Time        `json:"exported_at"`
+	Progress   []ProgressExport `json:"progress"`
+}
+
+// ProgressExport is the exported state of a single word's progress.
+// Word text is only filled in when the export includes vocabulary.
+type ProgressExport struct {
+	WordID      int64      `json:"word_id"`
+	English     string     `json:"english,omitempty"`
+	Dutch       string     `json:"dutch,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	State       string     `json:"state"`
+	Stability   float64    `json:"stability"`
+	Difficulty  float64    `json:"difficulty"`
+	ReviewCount int        `json:"review_count"`
+	Lapses      int        `json:"lapses"`
+	LastReview  *time.Time `json:"last_review,omitempty"`
+	DueDate     time.Time  `json:"due_date"`
+}
+
+// ExportUserData assembles the user's progress for export.
+// With includeWords set, each entry also carries the word's text so the export is human-readable.
+func (uc *LearningUseCase) ExportUserData(ctx context.Context, userID user.ID, includeWords bool) (*UserDataExport, error) {
+	allProgress, err := uc.learningRepo.FindProgressByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	export := &UserDataExport{
+		ExportedAt: time.Now(),
+		Progress:   make([]ProgressExport, 0, len(allProgress)),
+	}
+
+	for _, progress := range allProgress {
+		card := progress.FSRSCard()
+		entry := ProgressExport{
+			WordID:      int64(progress.WordID()),
+			State:       string(card.State()),
+			Stability:   card.Stability(),
+			Difficulty:  card.Difficulty(),
+			ReviewCount: card.ReviewCount(),
+			Lapses:      card.Lapses(),
+			DueDate:     card.DueDate(),
+		}
+		if lastReview := card.LastReview(); !lastReview.IsZero() {
+			entry.LastReview = &lastReview
+		}
+
+		if includeWords {
+			word, err := uc.vocabularyRepo.FindByID(ctx, progress.WordID())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get word %d: %w", progress.WordID(), err)
+			}
+			if word != nil {
+				entry.English = word.English()
+				entry.Dutch = word.Dutch()
+				entry.Category = string(word.Category())
+			}
+		}
+
+		export.Progress = append(export.Progress, entry)
+	}
+
+	return export, nil
+}
+
+// statsCSVHeader is the header row of the spreadsheet-friendly progress export
+var statsCSVHeader = []string{"english", "dutch", "category", "state", "stability", "difficulty", "due_date", "reviews", "lapses"}
+
+// WriteCSV writes one row per word with its scheduling stats, for spreadsheet users.
+// Word text is empty unless the export was made with words included.
+func (e *UserDataExport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(statsCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range e.Progress {
+		row := []string{
+			entry.English,
+			entry.Dutch,
+			entry.Category,
+			entry.State,
+			strconv.FormatFloat(entry.Stability, 'f', 2, 64),
+			strconv.FormatFloat(entry.Difficulty, 'f', 2, 64),
+			entry.DueDate.UTC().Format(time.RFC3339),
+			strconv.Itoa(entry.ReviewCount),
+			strconv.Itoa(entry.Lapses),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row for word %d: %w", entry.WordID, err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+)
+
+func TestExportUserData(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	word := f.addWord(t, "house", "huis", "basics")
+	f.addReviewCard(t, word, time.Now().Add(24*time.Hour))
+
+	tests := []struct {
+		name         string
+		includeWords bool
+	}{
+		{"with words", true},
+		{"ids only", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := f.uc.ExportUserData(context.Background(), f.userID, tt.includeWords)
+			if err != nil {
+				t.Fatalf("ExportUserData: %v", err)
+			}
+			if len(export.Progress) != 1 {
+				t.Fatalf("exported %d entries, want 1", len(export.Progress))
+			}
+			if got := export.Progress[0].WordID; got != int64(word.ID()) {
+				t.Errorf("WordID = %d, want %d", got, word.ID())
+			}
+
+			data, err := json.Marshal(export)
+			if err != nil {
+				t.Fatalf("failed to encode export: %v", err)
+			}
+			for _, text := range []string{`"english":"house"`, `"dutch":"huis"`, `"category":"basics"`} {
+				if got := strings.Contains(string(data), text); got != tt.includeWords {
+					t.Errorf("export contains %s = %v, want %v", text, got, tt.includeWords)
+				}
+			}
+		})
+	}
+}
+
+func TestUserDataExport_WriteCSV(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}} {
+		f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(24*time.Hour))
+	}
+	// Unstudied words have no stats to export
+	f.addWord(t, "dog", "hond", "basics")
+
+	export, err := f.uc.ExportUserData(context.Background(), f.userID, true)
+	if err != nil {
+		t.Fatalf("ExportUserData: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := export.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	wantHeader := "english,dutch,category,state,stability,difficulty,due_date,reviews,lapses"
+	if len(records) == 0 || strings.Join(records[0], ",") != wantHeader {
+		t.Fatalf("header = %v, want %s", records, wantHeader)
+	}
+	if rows := len(records) - 1; rows != 3 {
+		t.Fatalf("CSV has %d rows, want one per studied word (3)", rows)
+	}
+	for _, row := range records[1:] {
+		if row[2] != "basics" || row[3] != string(learning.StateReview) || row[4] != "5.00" || row[7] != "1" {
+			t.Errorf("row = %v, want a basics review card with stability 5.00 and 1 review", row)
+		}
+	}
+}
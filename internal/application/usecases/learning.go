@@ -3,10 +3,20 @@ package usecases
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"math/big"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 
 	"dutch-learning-bot/internal/domain/grammar"
 	"dutch-learning-bot/internal/domain/learning"
@@ -14,6 +24,56 @@ import (
 	"dutch-learning-bot/internal/domain/vocabulary"
 )
 
+// LearningConfig holds configuration for learning sessions
+type LearningConfig struct {
+	// How long a question may stay unanswered before it is auto-submitted as Again (0 disables)
+	QuestionTimeout time.Duration
+	// Number of distinct users reporting a word before it is archived for admin review (0 disables)
+	ReportArchiveThreshold int
+	// Words reviewed within this window are only served when nothing else is available
+	RecentReviewWindow time.Duration
+	// Suggest a break instead of repeating a word when only recently reviewed words remain
+	BreakOnRecentOnly bool
+	// Correct multiple-choice answers faster than this count as "fast" for the auto-Easy preference
+	FastAnswerThreshold time.Duration
+	// Reviews a word gets as Dutch-to-English recognition questions before production is mixed in,
+	// for users who enable recognition-first
+	RecognitionFirstReviews int
+	// Delete a user's progress on a word that no longer exists when a session runs into it,
+	// instead of only skipping the word
+	PruneMissingWordProgress bool
+	// How much confidence ratings stretch (certain) or shrink (guessed) the interval after a correct
+	// answer, for users who rate their confidence; 0.15 means ±15%
+	ConfidenceBoost float64
+}
+
+// ErrTakeBreak is returned when the only words left were just reviewed and the user should take a break
+var ErrTakeBreak = errors.New("only recently reviewed words remain")
+
+// errWordNotFound is returned when a progress record refers to a word that no longer exists
+var errWordNotFound = errors.New("word not found")
+
+// ErrNothingToUndo is returned when the user has no review to undo
+var ErrNothingToUndo = errors.New("no review to undo")
+
+// ErrReviewNotUndoable is returned when the last review was saved without the card state needed to undo it
+var ErrReviewNotUndoable = errors.New("last review can't be undone")
+
+// DefaultLearningConfig returns sensible defaults for learning sessions
+func DefaultLearningConfig() *LearningConfig {
+	return &LearningConfig{
+		QuestionTimeout:         0, // Questions wait for an answer indefinitely
+		ReportArchiveThreshold:  3,
+		RecentReviewWindow:      10 * time.Minute,
+		BreakOnRecentOnly:       false, // Repeat recently reviewed words rather than stopping
+		FastAnswerThreshold:     3 * time.Second,
+		RecognitionFirstReviews: 3,
+		// Missing words are skipped but their progress is kept, in case they come back
+		PruneMissingWordProgress: false,
+		ConfidenceBoost:          0.15,
+	}
+}
+
 // LearningUseCase handles learning-related business operations
 type LearningUseCase struct {
 	learningRepo    learning.Repository
@@ -21,6 +81,8 @@ type LearningUseCase struct {
 	userRepo        user.Repository
 	grammarRepo     grammar.Repository
 	preferencesRepo user.PreferencesRepository
+	optionGenerator *OptionGenerator
+	config          *LearningConfig
 }
 
 // NewLearningUseCase creates a new learning use case
@@ -30,16 +92,28 @@ func NewLearningUseCase(
 	userRepo user.Repository,
 	grammarRepo grammar.Repository,
 	preferencesRepo user.PreferencesRepository,
+	config *LearningConfig,
 ) *LearningUseCase {
+	if config == nil {
+		config = DefaultLearningConfig()
+	}
+
 	return &LearningUseCase{
 		learningRepo:    learningRepo,
 		vocabularyRepo:  vocabularyRepo,
 		userRepo:        userRepo,
 		grammarRepo:     grammarRepo,
 		preferencesRepo: preferencesRepo,
+		optionGenerator: NewOptionGenerator(nil),
+		config:          config,
 	}
 }
 
+// Config returns the learning configuration
+func (uc *LearningUseCase) Config() *LearningConfig {
+	return uc.config
+}
+
 // LearningSession represents an active learning session
 type LearningSession struct {
 	UserID       user.ID
@@ -50,6 +124,131 @@ type LearningSession struct {
 	Options      []string
 	CorrectIndex int
 	GrammarTip   *grammar.GrammarTip // Optional grammar tip
+	HintType     user.HintType
+	Practice     bool                // Extra practice: answers don't update the word's schedule
+	Tag          string              // Set when the session only studies words with this tag
+	Category     vocabulary.Category // Set when the session only studies words in this category
+	ShowSense    bool                // Show the meaning of an ambiguous English prompt
+	Typed        bool                // The user types the translation instead of picking an option
+
+	// Answer state, set once the user picks an option or sends a typed answer
+	SelectedIndex      int
+	TypedAnswer        string
+	AnswerCorrect      bool
+	AnswerScore        float64             // Partial credit for the answer, recorded with the review
+	AwaitingReveal     bool                // Verdict shown, translation and rating buttons still hidden
+	Confidence         learning.Confidence // How sure the user was, for users who rate their confidence
+	AwaitingConfidence bool                // Answer claimed, waiting for the confidence rating before showing the result
+	AllowedRatings     []learning.Rating   // Ratings the user may pick for this answer (nil allows all)
+
+	// Session-wide state, carried from question to question
+	SessionStart   time.Time
+	CorrectCount   int
+	IncorrectCount int
+	NewCount       int  // Answers to words seen for the first time
+	Focus          bool // Focus session: no grammar tips, whatever the user's preference
+
+	answered int32       // Set once the question has been answered or timed out
+	timer    *time.Timer // Optional question timeout timer
+}
+
+// Prompt returns the side of the word shown in the question. An English prompt carries
+// the word's sense in parentheses, like "right (direction)", when the user wants it.
+// Dutch prompts never do, as the sense would give the answer away.
+func (s *LearningSession) Prompt() string {
+	if s.QuestionType == QuestionTypeEnglishToDutch {
+		if s.ShowSense && s.Word.Sense() != "" {
+			return fmt.Sprintf("%s (%s)", s.Word.English(), s.Word.Sense())
+		}
+		return s.Word.English()
+	}
+	return s.Word.Dutch()
+}
+
+// ExpectedAnswer returns the translation the user is asked to pick
+func (s *LearningSession) ExpectedAnswer() string {
+	if s.QuestionType == QuestionTypeEnglishToDutch {
+		return s.Word.Dutch()
+	}
+	return s.Word.English()
+}
+
+// RecordAnswer updates the session scoreboard with an answer
+func (s *LearningSession) RecordAnswer(correct bool) {
+	if s.Progress.FSRSCard().State() == learning.StateNew {
+		s.NewCount++
+	}
+	if correct {
+		s.CorrectCount++
+	} else {
+		s.IncorrectCount++
+	}
+}
+
+// AnswerCount returns how many questions have been answered in the session
+func (s *LearningSession) AnswerCount() int {
+	return s.CorrectCount + s.IncorrectCount
+}
+
+// Accuracy returns the share of the session's answers that were correct, as a percentage
+func (s *LearningSession) Accuracy() float64 {
+	if s.AnswerCount() == 0 {
+		return 0
+	}
+	return float64(s.CorrectCount) / float64(s.AnswerCount()) * 100
+}
+
+// AllowsRating reports whether the user may rate this answer with the given rating
+func (s *LearningSession) AllowsRating(rating learning.Rating) bool {
+	if s.AllowedRatings == nil {
+		return true
+	}
+	for _, allowed := range s.AllowedRatings {
+		if allowed == rating {
+			return true
+		}
+	}
+	return false
+}
+
+// ContinueFrom carries session-wide state over from the previous question in this session
+func (s *LearningSession) ContinueFrom(previous *LearningSession) {
+	s.SessionStart = previous.SessionStart
+	s.CorrectCount = previous.CorrectCount
+	s.IncorrectCount = previous.IncorrectCount
+	s.NewCount = previous.NewCount
+	s.Focus = previous.Focus
+}
+
+// Elapsed returns how long the session has been running
+func (s *LearningSession) Elapsed() time.Duration {
+	return time.Since(s.SessionStart)
+}
+
+// ClaimAnswer marks the question as answered.
+// It returns false if the question was already answered or timed out.
+func (s *LearningSession) ClaimAnswer() bool {
+	answered := atomic.CompareAndSwapInt32(&s.answered, 0, 1)
+	if answered && s.timer != nil {
+		s.timer.Stop()
+	}
+	return answered
+}
+
+// Answered reports whether the question was already answered or timed out
+func (s *LearningSession) Answered() bool {
+	return atomic.LoadInt32(&s.answered) == 1
+}
+
+// StartTimeout calls onTimeout after the given duration unless the question is answered first
+func (s *LearningSession) StartTimeout(timeout time.Duration, onTimeout func()) {
+	s.timer = time.AfterFunc(timeout, func() {
+		// The timer has already fired, so claim without stopping it: s.timer may
+		// still be being assigned when a short timeout runs out
+		if atomic.CompareAndSwapInt32(&s.answered, 0, 1) {
+			onTimeout()
+		}
+	})
 }
 
 // QuestionType represents the type of question being asked
@@ -60,8 +259,64 @@ const (
 	QuestionTypeDutchToEnglish QuestionType = "dutch_to_english"
 )
 
-// GetNextDueWord retrieves the next word due for review
-func (uc *LearningUseCase) GetNextDueWord(ctx context.Context, userID user.ID) (*LearningSession, error) {
+// Language labels one side of a vocabulary pair in the UI
+type Language struct {
+	Flag string
+	Name string
+}
+
+var (
+	LanguageDutch   = Language{Flag: "🇳🇱", Name: "Dutch"}
+	LanguageEnglish = Language{Flag: "🇬🇧", Name: "English"}
+)
+
+// PromptLanguage returns the language the question is shown in
+func (qt QuestionType) PromptLanguage() Language {
+	if qt == QuestionTypeEnglishToDutch {
+		return LanguageEnglish
+	}
+	return LanguageDutch
+}
+
+// AnswerLanguage returns the language the user answers in
+func (qt QuestionType) AnswerLanguage() Language {
+	if qt == QuestionTypeEnglishToDutch {
+		return LanguageDutch
+	}
+	return LanguageEnglish
+}
+
+// questionTypeFor picks the question type for a user's preferred direction
+func questionTypeFor(direction user.QuestionDirection) QuestionType {
+	switch direction {
+	case user.QuestionDirectionToDutch:
+		return QuestionTypeEnglishToDutch
+	case user.QuestionDirectionFromDutch:
+		return QuestionTypeDutchToEnglish
+	}
+
+	// Mixed: randomly choose question type
+	if time.Now().UnixNano()%2 == 0 {
+		return QuestionTypeDutchToEnglish
+	}
+	return QuestionTypeEnglishToDutch
+}
+
+// GetNextDueWord retrieves the next word due for review.
+// In a focus session the question never carries a grammar tip.
+func (uc *LearningUseCase) GetNextDueWord(ctx context.Context, userID user.ID, focus bool) (*LearningSession, error) {
+	// Work through the user's per-category daily plan first, if they have one
+	mixProgress, err := uc.getDailyMixWords(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(mixProgress) > 0 {
+		session, err := uc.sessionForCandidates(ctx, userID, mixProgress, focus)
+		if session != nil || (err != nil && !errors.Is(err, ErrTakeBreak)) {
+			return session, err
+		}
+	}
+
 	// Get available words for learning using business logic
 	availableProgress, err := uc.getAvailableWordsForLearning(ctx, userID, 10) // Get more than 1 to have options
 	if err != nil {
@@ -72,18 +327,242 @@ func (uc *LearningUseCase) GetNextDueWord(ctx context.Context, userID user.ID) (
 		return nil, nil // No words available
 	}
 
-	// Select the best word based on priority
-	selectedProgress := uc.selectBestWordForLearning(availableProgress)
+	return uc.sessionForCandidates(ctx, userID, availableProgress, focus)
+}
+
+// sessionForCandidates builds a question for the best candidate under the user's prioritization
+// strategy, skipping candidates whose word no longer exists. It returns nil when every candidate's
+// word is missing, and ErrTakeBreak when only recently reviewed words remain.
+func (uc *LearningUseCase) sessionForCandidates(ctx context.Context, userID user.ID, candidates []*learning.UserProgress, focus bool) (*LearningSession, error) {
+	priority := uc.getStudyPriority(ctx, userID)
+	for len(candidates) > 0 {
+		selectedProgress := uc.selectBestWordForLearning(candidates, priority)
+		if selectedProgress == nil {
+			return nil, ErrTakeBreak
+		}
+
+		session, err := uc.newSession(ctx, userID, selectedProgress, focus)
+		if !errors.Is(err, errWordNotFound) {
+			return session, err
+		}
+		uc.handleMissingWord(ctx, selectedProgress)
+		candidates = withoutProgress(candidates, selectedProgress)
+	}
+	return nil, nil
+}
+
+// handleMissingWord deals with a progress record whose word no longer exists, deleting it
+// when the config asks for that
+func (uc *LearningUseCase) handleMissingWord(ctx context.Context, progress *learning.UserProgress) {
+	log.Printf("Skipping word %d for user %d: the word no longer exists", progress.WordID(), progress.UserID())
+	if !uc.config.PruneMissingWordProgress || progress.ID() == 0 {
+		return
+	}
+	if err := uc.learningRepo.DeleteProgress(ctx, progress.ID()); err != nil {
+		log.Printf("Failed to delete progress for missing word %d: %v", progress.WordID(), err)
+	}
+}
+
+// withoutProgress returns the candidates other than the given one
+func withoutProgress(candidates []*learning.UserProgress, progress *learning.UserProgress) []*learning.UserProgress {
+	remaining := make([]*learning.UserProgress, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate != progress {
+			remaining = append(remaining, candidate)
+		}
+	}
+	return remaining
+}
+
+// GetNextTaggedWord retrieves the next due or new word carrying the user's tag.
+// In a focus session the question never carries a grammar tip.
+func (uc *LearningUseCase) GetNextTaggedWord(ctx context.Context, userID user.ID, tag string, focus bool) (*LearningSession, error) {
+	const maxWords = 10
+
+	availableProgress, err := uc.learningRepo.FindDueWordsByTag(ctx, userID, tag, uc.getReviewAheadWindow(ctx, userID), maxWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due tagged words: %w", err)
+	}
+
+	if newLimit := uc.newWordLimit(ctx, userID, maxWords-len(availableProgress)); newLimit > 0 {
+		newProgress, err := uc.learningRepo.FindNewWordsByTag(ctx, userID, tag, uc.getNewWordSelection(ctx, userID), newLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get new tagged words: %w", err)
+		}
+		availableProgress = append(availableProgress, newProgress...)
+	}
+
+	if len(availableProgress) == 0 {
+		return nil, nil // Nothing due for this tag
+	}
+
+	session, err := uc.sessionForCandidates(ctx, userID, availableProgress, focus)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	session.Tag = tag
+
+	return session, nil
+}
+
+// GetNextDueWordInCategory retrieves the next due or new word in a vocabulary category,
+// for users drilling a single topic. In a focus session the question never carries a grammar tip.
+func (uc *LearningUseCase) GetNextDueWordInCategory(ctx context.Context, userID user.ID, category vocabulary.Category, focus bool) (*LearningSession, error) {
+	const maxWords = 10
+
+	availableProgress, err := uc.learningRepo.FindDueWordsByCategory(ctx, userID, category, uc.getReviewAheadWindow(ctx, userID), maxWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due words for %s: %w", category, err)
+	}
+
+	if newLimit := uc.newWordLimit(ctx, userID, maxWords-len(availableProgress)); newLimit > 0 {
+		newProgress, err := uc.learningRepo.FindNewWordsByCategory(ctx, userID, category, uc.getNewWordSelection(ctx, userID), newLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get new words for %s: %w", category, err)
+		}
+		availableProgress = append(availableProgress, newProgress...)
+	}
+
+	if len(availableProgress) == 0 {
+		return nil, nil // Nothing due in this category
+	}
+
+	session, err := uc.sessionForCandidates(ctx, userID, availableProgress, focus)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	session.Category = category
+
+	return session, nil
+}
+
+// TagWord resolves a term and attaches the user's tag to it.
+// It returns nil if the term doesn't match any word.
+func (uc *LearningUseCase) TagWord(ctx context.Context, userID user.ID, term, tag string) (*vocabulary.Word, error) {
+	word, err := uc.vocabularyRepo.FindByTerm(ctx, term)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find word: %w", err)
+	}
+	if word == nil {
+		return nil, nil
+	}
+
+	if err := uc.learningRepo.TagWord(ctx, userID, word.ID(), tag); err != nil {
+		return nil, fmt.Errorf("failed to tag word: %w", err)
+	}
+
+	return word, nil
+}
+
+// GetUserTags retrieves the tags the user has created
+func (uc *LearningUseCase) GetUserTags(ctx context.Context, userID user.ID) ([]string, error) {
+	tags, err := uc.learningRepo.FindTags(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// LogSession records the aggregates of a finished session for long-term analytics.
+// Sessions without any answers are not recorded.
+func (uc *LearningUseCase) LogSession(ctx context.Context, session *LearningSession) error {
+	answered := session.CorrectCount + session.IncorrectCount
+	if answered == 0 {
+		return nil
+	}
+
+	sessionLog := &learning.SessionLog{
+		UserID:         session.UserID,
+		StartedAt:      session.SessionStart,
+		EndedAt:        time.Now(),
+		CorrectCount:   session.CorrectCount,
+		IncorrectCount: session.IncorrectCount,
+		NewWords:       session.NewCount,
+		ReviewWords:    answered - session.NewCount,
+		Practice:       session.Practice,
+	}
+
+	if err := uc.learningRepo.SaveSessionLog(ctx, sessionLog); err != nil {
+		return fmt.Errorf("failed to log session: %w", err)
+	}
+
+	return nil
+}
+
+// practicePoolSize is how many of the weakest studied words extra practice picks from
+const practicePoolSize = 5
+
+// GetPracticeWord picks one of the user's weakest studied words for extra practice.
+// Practice answers don't change the word's schedule, so it is safe to use when nothing is due.
+func (uc *LearningUseCase) GetPracticeWord(ctx context.Context, userID user.ID) (*LearningSession, error) {
+	allProgress, err := uc.learningRepo.FindProgressByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	if len(allProgress) == 0 {
+		return nil, nil // Nothing studied yet
+	}
+
+	// Weakest memories first
+	sort.Slice(allProgress, func(i, j int) bool {
+		return allProgress[i].FSRSCard().Stability() < allProgress[j].FSRSCard().Stability()
+	})
+	pool := allProgress
+	if len(pool) > practicePoolSize {
+		pool = pool[:practicePoolSize]
+	}
+
+	for len(pool) > 0 {
+		pick, err := rand.Int(rand.Reader, big.NewInt(int64(len(pool))))
+		if err != nil {
+			pick = big.NewInt(time.Now().UnixNano() % int64(len(pool)))
+		}
+		selectedProgress := pool[pick.Int64()]
+
+		session, err := uc.newSession(ctx, userID, selectedProgress, false)
+		if errors.Is(err, errWordNotFound) {
+			uc.handleMissingWord(ctx, selectedProgress)
+			pool = withoutProgress(pool, selectedProgress)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		session.Practice = true
+
+		return session, nil
+	}
+
+	return nil, nil // Every word in the pool is gone
+}
 
+// newSession builds a multiple choice question for the given progress.
+// Focus sessions skip grammar tips.
+func (uc *LearningUseCase) newSession(ctx context.Context, userID user.ID, selectedProgress *learning.UserProgress, focus bool) (*LearningSession, error) {
 	// Get the word details
 	word, err := uc.vocabularyRepo.FindByID(ctx, selectedProgress.WordID())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get word: %w", err)
 	}
+	if word == nil {
+		// The word was deleted while the user still has progress on it
+		return nil, fmt.Errorf("word %d: %w", selectedProgress.WordID(), errWordNotFound)
+	}
 
-	// Randomly choose question type
-	questionType := QuestionTypeEnglishToDutch
-	if time.Now().UnixNano()%2 == 0 {
+	preferences, prefErr := uc.preferencesRepo.FindPreferences(ctx, userID)
+	hasPreferences := prefErr == nil && preferences != nil
+
+	// Choose the question type from the user's preferred direction for the word's category
+	direction := user.DefaultQuestionDirection
+	if hasPreferences {
+		direction = preferences.QuestionDirectionFor(string(word.Category()))
+	}
+	questionType := questionTypeFor(direction)
+
+	// Recognising a word comes before producing it: early reviews always show the Dutch side
+	if hasPreferences && preferences.RecognitionFirst() &&
+		selectedProgress.FSRSCard().ReviewCount() < uc.config.RecognitionFirstReviews {
 		questionType = QuestionTypeDutchToEnglish
 	}
 
@@ -99,13 +578,22 @@ func (uc *LearningUseCase) GetNextDueWord(ctx context.Context, userID user.ID) (
 		Progress:     selectedProgress,
 		QuestionType: questionType,
 		StartTime:    time.Now(),
+		SessionStart: time.Now(),
 		Options:      options,
 		CorrectIndex: correctIndex,
+		HintType:     user.DefaultHintType,
+		ShowSense:    user.DefaultShowWordSense,
+		Focus:        focus,
 	}
 
 	// Check if user has grammar tips enabled before showing them
-	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
-	if err == nil && preferences != nil && preferences.GrammarTipsEnabled() {
+	if hasPreferences {
+		session.HintType = preferences.GetHintType()
+		session.ShowSense = preferences.ShowWordSense()
+		session.Typed = preferences.GetAnswerMode() == user.AnswerModeTyped
+	}
+	// Focus sessions don't even roll for a tip, so no tips are looked up
+	if !focus && hasPreferences && preferences.GrammarTipsEnabled() {
 		// 20% chance to include a contextual grammar tip
 		if shouldShowGrammarTip() {
 			grammarTip, err := uc.GetContextualGrammarTip(ctx, word, userID)
@@ -119,21 +607,70 @@ func (uc *LearningUseCase) GetNextDueWord(ctx context.Context, userID user.ID) (
 	return session, nil
 }
 
+// getDailyMixWords returns due and new words from the first category in the user's daily mix
+// whose quota for today isn't met yet. It returns nil once every quota is met.
+func (uc *LearningUseCase) getDailyMixWords(ctx context.Context, userID user.ID) ([]*learning.UserProgress, error) {
+	const maxWords = 10
+
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return nil, nil // Fall back to the regular selection
+	}
+	mix := preferences.GetDailyMix()
+	if len(mix) == 0 {
+		return nil, nil
+	}
+
+	now := uc.getUserNow(ctx, userID)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	reviewed, err := uc.learningRepo.CountReviewedWordsByCategory(ctx, userID, startOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count today's reviews: %w", err)
+	}
+
+	reviewAhead := uc.getReviewAheadWindow(ctx, userID)
+	for _, quota := range mix {
+		category := vocabulary.Category(quota.Category)
+		remaining := quota.Words - reviewed[category]
+		if remaining <= 0 {
+			continue
+		}
+
+		limit := min(remaining, maxWords)
+		progress, err := uc.learningRepo.FindDueWordsByCategory(ctx, userID, category, reviewAhead, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get due words for %s: %w", category, err)
+		}
+		if newLimit := uc.newWordLimit(ctx, userID, limit-len(progress)); newLimit > 0 {
+			newProgress, err := uc.learningRepo.FindNewWordsByCategory(ctx, userID, category, uc.getNewWordSelection(ctx, userID), newLimit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get new words for %s: %w", category, err)
+			}
+			progress = append(progress, newProgress...)
+		}
+
+		if len(progress) > 0 {
+			return progress, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // getAvailableWordsForLearning gets words available for learning with business logic
 func (uc *LearningUseCase) getAvailableWordsForLearning(ctx context.Context, userID user.ID, maxWords int) ([]*learning.UserProgress, error) {
 	var allProgress []*learning.UserProgress
 
 	// First, get words that have progress and are due for review
-	dueProgress, err := uc.learningRepo.FindDueWords(ctx, userID, maxWords)
+	dueProgress, err := uc.learningRepo.FindDueWords(ctx, userID, uc.getReviewAheadWindow(ctx, userID), maxWords)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get due progress words: %w", err)
 	}
 	allProgress = append(allProgress, dueProgress...)
 
-	// If we need more words, get new words (without progress)
-	if len(allProgress) < maxWords {
-		remainingLimit := maxWords - len(allProgress)
-		newProgress, err := uc.learningRepo.FindNewWords(ctx, userID, remainingLimit)
+	// If we need more words, get new words (without progress), as many as the user may still be introduced to
+	if newLimit := uc.newWordLimit(ctx, userID, maxWords-len(allProgress)); newLimit > 0 {
+		newProgress, err := uc.learningRepo.FindNewWords(ctx, userID, uc.getNewWordSelection(ctx, userID), newLimit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get new words: %w", err)
 		}
@@ -143,20 +680,25 @@ func (uc *LearningUseCase) getAvailableWordsForLearning(ctx context.Context, use
 	return allProgress, nil
 }
 
-// selectBestWordForLearning applies business logic for word selection and prioritization
-func (uc *LearningUseCase) selectBestWordForLearning(allProgress []*learning.UserProgress) *learning.UserProgress {
+// selectBestWordForLearning applies business logic for word selection and prioritization.
+// It returns nil when only recently reviewed words remain and the config asks for a break instead.
+func (uc *LearningUseCase) selectBestWordForLearning(allProgress []*learning.UserProgress, priority user.StudyPriority) *learning.UserProgress {
 	// Separate words into categories
+	var learningWords []*learning.UserProgress
 	var dueWords []*learning.UserProgress
 	var newWords []*learning.UserProgress
 	var recentlyReviewedWords []*learning.UserProgress
 
-	tenMinutesAgo := time.Now().Add(-10 * time.Minute)
+	recentCutoff := time.Now().Add(-uc.config.RecentReviewWindow)
 
 	for _, progress := range allProgress {
 		if progress.ID() == 0 {
 			// New word (no ID means it wasn't saved yet)
 			newWords = append(newWords, progress)
-		} else if progress.FSRSCard().LastReview().After(tenMinutesAgo) {
+		} else if priority == user.StudyPriorityLearningFirst && isInLearningPhase(progress) {
+			// Word still being learned (graduate it before anything else)
+			learningWords = append(learningWords, progress)
+		} else if progress.FSRSCard().LastReview().After(recentCutoff) {
 			// Recently reviewed word (deprioritize)
 			recentlyReviewedWords = append(recentlyReviewedWords, progress)
 		} else {
@@ -166,29 +708,182 @@ func (uc *LearningUseCase) selectBestWordForLearning(allProgress []*learning.Use
 	}
 
 	// Priority order:
+	// 0. Learning/relearning words (learning-first strategy only)
 	// 1. Due words (not recently reviewed)
 	// 2. New words
-	// 3. Recently reviewed words
-	if len(dueWords) > 0 {
-		return dueWords[0]
+	// 3. Recently reviewed words (unless a break is preferred)
+	if uc.config.BreakOnRecentOnly && len(learningWords)+len(dueWords)+len(newWords) == 0 && len(recentlyReviewedWords) > 0 {
+		return nil
 	}
-	if len(newWords) > 0 {
-		return newWords[0]
-	}
-	if len(recentlyReviewedWords) > 0 {
-		return recentlyReviewedWords[0]
+	for _, bucket := range [][]*learning.UserProgress{learningWords, dueWords, newWords, recentlyReviewedWords} {
+		if len(bucket) > 0 {
+			return bucket[0]
+		}
 	}
 
 	// Fallback (shouldn't happen if allProgress is not empty)
 	return allProgress[0]
 }
 
+// isInLearningPhase reports whether a card has not yet graduated to the review state
+func isInLearningPhase(progress *learning.UserProgress) bool {
+	state := progress.FSRSCard().State()
+	return state == learning.StateLearning || state == learning.StateRelearning
+}
+
+// getStudyPriority returns the user's prioritization strategy, or the default if preferences are unavailable
+func (uc *LearningUseCase) getStudyPriority(ctx context.Context, userID user.ID) user.StudyPriority {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultStudyPriority
+	}
+	return preferences.GetStudyPriority()
+}
+
+// reviewsOnly reports whether the user wants sessions to skip new words
+func (uc *LearningUseCase) reviewsOnly(ctx context.Context, userID user.ID) bool {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultReviewsOnly
+	}
+	return preferences.ReviewsOnly()
+}
+
+// newWordLimit caps how many of the wanted new words may be fetched: none in reviews-only mode,
+// and no more than what is left of the user's daily new-word limit
+func (uc *LearningUseCase) newWordLimit(ctx context.Context, userID user.ID, wanted int) int {
+	if wanted <= 0 || uc.reviewsOnly(ctx, userID) {
+		return 0
+	}
+	return min(wanted, uc.newWordsLeftToday(ctx, userID))
+}
+
+// newWordsLeftToday returns how many more new words the user may be introduced to today
+func (uc *LearningUseCase) newWordsLeftToday(ctx context.Context, userID user.ID) int {
+	dailyLimit := user.DefaultDailyNewLimit
+	if preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID); err == nil && preferences != nil {
+		dailyLimit = preferences.GetDailyNewLimit()
+	}
+	if dailyLimit == 0 {
+		return math.MaxInt // No limit
+	}
+
+	now := uc.getUserNow(ctx, userID)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	introduced, err := uc.learningRepo.CountIntroducedWords(ctx, userID, startOfDay)
+	if err != nil {
+		// Don't hold back new words because the count failed
+		log.Printf("Failed to count today's new words for user %d: %v", userID, err)
+		return dailyLimit
+	}
+	return max(0, dailyLimit-introduced)
+}
+
+// DailyNewLimitReached reports whether the user has met today's new-word limit
+func (uc *LearningUseCase) DailyNewLimitReached(ctx context.Context, userID user.ID) bool {
+	return uc.newWordsLeftToday(ctx, userID) == 0
+}
+
+// getNewWordSelection returns the user's preferred order for introducing new words
+func (uc *LearningUseCase) getNewWordSelection(ctx context.Context, userID user.ID) user.NewWordSelection {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.NewWordSelection{Order: user.DefaultNewWordOrder}
+	}
+	return preferences.GetNewWordSelection()
+}
+
+// getFSRSParams returns the user's custom FSRS weights, learning steps and request retention, or nil
+// to schedule with the defaults. Malformed stored values are ignored so a bad value never blocks reviews.
+func (uc *LearningUseCase) getFSRSParams(ctx context.Context, userID user.ID) *learning.FSRSParams {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return nil
+	}
+
+	params := learning.DefaultFSRSParams()
+	custom := false
+
+	if weights := preferences.GetFSRSWeights(); weights != "" {
+		if parsed, err := learning.ParseFSRSParams(weights); err != nil {
+			log.Printf("Ignoring invalid FSRS weights for user %d: %v", userID, err)
+		} else {
+			params.Weights = parsed.Weights
+			custom = true
+		}
+	}
+
+	if value := preferences.GetLearningSteps(); value != "" {
+		if steps, err := learning.ParseLearningSteps(value); err != nil {
+			log.Printf("Ignoring invalid learning steps for user %d: %v", userID, err)
+		} else {
+			params.LearningSteps = steps
+			custom = true
+		}
+	}
+
+	if value := preferences.GetRequestRetention(); value != "" {
+		if retention, err := learning.ParseRequestRetention(value); err != nil {
+			log.Printf("Ignoring invalid request retention for user %d: %v", userID, err)
+		} else {
+			params.RequestRetention = retention
+			custom = true
+		}
+	}
+
+	if !custom {
+		return nil
+	}
+	return params
+}
+
+// intervalFuzz reports whether the user's review intervals are spread randomly, defaulting to on
+func (uc *LearningUseCase) intervalFuzz(ctx context.Context, userID user.ID) bool {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultIntervalFuzz
+	}
+	return preferences.IntervalFuzz()
+}
+
+// minCommunityLearners is how many other learners must have reviewed a word before their
+// average difficulty is trusted to seed a new card
+const minCommunityLearners = 3
+
+// seedCommunityDifficulty starts an unstudied word at the average difficulty other learners
+// found it to have, for users who opted in. Without enough data the card keeps the default.
+// Failures are logged, as seeding only refines the first schedule.
+func (uc *LearningUseCase) seedCommunityDifficulty(ctx context.Context, userID user.ID, progress *learning.UserProgress) {
+	card := progress.FSRSCard()
+	if card.State() != learning.StateNew || card.ReviewCount() > 0 {
+		return
+	}
+
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil || !preferences.CommunityDifficulty() {
+		return
+	}
+
+	avgDifficulty, learners, err := uc.learningRepo.GetCommunityDifficulty(ctx, progress.WordID(), userID)
+	if err != nil {
+		log.Printf("Failed to get community difficulty for word %d: %v", progress.WordID(), err)
+		return
+	}
+	if learners >= minCommunityLearners {
+		progress.SeedDifficulty(avgDifficulty)
+	}
+}
+
 // GetContextualGrammarTip gets a grammar tip that's relevant to the current word
 func (uc *LearningUseCase) GetContextualGrammarTip(ctx context.Context, word *vocabulary.Word, userID user.ID) (*grammar.GrammarTip, error) {
-	// First try to find tips that specifically apply to this word
-	applicableTips, err := uc.grammarRepo.FindApplicableToWord(ctx, word.Dutch(), word.English(), string(word.Category()))
+	// Grammar tips are optional; without a repository there is nothing to show
+	if uc.grammarRepo == nil {
+		return nil, nil
+	}
+
+	applicableTips, err := uc.findGrammarTips(ctx, word)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find applicable grammar tips: %w", err)
+		return nil, err
 	}
 
 	if len(applicableTips) > 0 {
@@ -205,115 +900,99 @@ func (uc *LearningUseCase) GetContextualGrammarTip(ctx context.Context, word *vo
 	return nil, nil
 }
 
-// shouldShowGrammarTip determines if we should show a grammar tip (20% chance)
-func shouldShowGrammarTip() bool {
-	randomNum, err := rand.Int(rand.Reader, big.NewInt(100))
-	if err != nil {
-		// Fallback to time-based randomization
-		return time.Now().UnixNano()%100 < 20
-	}
-	return randomNum.Int64() < 20
-}
-
-// generateMultipleChoiceOptions generates 4 options with one correct answer
-func (uc *LearningUseCase) generateMultipleChoiceOptions(ctx context.Context, word *vocabulary.Word, questionType QuestionType) ([]string, int, error) {
-	// Get all words from the same category for wrong options
-	categoryWords, err := uc.vocabularyRepo.FindByCategory(ctx, word.Category())
+// findGrammarTips finds the grammar tips that apply to a word,
+// preferring tips about the word's part of speech when it is known
+func (uc *LearningUseCase) findGrammarTips(ctx context.Context, word *vocabulary.Word) ([]*grammar.GrammarTip, error) {
+	applicableTips, err := uc.grammarRepo.FindApplicableToWord(ctx, word.Dutch(), word.English(), string(word.Category()))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get category words: %w", err)
+		return nil, fmt.Errorf("failed to find applicable grammar tips: %w", err)
 	}
 
-	var correctAnswer string
-	var wrongAnswers []string
-
-	if questionType == QuestionTypeEnglishToDutch {
-		correctAnswer = word.Dutch()
-		for _, w := range categoryWords {
-			if w.ID() != word.ID() && w.Dutch() != correctAnswer {
-				wrongAnswers = append(wrongAnswers, w.Dutch())
+	if pos := string(word.PartOfSpeech()); pos != "" {
+		var matchingTips []*grammar.GrammarTip
+		for _, tip := range applicableTips {
+			if tip.MatchesPartOfSpeech(pos) {
+				matchingTips = append(matchingTips, tip)
 			}
 		}
-	} else {
-		correctAnswer = word.English()
-		for _, w := range categoryWords {
-			if w.ID() != word.ID() && w.English() != correctAnswer {
-				wrongAnswers = append(wrongAnswers, w.English())
-			}
+		if len(matchingTips) > 0 {
+			applicableTips = matchingTips
 		}
 	}
 
-	// If we don't have enough words in the category, get from all words
-	if len(wrongAnswers) < 3 {
-		allWords, err := uc.vocabularyRepo.FindAll(ctx)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get all words: %w", err)
-		}
-
-		for _, w := range allWords {
-			if w.ID() != word.ID() {
-				var candidate string
-				if questionType == QuestionTypeEnglishToDutch {
-					candidate = w.Dutch()
-				} else {
-					candidate = w.English()
-				}
-
-				if candidate != correctAnswer {
-					// Check if we already have this answer
-					found := false
-					for _, existing := range wrongAnswers {
-						if existing == candidate {
-							found = true
-							break
-						}
-					}
-					if !found {
-						wrongAnswers = append(wrongAnswers, candidate)
-					}
-				}
-			}
-			if len(wrongAnswers) >= 3 {
-				break
-			}
-		}
-	}
+	return applicableTips, nil
+}
+
+// WordDefinition is a vocabulary entry found by a lookup, with the grammar tips that apply to it
+type WordDefinition struct {
+	Word *vocabulary.Word
+	Tips []*grammar.GrammarTip
+}
+
+// maxDefinitions caps how many matching words a lookup returns
+const maxDefinitions = 5
 
-	// Ensure we have at least 3 wrong answers
-	if len(wrongAnswers) < 3 {
-		return nil, 0, fmt.Errorf("not enough words to generate options")
+// DefineWord looks up words whose Dutch or English text contains the query, without starting a review
+func (uc *LearningUseCase) DefineWord(ctx context.Context, query string) ([]*WordDefinition, error) {
+	words, err := uc.vocabularyRepo.Search(ctx, query, maxDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search words: %w", err)
 	}
 
-	// Select 3 random wrong answers
-	selectedWrong := make([]string, 3)
-	if len(wrongAnswers) >= 3 {
-		// Better shuffling using crypto/rand
-		for i := len(wrongAnswers) - 1; i > 0; i-- {
-			j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+	definitions := make([]*WordDefinition, 0, len(words))
+	for _, word := range words {
+		definition := &WordDefinition{Word: word}
+		if uc.grammarRepo != nil {
+			tips, err := uc.findGrammarTips(ctx, word)
 			if err != nil {
-				// Fallback to time-based if crypto/rand fails
-				j = big.NewInt(int64(time.Now().UnixNano() % int64(i+1)))
+				// The entry is still useful without its tips
+				log.Printf("Failed to find grammar tips for %q: %v", word.Dutch(), err)
 			}
-			wrongAnswers[i], wrongAnswers[j.Int64()] = wrongAnswers[j.Int64()], wrongAnswers[i]
+			definition.Tips = tips
 		}
-		copy(selectedWrong, wrongAnswers[:3])
+		definitions = append(definitions, definition)
 	}
 
-	// Create options array with correct answer at random position
-	options := make([]string, 4)
-	correctIndexBig, err := rand.Int(rand.Reader, big.NewInt(4))
-	correctIndex := int(correctIndexBig.Int64())
+	return definitions, nil
+}
+
+// SearchWords looks up to limit words whose Dutch or English text contains the query
+func (uc *LearningUseCase) SearchWords(ctx context.Context, query string, limit int) ([]*vocabulary.Word, error) {
+	words, err := uc.vocabularyRepo.Search(ctx, query, limit)
 	if err != nil {
-		// Fallback to time-based if crypto/rand fails
-		correctIndex = int(time.Now().UnixNano()) % 4
+		return nil, fmt.Errorf("failed to search words: %w", err)
 	}
+	return words, nil
+}
 
-	options[correctIndex] = correctAnswer
-	wrongIndex := 0
-	for i := 0; i < 4; i++ {
-		if i != correctIndex {
-			options[i] = selectedWrong[wrongIndex]
-			wrongIndex++
+// shouldShowGrammarTip determines if we should show a grammar tip (20% chance)
+func shouldShowGrammarTip() bool {
+	randomNum, err := rand.Int(rand.Reader, big.NewInt(100))
+	if err != nil {
+		// Fallback to time-based randomization
+		return time.Now().UnixNano()%100 < 20
+	}
+	return randomNum.Int64() < 20
+}
+
+// generateMultipleChoiceOptions generates 4 options with one correct answer, drawing wrong answers
+// from the word's category and only loading the whole vocabulary when the category is too small
+func (uc *LearningUseCase) generateMultipleChoiceOptions(ctx context.Context, word *vocabulary.Word, questionType QuestionType) ([]string, int, error) {
+	categoryWords, err := uc.vocabularyRepo.FindByCategory(ctx, word.Category())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get category words: %w", err)
+	}
+
+	options, correctIndex, err := uc.optionGenerator.Generate(word, questionType, categoryWords)
+	if errors.Is(err, ErrNotEnoughOptions) {
+		allWords, findErr := uc.vocabularyRepo.FindAll(ctx)
+		if findErr != nil {
+			return nil, 0, fmt.Errorf("failed to get all words: %w", findErr)
 		}
+		options, correctIndex, err = uc.optionGenerator.Generate(word, questionType, categoryWords, allWords)
+	}
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return options, correctIndex, nil
@@ -331,9 +1010,22 @@ func (uc *LearningUseCase) ProcessReview(
 	rating learning.Rating,
 	responseTime time.Duration,
 ) error {
-	// Process the review
+	// Keep the card as it was, so the review can be undone
+	priorCard := session.Progress.FSRSCard().Snapshot()
+
+	// A word's first review may start from how hard other learners found it
+	uc.seedCommunityDifficulty(ctx, session.UserID, session.Progress)
+
+	// Process the review with the user's own FSRS weights, if they set any
+	session.Progress.FSRSCard().SetParams(uc.getFSRSParams(ctx, session.UserID))
+	session.Progress.FSRSCard().SetIntervalFuzz(uc.intervalFuzz(ctx, session.UserID))
 	session.Progress.Review(rating)
 
+	// A correct answer the user was certain of (or only guessed) gets a longer (or shorter) interval
+	if session.AnswerCorrect && session.Confidence.IsValid() {
+		session.Progress.FSRSCard().ScaleInterval(session.Confidence.IntervalMultiplier(uc.config.ConfidenceBoost))
+	}
+
 	// Create review history
 	history := learning.NewReviewHistory(
 		session.UserID,
@@ -341,6 +1033,8 @@ func (uc *LearningUseCase) ProcessReview(
 		rating,
 		responseTime,
 	)
+	history.SetScore(session.AnswerScore)
+	history.SetPriorCard(priorCard)
 
 	// Save both progress and history in a single transaction
 	err := uc.learningRepo.SaveProgressAndHistory(ctx, session.Progress, history)
@@ -348,9 +1042,40 @@ func (uc *LearningUseCase) ProcessReview(
 		return fmt.Errorf("failed to save progress and history: %w", err)
 	}
 
+	// Keep today's difficulty snapshot current for the stats trend; the review itself is already saved
+	if err := uc.learningRepo.RecordDifficultySnapshot(ctx, session.UserID, uc.getUserNow(ctx, session.UserID)); err != nil {
+		log.Printf("Failed to record difficulty snapshot for user %d: %v", session.UserID, err)
+	}
+
 	return nil
 }
 
+// UndoLastReview reverts the user's most recent review, restoring the word's card to its state
+// before that review. It returns the word whose review was undone.
+func (uc *LearningUseCase) UndoLastReview(ctx context.Context, userID user.ID) (*vocabulary.Word, error) {
+	history, err := uc.learningRepo.FindLastReview(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find last review: %w", err)
+	}
+	if history == nil {
+		return nil, ErrNothingToUndo
+	}
+	if history.PriorCard() == nil {
+		return nil, ErrReviewNotUndoable
+	}
+
+	if err := uc.learningRepo.RevertReview(ctx, history); err != nil {
+		return nil, fmt.Errorf("failed to revert review: %w", err)
+	}
+
+	word, err := uc.vocabularyRepo.FindByID(ctx, history.WordID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word: %w", err)
+	}
+
+	return word, nil
+}
+
 // GetOrCreateProgress gets existing progress or creates new progress for a user-word pair
 func (uc *LearningUseCase) GetOrCreateProgress(
 	ctx context.Context,
@@ -366,6 +1091,7 @@ func (uc *LearningUseCase) GetOrCreateProgress(
 	// If no progress exists, create new one
 	if progress == nil {
 		progress = learning.NewUserProgress(userID, wordID)
+		uc.seedCommunityDifficulty(ctx, userID, progress)
 		err = uc.learningRepo.SaveProgress(ctx, progress)
 		if err != nil {
 			return nil, fmt.Errorf("failed to save new progress: %w", err)
@@ -375,18 +1101,319 @@ func (uc *LearningUseCase) GetOrCreateProgress(
 	return progress, nil
 }
 
+// GetNextWordToAssess retrieves a word the user has not studied yet for self-assessment
+func (uc *LearningUseCase) GetNextWordToAssess(ctx context.Context, userID user.ID) (*vocabulary.Word, error) {
+	newProgress, err := uc.learningRepo.FindNewWords(ctx, userID, uc.getNewWordSelection(ctx, userID), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new words: %w", err)
+	}
+
+	if len(newProgress) == 0 {
+		return nil, nil // Every word already has progress
+	}
+
+	word, err := uc.vocabularyRepo.FindByID(ctx, newProgress[0].WordID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word: %w", err)
+	}
+
+	return word, nil
+}
+
+// AssessWord records whether the user already knows a word.
+// Known words are seeded into review state; unknown words start as new.
+func (uc *LearningUseCase) AssessWord(ctx context.Context, userID user.ID, wordID vocabulary.ID, known bool) error {
+	progress, err := uc.GetOrCreateProgress(ctx, userID, wordID)
+	if err != nil {
+		return err
+	}
+
+	if !known {
+		return nil
+	}
+
+	progress.SeedAsKnown()
+	err = uc.learningRepo.UpdateProgress(ctx, progress)
+	if err != nil {
+		return fmt.Errorf("failed to seed progress: %w", err)
+	}
+
+	return nil
+}
+
+// IsWordLowPriority checks if the user muted reminders for a word
+func (uc *LearningUseCase) IsWordLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID) (bool, error) {
+	lowPriority, err := uc.learningRepo.IsLowPriority(ctx, userID, wordID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check low priority: %w", err)
+	}
+	return lowPriority, nil
+}
+
+// SetWordLowPriority mutes or unmutes reminders for a word without removing it from study
+func (uc *LearningUseCase) SetWordLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID, lowPriority bool) error {
+	if err := uc.learningRepo.SetLowPriority(ctx, userID, wordID, lowPriority); err != nil {
+		return fmt.Errorf("failed to set low priority: %w", err)
+	}
+	return nil
+}
+
+// MergeWords merges a duplicate word into another, keeping each user's stronger progress
+func (uc *LearningUseCase) MergeWords(ctx context.Context, winnerID, loserID vocabulary.ID) error {
+	if err := uc.vocabularyRepo.MergeWords(ctx, winnerID, loserID); err != nil {
+		return fmt.Errorf("failed to merge words: %w", err)
+	}
+	return nil
+}
+
+// ReportWord records a user's report that a word is wrong.
+// Once enough distinct users report the same word it is archived and true is returned so admins can review it.
+func (uc *LearningUseCase) ReportWord(ctx context.Context, userID user.ID, wordID vocabulary.ID) (bool, error) {
+	reporters, err := uc.learningRepo.ReportWord(ctx, userID, wordID)
+	if err != nil {
+		return false, fmt.Errorf("failed to report word: %w", err)
+	}
+
+	threshold := uc.config.ReportArchiveThreshold
+	if threshold <= 0 || reporters < threshold {
+		return false, nil
+	}
+
+	if err := uc.vocabularyRepo.ArchiveWord(ctx, wordID); err != nil {
+		return false, fmt.Errorf("failed to archive reported word: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetCardDetails resolves a term to a word and returns the user's progress on it.
+// The word is nil when the term is unknown, and the progress is nil when the user has not studied it yet.
+func (uc *LearningUseCase) GetCardDetails(ctx context.Context, userID user.ID, term string) (*vocabulary.Word, *learning.UserProgress, error) {
+	word, err := uc.vocabularyRepo.FindByTerm(ctx, term)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find word: %w", err)
+	}
+	if word == nil {
+		return nil, nil, nil
+	}
+
+	progress, err := uc.learningRepo.FindProgress(ctx, userID, word.ID())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find progress: %w", err)
+	}
+
+	return word, progress, nil
+}
+
+// ExplainSchedule resolves a term to a word and explains, from its card and current recall odds,
+// why the word is due when it is. The word is nil when the term is unknown, and the explanation
+// is empty when the user has not studied it yet.
+func (uc *LearningUseCase) ExplainSchedule(ctx context.Context, userID user.ID, term string) (*vocabulary.Word, string, error) {
+	word, progress, err := uc.GetCardDetails(ctx, userID, term)
+	if err != nil || progress == nil {
+		return word, "", err
+	}
+
+	return word, learning.ExplainSchedule(progress.FSRSCard(), uc.getUserNow(ctx, userID)), nil
+}
+
+// SetWordDifficulty manually overrides the FSRS difficulty of a studied word.
+// It returns the word, or nil if the term is unknown or the user hasn't studied it yet.
+func (uc *LearningUseCase) SetWordDifficulty(ctx context.Context, userID user.ID, term string, difficulty float64) (*vocabulary.Word, error) {
+	if !(difficulty >= learning.MinDifficulty && difficulty <= learning.MaxDifficulty) {
+		return nil, fmt.Errorf("difficulty must be between %.0f and %.0f", learning.MinDifficulty, learning.MaxDifficulty)
+	}
+
+	word, progress, err := uc.GetCardDetails(ctx, userID, term)
+	if err != nil {
+		return nil, err
+	}
+	if word == nil || progress == nil {
+		return nil, nil
+	}
+
+	progress.FSRSCard().SetDifficulty(difficulty)
+	if err := uc.learningRepo.UpdateProgress(ctx, progress); err != nil {
+		return nil, fmt.Errorf("failed to update progress: %w", err)
+	}
+
+	return word, nil
+}
+
+// SnoozeWord pushes a single word's next review back without changing its FSRS stability
+func (uc *LearningUseCase) SnoozeWord(ctx context.Context, userID user.ID, wordID vocabulary.ID, duration time.Duration) error {
+	progress, err := uc.learningRepo.FindProgress(ctx, userID, wordID)
+	if err != nil {
+		return fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	// A word that has never been studied gets a progress record so it stays out of new-word picks too
+	if progress == nil {
+		progress = learning.NewUserProgress(userID, wordID)
+		progress.Postpone(duration)
+		if err := uc.learningRepo.SaveProgress(ctx, progress); err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+		return nil
+	}
+
+	progress.Postpone(duration)
+	if err := uc.learningRepo.UpdateProgress(ctx, progress); err != nil {
+		return fmt.Errorf("failed to update progress: %w", err)
+	}
+
+	return nil
+}
+
+// RecalculateSchedule recomputes the due date of each of the user's review cards from its
+// stability and last review under the current scheduling parameters, and returns how many moved
+func (uc *LearningUseCase) RecalculateSchedule(ctx context.Context, userID user.ID) (int, error) {
+	allProgress, err := uc.learningRepo.FindProgressByUser(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user progress: %w", err)
+	}
+
+	params := uc.getFSRSParams(ctx, userID)
+	var changed []*learning.UserProgress
+	for _, progress := range allProgress {
+		progress.FSRSCard().SetParams(params)
+		if progress.Reschedule() {
+			changed = append(changed, progress)
+		}
+	}
+
+	if err := uc.learningRepo.SaveProgressBatch(ctx, changed); err != nil {
+		return 0, fmt.Errorf("failed to save progress: %w", err)
+	}
+
+	return len(changed), nil
+}
+
+// ResetProgress deletes everything the user has learned, including their review history,
+// so they can start again from a clean slate. Settings, tags and session logs are kept.
+func (uc *LearningUseCase) ResetProgress(ctx context.Context, userID user.ID) error {
+	if err := uc.learningRepo.DeleteAllProgress(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete progress: %w", err)
+	}
+	return nil
+}
+
+// GetWord retrieves a word by its ID, returning nil if it doesn't exist
+func (uc *LearningUseCase) GetWord(ctx context.Context, wordID vocabulary.ID) (*vocabulary.Word, error) {
+	word, err := uc.vocabularyRepo.FindByID(ctx, wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word: %w", err)
+	}
+	return word, nil
+}
+
+// GetWeeklyLeaderboard returns the users with the most reviews in the last week, best first
+func (uc *LearningUseCase) GetWeeklyLeaderboard(ctx context.Context, limit int) ([]*learning.LeaderboardEntry, error) {
+	entries, err := uc.learningRepo.GetWeeklyLeaderboard(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+	return entries, nil
+}
+
 // GetUserStats retrieves learning statistics for a user
 func (uc *LearningUseCase) GetUserStats(ctx context.Context, userID user.ID) (*learning.UserStats, error) {
-	stats, err := uc.learningRepo.GetUserStats(ctx, userID)
+	stats, err := uc.learningRepo.GetUserStats(ctx, userID, uc.getReviewAheadWindow(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
 
+	stats.DifficultyTrend = uc.getDifficultyTrend(ctx, userID)
+
+	stats.CurrentStreak, stats.LongestStreak, err = uc.learningRepo.GetStreak(ctx, userID, uc.getUserNow(ctx, userID))
+	if err != nil {
+		log.Printf("Failed to get study streak for user %d: %v", userID, err)
+	}
+
+	stats.RetentionRate, err = uc.GetRetentionRate(ctx, userID, learning.RetentionWindow)
+	if err != nil {
+		log.Printf("Failed to get retention rate for user %d: %v", userID, err)
+		stats.RetentionRate = learning.NoRetentionRate
+	}
+
+	return stats, nil
+}
+
+// GetRetentionRate computes the fraction of the user's reviews within the window that were rated Good
+// or Easy, leaving out words still in learning. It returns learning.NoRetentionRate if there were none.
+func (uc *LearningUseCase) GetRetentionRate(ctx context.Context, userID user.ID, window time.Duration) (float64, error) {
+	return uc.learningRepo.GetRetentionRate(ctx, userID, window)
+}
+
+// GetReviewForecast counts the user's words coming due on each of the next days, starting today
+// in the user's time zone
+func (uc *LearningUseCase) GetReviewForecast(ctx context.Context, userID user.ID, days int) ([]learning.ForecastDay, error) {
+	forecast, err := uc.learningRepo.GetReviewForecast(ctx, userID, uc.getUserNow(ctx, userID), days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review forecast: %w", err)
+	}
+	return forecast, nil
+}
+
+// GetUserStatsByCategory retrieves statistics for each category the user has studied words in
+func (uc *LearningUseCase) GetUserStatsByCategory(ctx context.Context, userID user.ID) (map[vocabulary.Category]*learning.CategoryStats, error) {
+	stats, err := uc.learningRepo.GetUserStatsByCategory(ctx, userID, uc.getReviewAheadWindow(ctx, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category stats: %w", err)
+	}
 	return stats, nil
 }
 
-// CheckAnswer checks if the user's answer is correct
-func (uc *LearningUseCase) CheckAnswer(session *LearningSession, userAnswer string) bool {
+// difficultyTrendWindowDays is the length of each window compared for the difficulty trend
+const difficultyTrendWindowDays = 7
+
+// getDifficultyTrend computes the trend of the trailing week of difficulty snapshots against the week before it.
+// Snapshots are recorded as the user reviews, so days without reviews keep no snapshot. Snapshot days
+// are the user's calendar dates, which the repository returns as midnight UTC.
+func (uc *LearningUseCase) getDifficultyTrend(ctx context.Context, userID user.ID) learning.Trend {
+	now := uc.getUserNow(ctx, userID)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	windowStart := today.AddDate(0, 0, -(difficultyTrendWindowDays - 1))
+	since := windowStart.AddDate(0, 0, -difficultyTrendWindowDays)
+
+	snapshots, err := uc.learningRepo.FindDifficultySnapshots(ctx, userID, since)
+	if err != nil {
+		log.Printf("Failed to get difficulty snapshots for user %d: %v", userID, err)
+		return learning.TrendUnknown
+	}
+
+	return learning.DifficultyTrend(snapshots, windowStart)
+}
+
+// getUserNow returns the current time in the user's time zone, so days start at the user's midnight
+func (uc *LearningUseCase) getUserNow(ctx context.Context, userID user.ID) time.Time {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return time.Now().UTC()
+	}
+	return time.Now().In(preferences.Location())
+}
+
+// getReviewAheadWindow returns the user's review-ahead window, or zero if preferences are unavailable
+func (uc *LearningUseCase) getReviewAheadWindow(ctx context.Context, userID user.ID) time.Duration {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return 0
+	}
+	return preferences.ReviewAheadWindow()
+}
+
+// CheckAnswer checks if the user's answer is correct, giving any partial credit
+func (uc *LearningUseCase) CheckAnswer(ctx context.Context, session *LearningSession, userAnswer string) bool {
+	return uc.GradeAnswer(ctx, session, userAnswer) > learning.ScoreWrong
+}
+
+// GradeAnswer scores the user's answer: an exact match earns full credit, and a Dutch answer
+// that only matches once a leading article is ignored earns partial credit when the user has
+// opted into article-insensitive matching. Accents and diacritics are ignored unless the user
+// asked for strict accents, and English answers may use British or American spelling unless the
+// user turned that off.
+func (uc *LearningUseCase) GradeAnswer(ctx context.Context, session *LearningSession, userAnswer string) float64 {
 	var correctAnswer string
 
 	switch session.QuestionType {
@@ -396,14 +1423,83 @@ func (uc *LearningUseCase) CheckAnswer(session *LearningSession, userAnswer stri
 		correctAnswer = session.Word.English()
 	}
 
+	strict := uc.strictAccents(ctx, session.UserID)
+	userAnswer = normalizeAnswer(userAnswer, strict)
+	correctAnswer = normalizeAnswer(correctAnswer, strict)
+
 	// Simple case-insensitive comparison
-	// Could be enhanced with fuzzy matching, accent handling, etc.
-	return normalizeAnswer(userAnswer) == normalizeAnswer(correctAnswer)
+	// Could be enhanced with fuzzy matching
+	if userAnswer == correctAnswer {
+		return learning.ScoreCorrect
+	}
+
+	if session.QuestionType == QuestionTypeDutchToEnglish && uc.acceptsSpellingVariants(ctx, session.UserID) &&
+		americanSpelling(userAnswer) == americanSpelling(correctAnswer) {
+		return learning.ScoreCorrect
+	}
+
+	if session.QuestionType == QuestionTypeEnglishToDutch && uc.ignoresArticles(ctx, session.UserID) &&
+		stripDutchArticle(userAnswer) == stripDutchArticle(correctAnswer) {
+		return learning.ScorePartial
+	}
+
+	return learning.ScoreWrong
+}
+
+// ignoresArticles reports whether the user wants article-insensitive matching, defaulting to strict
+func (uc *LearningUseCase) ignoresArticles(ctx context.Context, userID user.ID) bool {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultIgnoreArticles
+	}
+	return preferences.IgnoreArticles()
+}
+
+// strictAccents reports whether the user wants answers to match accents exactly, defaulting to lenient
+func (uc *LearningUseCase) strictAccents(ctx context.Context, userID user.ID) bool {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultStrictAccents
+	}
+	return preferences.StrictAccents()
+}
+
+// acceptsSpellingVariants reports whether British and American spellings count as the same answer, defaulting to yes
+func (uc *LearningUseCase) acceptsSpellingVariants(ctx context.Context, userID user.ID) bool {
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return user.DefaultSpellingVariants
+	}
+	return preferences.SpellingVariants()
 }
 
-// normalizeAnswer normalizes an answer for comparison
-func normalizeAnswer(answer string) string {
+// dutchArticles are the leading articles dropped by article-insensitive matching
+var dutchArticles = []string{"de ", "het ", "een ", "'t "}
+
+// stripDutchArticle removes a single leading Dutch article from a normalized answer
+func stripDutchArticle(answer string) string {
+	for _, article := range dutchArticles {
+		if rest := strings.TrimPrefix(answer, article); rest != answer {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return answer
+}
+
+// normalizeAnswer normalizes an answer for comparison. Compatibility forms such as the "ĳ"
+// ligature are always unfolded; accents and diacritics are dropped unless strict is set,
+// so "één" and "een" compare equal.
+func normalizeAnswer(answer string, strict bool) string {
 	// Convert to lowercase and trim whitespace
-	// Could be enhanced with more sophisticated normalization
-	return strings.ToLower(strings.TrimSpace(answer))
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if strict {
+		t = norm.NFKC
+	}
+	normalized, _, err := transform.String(t, answer)
+	if err != nil {
+		return answer
+	}
+	return normalized
 }
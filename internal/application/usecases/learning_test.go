@@ -0,0 +1,1724 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/grammar"
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+// learningFixture is a learning use case backed by a fresh database, with one user
+type learningFixture struct {
+	db           *sql.DB
+	uc           *LearningUseCase
+	learningRepo learning.Repository
+	vocabRepo    vocabulary.Repository
+	prefsRepo    user.PreferencesRepository
+	userID       user.ID
+}
+
+func newLearningFixture(t *testing.T, config *LearningConfig) *learningFixture {
+	t.Helper()
+
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	userRepo := persistence.NewUserRepository(db)
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	if err := userRepo.Save(context.Background(), u); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	f := &learningFixture{
+		db:           db,
+		learningRepo: persistence.NewLearningRepository(db),
+		vocabRepo:    persistence.NewVocabularyRepository(db),
+		prefsRepo:    persistence.NewUserPreferencesRepository(db),
+		userID:       u.ID(),
+	}
+	f.uc = NewLearningUseCase(f.learningRepo, f.vocabRepo, userRepo, persistence.NewGrammarRepository(db), f.prefsRepo, config)
+	return f
+}
+
+// addWord saves a word to the vocabulary
+func (f *learningFixture) addWord(t *testing.T, english, dutch, category string) *vocabulary.Word {
+	t.Helper()
+
+	word := vocabulary.NewWord(english, dutch, vocabulary.Category(category))
+	if err := f.vocabRepo.Save(context.Background(), word); err != nil {
+		t.Fatalf("failed to save word: %v", err)
+	}
+	return word
+}
+
+// addReviewCard gives the user a review card for the word, due at dueDate
+func (f *learningFixture) addReviewCard(t *testing.T, word *vocabulary.Word, dueDate time.Time) *learning.UserProgress {
+	t.Helper()
+
+	progress := learning.NewUserProgress(f.userID, word.ID())
+	card := progress.FSRSCard()
+	card.SetState(learning.StateReview)
+	card.SetStability(5)
+	card.SetReviewCount(1)
+	card.SetLastReview(dueDate.Add(-5 * 24 * time.Hour))
+	card.SetDueDate(dueDate)
+	if err := f.learningRepo.SaveProgress(context.Background(), progress); err != nil {
+		t.Fatalf("failed to save progress: %v", err)
+	}
+	return progress
+}
+
+// updatePreferences loads the user's preferences, applies update and saves them
+func (f *learningFixture) updatePreferences(t *testing.T, update func(*user.UserPreferences)) {
+	t.Helper()
+
+	prefs, err := f.prefsRepo.FindPreferences(context.Background(), f.userID)
+	if err != nil {
+		t.Fatalf("failed to load preferences: %v", err)
+	}
+	update(prefs)
+	if err := f.prefsRepo.SavePreferences(context.Background(), prefs); err != nil {
+		t.Fatalf("failed to save preferences: %v", err)
+	}
+}
+
+// progress loads the user's progress on the word
+func (f *learningFixture) progress(t *testing.T, word *vocabulary.Word) *learning.UserProgress {
+	t.Helper()
+
+	progress, err := f.learningRepo.FindProgress(context.Background(), f.userID, word.ID())
+	if err != nil {
+		t.Fatalf("failed to load progress: %v", err)
+	}
+	return progress
+}
+
+func TestSetWordDifficulty_RejectsOutOfRange(t *testing.T) {
+	// The difficulty is validated before any repository is touched
+	uc := NewLearningUseCase(nil, nil, nil, nil, nil, nil)
+
+	for _, difficulty := range []float64{0, 10.5, -1, math.NaN(), math.Inf(1)} {
+		if _, err := uc.SetWordDifficulty(context.Background(), 1, "huis", difficulty); err == nil {
+			t.Errorf("SetWordDifficulty(%v) succeeded, want an error", difficulty)
+		}
+	}
+}
+
+func TestAssessWord(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	known := f.addWord(t, "house", "huis", "basics")
+	unknown := f.addWord(t, "tree", "boom", "basics")
+	start := time.Now()
+
+	if err := f.uc.AssessWord(context.Background(), f.userID, known.ID(), true); err != nil {
+		t.Fatalf("AssessWord(known): %v", err)
+	}
+	if err := f.uc.AssessWord(context.Background(), f.userID, unknown.ID(), false); err != nil {
+		t.Fatalf("AssessWord(unknown): %v", err)
+	}
+
+	card := f.progress(t, known).FSRSCard()
+	if card.State() != learning.StateReview {
+		t.Errorf("known word state = %q, want review", card.State())
+	}
+	if !card.DueDate().After(start.Add(24 * time.Hour)) {
+		t.Errorf("known word due %v, want at least a day in the future", card.DueDate())
+	}
+
+	if state := f.progress(t, unknown).FSRSCard().State(); state != learning.StateNew {
+		t.Errorf("unknown word state = %q, want new", state)
+	}
+}
+
+func TestStartTimeout_AnsweredFirst(t *testing.T) {
+	session := &LearningSession{}
+	var timeouts int32
+	session.StartTimeout(20*time.Millisecond, func() { atomic.AddInt32(&timeouts, 1) })
+
+	if !session.ClaimAnswer() {
+		t.Fatal("ClaimAnswer() = false, want the answer to claim the question")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&timeouts); n != 0 {
+		t.Errorf("onTimeout ran %d times after the question was answered, want 0", n)
+	}
+}
+
+func TestStartTimeout_TimedOutFirst(t *testing.T) {
+	session := &LearningSession{}
+	fired := make(chan struct{}, 2)
+	session.StartTimeout(time.Millisecond, func() { fired <- struct{}{} })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("onTimeout did not run")
+	}
+
+	if session.ClaimAnswer() {
+		t.Error("ClaimAnswer() = true after the timeout, want the late answer rejected")
+	}
+	if !session.Answered() {
+		t.Error("Answered() = false after the timeout")
+	}
+	select {
+	case <-fired:
+		t.Error("onTimeout ran twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestClaimAnswer_Race(t *testing.T) {
+	// However the answer and the timer interleave, exactly one of them claims the question
+	for i := 0; i < 50; i++ {
+		session := &LearningSession{}
+		var claims int32
+		done := make(chan struct{})
+		session.StartTimeout(0, func() {
+			atomic.AddInt32(&claims, 1)
+			close(done)
+		})
+		if session.ClaimAnswer() {
+			atomic.AddInt32(&claims, 1)
+		} else {
+			<-done
+		}
+		time.Sleep(time.Millisecond)
+
+		if n := atomic.LoadInt32(&claims); n != 1 {
+			t.Fatalf("question claimed %d times, want exactly once", n)
+		}
+	}
+}
+
+func TestRecordAnswer_ScoreboardAcrossQuestions(t *testing.T) {
+	answers := []struct {
+		correct       bool
+		wantCorrect   int
+		wantIncorrect int
+	}{
+		{true, 1, 0},
+		{true, 2, 0},
+		{false, 2, 1},
+		{true, 3, 1},
+		{true, 4, 1},
+	}
+
+	var previous *LearningSession
+	for i, answer := range answers {
+		// Each question gets a fresh session that continues the previous one
+		session := &LearningSession{Progress: learning.NewUserProgress(1, vocabulary.ID(i+1))}
+		if previous != nil {
+			session.ContinueFrom(previous)
+		}
+		session.RecordAnswer(answer.correct)
+
+		if session.CorrectCount != answer.wantCorrect || session.IncorrectCount != answer.wantIncorrect {
+			t.Errorf("after answer %d: %d correct / %d wrong, want %d / %d", i+1,
+				session.CorrectCount, session.IncorrectCount, answer.wantCorrect, answer.wantIncorrect)
+		}
+		previous = session
+	}
+
+	if got := previous.AnswerCount(); got != len(answers) {
+		t.Errorf("AnswerCount() = %d, want %d", got, len(answers))
+	}
+	if got := previous.NewCount; got != len(answers) {
+		t.Errorf("NewCount = %d, want %d", got, len(answers))
+	}
+	if got := previous.Accuracy(); got != 80 {
+		t.Errorf("Accuracy() = %v, want 80", got)
+	}
+}
+
+func TestContinueFrom_ElapsedCrossesTimeCap(t *testing.T) {
+	prefs := user.NewUserPreferences(1)
+	prefs.SetMaxSessionMinutes(10)
+	limit := prefs.MaxSessionDuration()
+
+	first := &LearningSession{SessionStart: time.Now().Add(-9 * time.Minute)}
+	if first.Elapsed() >= limit {
+		t.Fatalf("elapsed %v already past the %v cap", first.Elapsed(), limit)
+	}
+
+	// Later questions keep the original start, so time spent keeps counting toward the cap
+	next := &LearningSession{SessionStart: time.Now()}
+	next.ContinueFrom(first)
+	next.SessionStart = next.SessionStart.Add(-2 * time.Minute)
+	if next.Elapsed() < limit {
+		t.Errorf("elapsed %v should be past the %v cap", next.Elapsed(), limit)
+	}
+}
+
+func TestSessionWithoutGrammarTips(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	withoutRepo := NewLearningUseCase(f.learningRepo, f.vocabRepo, persistence.NewUserRepository(f.db), nil, f.prefsRepo, nil)
+	word := f.addWord(t, "house", "huis", "basics")
+	f.addWord(t, "tree", "boom", "basics")
+	f.addWord(t, "cat", "kat", "basics")
+	f.addWord(t, "dog", "hond", "basics")
+	f.addReviewCard(t, word, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name string
+		uc   *LearningUseCase
+	}{
+		{"empty grammar table", f.uc},
+		{"no grammar repository", withoutRepo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := tt.uc.GetNextDueWord(context.Background(), f.userID, false)
+			if err != nil {
+				t.Fatalf("GetNextDueWord: %v", err)
+			}
+			if session == nil || session.Word.ID() != word.ID() {
+				t.Fatalf("GetNextDueWord = %+v, want a session for the due word", session)
+			}
+
+			tip, err := tt.uc.GetContextualGrammarTip(context.Background(), session.Word, f.userID)
+			if err != nil || tip != nil {
+				t.Errorf("GetContextualGrammarTip = %v, %v, want no tip and no error", tip, err)
+			}
+		})
+	}
+}
+
+func TestGradeAnswer_Articles(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	withArticle := f.addWord(t, "the man", "de man", "people")
+	bare := f.addWord(t, "woman", "vrouw", "people")
+
+	tests := []struct {
+		name           string
+		word           *vocabulary.Word
+		answer         string
+		ignoreArticles bool
+		want           float64
+	}{
+		{"article dropped, lenient", withArticle, "man", true, learning.ScorePartial},
+		{"article dropped, strict", withArticle, "man", false, learning.ScoreWrong},
+		{"article added, lenient", bare, "de vrouw", true, learning.ScorePartial},
+		{"article added, strict", bare, "de vrouw", false, learning.ScoreWrong},
+		{"wrong article, lenient", withArticle, "het man", true, learning.ScorePartial},
+		{"exact, strict", withArticle, "De Man", false, learning.ScoreCorrect},
+		{"different word, lenient", withArticle, "de vrouw", true, learning.ScoreWrong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.updatePreferences(t, func(p *user.UserPreferences) { p.SetIgnoreArticles(tt.ignoreArticles) })
+			session := &LearningSession{UserID: f.userID, Word: tt.word, QuestionType: QuestionTypeEnglishToDutch}
+
+			if got := f.uc.GradeAnswer(context.Background(), session, tt.answer); got != tt.want {
+				t.Errorf("GradeAnswer(%q) = %v, want %v", tt.answer, got, tt.want)
+			}
+			if got, want := f.uc.CheckAnswer(context.Background(), session, tt.answer), tt.want > learning.ScoreWrong; got != want {
+				t.Errorf("CheckAnswer(%q) = %v, want %v", tt.answer, got, want)
+			}
+		})
+	}
+}
+
+// testProgress builds saved progress in the given state, last reviewed at lastReview
+func testProgress(id learning.ID, state learning.State, lastReview time.Time) *learning.UserProgress {
+	progress := learning.NewUserProgress(1, vocabulary.ID(id))
+	progress.SetID(id)
+	progress.FSRSCard().SetState(state)
+	progress.FSRSCard().SetLastReview(lastReview)
+	return progress
+}
+
+func TestSelectBestWordForLearning(t *testing.T) {
+	uc := NewLearningUseCase(nil, nil, nil, nil, nil, nil)
+	twoDaysAgo := time.Now().Add(-48 * time.Hour)
+
+	recent := testProgress(1, learning.StateReview, time.Now())
+	due := testProgress(2, learning.StateReview, twoDaysAgo)
+	relearning := testProgress(3, learning.StateRelearning, twoDaysAgo)
+	newWord := learning.NewUserProgress(1, 4)
+	mixed := []*learning.UserProgress{recent, due, relearning, newWord}
+
+	tests := []struct {
+		name     string
+		words    []*learning.UserProgress
+		priority user.StudyPriority
+		want     *learning.UserProgress
+	}{
+		{"balanced serves the due review", mixed, user.StudyPriorityBalanced, due},
+		{"learning first serves the relearning card", mixed, user.StudyPriorityLearningFirst, relearning},
+		{"learning first without learning cards", []*learning.UserProgress{recent, due, newWord}, user.StudyPriorityLearningFirst, due},
+		{"new before recently reviewed", []*learning.UserProgress{recent, newWord}, user.StudyPriorityBalanced, newWord},
+		{"only recently reviewed", []*learning.UserProgress{recent}, user.StudyPriorityBalanced, recent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uc.selectBestWordForLearning(tt.words, tt.priority); got != tt.want {
+				t.Errorf("selected word %d, want word %d", got.WordID(), tt.want.WordID())
+			}
+		})
+	}
+}
+
+func TestReportWord_ArchivesAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	word := f.addWord(t, "house", "huis", "basics")
+
+	userRepo := persistence.NewUserRepository(f.db)
+	reporters := []user.ID{f.userID}
+	for _, telegramID := range []user.TelegramID{43, 44} {
+		u := user.NewUser(telegramID, "", "Tester", "", "en")
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+		reporters = append(reporters, u.ID())
+	}
+
+	// The default threshold is three distinct users; a repeated report doesn't count twice
+	reports := []struct {
+		userID       user.ID
+		wantArchived bool
+	}{
+		{reporters[0], false},
+		{reporters[0], false},
+		{reporters[1], false},
+		{reporters[2], true},
+	}
+	for i, report := range reports {
+		archived, err := f.uc.ReportWord(ctx, report.userID, word.ID())
+		if err != nil {
+			t.Fatalf("report %d: %v", i+1, err)
+		}
+		if archived != report.wantArchived {
+			t.Errorf("report %d archived = %v, want %v", i+1, archived, report.wantArchived)
+		}
+	}
+
+	var isArchived bool
+	if err := f.db.QueryRow(`SELECT archived FROM words WHERE id = ?`, int64(word.ID())).Scan(&isArchived); err != nil {
+		t.Fatalf("failed to read word: %v", err)
+	}
+	if !isArchived {
+		t.Error("word not archived after reaching the report threshold")
+	}
+}
+
+func TestGetNextDueWord_OnlyRecentlyReviewed(t *testing.T) {
+	tests := []struct {
+		name          string
+		breakOnRecent bool
+		withNewWord   bool
+		wantBreak     bool
+	}{
+		{"break when only recent words remain", true, false, true},
+		{"repeat when breaks are off", false, false, false},
+		{"new word instead of a break", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultLearningConfig()
+			config.BreakOnRecentOnly = tt.breakOnRecent
+			config.RecentReviewWindow = 30 * time.Minute
+			f := newLearningFixture(t, config)
+			// Distractors for the answer options, studied and not due
+			for _, pair := range [][2]string{{"cat", "kat"}, {"dog", "hond"}, {"car", "auto"}} {
+				f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+			}
+
+			// Due again already, but reviewed only five minutes ago
+			recent := f.addWord(t, "house", "huis", "basics")
+			progress := f.addReviewCard(t, recent, time.Now().Add(-time.Minute))
+			progress.FSRSCard().SetLastReview(time.Now().Add(-5 * time.Minute))
+			if err := f.learningRepo.UpdateProgress(context.Background(), progress); err != nil {
+				t.Fatalf("failed to update progress: %v", err)
+			}
+			if tt.withNewWord {
+				f.addWord(t, "tree", "boom", "basics")
+			}
+
+			session, err := f.uc.GetNextDueWord(context.Background(), f.userID, false)
+			if tt.wantBreak {
+				if !errors.Is(err, ErrTakeBreak) {
+					t.Errorf("GetNextDueWord error = %v, want ErrTakeBreak", err)
+				}
+				return
+			}
+			if err != nil || session == nil {
+				t.Fatalf("GetNextDueWord = %v, %v, want a question", session, err)
+			}
+			if tt.withNewWord && session.Word.ID() == recent.ID() {
+				t.Error("served the recently reviewed word over a new one")
+			}
+		})
+	}
+}
+
+func TestSetWordDifficulty_PersistsAndAffectsInterval(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	f.updatePreferences(t, func(p *user.UserPreferences) { p.SetIntervalFuzz(false) })
+
+	// Two words with identical review cards, then marked hard and easy
+	hard := f.addWord(t, "house", "huis", "basics")
+	easy := f.addWord(t, "tree", "boom", "basics")
+	dueDate := time.Now().Add(-time.Hour)
+	f.addReviewCard(t, hard, dueDate)
+	f.addReviewCard(t, easy, dueDate)
+	for term, difficulty := range map[string]float64{"huis": 9, "boom": 2} {
+		if _, err := f.uc.SetWordDifficulty(ctx, f.userID, term, difficulty); err != nil {
+			t.Fatalf("SetWordDifficulty(%q): %v", term, err)
+		}
+	}
+
+	if got := f.progress(t, hard).FSRSCard().Difficulty(); got != 9 {
+		t.Fatalf("stored difficulty = %v, want 9", got)
+	}
+
+	// The same Good review schedules the harder word sooner
+	nextDue := make(map[vocabulary.ID]time.Time)
+	for _, word := range []*vocabulary.Word{hard, easy} {
+		session := &LearningSession{UserID: f.userID, Word: word, Progress: f.progress(t, word), AnswerCorrect: true}
+		if err := f.uc.ProcessReview(ctx, session, learning.Good, 2*time.Second); err != nil {
+			t.Fatalf("ProcessReview: %v", err)
+		}
+		nextDue[word.ID()] = f.progress(t, word).FSRSCard().DueDate()
+	}
+	if !nextDue[hard.ID()].Before(nextDue[easy.ID()]) {
+		t.Errorf("hard word due %v, want before the easy word's %v", nextDue[hard.ID()], nextDue[easy.ID()])
+	}
+}
+
+func TestTagWord(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	f.addWord(t, "house", "huis", "basics")
+	f.addWord(t, "car", "auto", "transport")
+
+	for _, tagging := range []struct{ term, tag string }{
+		{"huis", "exam"},
+		{"huis", "exam"}, // Tagging twice is harmless
+		{"car", "travel"},
+		{"huis", "travel"},
+	} {
+		word, err := f.uc.TagWord(ctx, f.userID, tagging.term, tagging.tag)
+		if err != nil || word == nil {
+			t.Fatalf("TagWord(%q, %q) = %v, %v", tagging.term, tagging.tag, word, err)
+		}
+	}
+	if word, err := f.uc.TagWord(ctx, f.userID, "fiets", "exam"); err != nil || word != nil {
+		t.Errorf("TagWord(unknown) = %v, %v, want no word and no error", word, err)
+	}
+
+	tags, err := f.uc.GetUserTags(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("GetUserTags: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "exam" || tags[1] != "travel" {
+		t.Errorf("GetUserTags = %v, want [exam travel]", tags)
+	}
+}
+
+func TestGetNextTaggedWord(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	overdue := time.Now().Add(-time.Hour)
+
+	tagged := f.addWord(t, "house", "huis", "basics")
+	f.addReviewCard(t, tagged, overdue)
+	untagged := f.addWord(t, "tree", "boom", "basics")
+	f.addReviewCard(t, untagged, overdue.Add(-time.Hour)) // More overdue, but not tagged
+	newTagged := f.addWord(t, "car", "auto", "transport")
+	for _, pair := range [][2]string{{"cat", "kat"}, {"dog", "hond"}} {
+		f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+	}
+	for term, tag := range map[string]string{"huis": "exam", "auto": "travel"} {
+		if _, err := f.uc.TagWord(ctx, f.userID, term, tag); err != nil {
+			t.Fatalf("TagWord: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag  string
+		want *vocabulary.Word
+	}{
+		{"exam", tagged},
+		{"travel", newTagged},
+		{"unused", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			session, err := f.uc.GetNextTaggedWord(ctx, f.userID, tt.tag, false)
+			if err != nil {
+				t.Fatalf("GetNextTaggedWord: %v", err)
+			}
+			if tt.want == nil {
+				if session != nil {
+					t.Errorf("served %q for a tag without words", session.Word.Dutch())
+				}
+				return
+			}
+			if session == nil || session.Word.ID() != tt.want.ID() {
+				t.Fatalf("GetNextTaggedWord = %+v, want %q", session, tt.want.Dutch())
+			}
+			if session.Tag != tt.tag {
+				t.Errorf("session tag = %q, want %q", session.Tag, tt.tag)
+			}
+		})
+	}
+}
+
+func TestLogSession(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	start := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+
+	// A session nobody answered anything in isn't logged
+	if err := f.uc.LogSession(ctx, &LearningSession{UserID: f.userID, SessionStart: start}); err != nil {
+		t.Fatalf("LogSession(empty): %v", err)
+	}
+
+	session := &LearningSession{UserID: f.userID, SessionStart: start, CorrectCount: 3, IncorrectCount: 1, NewCount: 1}
+	if err := f.uc.LogSession(ctx, session); err != nil {
+		t.Fatalf("LogSession: %v", err)
+	}
+
+	logs, err := f.learningRepo.FindSessionLogs(ctx, f.userID, start.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("FindSessionLogs: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("%d sessions logged, want 1", len(logs))
+	}
+	got := logs[0]
+	if got.WordCount() != 4 || got.CorrectCount != 3 || got.IncorrectCount != 1 {
+		t.Errorf("logged %d words (%d correct, %d incorrect), want 4 (3, 1)", got.WordCount(), got.CorrectCount, got.IncorrectCount)
+	}
+	if got.NewWords != 1 || got.ReviewWords != 3 {
+		t.Errorf("logged %d new and %d review words, want 1 and 3", got.NewWords, got.ReviewWords)
+	}
+	if got.Accuracy() != 0.75 {
+		t.Errorf("Accuracy() = %v, want 0.75", got.Accuracy())
+	}
+	if !got.StartedAt.Equal(start) {
+		t.Errorf("StartedAt = %v, want %v", got.StartedAt, start)
+	}
+	if d := got.Duration(); d < 10*time.Minute || d > 11*time.Minute {
+		t.Errorf("Duration() = %v, want about 10 minutes", d)
+	}
+}
+
+func TestQuestionTypeLanguages(t *testing.T) {
+	tests := []struct {
+		direction  user.QuestionDirection
+		want       QuestionType
+		wantPrompt Language
+		wantAnswer Language
+	}{
+		{user.QuestionDirectionToDutch, QuestionTypeEnglishToDutch, LanguageEnglish, LanguageDutch},
+		{user.QuestionDirectionFromDutch, QuestionTypeDutchToEnglish, LanguageDutch, LanguageEnglish},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.direction), func(t *testing.T) {
+			questionType := questionTypeFor(tt.direction)
+			if questionType != tt.want {
+				t.Fatalf("questionTypeFor(%q) = %q, want %q", tt.direction, questionType, tt.want)
+			}
+			if got := questionType.PromptLanguage(); got != tt.wantPrompt {
+				t.Errorf("PromptLanguage() = %v, want %v", got, tt.wantPrompt)
+			}
+			if got := questionType.AnswerLanguage(); got != tt.wantAnswer {
+				t.Errorf("AnswerLanguage() = %v, want %v", got, tt.wantAnswer)
+			}
+		})
+	}
+
+	// A mixed direction picks either question type
+	if got := questionTypeFor(user.QuestionDirectionMixed); got != QuestionTypeEnglishToDutch && got != QuestionTypeDutchToEnglish {
+		t.Errorf("questionTypeFor(mixed) = %q", got)
+	}
+}
+
+func TestSnoozeWord(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	studied := f.addWord(t, "house", "huis", "basics")
+	f.addReviewCard(t, studied, time.Now().Add(-time.Hour))
+	unstudied := f.addWord(t, "tree", "boom", "basics")
+
+	before := time.Now()
+	for _, word := range []*vocabulary.Word{studied, unstudied} {
+		if err := f.uc.SnoozeWord(ctx, f.userID, word.ID(), 24*time.Hour); err != nil {
+			t.Fatalf("SnoozeWord(%q): %v", word.Dutch(), err)
+		}
+	}
+
+	card := f.progress(t, studied).FSRSCard()
+	if card.Stability() != 5 {
+		t.Errorf("stability = %v after snoozing, want it unchanged at 5", card.Stability())
+	}
+	if due := card.DueDate(); due.Before(before.Add(24*time.Hour-time.Second)) || due.After(time.Now().Add(24*time.Hour)) {
+		t.Errorf("due date = %v, want a day from now", due)
+	}
+	if f.progress(t, unstudied) == nil {
+		t.Error("snoozing an unstudied word saved no progress")
+	}
+
+	// The snoozed words stay out of due words until the snooze runs out
+	tests := []struct {
+		name        string
+		reviewAhead time.Duration
+		wantDue     int
+	}{
+		{"now", 0, 0},
+		{"after the snooze", 25 * time.Hour, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, err := f.learningRepo.FindDueWords(ctx, f.userID, tt.reviewAhead, 10)
+			if err != nil {
+				t.Fatalf("FindDueWords: %v", err)
+			}
+			if len(due) != tt.wantDue {
+				t.Errorf("%d due words, want %d", len(due), tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestGetNextDueWord_DailyMixQuotas(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	mix, err := user.ParseDailyMix("food:1,verbs:1")
+	if err != nil {
+		t.Fatalf("ParseDailyMix: %v", err)
+	}
+	f.updatePreferences(t, func(p *user.UserPreferences) { p.SetDailyMix(mix) })
+
+	// The basics word is the most overdue, but the plan comes first
+	for _, word := range []struct {
+		english, dutch, category string
+		overdue                  time.Duration
+	}{
+		{"bread", "brood", "food", time.Hour},
+		{"cheese", "kaas", "food", time.Hour},
+		{"to walk", "lopen", "verbs", time.Hour},
+		{"house", "huis", "basics", 48 * time.Hour},
+	} {
+		f.addReviewCard(t, f.addWord(t, word.english, word.dutch, word.category), time.Now().Add(-word.overdue))
+	}
+
+	var served []vocabulary.Category
+	for i := 0; i < 3; i++ {
+		session, err := f.uc.GetNextDueWord(ctx, f.userID, false)
+		if err != nil || session == nil {
+			t.Fatalf("question %d: GetNextDueWord = %v, %v", i+1, session, err)
+		}
+		served = append(served, session.Word.Category())
+		if err := f.uc.ProcessReview(ctx, session, learning.Good, 2*time.Second); err != nil {
+			t.Fatalf("ProcessReview: %v", err)
+		}
+	}
+
+	// One food word and one verb fill the plan, then the regular selection takes over
+	want := []vocabulary.Category{"food", "verbs", "basics"}
+	for i := range want {
+		if served[i] != want[i] {
+			t.Errorf("served categories %v, want %v", served, want)
+			break
+		}
+	}
+}
+
+func TestReshuffleNewWords_OnlyAffectsUnstudiedWords(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	userUC := NewUserUseCase(persistence.NewUserRepository(f.db), f.prefsRepo, &UserConfig{DefaultReminderInterval: user.DefaultReminderInterval})
+
+	var studied []*learning.UserProgress
+	unstudied := make(map[vocabulary.ID]bool)
+	for i, pair := range [][2]string{
+		{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"},
+		{"bird", "vogel"}, {"fish", "vis"}, {"horse", "paard"}, {"cow", "koe"},
+	} {
+		word := f.addWord(t, pair[0], pair[1], "basics")
+		if i%3 == 0 {
+			studied = append(studied, f.addReviewCard(t, word, time.Now().Add(time.Duration(i+1)*24*time.Hour)))
+		} else {
+			unstudied[word.ID()] = true
+		}
+	}
+
+	if err := userUC.ReshuffleNewWords(ctx, f.userID); err != nil {
+		t.Fatalf("ReshuffleNewWords: %v", err)
+	}
+	prefs, err := f.prefsRepo.FindPreferences(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("failed to load preferences: %v", err)
+	}
+	selection := prefs.GetNewWordSelection()
+	if selection.Order != user.NewWordOrderRandom || selection.Seed == 0 {
+		t.Fatalf("selection = %+v, want a seeded random order", selection)
+	}
+
+	var firstOrder []vocabulary.ID
+	for i := 0; i < 2; i++ {
+		words, err := f.learningRepo.FindNewWords(ctx, f.userID, selection, 20)
+		if err != nil {
+			t.Fatalf("FindNewWords: %v", err)
+		}
+		if len(words) != len(unstudied) {
+			t.Fatalf("FindNewWords returned %d words, want the %d unstudied words", len(words), len(unstudied))
+		}
+		for j, progress := range words {
+			if !unstudied[progress.WordID()] {
+				t.Errorf("word %d already has progress but was offered as new", progress.WordID())
+			}
+			if i == 0 {
+				firstOrder = append(firstOrder, progress.WordID())
+			} else if progress.WordID() != firstOrder[j] {
+				t.Errorf("position %d = word %d, want %d: a seeded order should be stable", j, progress.WordID(), firstOrder[j])
+			}
+		}
+	}
+
+	for _, before := range studied {
+		after, err := f.learningRepo.FindProgress(ctx, f.userID, before.WordID())
+		if err != nil {
+			t.Fatalf("failed to load progress: %v", err)
+		}
+		if !after.FSRSCard().DueDate().Equal(before.FSRSCard().DueDate()) ||
+			after.FSRSCard().Stability() != before.FSRSCard().Stability() ||
+			after.FSRSCard().State() != before.FSRSCard().State() {
+			t.Errorf("progress of studied word %d changed after a reshuffle", before.WordID())
+		}
+	}
+}
+
+func TestRecognitionFirst_EarlyReviews(t *testing.T) {
+	tests := []struct {
+		name             string
+		recognitionFirst bool
+		reviewCount      int
+		want             QuestionType
+	}{
+		{"first review", true, 0, QuestionTypeDutchToEnglish},
+		{"last early review", true, 2, QuestionTypeDutchToEnglish},
+		{"after the early reviews", true, 3, QuestionTypeEnglishToDutch},
+		{"well-known word", true, 10, QuestionTypeEnglishToDutch},
+		{"option off", false, 0, QuestionTypeEnglishToDutch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLearningFixture(t, nil)
+			f.updatePreferences(t, func(prefs *user.UserPreferences) {
+				prefs.SetQuestionDirection(user.QuestionDirectionToDutch)
+				prefs.SetRecognitionFirst(tt.recognitionFirst)
+			})
+			due := f.addWord(t, "house", "huis", "basics")
+			progress := f.addReviewCard(t, due, time.Now().Add(-time.Hour))
+			progress.FSRSCard().SetReviewCount(tt.reviewCount)
+			if err := f.learningRepo.SaveProgress(context.Background(), progress); err != nil {
+				t.Fatalf("failed to save progress: %v", err)
+			}
+			// Distractors the user already studied, so the due word is the only candidate
+			for _, pair := range [][2]string{{"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+				f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+			}
+
+			session, err := f.uc.GetNextDueWord(context.Background(), f.userID, false)
+			if err != nil {
+				t.Fatalf("GetNextDueWord: %v", err)
+			}
+			if session.Word.ID() != due.ID() {
+				t.Fatalf("served word %d, want the due word %d", session.Word.ID(), due.ID())
+			}
+			if session.QuestionType != tt.want {
+				t.Errorf("QuestionType = %q, want %q", session.QuestionType, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecalculateSchedule_FollowsRetention(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	short := f.addWord(t, "house", "huis", "basics")
+	long := f.addWord(t, "tree", "boom", "basics")
+	f.addReviewCard(t, short, time.Now().Add(24*time.Hour))
+	longProgress := f.addReviewCard(t, long, time.Now().Add(24*time.Hour))
+	longProgress.FSRSCard().SetStability(20)
+	if err := f.learningRepo.SaveProgress(ctx, longProgress); err != nil {
+		t.Fatalf("failed to save progress: %v", err)
+	}
+	// Words still in their learning steps keep their short-term schedule
+	learningWord := f.addWord(t, "cat", "kat", "basics")
+	learningProgress := learning.NewUserProgress(f.userID, learningWord.ID())
+	learningDue := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	learningProgress.FSRSCard().SetState(learning.StateLearning)
+	learningProgress.FSRSCard().SetLastReview(time.Now().Add(-time.Minute))
+	learningProgress.FSRSCard().SetDueDate(learningDue)
+	if err := f.learningRepo.SaveProgress(ctx, learningProgress); err != nil {
+		t.Fatalf("failed to save progress: %v", err)
+	}
+
+	// intervals reschedules under the retention and returns each review card's interval
+	intervals := func(retention string) map[*vocabulary.Word]time.Duration {
+		t.Helper()
+		f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetRequestRetention(retention) })
+		moved, err := f.uc.RecalculateSchedule(ctx, f.userID)
+		if err != nil {
+			t.Fatalf("RecalculateSchedule: %v", err)
+		}
+		if moved != 2 {
+			t.Errorf("retention %s moved %d cards, want the 2 review cards", retention, moved)
+		}
+		if moved, err := f.uc.RecalculateSchedule(ctx, f.userID); err != nil || moved != 0 {
+			t.Errorf("recalculating again moved %d cards (err %v), want 0", moved, err)
+		}
+
+		result := make(map[*vocabulary.Word]time.Duration)
+		for _, word := range []*vocabulary.Word{short, long} {
+			card := f.progress(t, word).FSRSCard()
+			result[word] = card.DueDate().Sub(card.LastReview())
+		}
+		if due := f.progress(t, learningWord).FSRSCard().DueDate(); !due.Equal(learningDue) {
+			t.Errorf("learning card moved to %v, want %v", due, learningDue)
+		}
+		return result
+	}
+
+	relaxed := intervals("0.85")
+	strict := intervals("0.95")
+	for _, word := range []*vocabulary.Word{short, long} {
+		if strict[word] >= relaxed[word] {
+			t.Errorf("%s: interval %v at 0.95 retention, want shorter than %v at 0.85", word.Dutch(), strict[word], relaxed[word])
+		}
+		if strict[word]%(24*time.Hour) != 0 {
+			t.Errorf("%s: interval %v is not a whole number of days from the last review", word.Dutch(), strict[word])
+		}
+	}
+	if strict[long] <= strict[short] || relaxed[long] <= relaxed[short] {
+		t.Error("a more stable card should be due later under either retention")
+	}
+}
+
+func TestLearningSessionPrompt_Sense(t *testing.T) {
+	right := vocabulary.NewWord("right", "rechts", vocabulary.CategoryPrepositions)
+	right.SetSense("direction")
+	house := vocabulary.NewWord("house", "huis", vocabulary.CategoryHome)
+
+	tests := []struct {
+		name         string
+		word         *vocabulary.Word
+		questionType QuestionType
+		showSense    bool
+		want         string
+	}{
+		{"sense shown", right, QuestionTypeEnglishToDutch, true, "right (direction)"},
+		{"sense turned off", right, QuestionTypeEnglishToDutch, false, "right"},
+		{"word without a sense", house, QuestionTypeEnglishToDutch, true, "house"},
+		{"dutch prompt hides the sense", right, QuestionTypeDutchToEnglish, true, "rechts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &LearningSession{Word: tt.word, QuestionType: tt.questionType, ShowSense: tt.showSense}
+			if got := session.Prompt(); got != tt.want {
+				t.Errorf("Prompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFSRSParams_StoredWeights(t *testing.T) {
+	custom := learning.DefaultFSRSParams()
+	custom.Weights[8] = 2.5
+
+	tests := []struct {
+		name        string
+		weights     string
+		wantCustom  bool
+		wantWeight8 float64
+	}{
+		{"missing", "", false, 0},
+		{"malformed", "[1, 2,", false, 0},
+		{"wrong length", "[1, 2, 3]", false, 0},
+		{"custom", custom.String(), true, 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLearningFixture(t, nil)
+			f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetFSRSWeights(tt.weights) })
+
+			params := f.uc.getFSRSParams(context.Background(), f.userID)
+			if !tt.wantCustom {
+				if params != nil {
+					t.Errorf("getFSRSParams = %v, want nil so cards use the defaults", params)
+				}
+				return
+			}
+			if params == nil || params.Weights[8] != tt.wantWeight8 {
+				t.Errorf("getFSRSParams = %v, want weight 8 = %v", params, tt.wantWeight8)
+			}
+		})
+	}
+}
+
+func TestNormalizeAnswer_Accents(t *testing.T) {
+	tests := []struct {
+		typed      string
+		expected   string
+		wantStrict bool // Equal with strict accents
+		wantLoose  bool // Equal with accents ignored
+	}{
+		{"een", "één", false, true},
+		{"één", "één", true, true},
+		{"ideeen", "ideeën", false, true},
+		{"ruine", "ruïne", false, true},
+		{"naief", "naïef", false, true},
+		{"cafe", "café", false, true},
+		{"Café ", "café", true, true},
+		{"ĳs", "ijs", true, true}, // The ij ligature is two letters in either mode
+		{"ĲSSEL", "ijssel", true, true},
+		{"e\u0301e\u0301n", "één", true, true}, // Decomposed accents match precomposed ones
+		{"ijs", "eis", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typed, func(t *testing.T) {
+			if got := normalizeAnswer(tt.typed, true) == normalizeAnswer(tt.expected, true); got != tt.wantStrict {
+				t.Errorf("strict: %q == %q is %v, want %v", tt.typed, tt.expected, got, tt.wantStrict)
+			}
+			if got := normalizeAnswer(tt.typed, false) == normalizeAnswer(tt.expected, false); got != tt.wantLoose {
+				t.Errorf("loose: %q == %q is %v, want %v", tt.typed, tt.expected, got, tt.wantLoose)
+			}
+		})
+	}
+}
+
+func TestReviewsOnly_SkipsNewWords(t *testing.T) {
+	tests := []struct {
+		name        string
+		reviewsOnly bool
+		wantNew     bool
+	}{
+		{"reviews only", true, false},
+		{"mixed", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newLearningFixture(t, nil)
+			f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetReviewsOnly(tt.reviewsOnly) })
+			for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}} {
+				f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(-time.Hour))
+			}
+			for _, pair := range [][2]string{{"cat", "kat"}, {"dog", "hond"}, {"bird", "vogel"}, {"fish", "vis"}} {
+				f.addWord(t, pair[0], pair[1], "basics")
+			}
+
+			available, err := f.uc.getAvailableWordsForLearning(ctx, f.userID, 20)
+			if err != nil {
+				t.Fatalf("getAvailableWordsForLearning: %v", err)
+			}
+			var newWords int
+			for _, progress := range available {
+				if progress.ID() == 0 {
+					newWords++
+				}
+			}
+			if (newWords > 0) != tt.wantNew {
+				t.Errorf("%d of %d available words are new, want new words %v", newWords, len(available), tt.wantNew)
+			}
+
+			if !tt.reviewsOnly {
+				return
+			}
+			for i := 0; i < 10; i++ {
+				session, err := f.uc.GetNextDueWord(ctx, f.userID, false)
+				if err != nil {
+					t.Fatalf("GetNextDueWord: %v", err)
+				}
+				if session.Progress.ID() == 0 {
+					t.Fatalf("reviews-only session served new word %q", session.Word.Dutch())
+				}
+			}
+		})
+	}
+}
+
+func TestGetNextDueWord_PinnedDirection(t *testing.T) {
+	tests := []struct {
+		name       string
+		overall    user.QuestionDirection
+		categories user.CategoryDirections
+		want       QuestionType
+	}{
+		{"english to dutch", user.QuestionDirectionToDutch, nil, QuestionTypeEnglishToDutch},
+		{"dutch to english", user.QuestionDirectionFromDutch, nil, QuestionTypeDutchToEnglish},
+		{"category pin wins", user.QuestionDirectionToDutch, user.CategoryDirections{"basics": user.QuestionDirectionFromDutch}, QuestionTypeDutchToEnglish},
+		{"other category's pin ignored", user.QuestionDirectionFromDutch, user.CategoryDirections{"food": user.QuestionDirectionToDutch}, QuestionTypeDutchToEnglish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLearningFixture(t, nil)
+			f.updatePreferences(t, func(prefs *user.UserPreferences) {
+				prefs.SetQuestionDirection(tt.overall)
+				prefs.SetCategoryDirections(tt.categories)
+			})
+			f.addReviewCard(t, f.addWord(t, "house", "huis", "basics"), time.Now().Add(-time.Hour))
+			for _, pair := range [][2]string{{"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+				f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+			}
+
+			for i := 0; i < 30; i++ {
+				session, err := f.uc.GetNextDueWord(context.Background(), f.userID, false)
+				if err != nil {
+					t.Fatalf("GetNextDueWord: %v", err)
+				}
+				if session.QuestionType != tt.want {
+					t.Fatalf("call %d asked %q, want %q every time", i, session.QuestionType, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUndoLastReview_RestoresDueDate(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	if _, err := f.uc.UndoLastReview(ctx, f.userID); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("undo without reviews: error = %v, want %v", err, ErrNothingToUndo)
+	}
+
+	// Two words reviewed in turn; undo walks back the most recent review first
+	dueDate := time.Now().Add(-time.Hour).Truncate(time.Second)
+	words := []*vocabulary.Word{f.addWord(t, "house", "huis", "basics"), f.addWord(t, "tree", "boom", "basics")}
+	original := make(map[vocabulary.ID]*learning.FSRSCard)
+	for _, word := range words {
+		original[word.ID()] = f.addReviewCard(t, word, dueDate).FSRSCard()
+		session := &LearningSession{UserID: f.userID, Word: word, Progress: f.progress(t, word), AnswerCorrect: true}
+		if err := f.uc.ProcessReview(ctx, session, learning.Good, 2*time.Second); err != nil {
+			t.Fatalf("ProcessReview: %v", err)
+		}
+		if f.progress(t, word).FSRSCard().DueDate().Equal(dueDate) {
+			t.Fatalf("review of %q didn't move its due date", word.Dutch())
+		}
+	}
+
+	for i := len(words) - 1; i >= 0; i-- {
+		word := words[i]
+		undone, err := f.uc.UndoLastReview(ctx, f.userID)
+		if err != nil {
+			t.Fatalf("UndoLastReview: %v", err)
+		}
+		if undone.ID() != word.ID() {
+			t.Fatalf("undid the review of %q, want %q", undone.Dutch(), word.Dutch())
+		}
+
+		card, want := f.progress(t, word).FSRSCard(), original[word.ID()]
+		if !card.DueDate().Equal(dueDate) {
+			t.Errorf("%s: due %v after undo, want the original %v", word.Dutch(), card.DueDate(), dueDate)
+		}
+		if card.Stability() != want.Stability() || card.State() != want.State() || card.ReviewCount() != want.ReviewCount() {
+			t.Errorf("%s: card after undo = stability %v, state %q, %d reviews; want %v, %q, %d", word.Dutch(),
+				card.Stability(), card.State(), card.ReviewCount(), want.Stability(), want.State(), want.ReviewCount())
+		}
+		// The other word still carries its review until its own undo
+		if i > 0 && f.progress(t, words[0]).FSRSCard().DueDate().Equal(dueDate) {
+			t.Errorf("undo of %q also reverted %q", word.Dutch(), words[0].Dutch())
+		}
+	}
+
+	var reviews int
+	if err := f.db.QueryRow(`SELECT COUNT(*) FROM review_history`).Scan(&reviews); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	if reviews != 0 {
+		t.Errorf("%d reviews left in history after undoing them all, want 0", reviews)
+	}
+	if _, err := f.uc.UndoLastReview(ctx, f.userID); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("undo after undoing everything: error = %v, want %v", err, ErrNothingToUndo)
+	}
+}
+
+// vanishingVocabularyRepository hides a word from FindByID, as if it was deleted
+// after the due words were queried
+type vanishingVocabularyRepository struct {
+	vocabulary.Repository
+	gone vocabulary.ID
+}
+
+func (r *vanishingVocabularyRepository) FindByID(ctx context.Context, id vocabulary.ID) (*vocabulary.Word, error) {
+	if id == r.gone {
+		return nil, nil
+	}
+	return r.Repository.FindByID(ctx, id)
+}
+
+func TestGetNextDueWord_SkipsMissingWords(t *testing.T) {
+	tests := []struct {
+		name         string
+		prune        bool
+		otherNew     bool
+		wantSession  bool
+		wantProgress bool
+	}{
+		{"skips to another word", false, true, true, true},
+		{"prunes the orphaned progress", true, true, true, false},
+		{"nothing else due", false, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			config := DefaultLearningConfig()
+			config.PruneMissingWordProgress = tt.prune
+			f := newLearningFixture(t, config)
+
+			// The missing word is the only due review, so it is picked before any new word
+			ghost := f.addWord(t, "ghost", "spook", "basics")
+			f.addReviewCard(t, ghost, time.Now().Add(-time.Hour))
+			other := f.addWord(t, "house", "huis", "basics")
+			if !tt.otherNew {
+				f.addReviewCard(t, other, time.Now().Add(48*time.Hour))
+			}
+			for _, pair := range [][2]string{{"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+				f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+			}
+			vocabRepo := &vanishingVocabularyRepository{Repository: f.vocabRepo, gone: ghost.ID()}
+			uc := NewLearningUseCase(f.learningRepo, vocabRepo, persistence.NewUserRepository(f.db),
+				persistence.NewGrammarRepository(f.db), f.prefsRepo, config)
+
+			session, err := uc.GetNextDueWord(ctx, f.userID, false)
+			if err != nil {
+				t.Fatalf("GetNextDueWord: %v", err)
+			}
+			if (session != nil) != tt.wantSession {
+				t.Fatalf("got session %v, want one: %v", session != nil, tt.wantSession)
+			}
+			if session != nil && (session.Word == nil || session.Word.ID() != other.ID()) {
+				t.Errorf("served %v, want the new word %q", session.Word, other.Dutch())
+			}
+
+			progress, err := f.learningRepo.FindProgress(ctx, f.userID, ghost.ID())
+			if err != nil {
+				t.Fatalf("FindProgress: %v", err)
+			}
+			if (progress != nil) != tt.wantProgress {
+				t.Errorf("orphaned progress kept = %v, want %v", progress != nil, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestProcessReview_ConfidenceScalesInterval(t *testing.T) {
+	// reviewInterval reviews a fresh overdue card and returns the time until it is next due
+	reviewInterval := func(t *testing.T, correct bool, confidence learning.Confidence) time.Duration {
+		t.Helper()
+
+		f := newLearningFixture(t, nil)
+		f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetIntervalFuzz(false) })
+		word := f.addWord(t, "house", "huis", "basics")
+		f.addReviewCard(t, word, time.Now().Add(-time.Hour))
+
+		session := &LearningSession{UserID: f.userID, Word: word, Progress: f.progress(t, word),
+			AnswerCorrect: correct, Confidence: confidence}
+		if err := f.uc.ProcessReview(context.Background(), session, learning.Good, 2*time.Second); err != nil {
+			t.Fatalf("ProcessReview: %v", err)
+		}
+		card := f.progress(t, word).FSRSCard()
+		return card.DueDate().Sub(card.LastReview())
+	}
+
+	boost := DefaultLearningConfig().ConfidenceBoost
+	baseline := reviewInterval(t, true, learning.ConfidenceUnrated)
+	tests := []struct {
+		name       string
+		correct    bool
+		confidence learning.Confidence
+		want       float64 // Expected interval relative to an unrated review
+	}{
+		{"certain stretches the interval", true, learning.ConfidenceHigh, 1 + boost},
+		{"guessed shrinks the interval", true, learning.ConfidenceLow, 1 - boost},
+		{"fairly sure keeps the interval", true, learning.ConfidenceMedium, 1},
+		{"a wrong answer ignores confidence", false, learning.ConfidenceHigh, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := float64(reviewInterval(t, tt.correct, tt.confidence)) / float64(baseline)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("interval is %.3f× the unrated one, want %.2f×", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingLearningRepository counts how often new words are looked up
+type countingLearningRepository struct {
+	learning.Repository
+	findNewWordsCalls int
+}
+
+func (r *countingLearningRepository) FindNewWords(ctx context.Context, userID user.ID, selection user.NewWordSelection, limit int) ([]*learning.UserProgress, error) {
+	r.findNewWordsCalls++
+	return r.Repository.FindNewWords(ctx, userID, selection, limit)
+}
+
+func TestDailyNewLimit_StopsDrawingNewWords(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetDailyNewLimit(2) })
+	for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}, {"bird", "vogel"}} {
+		f.addWord(t, pair[0], pair[1], "basics")
+	}
+	learningRepo := &countingLearningRepository{Repository: f.learningRepo}
+	uc := NewLearningUseCase(learningRepo, f.vocabRepo, persistence.NewUserRepository(f.db),
+		persistence.NewGrammarRepository(f.db), f.prefsRepo, nil)
+
+	// Introduce as many new words as the limit allows
+	for i := 0; i < 2; i++ {
+		session, err := uc.GetNextDueWord(ctx, f.userID, false)
+		if err != nil {
+			t.Fatalf("GetNextDueWord: %v", err)
+		}
+		if session == nil || session.Progress.ID() != 0 {
+			t.Fatalf("question %d isn't a new word", i+1)
+		}
+		session.AnswerCorrect = true
+		if err := uc.ProcessReview(ctx, session, learning.Good, 2*time.Second); err != nil {
+			t.Fatalf("ProcessReview: %v", err)
+		}
+	}
+	if learningRepo.findNewWordsCalls == 0 {
+		t.Fatal("new words were introduced without FindNewWords")
+	}
+	if !uc.DailyNewLimitReached(ctx, f.userID) {
+		t.Error("DailyNewLimitReached = false after introducing the limit")
+	}
+
+	// The words just introduced are in learning steps and not due yet, so nothing is left today
+	learningRepo.findNewWordsCalls = 0
+	for i := 0; i < 3; i++ {
+		session, err := uc.GetNextDueWord(ctx, f.userID, false)
+		if err != nil && !errors.Is(err, ErrTakeBreak) {
+			t.Fatalf("GetNextDueWord: %v", err)
+		}
+		if session != nil && session.Progress.ID() == 0 {
+			t.Fatalf("served new word %q past the daily limit", session.Word.Dutch())
+		}
+	}
+	if learningRepo.findNewWordsCalls != 0 {
+		t.Errorf("FindNewWords called %d times after the daily limit was hit", learningRepo.findNewWordsCalls)
+	}
+}
+
+// countingGrammarRepository counts lookups of the grammar tips applying to a word
+type countingGrammarRepository struct {
+	grammar.Repository
+	lookups int
+}
+
+func (r *countingGrammarRepository) FindApplicableToWord(ctx context.Context, dutchWord, englishWord, category string) ([]*grammar.GrammarTip, error) {
+	r.lookups++
+	return r.Repository.FindApplicableToWord(ctx, dutchWord, englishWord, category)
+}
+
+func TestFocusSession_SkipsGrammarTips(t *testing.T) {
+	// Each way of getting the next question
+	entries := []struct {
+		name string
+		next func(uc *LearningUseCase, userID user.ID, focus bool) (*LearningSession, error)
+	}{
+		{"due words", func(uc *LearningUseCase, userID user.ID, focus bool) (*LearningSession, error) {
+			return uc.GetNextDueWord(context.Background(), userID, focus)
+		}},
+		{"tag", func(uc *LearningUseCase, userID user.ID, focus bool) (*LearningSession, error) {
+			return uc.GetNextTaggedWord(context.Background(), userID, "home", focus)
+		}},
+		{"category", func(uc *LearningUseCase, userID user.ID, focus bool) (*LearningSession, error) {
+			return uc.GetNextDueWordInCategory(context.Background(), userID, "basics", focus)
+		}},
+	}
+	tests := []struct {
+		name        string
+		focus       bool
+		wantLookups bool
+	}{
+		{"focus session", true, false},
+		{"regular session", false, true},
+	}
+	for _, entry := range entries {
+		for _, tt := range tests {
+			t.Run(entry.name+", "+tt.name, func(t *testing.T) {
+				f := newLearningFixture(t, nil)
+				f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetGrammarTipsEnabled(true) })
+				word := f.addWord(t, "house", "huis", "basics")
+				for _, pair := range [][2]string{{"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+					f.addWord(t, pair[0], pair[1], "basics")
+				}
+				f.addReviewCard(t, word, time.Now().Add(-time.Hour))
+				if _, err := f.uc.TagWord(context.Background(), f.userID, "huis", "home"); err != nil {
+					t.Fatalf("TagWord: %v", err)
+				}
+				grammarRepo := &countingGrammarRepository{Repository: persistence.NewGrammarRepository(f.db)}
+				uc := NewLearningUseCase(f.learningRepo, f.vocabRepo, persistence.NewUserRepository(f.db), grammarRepo, f.prefsRepo, nil)
+
+				// A regular question rolls a 20% chance for a tip, so a hundred questions all but surely look one up
+				for i := 0; i < 100; i++ {
+					session, err := entry.next(uc, f.userID, tt.focus)
+					if err != nil {
+						t.Fatalf("next word: %v", err)
+					}
+					if session == nil {
+						t.Fatal("no session for the due word")
+					}
+					if session.Focus != tt.focus {
+						t.Errorf("session.Focus = %v, want %v", session.Focus, tt.focus)
+					}
+				}
+				if (grammarRepo.lookups > 0) != tt.wantLookups {
+					t.Errorf("looked up grammar tips %d times, want lookups %v", grammarRepo.lookups, tt.wantLookups)
+				}
+			})
+		}
+	}
+}
+
+func TestNewWordCategories_KeepReviews(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	// A word already started in a category that is later excluded
+	started := f.addWord(t, "house", "huis", "home")
+	f.addReviewCard(t, started, time.Now().Add(-time.Hour))
+	f.addWord(t, "door", "deur", "home")
+	f.addWord(t, "tree", "boom", "nature")
+	cat := f.addWord(t, "cat", "kat", "animals")
+	dog := f.addWord(t, "dog", "hond", "animals")
+
+	tests := []struct {
+		name       string
+		categories []string
+		wantNew    []vocabulary.ID // nil means every unstarted word
+	}{
+		{"every category", nil, nil},
+		{"only animals", []string{"animals"}, []vocabulary.ID{cat.ID(), dog.ID()}},
+		{"category without words", []string{"particles"}, []vocabulary.ID{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetNewWordCategories(tt.categories) })
+
+			available, err := f.uc.getAvailableWordsForLearning(ctx, f.userID, 20)
+			if err != nil {
+				t.Fatalf("getAvailableWordsForLearning: %v", err)
+			}
+			var reviews int
+			newWords := make(map[vocabulary.ID]bool)
+			for _, progress := range available {
+				if progress.ID() == 0 {
+					newWords[progress.WordID()] = true
+				} else if progress.WordID() == started.ID() {
+					reviews++
+				}
+			}
+			if reviews != 1 {
+				t.Errorf("the started word appears %d times, want its review kept", reviews)
+			}
+			if tt.wantNew == nil {
+				if len(newWords) != 4 {
+					t.Errorf("%d new words available, want all 4", len(newWords))
+				}
+				return
+			}
+			if len(newWords) != len(tt.wantNew) {
+				t.Errorf("%d new words available, want %d", len(newWords), len(tt.wantNew))
+			}
+			for _, id := range tt.wantNew {
+				if !newWords[id] {
+					t.Errorf("new word %d from an allowed category is missing", id)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessReview_ConcurrentNewWordSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	word := f.addWord(t, "house", "huis", "basics")
+	for _, pair := range [][2]string{{"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+		f.addReviewCard(t, f.addWord(t, pair[0], pair[1], "basics"), time.Now().Add(48*time.Hour))
+	}
+
+	// Two sessions (say, two devices) both start before either creates progress for the new word
+	var sessions []*LearningSession
+	for i := 0; i < 2; i++ {
+		session, err := f.uc.GetNextDueWord(ctx, f.userID, false)
+		if err != nil {
+			t.Fatalf("GetNextDueWord: %v", err)
+		}
+		if session == nil || session.Word.ID() != word.ID() || session.Progress.ID() != 0 {
+			t.Fatalf("session %d isn't for the new word", i+1)
+		}
+		session.AnswerCorrect = true
+		sessions = append(sessions, session)
+	}
+
+	errs := make(chan error, 2*len(sessions))
+	start := make(chan struct{})
+	for _, session := range sessions {
+		session := session
+		go func() {
+			<-start
+			_, err := f.uc.GetOrCreateProgress(ctx, f.userID, word.ID())
+			errs <- err
+		}()
+		go func() {
+			<-start
+			errs <- f.uc.ProcessReview(ctx, session, learning.Good, 2*time.Second)
+		}()
+	}
+	close(start)
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent progress save failed: %v", err)
+		}
+	}
+
+	var rows, reviews int
+	if err := f.db.QueryRow(`SELECT COUNT(*) FROM user_progress WHERE user_id = ? AND word_id = ?`,
+		int64(f.userID), int64(word.ID())).Scan(&rows); err != nil {
+		t.Fatalf("failed to count progress: %v", err)
+	}
+	if err := f.db.QueryRow(`SELECT COUNT(*) FROM review_history WHERE user_id = ?`, int64(f.userID)).Scan(&reviews); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	if rows != 1 || reviews != 2 {
+		t.Errorf("%d progress rows and %d reviews, want 1 and 2", rows, reviews)
+	}
+}
+
+func TestGetNextDueWordInCategory(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	categories := map[vocabulary.ID]vocabulary.Category{}
+	for _, w := range []struct {
+		english, dutch, category string
+		due                      bool
+	}{
+		{"house", "huis", "home", true},
+		{"door", "deur", "home", false},
+		{"window", "raam", "home", false},
+		{"roof", "dak", "home", false},
+		{"tree", "boom", "nature", true},
+		{"flower", "bloem", "nature", false},
+		{"river", "rivier", "nature", false},
+	} {
+		word := f.addWord(t, w.english, w.dutch, w.category)
+		if w.due {
+			f.addReviewCard(t, word, time.Now().Add(-time.Hour))
+		}
+		categories[word.ID()] = word.Category()
+	}
+
+	tests := []struct {
+		category    vocabulary.Category
+		wantSession bool
+	}{
+		{"home", true},
+		{"nature", true},
+		{"particles", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			due, err := f.learningRepo.FindDueWordsByCategory(ctx, f.userID, tt.category, 0, 10)
+			if err != nil {
+				t.Fatalf("FindDueWordsByCategory: %v", err)
+			}
+			fresh, err := f.learningRepo.FindNewWordsByCategory(ctx, f.userID, tt.category, user.NewWordSelection{}, 10)
+			if err != nil {
+				t.Fatalf("FindNewWordsByCategory: %v", err)
+			}
+			for _, progress := range append(due, fresh...) {
+				if got := categories[progress.WordID()]; got != tt.category {
+					t.Errorf("word %d from %q returned for %q", progress.WordID(), got, tt.category)
+				}
+			}
+			if tt.wantSession && (len(due) != 1 || len(fresh) == 0) {
+				t.Errorf("%d due and %d new words, want 1 due and some new", len(due), len(fresh))
+			}
+
+			for i := 0; i < 10; i++ {
+				session, err := f.uc.GetNextDueWordInCategory(ctx, f.userID, tt.category, false)
+				if err != nil {
+					t.Fatalf("GetNextDueWordInCategory: %v", err)
+				}
+				if (session != nil) != tt.wantSession {
+					t.Fatalf("got session %v, want one: %v", session != nil, tt.wantSession)
+				}
+				if session == nil {
+					continue
+				}
+				if session.Word.Category() != tt.category || session.Category != tt.category {
+					t.Errorf("served %q from %q (session category %q), want %q",
+						session.Word.Dutch(), session.Word.Category(), session.Category, tt.category)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOrCreateProgress_CommunityDifficulty(t *testing.T) {
+	tests := []struct {
+		name       string
+		optedIn    bool
+		others     []float64 // Difficulty of the word for other learners who reviewed it
+		unreviewed bool      // Whether another learner has an unreviewed, very hard card for it
+		want       float64
+	}{
+		{"community average", true, []float64{6, 7, 8}, false, 7},
+		{"unreviewed cards don't count", true, []float64{6, 7, 8}, true, 7},
+		{"too few learners", true, []float64{6, 8}, false, 5},
+		{"nobody else", true, nil, false, 5},
+		{"opted out", false, []float64{6, 7, 8}, false, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newLearningFixture(t, nil)
+			f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetCommunityDifficulty(tt.optedIn) })
+			word := f.addWord(t, "house", "huis", "basics")
+
+			saveOther := func(i int, difficulty float64, reviewCount int) {
+				progress := learning.NewUserProgress(f.addUser(t, user.TelegramID(100+i), "Other"), word.ID())
+				progress.FSRSCard().SetDifficulty(difficulty)
+				progress.FSRSCard().SetReviewCount(reviewCount)
+				if err := f.learningRepo.SaveProgress(ctx, progress); err != nil {
+					t.Fatalf("failed to save progress: %v", err)
+				}
+			}
+			for i, difficulty := range tt.others {
+				saveOther(i, difficulty, 1)
+			}
+			if tt.unreviewed {
+				saveOther(len(tt.others), 10, 0)
+			}
+
+			progress, err := f.uc.GetOrCreateProgress(ctx, f.userID, word.ID())
+			if err != nil {
+				t.Fatalf("GetOrCreateProgress: %v", err)
+			}
+			if got := progress.FSRSCard().Difficulty(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("initial difficulty = %v, want %v", got, tt.want)
+			}
+			if stored := f.progress(t, word).FSRSCard().Difficulty(); math.Abs(stored-tt.want) > 1e-9 {
+				t.Errorf("stored difficulty = %v, want %v", stored, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFSRSParams_LearningSteps(t *testing.T) {
+	f := newLearningFixture(t, nil)
+
+	tests := []struct {
+		name      string
+		stored    string
+		wantSteps string // "" when the defaults are used
+	}{
+		{"custom steps", "30s,5m,1h", "30s,5m,1h"},
+		{"not set", "", ""},
+		{"unparsable", "ten minutes", ""},
+		{"zero step", "1m,0s", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.updatePreferences(t, func(p *user.UserPreferences) { p.SetLearningSteps(tt.stored) })
+
+			params := f.uc.getFSRSParams(context.Background(), f.userID)
+			if tt.wantSteps == "" {
+				if params != nil {
+					t.Errorf("stored steps %q gave params with steps %s, want the defaults", tt.stored, learning.FormatLearningSteps(params.LearningSteps))
+				}
+				return
+			}
+			if params == nil {
+				t.Fatalf("stored steps %q gave the defaults, want %s", tt.stored, tt.wantSteps)
+			}
+			if got := learning.FormatLearningSteps(params.LearningSteps); got != tt.wantSteps {
+				t.Errorf("steps = %s, want %s", got, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestDifficultyTrend_UsesUserLocalDays(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+
+	// Pick a zone whose calendar date differs from the server's right now
+	serverDate := time.Now().Format("2006-01-02")
+	var location *time.Location
+	for _, name := range []string{"Pacific/Kiritimati", "Etc/GMT+12"} {
+		candidate, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", name, err)
+		}
+		if time.Now().In(candidate).Format("2006-01-02") != serverDate {
+			location = candidate
+			break
+		}
+	}
+	if location == nil {
+		t.Skip("no time zone is on a different date than the server")
+	}
+	f.updatePreferences(t, func(prefs *user.UserPreferences) { prefs.SetTimezone(location.String()) })
+
+	userNow := time.Now().In(location)
+	today := time.Date(userNow.Year(), userNow.Month(), userNow.Day(), 0, 0, 0, 0, time.UTC)
+	windowStart := today.AddDate(0, 0, -(difficultyTrendWindowDays - 1))
+
+	// One snapshot on each side of the user's window start; a window off by a day puts both on one side
+	for _, snapshot := range []struct {
+		day        time.Time
+		difficulty float64
+	}{
+		{windowStart.AddDate(0, 0, -1), 4},
+		{windowStart, 6},
+	} {
+		if _, err := f.db.Exec(`INSERT INTO difficulty_snapshots (user_id, snapshot_date, avg_difficulty) VALUES (?, ?, ?)`,
+			int64(f.userID), snapshot.day.Format("2006-01-02"), snapshot.difficulty); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+	if got := f.uc.getDifficultyTrend(ctx, f.userID); got != learning.TrendUp {
+		t.Errorf("difficulty trend = %q, want %q", got, learning.TrendUp)
+	}
+
+	// A review records today's snapshot under the user's date
+	word := f.addWord(t, "house", "huis", "basics")
+	f.addReviewCard(t, word, time.Now().Add(-time.Hour))
+	session := &LearningSession{UserID: f.userID, Word: word, Progress: f.progress(t, word), AnswerCorrect: true}
+	if err := f.uc.ProcessReview(ctx, session, learning.Good, 2*time.Second); err != nil {
+		t.Fatalf("ProcessReview: %v", err)
+	}
+	var latest string
+	if err := f.db.QueryRow(`SELECT MAX(snapshot_date) FROM difficulty_snapshots WHERE user_id = ?`, int64(f.userID)).Scan(&latest); err != nil {
+		t.Fatalf("failed to read snapshots: %v", err)
+	}
+	if want := today.Format("2006-01-02"); latest != want {
+		t.Errorf("latest snapshot date = %s, want %s", latest, want)
+	}
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// optionCount is the number of answers offered in a multiple-choice question
+const optionCount = 4
+
+// ErrNotEnoughOptions is returned when the candidate words can't supply enough distinct wrong answers
+var ErrNotEnoughOptions = errors.New("not enough words to generate options")
+
+// Randomizer picks random numbers for option generation; inject a fixed one for deterministic output
+type Randomizer interface {
+	// Intn returns a number in [0, n)
+	Intn(n int) int
+}
+
+// cryptoRandomizer draws from crypto/rand, falling back to the clock if it fails
+type cryptoRandomizer struct{}
+
+func (cryptoRandomizer) Intn(n int) int {
+	value, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return int(time.Now().UnixNano() % int64(n))
+	}
+	return int(value.Int64())
+}
+
+// OptionGenerator builds multiple-choice options from candidate words without any data access,
+// so the same selection logic serves every question flow
+type OptionGenerator struct {
+	random Randomizer
+}
+
+// NewOptionGenerator creates an option generator; a nil randomizer uses crypto/rand
+func NewOptionGenerator(random Randomizer) *OptionGenerator {
+	if random == nil {
+		random = cryptoRandomizer{}
+	}
+	return &OptionGenerator{random: random}
+}
+
+// Generate returns the options for a question about the word and the index of the correct one.
+// Wrong answers come from the candidate sets in order of preference: all of the first set is
+// eligible, and later sets only top it up when it can't supply enough distinct answers. Within the
+// first set, words with the same part of speech are preferred when there are enough of them, so the
+// answer can't be picked out by its word form alone. English answers differing only in British or
+// American spelling are treated as one.
+func (g *OptionGenerator) Generate(word *vocabulary.Word, questionType QuestionType, candidateSets ...[]*vocabulary.Word) ([]string, int, error) {
+	answerOf := (*vocabulary.Word).English
+	// Spelling variants of one English answer count as duplicates, so "colour" can't sit beside "color"
+	keyOf := func(answer string) string { return americanSpelling(strings.ToLower(answer)) }
+	if questionType == QuestionTypeEnglishToDutch {
+		answerOf = (*vocabulary.Word).Dutch
+		keyOf = func(answer string) string { return answer }
+	}
+	correctAnswer := answerOf(word)
+	wanted := optionCount - 1
+
+	seen := map[string]bool{keyOf(correctAnswer): true}
+	var wrongAnswers []string
+	for i, candidates := range candidateSets {
+		if i == 0 {
+			if samePOS := filterByPartOfSpeech(candidates, word.PartOfSpeech()); len(samePOS) > wanted {
+				candidates = samePOS
+			}
+		} else if len(wrongAnswers) >= wanted {
+			break
+		}
+
+		for _, w := range candidates {
+			if i > 0 && len(wrongAnswers) >= wanted {
+				break
+			}
+			candidate := answerOf(w)
+			if w.ID() == word.ID() || seen[keyOf(candidate)] {
+				continue
+			}
+			seen[keyOf(candidate)] = true
+			wrongAnswers = append(wrongAnswers, candidate)
+		}
+	}
+
+	if len(wrongAnswers) < wanted {
+		return nil, 0, ErrNotEnoughOptions
+	}
+
+	// Pick the wrong answers with a Fisher-Yates shuffle
+	for i := len(wrongAnswers) - 1; i > 0; i-- {
+		j := g.random.Intn(i + 1)
+		wrongAnswers[i], wrongAnswers[j] = wrongAnswers[j], wrongAnswers[i]
+	}
+
+	// Place the correct answer at a random position among them
+	correctIndex := g.random.Intn(optionCount)
+	options := make([]string, 0, optionCount)
+	options = append(options, wrongAnswers[:correctIndex]...)
+	options = append(options, correctAnswer)
+	options = append(options, wrongAnswers[correctIndex:wanted]...)
+
+	return options, correctIndex, nil
+}
+
+// filterByPartOfSpeech returns the words with the given part of speech (none when it is unknown)
+func filterByPartOfSpeech(words []*vocabulary.Word, pos vocabulary.PartOfSpeech) []*vocabulary.Word {
+	if pos == "" {
+		return nil
+	}
+
+	var filtered []*vocabulary.Word
+	for _, w := range words {
+		if w.PartOfSpeech() == pos {
+			filtered = append(filtered, w)
+		}
+	}
+	return filtered
+}
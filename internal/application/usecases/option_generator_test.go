@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// firstRandomizer always picks 0, so option generation is deterministic
+type firstRandomizer struct{}
+
+func (firstRandomizer) Intn(int) int { return 0 }
+
+// lastRandomizer always picks n-1, leaving candidates in order and the answer last
+type lastRandomizer struct{}
+
+func (lastRandomizer) Intn(n int) int { return n - 1 }
+
+func testWord(id vocabulary.ID, english, dutch string, pos vocabulary.PartOfSpeech) *vocabulary.Word {
+	word := vocabulary.NewWord(english, dutch, vocabulary.CategoryHome)
+	word.SetID(id)
+	word.SetPartOfSpeech(pos)
+	return word
+}
+
+func TestOptionGenerator_PartOfSpeech(t *testing.T) {
+	nouns := []*vocabulary.Word{
+		testWord(2, "table", "tafel", vocabulary.PartOfSpeechNoun),
+		testWord(3, "chair", "stoel", vocabulary.PartOfSpeechNoun),
+		testWord(4, "door", "deur", vocabulary.PartOfSpeechNoun),
+		testWord(5, "window", "raam", vocabulary.PartOfSpeechNoun),
+	}
+	verbs := []*vocabulary.Word{
+		testWord(6, "to sleep", "slapen", vocabulary.PartOfSpeechVerb),
+		testWord(7, "to cook", "koken", vocabulary.PartOfSpeechVerb),
+		testWord(8, "to clean", "schoonmaken", vocabulary.PartOfSpeechVerb),
+	}
+	house := testWord(1, "house", "huis", vocabulary.PartOfSpeechNoun)
+	all := append(append([]*vocabulary.Word{}, verbs...), nouns...)
+	fewNouns := append(append([]*vocabulary.Word{}, verbs...), nouns[:2]...)
+
+	tests := []struct {
+		name       string
+		word       *vocabulary.Word
+		candidates []*vocabulary.Word
+		// wantPOS is the part of speech every wrong answer must share, or "" when any is allowed
+		wantPOS vocabulary.PartOfSpeech
+		// wantMixed requires at least one wrong answer with a different part of speech
+		wantMixed bool
+	}{
+		{"enough same-POS words", house, all, vocabulary.PartOfSpeechNoun, false},
+		{"too few same-POS words fall back", house, fewNouns, "", true},
+		{"unknown POS uses every word", testWord(1, "house", "huis", ""), all, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posOf := make(map[string]vocabulary.PartOfSpeech)
+			for _, w := range tt.candidates {
+				posOf[w.Dutch()] = w.PartOfSpeech()
+			}
+
+			options, correctIndex, err := NewOptionGenerator(firstRandomizer{}).Generate(tt.word, QuestionTypeEnglishToDutch, tt.candidates)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if len(options) != optionCount || options[correctIndex] != tt.word.Dutch() {
+				t.Fatalf("options = %v with correct index %d, want %d options around %q", options, correctIndex, optionCount, tt.word.Dutch())
+			}
+
+			mixed := false
+			for i, option := range options {
+				if i == correctIndex {
+					continue
+				}
+				if tt.wantPOS != "" && posOf[option] != tt.wantPOS {
+					t.Errorf("distractor %q is a %s, want %s", option, posOf[option], tt.wantPOS)
+				}
+				if posOf[option] != tt.word.PartOfSpeech() {
+					mixed = true
+				}
+			}
+			if tt.wantMixed && !mixed {
+				t.Errorf("options %v should fall back to other parts of speech", options)
+			}
+		})
+	}
+}
+
+func TestOptionGenerator_Generate(t *testing.T) {
+	house := testWord(1, "house", "huis", "")
+	color := testWord(2, "color", "kleur", "")
+	colour := testWord(3, "colour", "tint", "")
+	tree := testWord(4, "tree", "boom", "")
+	cat := testWord(5, "cat", "kat", "")
+	dog := testWord(6, "dog", "hond", "")
+	duplicate := testWord(7, "home", "huis", "")
+
+	tests := []struct {
+		name          string
+		questionType  QuestionType
+		candidateSets [][]*vocabulary.Word
+		want          []string
+		wantErr       error
+	}{
+		{"dutch answers", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{tree, cat, dog}}, []string{"boom", "kat", "hond", "huis"}, nil},
+		{"english answers", QuestionTypeDutchToEnglish, [][]*vocabulary.Word{{tree, cat, dog}}, []string{"tree", "cat", "dog", "house"}, nil},
+		{"skips the word itself", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{house, tree, cat, dog}}, []string{"boom", "kat", "hond", "huis"}, nil},
+		{"skips answers equal to the correct one", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{duplicate, tree, cat, dog}}, []string{"boom", "kat", "hond", "huis"}, nil},
+		{"spelling variants count once", QuestionTypeDutchToEnglish, [][]*vocabulary.Word{{color, colour, tree, cat}}, []string{"color", "tree", "cat", "house"}, nil},
+		{"later sets top up", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{tree}, {tree, cat, dog}}, []string{"boom", "kat", "hond", "huis"}, nil},
+		{"later sets only fill what's missing", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{tree, cat}, {color, dog}}, []string{"boom", "kat", "kleur", "huis"}, nil},
+		{"not enough candidates", QuestionTypeEnglishToDutch, [][]*vocabulary.Word{{house, tree, cat}}, nil, ErrNotEnoughOptions},
+		{"no candidates", QuestionTypeEnglishToDutch, nil, nil, ErrNotEnoughOptions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, correctIndex, err := NewOptionGenerator(lastRandomizer{}).Generate(house, tt.questionType, tt.candidateSets...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Generate error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if strings.Join(options, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("options = %v, want %v", options, tt.want)
+			}
+			if correctIndex != optionCount-1 {
+				t.Errorf("correct index = %d, want %d", correctIndex, optionCount-1)
+			}
+		})
+	}
+}
+
+func TestOptionGenerator_CorrectIndexFollowsRandomizer(t *testing.T) {
+	house := testWord(1, "house", "huis", "")
+	candidates := []*vocabulary.Word{
+		testWord(2, "tree", "boom", ""), testWord(3, "cat", "kat", ""), testWord(4, "dog", "hond", ""),
+	}
+
+	for _, random := range []Randomizer{firstRandomizer{}, lastRandomizer{}, nil} {
+		options, correctIndex, err := NewOptionGenerator(random).Generate(house, QuestionTypeEnglishToDutch, candidates)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if len(options) != optionCount || options[correctIndex] != "huis" {
+			t.Errorf("%T: options %v with correct index %d don't point at the answer", random, options, correctIndex)
+		}
+	}
+}
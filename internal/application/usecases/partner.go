@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+var (
+	// ErrInvalidPartnerInvite is returned when an invite code is malformed, unknown or already used
+	ErrInvalidPartnerInvite = errors.New("invalid partner invite")
+	// ErrOwnPartnerInvite is returned when a user tries to accept their own invite
+	ErrOwnPartnerInvite = errors.New("cannot accept your own partner invite")
+	// ErrNoStudyPartner is returned when a partner action needs a study partner the user doesn't have
+	ErrNoStudyPartner = errors.New("user has no study partner")
+)
+
+// StudyPartnerOverview is the shared-progress view of a study partnership
+type StudyPartnerOverview struct {
+	Partner *user.User
+	Mine    *learning.DeckProgress
+	Theirs  *learning.DeckProgress
+}
+
+// CreatePartnerInvite generates a fresh invite code that another user can accept to become
+// the user's study partner. Creating a new code invalidates the previous one.
+func (uc *LearningUseCase) CreatePartnerInvite(ctx context.Context, userID user.ID) (string, error) {
+	secret := make([]byte, 4)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	code := fmt.Sprintf("%d-%s", userID, hex.EncodeToString(secret))
+	if err := uc.preferencesRepo.UpdatePreference(ctx, userID, user.PrefPartnerInvite, code); err != nil {
+		return "", fmt.Errorf("failed to save invite code: %w", err)
+	}
+
+	return code, nil
+}
+
+// AcceptPartnerInvite links the user with the owner of the invite code and returns the new partner
+func (uc *LearningUseCase) AcceptPartnerInvite(ctx context.Context, userID user.ID, code string) (*user.User, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	idPart, _, found := strings.Cut(code, "-")
+	if !found {
+		return nil, ErrInvalidPartnerInvite
+	}
+	inviterID, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPartnerInvite
+	}
+	if user.ID(inviterID) == userID {
+		return nil, ErrOwnPartnerInvite
+	}
+
+	preferences, err := uc.preferencesRepo.FindPreferences(ctx, user.ID(inviterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inviter preferences: %w", err)
+	}
+	if preferences == nil || preferences.GetPartnerInvite() == "" || preferences.GetPartnerInvite() != code {
+		return nil, ErrInvalidPartnerInvite
+	}
+
+	partner, err := uc.userRepo.FindByID(ctx, user.ID(inviterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inviter: %w", err)
+	}
+	if partner == nil {
+		return nil, ErrInvalidPartnerInvite
+	}
+
+	if _, err := uc.learningRepo.CreatePartnerLink(ctx, partner.ID(), userID); err != nil {
+		return nil, fmt.Errorf("failed to link partners: %w", err)
+	}
+
+	// Invite codes are single-use
+	if err := uc.preferencesRepo.UpdatePreference(ctx, partner.ID(), user.PrefPartnerInvite, ""); err != nil {
+		return nil, fmt.Errorf("failed to clear invite code: %w", err)
+	}
+
+	return partner, nil
+}
+
+// RemoveStudyPartner ends the user's study partnership and returns the former partner.
+// Both users keep their progress on the shared words.
+func (uc *LearningUseCase) RemoveStudyPartner(ctx context.Context, userID user.ID) (*user.User, error) {
+	link, err := uc.learningRepo.FindPartnerLink(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrNoStudyPartner
+	}
+
+	if err := uc.learningRepo.DeletePartnerLink(ctx, link.ID); err != nil {
+		return nil, fmt.Errorf("failed to remove partner link: %w", err)
+	}
+
+	partner, err := uc.userRepo.FindByID(ctx, link.Other(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	return partner, nil
+}
+
+// AddToSharedDeck resolves a term and adds the word to the user's shared deck.
+// The word is nil when the term is unknown; added is false when the word was already in the deck.
+func (uc *LearningUseCase) AddToSharedDeck(ctx context.Context, userID user.ID, term string) (word *vocabulary.Word, partner *user.User, added bool, err error) {
+	link, err := uc.learningRepo.FindPartnerLink(ctx, userID)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get partner link: %w", err)
+	}
+	if link == nil {
+		return nil, nil, false, ErrNoStudyPartner
+	}
+
+	word, err = uc.vocabularyRepo.FindByTerm(ctx, term)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to find word: %w", err)
+	}
+	if word == nil {
+		return nil, nil, false, nil
+	}
+
+	added, err = uc.learningRepo.AddSharedDeckWord(ctx, link, word.ID())
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to add word to shared deck: %w", err)
+	}
+
+	partner, err = uc.userRepo.FindByID(ctx, link.Other(userID))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	return word, partner, added, nil
+}
+
+// GetStudyPartnerOverview returns both partners' progress on their shared deck, or nil if the user has no partner
+func (uc *LearningUseCase) GetStudyPartnerOverview(ctx context.Context, userID user.ID) (*StudyPartnerOverview, error) {
+	link, err := uc.learningRepo.FindPartnerLink(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner link: %w", err)
+	}
+	if link == nil {
+		return nil, nil
+	}
+
+	partnerID := link.Other(userID)
+	partner, err := uc.userRepo.FindByID(ctx, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	mine, err := uc.learningRepo.GetSharedDeckProgress(ctx, link.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared deck progress: %w", err)
+	}
+	theirs, err := uc.learningRepo.GetSharedDeckProgress(ctx, link.ID, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner's shared deck progress: %w", err)
+	}
+
+	return &StudyPartnerOverview{Partner: partner, Mine: mine, Theirs: theirs}, nil
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+// addUser saves another user alongside the fixture's own
+func (f *learningFixture) addUser(t *testing.T, telegramID user.TelegramID, firstName string) user.ID {
+	t.Helper()
+
+	u := user.NewUser(telegramID, "", firstName, "", "en")
+	if err := persistence.NewUserRepository(f.db).Save(context.Background(), u); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	return u.ID()
+}
+
+func TestPartnerLinking(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	bob := f.addUser(t, 43, "Bob")
+	carol := f.addUser(t, 44, "Carol")
+
+	code, err := f.uc.CreatePartnerInvite(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("CreatePartnerInvite: %v", err)
+	}
+
+	rejected := []struct {
+		name    string
+		userID  user.ID
+		code    string
+		wantErr error
+	}{
+		{"own invite", f.userID, code, ErrOwnPartnerInvite},
+		{"malformed code", bob, "not-a-code", ErrInvalidPartnerInvite},
+		{"wrong secret", bob, code + "0", ErrInvalidPartnerInvite},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.uc.AcceptPartnerInvite(ctx, tt.userID, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AcceptPartnerInvite error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	partner, err := f.uc.AcceptPartnerInvite(ctx, bob, " "+code+" ")
+	if err != nil {
+		t.Fatalf("AcceptPartnerInvite: %v", err)
+	}
+	if partner.ID() != f.userID {
+		t.Errorf("partner = user %d, want the inviter %d", partner.ID(), f.userID)
+	}
+	for _, userID := range []user.ID{f.userID, bob} {
+		link, err := f.learningRepo.FindPartnerLink(ctx, userID)
+		if err != nil || link == nil {
+			t.Fatalf("user %d has no partner link (err %v)", userID, err)
+		}
+		if want := map[user.ID]user.ID{f.userID: bob, bob: f.userID}[userID]; link.Other(userID) != want {
+			t.Errorf("user %d is linked to %d, want %d", userID, link.Other(userID), want)
+		}
+	}
+
+	// Invites are single-use, and a linked user can't take a second partner
+	if _, err := f.uc.AcceptPartnerInvite(ctx, carol, code); !errors.Is(err, ErrInvalidPartnerInvite) {
+		t.Errorf("reusing an invite: error = %v, want %v", err, ErrInvalidPartnerInvite)
+	}
+	second, err := f.uc.CreatePartnerInvite(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("CreatePartnerInvite: %v", err)
+	}
+	if _, err := f.uc.AcceptPartnerInvite(ctx, carol, second); !errors.Is(err, learning.ErrAlreadyLinked) {
+		t.Errorf("linking an already linked user: error = %v, want %v", err, learning.ErrAlreadyLinked)
+	}
+
+	former, err := f.uc.RemoveStudyPartner(ctx, bob)
+	if err != nil {
+		t.Fatalf("RemoveStudyPartner: %v", err)
+	}
+	if former.ID() != f.userID {
+		t.Errorf("former partner = user %d, want %d", former.ID(), f.userID)
+	}
+	if _, err := f.uc.RemoveStudyPartner(ctx, f.userID); !errors.Is(err, ErrNoStudyPartner) {
+		t.Errorf("removing a partner twice: error = %v, want %v", err, ErrNoStudyPartner)
+	}
+}
+
+func TestSharedDeckMembership(t *testing.T) {
+	ctx := context.Background()
+	f := newLearningFixture(t, nil)
+	bob := f.addUser(t, 43, "Bob")
+	house := f.addWord(t, "house", "huis", "basics")
+	f.addWord(t, "tree", "boom", "basics")
+
+	if _, _, _, err := f.uc.AddToSharedDeck(ctx, f.userID, "huis"); !errors.Is(err, ErrNoStudyPartner) {
+		t.Fatalf("adding without a partner: error = %v, want %v", err, ErrNoStudyPartner)
+	}
+
+	code, err := f.uc.CreatePartnerInvite(ctx, f.userID)
+	if err != nil {
+		t.Fatalf("CreatePartnerInvite: %v", err)
+	}
+	if _, err := f.uc.AcceptPartnerInvite(ctx, bob, code); err != nil {
+		t.Fatalf("AcceptPartnerInvite: %v", err)
+	}
+
+	additions := []struct {
+		name      string
+		term      string
+		wantWord  bool
+		wantAdded bool
+	}{
+		{"new word", "huis", true, true},
+		{"already in the deck", "house", true, false},
+		{"unknown term", "fiets", false, false},
+	}
+	for _, tt := range additions {
+		t.Run(tt.name, func(t *testing.T) {
+			word, partner, added, err := f.uc.AddToSharedDeck(ctx, f.userID, tt.term)
+			if err != nil {
+				t.Fatalf("AddToSharedDeck: %v", err)
+			}
+			if (word != nil) != tt.wantWord || added != tt.wantAdded {
+				t.Fatalf("AddToSharedDeck(%q) = word %v, added %v; want word %v, added %v", tt.term, word != nil, added, tt.wantWord, tt.wantAdded)
+			}
+			if word != nil && partner.ID() != bob {
+				t.Errorf("partner = user %d, want %d", partner.ID(), bob)
+			}
+		})
+	}
+
+	// Both partners get the shared word tagged, so either can study the deck
+	for _, userID := range []user.ID{f.userID, bob} {
+		words, err := f.learningRepo.FindNewWordsByTag(ctx, userID, learning.PartnerDeckTag, user.NewWordSelection{}, 10)
+		if err != nil {
+			t.Fatalf("FindNewWordsByTag: %v", err)
+		}
+		if len(words) != 1 || words[0].WordID() != house.ID() {
+			t.Errorf("user %d's deck has %d words, want only %d", userID, len(words), house.ID())
+		}
+	}
+
+	// Content is shared, progress is not
+	f.addReviewCard(t, house, time.Now().Add(48*time.Hour))
+	overview, err := f.uc.GetStudyPartnerOverview(ctx, bob)
+	if err != nil {
+		t.Fatalf("GetStudyPartnerOverview: %v", err)
+	}
+	if overview.Partner.ID() != f.userID {
+		t.Errorf("overview partner = user %d, want %d", overview.Partner.ID(), f.userID)
+	}
+	if overview.Mine.TotalWords != 1 || overview.Mine.StudiedWords != 0 {
+		t.Errorf("bob's deck progress = %+v, want 1 word, none studied", *overview.Mine)
+	}
+	if overview.Theirs.TotalWords != 1 || overview.Theirs.LearnedWords != 1 {
+		t.Errorf("partner's deck progress = %+v, want 1 word, learned", *overview.Theirs)
+	}
+
+	if _, err := f.uc.RemoveStudyPartner(ctx, f.userID); err != nil {
+		t.Fatalf("RemoveStudyPartner: %v", err)
+	}
+	if overview, err := f.uc.GetStudyPartnerOverview(ctx, bob); err != nil || overview != nil {
+		t.Errorf("overview after unlinking = %v (err %v), want none", overview, err)
+	}
+}
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// preferencesCache is a read-through cache in front of a preferences repository.
+// Writes go through the cache and drop the user's entry, so a changed setting is
+// visible on the very next read.
+type preferencesCache struct {
+	repo    user.PreferencesRepository
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[user.ID]cachedPreferences
+}
+
+// cachedPreferences is a cached copy of a user's preferences
+type cachedPreferences struct {
+	preferences *user.UserPreferences
+	expiresAt   time.Time
+}
+
+// newPreferencesCache wraps a preferences repository with a cache whose entries live for ttl
+func newPreferencesCache(repo user.PreferencesRepository, ttl time.Duration) *preferencesCache {
+	return &preferencesCache{
+		repo:    repo,
+		ttl:     ttl,
+		entries: make(map[user.ID]cachedPreferences),
+	}
+}
+
+// FindPreferences returns the user's preferences, loading them from the repository on a miss.
+// Callers get their own copy, so mutating it without saving never leaks into the cache.
+func (c *preferencesCache) FindPreferences(ctx context.Context, userID user.ID) (*user.UserPreferences, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[userID]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.preferences.Clone(), nil
+	}
+
+	preferences, err := c.repo.FindPreferences(ctx, userID)
+	if err != nil || preferences == nil {
+		return preferences, err
+	}
+
+	c.mu.Lock()
+	c.entries[userID] = cachedPreferences{
+		preferences: preferences.Clone(),
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return preferences, nil
+}
+
+// SavePreferences saves the user's preferences and invalidates their cached copy
+func (c *preferencesCache) SavePreferences(ctx context.Context, preferences *user.UserPreferences) error {
+	defer c.Invalidate(preferences.UserID())
+	return c.repo.SavePreferences(ctx, preferences)
+}
+
+// UpdatePreference updates a single preference and invalidates the user's cached copy
+func (c *preferencesCache) UpdatePreference(ctx context.Context, userID user.ID, key, value string) error {
+	defer c.Invalidate(userID)
+	return c.repo.UpdatePreference(ctx, userID, key, value)
+}
+
+// Invalidate drops the user's cached preferences so the next read goes to the repository
+func (c *preferencesCache) Invalidate(userID user.ID) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
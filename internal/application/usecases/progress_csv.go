@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// progressCSVHeader is the header row of the progress export that ImportProgressCSV reads back
+var progressCSVHeader = []string{"english", "dutch", "state", "stability", "difficulty", "due_date", "last_review", "review_count", "lapses"}
+
+// ProgressImportResult reports what an import of a progress CSV changed
+type ProgressImportResult struct {
+	Imported int // Words whose progress was created or replaced
+	Skipped  int // Rows naming a word this instance doesn't have
+}
+
+// progressCSVRow is one validated row of a progress CSV
+type progressCSVRow struct {
+	english string
+	dutch   string
+	card    *learning.FSRSCard
+}
+
+// ExportProgressCSV writes the user's FSRS cards as CSV, identifying words by their text
+// so the file can be imported on another instance
+func (uc *LearningUseCase) ExportProgressCSV(ctx context.Context, userID user.ID) ([]byte, error) {
+	allProgress, err := uc.learningRepo.FindProgressByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(progressCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, progress := range allProgress {
+		word, err := uc.vocabularyRepo.FindByID(ctx, progress.WordID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get word %d: %w", progress.WordID(), err)
+		}
+		if word == nil {
+			continue
+		}
+
+		card := progress.FSRSCard()
+		lastReview := ""
+		if !card.LastReview().IsZero() {
+			lastReview = card.LastReview().UTC().Format(time.RFC3339)
+		}
+
+		row := []string{
+			word.English(),
+			word.Dutch(),
+			string(card.State()),
+			strconv.FormatFloat(card.Stability(), 'g', -1, 64),
+			strconv.FormatFloat(card.Difficulty(), 'g', -1, 64),
+			card.DueDate().UTC().Format(time.RFC3339),
+			lastReview,
+			strconv.Itoa(card.ReviewCount()),
+			strconv.Itoa(card.Lapses()),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row for word %d: %w", progress.WordID(), err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportProgressCSV restores FSRS cards from ExportProgressCSV output, matching words by their English
+// and Dutch text. Existing progress for a matched word is replaced; rows for words this instance
+// doesn't have are skipped. Every row is validated before anything is saved.
+func (uc *LearningUseCase) ImportProgressCSV(ctx context.Context, userID user.ID, data []byte) (*ProgressImportResult, error) {
+	rows, err := parseProgressCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := uc.vocabularyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary: %w", err)
+	}
+	wordsByText := make(map[string]vocabulary.ID, len(words))
+	for _, word := range words {
+		wordsByText[progressWordKey(word.English(), word.Dutch())] = word.ID()
+	}
+
+	result := &ProgressImportResult{}
+	for _, row := range rows {
+		wordID, ok := wordsByText[progressWordKey(row.english, row.dutch)]
+		if !ok {
+			result.Skipped++
+			continue
+		}
+
+		progress, err := uc.learningRepo.FindProgress(ctx, userID, wordID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find progress: %w", err)
+		}
+
+		if progress == nil {
+			progress = learning.NewUserProgress(userID, wordID)
+			progress.RestoreCard(row.card)
+			err = uc.learningRepo.SaveProgress(ctx, progress)
+		} else {
+			progress.RestoreCard(row.card)
+			err = uc.learningRepo.UpdateProgress(ctx, progress)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save progress for %q: %w", row.dutch, err)
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+// progressWordKey identifies a word by its text, ignoring case and surrounding spaces
+func progressWordKey(english, dutch string) string {
+	return strings.ToLower(strings.TrimSpace(english)) + "\x00" + strings.ToLower(strings.TrimSpace(dutch))
+}
+
+// parseProgressCSV reads and validates a progress CSV, reporting the first bad line
+func parseProgressCSV(r io.Reader) ([]progressCSVRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(progressCSVHeader)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	for i, column := range progressCSVHeader {
+		if strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")) != column {
+			return nil, fmt.Errorf("unexpected header: want %s", strings.Join(progressCSVHeader, ","))
+		}
+	}
+
+	var rows []progressCSVRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row, err := parseProgressCSVRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseProgressCSVRecord validates one progress CSV record, in progressCSVHeader order
+func parseProgressCSVRecord(record []string) (progressCSVRow, error) {
+	row := progressCSVRow{english: record[0], dutch: record[1], card: learning.NewFSRSCard()}
+	if strings.TrimSpace(row.english) == "" || strings.TrimSpace(row.dutch) == "" {
+		return row, errors.New("missing word text")
+	}
+
+	state := learning.State(strings.TrimSpace(record[2]))
+	switch state {
+	case learning.StateNew, learning.StateLearning, learning.StateReview, learning.StateRelearning:
+	default:
+		return row, fmt.Errorf("invalid state %q", record[2])
+	}
+
+	stability, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+	if err != nil || math.IsNaN(stability) || math.IsInf(stability, 0) || stability < 0 {
+		return row, fmt.Errorf("invalid stability %q", record[3])
+	}
+	difficulty, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+	if err != nil || !(difficulty >= 1 && difficulty <= 10) {
+		return row, fmt.Errorf("invalid difficulty %q (must be 1-10)", record[4])
+	}
+	dueDate, err := time.Parse(time.RFC3339, strings.TrimSpace(record[5]))
+	if err != nil {
+		return row, fmt.Errorf("invalid due_date %q", record[5])
+	}
+	var lastReview time.Time
+	if value := strings.TrimSpace(record[6]); value != "" {
+		if lastReview, err = time.Parse(time.RFC3339, value); err != nil {
+			return row, fmt.Errorf("invalid last_review %q", record[6])
+		}
+	}
+	reviewCount, err := strconv.Atoi(strings.TrimSpace(record[7]))
+	if err != nil || reviewCount < 0 {
+		return row, fmt.Errorf("invalid review_count %q", record[7])
+	}
+	lapses, err := strconv.Atoi(strings.TrimSpace(record[8]))
+	if err != nil || lapses < 0 {
+		return row, fmt.Errorf("invalid lapses %q", record[8])
+	}
+
+	row.card.SetState(state)
+	row.card.SetStability(stability)
+	row.card.SetDifficulty(difficulty)
+	row.card.SetDueDate(dueDate)
+	row.card.SetLastReview(lastReview)
+	row.card.SetReviewCount(reviewCount)
+	row.card.SetLapses(lapses)
+	return row, nil
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestProgressCSV_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	source := newLearningFixture(t, nil)
+	house := source.addWord(t, "house", "huis", "home")
+	tree := source.addWord(t, "tree", "boom", "nature")
+	ghost := source.addWord(t, "ghost", "spook", "basics")
+	exported := map[string]*learning.FSRSCard{}
+	for i, word := range []*vocabulary.Word{house, tree, ghost} {
+		progress := source.addReviewCard(t, word, due.Add(time.Duration(i)*time.Hour))
+		progress.FSRSCard().SetDifficulty(float64(4 + i))
+		progress.FSRSCard().SetLapses(i)
+		if err := source.learningRepo.UpdateProgress(ctx, progress); err != nil {
+			t.Fatalf("failed to update progress: %v", err)
+		}
+		exported[word.Dutch()] = progress.FSRSCard()
+	}
+
+	data, err := source.uc.ExportProgressCSV(ctx, source.userID)
+	if err != nil {
+		t.Fatalf("ExportProgressCSV: %v", err)
+	}
+
+	// The other instance spells a word differently and doesn't have the ghost at all
+	target := newLearningFixture(t, nil)
+	targetWords := []*vocabulary.Word{target.addWord(t, "House", " Huis ", "home"), target.addWord(t, "tree", "boom", "nature")}
+	target.addReviewCard(t, targetWords[1], time.Now().Add(-time.Hour))
+
+	for _, run := range []string{"first import", "repeated import"} {
+		result, err := target.uc.ImportProgressCSV(ctx, target.userID, data)
+		if err != nil {
+			t.Fatalf("%s: ImportProgressCSV: %v", run, err)
+		}
+		if result.Imported != 2 || result.Skipped != 1 {
+			t.Errorf("%s: imported %d, skipped %d; want 2 and 1", run, result.Imported, result.Skipped)
+		}
+
+		for _, word := range targetWords {
+			got := target.progress(t, word).FSRSCard()
+			want := exported[strings.TrimSpace(strings.ToLower(word.Dutch()))]
+			if got.State() != want.State() || got.Stability() != want.Stability() || got.Difficulty() != want.Difficulty() ||
+				got.ReviewCount() != want.ReviewCount() || got.Lapses() != want.Lapses() {
+				t.Errorf("%s: %s card = %+v, want %+v", run, word.Dutch(), got, want)
+			}
+			if !got.DueDate().Equal(want.DueDate()) || !got.LastReview().Equal(want.LastReview()) {
+				t.Errorf("%s: %s due %v (last review %v), want %v (%v)", run, word.Dutch(),
+					got.DueDate(), got.LastReview(), want.DueDate(), want.LastReview())
+			}
+		}
+	}
+}
+
+func TestImportProgressCSV_RejectsInvalidRows(t *testing.T) {
+	const header = "english,dutch,state,stability,difficulty,due_date,last_review,review_count,lapses\n"
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty file", "", "empty"},
+		{"wrong header", "dutch,english\n", "invalid CSV"},
+		{"unknown state", header + "house,huis,forgotten,5,5,2024-03-20T10:00:00Z,,1,0\n", "line 2: invalid state"},
+		{"negative stability", header + "house,huis,review,-1,5,2024-03-20T10:00:00Z,,1,0\n", "line 2: invalid stability"},
+		{"difficulty out of range", header + "house,huis,review,5,11,2024-03-20T10:00:00Z,,1,0\n", "line 2: invalid difficulty"},
+		{"bad due date", header + "house,huis,review,5,5,tomorrow,,1,0\n", "line 2: invalid due_date"},
+		{"fractional review count", header + "house,huis,review,5,5,2024-03-20T10:00:00Z,,1.5,0\n", "line 2: invalid review_count"},
+		{"missing word text", header + "house, ,review,5,5,2024-03-20T10:00:00Z,,1,0\n", "line 2: missing word text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLearningFixture(t, nil)
+			word := f.addWord(t, "house", "huis", "home")
+
+			_, err := f.uc.ImportProgressCSV(context.Background(), f.userID, []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ImportProgressCSV error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+			// Nothing is saved from a file with a bad row
+			if progress, err := f.learningRepo.FindProgress(context.Background(), f.userID, word.ID()); err != nil || progress != nil {
+				t.Errorf("progress after a failed import = %v, %v; want none", progress, err)
+			}
+		})
+	}
+}
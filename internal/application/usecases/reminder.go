@@ -3,35 +3,89 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"golang.org/x/sync/errgroup"
+
 	"dutch-learning-bot/internal/domain/learning"
 	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/infrastructure/telegram"
 )
 
+// ReminderLearnCallback is the callback data of the reminder's "Start Learning" button
+const ReminderLearnCallback = "remind_learn"
+
 // ReminderConfig holds configuration for the reminder system
 type ReminderConfig struct {
 	// How often to check for reminders
 	CheckInterval time.Duration
-	// Minimum time between reminders for the same user
-	MinReminderInterval time.Duration
-	// Hours of day when reminders are sent (24-hour format)
+	// Hours of day, in each user's time zone, when no reminders are sent (24-hour format)
 	QuietHoursStart int
 	QuietHoursEnd   int
 	// Maximum reminders per day per user
 	MaxRemindersPerDay int
+	// Minimum number of reviews needed before a user's most active hour is trusted
+	BestTimeMinReviews int
+	// How many hours before the most active hour reminders are preferred
+	BestTimeLeadHours int
+	// Maximum number of users processed in parallel during a reminder sweep
+	MaxConcurrentReminders int
+	// Optional text/template for reminder messages; empty uses the built-in wording.
+	// Available fields are those of ReminderMessageData.
+	MessageTemplate string
+}
+
+// ReminderMessageData holds the values available to a custom reminder message template
+type ReminderMessageData struct {
+	FirstName   string
+	Greeting    string
+	DueWords    int
+	ReviewWords int
+}
+
+// Validate checks that the reminder configuration is usable
+func (c *ReminderConfig) Validate() error {
+	if c.MessageTemplate == "" {
+		return nil
+	}
+
+	tmpl, err := parseReminderTemplate(c.MessageTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid reminder message template: %w", err)
+	}
+
+	// Render with sample data to catch references to unknown fields
+	sample := ReminderMessageData{FirstName: "there", Greeting: "Hello", DueWords: 1, ReviewWords: 1}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return fmt.Errorf("invalid reminder message template: %w", err)
+	}
+
+	return nil
+}
+
+// parseReminderTemplate parses a custom reminder message template. Startup validation and sending
+// share it, so a template behaves the same in both.
+func parseReminderTemplate(text string) (*template.Template, error) {
+	return template.New("reminder").Option("missingkey=error").Parse(text)
 }
 
 // DefaultReminderConfig returns sensible defaults for reminders
 func DefaultReminderConfig() *ReminderConfig {
 	return &ReminderConfig{
-		CheckInterval:       1 * time.Minute, // Check every minute to support minimum interval
-		MinReminderInterval: 4 * time.Hour,   // Don't remind more than once every 4 hours
-		QuietHoursStart:     22,              // 10 PM
-		QuietHoursEnd:       8,               // 8 AM
-		MaxRemindersPerDay:  3,               // Max 3 reminders per day
+		CheckInterval:          1 * time.Minute, // Check every minute to support minimum interval
+		QuietHoursStart:        22,              // 10 PM
+		QuietHoursEnd:          8,               // 8 AM
+		MaxRemindersPerDay:     3,               // Max 3 reminders per day
+		BestTimeMinReviews:     20,              // Need 20 reviews to learn a user's best time
+		BestTimeLeadHours:      2,               // Prefer reminders up to 2 hours before it
+		MaxConcurrentReminders: 3,               // Stay well under Telegram's rate limits
 	}
 }
 
@@ -42,7 +96,10 @@ type ReminderUseCase struct {
 	learningRepo    learning.Repository
 	preferencesRepo user.PreferencesRepository
 	config          *ReminderConfig
+	messageTemplate *template.Template
 	reminderState   map[user.ID]*UserReminderState
+	stateMu         sync.Mutex
+	checkMu         sync.Mutex // Serializes reminder checks, so a manual check can't double-send with the ticker
 }
 
 // UserReminderState tracks reminder state for each user
@@ -64,12 +121,23 @@ func NewReminderUseCase(
 		config = DefaultReminderConfig()
 	}
 
+	var messageTemplate *template.Template
+	if config.MessageTemplate != "" {
+		tmpl, err := parseReminderTemplate(config.MessageTemplate)
+		if err != nil {
+			log.Printf("Invalid reminder message template, using built-in messages: %v", err)
+		} else {
+			messageTemplate = tmpl
+		}
+	}
+
 	return &ReminderUseCase{
 		bot:             bot,
 		userRepo:        userRepo,
 		learningRepo:    learningRepo,
 		preferencesRepo: preferencesRepo,
 		config:          config,
+		messageTemplate: messageTemplate,
 		reminderState:   make(map[user.ID]*UserReminderState),
 	}
 }
@@ -87,46 +155,82 @@ func (uc *ReminderUseCase) StartReminderService(ctx context.Context) {
 			log.Println("Reminder service stopping...")
 			return
 		case <-ticker.C:
-			uc.checkAndSendReminders(ctx)
+			if _, err := uc.checkAndSendReminders(ctx, false); err != nil {
+				log.Printf("Reminder check failed: %v", err)
+			}
 		}
 	}
 }
 
-// checkAndSendReminders checks for users needing reminders and sends them
-func (uc *ReminderUseCase) checkAndSendReminders(ctx context.Context) {
+// ReminderCheckResult summarizes one reminder check
+type ReminderCheckResult struct {
+	DryRun        bool
+	UsersChecked  int
+	RemindersDue  int // Users a reminder was due for
+	RemindersSent int // Always 0 for a dry run
+}
+
+// CheckRemindersNow runs a reminder check immediately instead of waiting for the ticker.
+// A dry run only counts the users a reminder is due for, without sending anything.
+func (uc *ReminderUseCase) CheckRemindersNow(ctx context.Context, dryRun bool) (*ReminderCheckResult, error) {
+	return uc.checkAndSendReminders(ctx, dryRun)
+}
+
+// checkAndSendReminders checks for users needing reminders and sends them, unless this is a dry run
+func (uc *ReminderUseCase) checkAndSendReminders(ctx context.Context, dryRun bool) (*ReminderCheckResult, error) {
+	uc.checkMu.Lock()
+	defer uc.checkMu.Unlock()
+
 	log.Printf("Checking for users needing reminders...")
 
 	// Get all users who have used the bot (have progress records)
 	users, err := uc.getUsersWithProgress(ctx)
 	if err != nil {
-		log.Printf("Failed to get users with progress: %v", err)
-		return
+		return nil, fmt.Errorf("failed to get users with progress: %w", err)
+	}
+
+	maxConcurrent := uc.config.MaxConcurrentReminders
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
 	}
 
-	remindersSent := 0
+	// Process users in parallel, at most maxConcurrent at a time
+	var remindersDue, remindersSent atomic.Int64
+	var group errgroup.Group
+	group.SetLimit(maxConcurrent)
+
 	for _, u := range users {
-		if uc.shouldSendReminder(ctx, u) {
-			if uc.sendReminderToUser(ctx, u) {
-				remindersSent++
+		u := u
+		group.Go(func() error {
+			if !uc.shouldSendReminder(ctx, u) {
+				return nil
 			}
-		}
+			remindersDue.Add(1)
+			if !dryRun && uc.sendReminderToUser(ctx, u) {
+				remindersSent.Add(1)
+			}
+			return nil
+		})
 	}
+	// A failed reminder is logged and doesn't stop the others, so there's no error to report
+	_ = group.Wait()
 
-	if remindersSent > 0 {
-		log.Printf("Sent %d smart reminders", remindersSent)
+	if sent := remindersSent.Load(); sent > 0 {
+		log.Printf("Sent %d smart reminders", sent)
 	}
+
+	return &ReminderCheckResult{
+		DryRun:        dryRun,
+		UsersChecked:  len(users),
+		RemindersDue:  int(remindersDue.Load()),
+		RemindersSent: int(remindersSent.Load()),
+	}, nil
 }
 
 // shouldSendReminder determines if a user should receive a reminder
 func (uc *ReminderUseCase) shouldSendReminder(ctx context.Context, u *user.User) bool {
-	now := time.Now()
 	userID := u.ID()
 
-	// Check quiet hours
-	if uc.isQuietTime(now) {
-		return false
-	}
-
 	// Get user preferences
 	preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID)
 	if err != nil {
@@ -134,26 +238,37 @@ func (uc *ReminderUseCase) shouldSendReminder(ctx context.Context, u *user.User)
 		return false
 	}
 
+	// Quiet hours, digest hours and days are all the user's local ones
+	now := time.Now().In(preferences.Location())
+
 	// Check if reminders are enabled
 	if !preferences.SmartRemindersEnabled() {
 		return false
 	}
 
-	// Get or create reminder state for this user
-	state, exists := uc.reminderState[userID]
-	if !exists {
-		state = &UserReminderState{
-			LastCheckDate: now.AddDate(0, 0, -1), // Set to yesterday to reset counter
-		}
-		uc.reminderState[userID] = state
+	// Digest users get exactly one message a day at the hour they chose, even inside quiet hours
+	if preferences.GetReminderMode() == user.ReminderModeDailyDigest {
+		return isDigestDue(preferences, now)
 	}
 
-	// Reset daily counter if it's a new day
-	if !isSameDay(state.LastCheckDate, now) {
-		state.RemindersToday = 0
-		state.LastCheckDate = now
+	// Check quiet hours
+	if uc.isQuietTime(now) {
+		return false
 	}
 
+	// A reminder the user scheduled themselves skips the usual pacing, as long as there is something to review
+	if remindAt, ok := preferences.GetNextReminderAt(); ok && !now.Before(remindAt) {
+		stats, err := uc.learningRepo.GetUserStats(ctx, userID, preferences.ReviewAheadWindow())
+		if err != nil {
+			log.Printf("Failed to get stats for user %d: %v", userID, err)
+			return false
+		}
+		return remindableDueWords(stats) > 0
+	}
+
+	// Get or create reminder state for this user
+	state := uc.getReminderState(userID, now)
+
 	// Check if we've exceeded daily limit
 	if state.RemindersToday >= uc.config.MaxRemindersPerDay {
 		return false
@@ -168,14 +283,15 @@ func (uc *ReminderUseCase) shouldSendReminder(ctx context.Context, u *user.User)
 	}
 
 	// Check if user has due words
-	stats, err := uc.learningRepo.GetUserStats(ctx, userID)
+	stats, err := uc.learningRepo.GetUserStats(ctx, userID, preferences.ReviewAheadWindow())
 	if err != nil {
 		log.Printf("Failed to get stats for user %d: %v", userID, err)
 		return false
 	}
 
-	// Only remind if there are actually due words
-	if stats.DueWords == 0 {
+	// Only remind if there are actually due words the user wants reminders about
+	remindableDueWords := remindableDueWords(stats)
+	if remindableDueWords <= 0 {
 		return false
 	}
 
@@ -193,63 +309,115 @@ func (uc *ReminderUseCase) shouldSendReminder(ctx context.Context, u *user.User)
 		return true
 	}
 
+	// Prefer sending shortly before the hour the user usually studies at. When quiet hours cover
+	// part of that window, or it's already over today, the usual pacing below applies instead.
+	if bestHour, ok := uc.GetMostActiveHour(ctx, userID, now.Location()); ok && uc.isLeadWindowReachable(now.Hour(), bestHour) {
+		if !isWithinLeadWindow(now.Hour(), bestHour, uc.config.BestTimeLeadHours) {
+			return false
+		}
+	}
+
 	// For users active within last 3 days, use a more sophisticated check
 	// Consider the number of due words and time since last reminder
 	hoursSinceLastReminder := now.Sub(state.LastReminderSent).Hours()
 
 	// If user has many due words (5+), remind sooner
-	if stats.DueWords >= 5 && hoursSinceLastReminder >= float64(reminderInterval.Hours())/2 {
+	if remindableDueWords >= 5 && hoursSinceLastReminder >= float64(reminderInterval.Hours())/2 {
 		return true
 	}
 
 	// If user has some due words (1-4), remind after normal interval
-	if stats.DueWords >= 1 && hoursSinceLastReminder >= float64(reminderInterval.Hours()) {
+	if remindableDueWords >= 1 && hoursSinceLastReminder >= float64(reminderInterval.Hours()) {
 		return true
 	}
 
 	return false
 }
 
+// remindableDueWords counts the due words worth a reminder, leaving out those the user muted as low priority
+func remindableDueWords(stats *learning.UserStats) int {
+	return stats.DueWords - stats.LowPriorityDueWords
+}
+
 // sendReminderToUser sends a smart reminder to a specific user
 func (uc *ReminderUseCase) sendReminderToUser(ctx context.Context, u *user.User) bool {
 	userID := u.ID()
 
 	// Get current stats
-	stats, err := uc.learningRepo.GetUserStats(ctx, userID)
+	var reviewAhead time.Duration
+	digest := false
+	now := time.Now().UTC()
+	if preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID); err == nil {
+		reviewAhead = preferences.ReviewAheadWindow()
+		digest = preferences.GetReminderMode() == user.ReminderModeDailyDigest
+		now = now.In(preferences.Location())
+	}
+	stats, err := uc.learningRepo.GetUserStats(ctx, userID, reviewAhead)
 	if err != nil {
 		log.Printf("Failed to get stats for user %d: %v", userID, err)
 		return false
 	}
 
 	// Create personalized reminder message
-	reminderText := uc.createReminderMessage(u, stats)
+	reminderText := uc.createReminderMessage(u, stats, now)
+	if digest {
+		reminderText = createDigestMessage(u, stats)
+	}
 
-	// Send the reminder
+	// Send the reminder with a button that jumps straight into a question
 	telegramID := int64(u.TelegramID())
-	err = uc.bot.SendMessageWithMarkdown(telegramID, reminderText)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📚 Start Learning", ReminderLearnCallback),
+		),
+	)
+	// Many reminders go out at once, so rate limited sends are retried rather than dropped
+	err = telegram.RetryRateLimited(func() error {
+		return uc.bot.SendMessageWithKeyboard(telegramID, reminderText, keyboard)
+	})
 	if err != nil {
 		log.Printf("Failed to send reminder to user %d (telegram: %d): %v", userID, telegramID, err)
 		return false
 	}
 
-	// Update reminder state
-	state := uc.reminderState[userID]
+	// A scheduled reminder has now been delivered
+	if preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID); err == nil {
+		if remindAt, ok := preferences.GetNextReminderAt(); ok && !time.Now().Before(remindAt) {
+			preferences.ClearNextReminderAt()
+			if err := uc.preferencesRepo.SavePreferences(ctx, preferences); err != nil {
+				log.Printf("Failed to clear scheduled reminder for user %d: %v", userID, err)
+			}
+		}
+	}
+
+	// Record the digest in preferences, so a restart the same day doesn't send another
+	if digest {
+		if preferences, err := uc.preferencesRepo.FindPreferences(ctx, userID); err == nil {
+			preferences.SetLastDigestAt(time.Now())
+			if err := uc.preferencesRepo.SavePreferences(ctx, preferences); err != nil {
+				log.Printf("Failed to record daily digest for user %d: %v", userID, err)
+			}
+		}
+	}
+
+	// Update reminder state. Digest and scheduled reminders skip getReminderState, so the entry may not exist yet
+	uc.stateMu.Lock()
+	state := uc.reminderStateLocked(userID, now)
 	state.LastReminderSent = time.Now()
 	state.RemindersToday++
+	uc.stateMu.Unlock()
 
-	log.Printf("Sent smart reminder to user %d (%s) - %d due words", userID, u.FirstName(), stats.DueWords)
+	log.Printf("Sent smart reminder to user %d (%s) - %d due words", userID, u.DisplayName(), stats.DueWords)
 	return true
 }
 
-// createReminderMessage creates a personalized reminder message
-func (uc *ReminderUseCase) createReminderMessage(u *user.User, stats *learning.UserStats) string {
-	firstName := u.FirstName()
-	if firstName == "" {
-		firstName = "there"
-	}
+// createReminderMessage creates a personalized reminder message, greeting the user for the time of day
+// at now, which is in the user's time zone
+func (uc *ReminderUseCase) createReminderMessage(u *user.User, stats *learning.UserStats, now time.Time) string {
+	firstName := u.DisplayName()
 
 	// Determine time of day greeting
-	hour := time.Now().Hour()
+	hour := now.Hour()
 	var greeting string
 	switch {
 	case hour < 12:
@@ -260,6 +428,23 @@ func (uc *ReminderUseCase) createReminderMessage(u *user.User, stats *learning.U
 		greeting = "Good evening"
 	}
 
+	// Use the configured template if there is one
+	if uc.messageTemplate != nil {
+		data := ReminderMessageData{
+			FirstName:   firstName,
+			Greeting:    greeting,
+			DueWords:    stats.DueWords,
+			ReviewWords: stats.ReviewWords,
+		}
+
+		var sb strings.Builder
+		err := uc.messageTemplate.Execute(&sb, data)
+		if err == nil {
+			return sb.String()
+		}
+		log.Printf("Failed to render reminder template, using built-in message: %v", err)
+	}
+
 	// Create personalized message based on due words count
 	var message string
 	switch {
@@ -304,6 +489,120 @@ func (uc *ReminderUseCase) createReminderMessage(u *user.User, stats *learning.U
 	return message
 }
 
+// isDigestDue checks if the user's daily digest hour has passed and no digest was sent yet today
+func isDigestDue(preferences *user.UserPreferences, now time.Time) bool {
+	if now.Hour() < preferences.GetDigestHour() {
+		return false
+	}
+	lastSent, ok := preferences.GetLastDigestAt()
+	return !ok || !isSameDay(lastSent.In(now.Location()), now)
+}
+
+// createDigestMessage creates the once-a-day summary sent to daily digest users
+func createDigestMessage(u *user.User, stats *learning.UserStats) string {
+	message := fmt.Sprintf("🗞 **Your daily Dutch summary**, %s\n\n"+
+		"⏰ Reviews due: %d\n"+
+		"🆕 New words available: %d\n"+
+		"✅ Words mastered: %d\n\n",
+		u.DisplayName(), stats.DueWords, stats.NewWords, stats.ReviewWords)
+
+	if stats.DueWords > 0 {
+		return message + "Use /learn to work through today's reviews."
+	}
+	return message + "Nothing is due - use /learn to pick up some new words."
+}
+
+// getReminderState returns a snapshot of the user's reminder state, creating it if needed
+// and resetting the daily counter when a new day has started
+func (uc *ReminderUseCase) getReminderState(userID user.ID, now time.Time) UserReminderState {
+	uc.stateMu.Lock()
+	defer uc.stateMu.Unlock()
+
+	return *uc.reminderStateLocked(userID, now)
+}
+
+// reminderStateLocked returns the user's reminder state, creating it if needed and resetting
+// the daily counter when a new day has started. The caller must hold stateMu.
+func (uc *ReminderUseCase) reminderStateLocked(userID user.ID, now time.Time) *UserReminderState {
+	state, exists := uc.reminderState[userID]
+	if !exists {
+		state = &UserReminderState{
+			LastCheckDate: now.AddDate(0, 0, -1), // Set to yesterday to reset counter
+		}
+		uc.reminderState[userID] = state
+	}
+
+	// Reset daily counter if it's a new day
+	if !isSameDay(state.LastCheckDate, now) {
+		state.RemindersToday = 0
+		state.LastCheckDate = now
+	}
+
+	return state
+}
+
+// reviewTimesSampleSize is how many recent reviews are used to learn a user's active hour
+const reviewTimesSampleSize = 500
+
+// GetMostActiveHour returns the hour of day (0-23), in the given location, in which the user reviews most often.
+// The second return value is false when there is too little history to tell.
+func (uc *ReminderUseCase) GetMostActiveHour(ctx context.Context, userID user.ID, location *time.Location) (int, bool) {
+	reviewTimes, err := uc.learningRepo.FindRecentReviewTimes(ctx, userID, reviewTimesSampleSize)
+	if err != nil {
+		log.Printf("Failed to get review times for user %d: %v", userID, err)
+		return 0, false
+	}
+
+	if len(reviewTimes) < uc.config.BestTimeMinReviews {
+		return 0, false
+	}
+
+	return modalHour(reviewTimes, location)
+}
+
+// modalHour finds the most common hour in the given location among the timestamps
+func modalHour(times []time.Time, location *time.Location) (int, bool) {
+	if len(times) == 0 {
+		return 0, false
+	}
+
+	var counts [24]int
+	for _, t := range times {
+		counts[t.In(location).Hour()]++
+	}
+
+	bestHour := 0
+	for hour := 1; hour < 24; hour++ {
+		if counts[hour] > counts[bestHour] {
+			bestHour = hour
+		}
+	}
+
+	return bestHour, true
+}
+
+// isWithinLeadWindow checks if hour falls in the leadHours before (and including) targetHour
+func isWithinLeadWindow(hour, targetHour, leadHours int) bool {
+	hoursBefore := (targetHour - hour + 24) % 24
+	return hoursBefore <= leadHours
+}
+
+// isLeadWindowReachable checks that a reminder can still be sent in the lead window before targetHour
+// today: none of the window's hours may be quiet, and the window mustn't have ended before hour
+func (uc *ReminderUseCase) isLeadWindowReachable(hour, targetHour int) bool {
+	reachable := false
+	for before := 0; before <= uc.config.BestTimeLeadHours; before++ {
+		windowHour := (targetHour - before + 24) % 24
+		if uc.isQuietHour(windowHour) {
+			return false
+		}
+		if windowHour >= hour {
+			reachable = true
+		}
+	}
+	return reachable
+}
+
 // getUsersWithProgress gets all users who have made progress (have used the bot)
 func (uc *ReminderUseCase) getUsersWithProgress(ctx context.Context) ([]*user.User, error) {
 	// This is a simplified approach - in a real implementation, you might want
@@ -336,19 +635,22 @@ func (uc *ReminderUseCase) getAllUsersWithLearningProgress(ctx context.Context)
 	return users, nil
 }
 
-// isQuietTime checks if current time is within quiet hours
+// isQuietTime checks if t is within quiet hours, judged by the hour in t's location
 func (uc *ReminderUseCase) isQuietTime(t time.Time) bool {
-	hour := t.Hour()
+	return uc.isQuietHour(t.Hour())
+}
+
+// isQuietHour checks if an hour of the day falls within quiet hours
+func (uc *ReminderUseCase) isQuietHour(hour int) bool {
 	start := uc.config.QuietHoursStart
 	end := uc.config.QuietHoursEnd
 
 	if start <= end {
-		// Normal case: e.g., 22:00 to 08:00 next day
-		return hour >= start || hour < end
-	} else {
-		// Quiet hours cross midnight: e.g., 10:00 to 06:00
+		// Same-day quiet hours: e.g., 13:00 to 15:00
 		return hour >= start && hour < end
 	}
+	// Quiet hours cross midnight: e.g., 22:00 to 08:00 next day
+	return hour >= start || hour < end
 }
 
 // isSameDay checks if two times are on the same day
@@ -358,19 +660,40 @@ func isSameDay(t1, t2 time.Time) bool {
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
 
+// ReminderStats holds reminder service statistics for debugging
+type ReminderStats struct {
+	UsersTracked       int
+	RemindersSentToday int
+	Config             ReminderConfig
+}
+
 // GetReminderStats returns statistics about reminders for debugging
-func (uc *ReminderUseCase) GetReminderStats() map[string]interface{} {
-	stats := make(map[string]interface{})
-	stats["total_users_tracked"] = len(uc.reminderState)
-	stats["config"] = uc.config
+func (uc *ReminderUseCase) GetReminderStats() *ReminderStats {
+	uc.stateMu.Lock()
+	defer uc.stateMu.Unlock()
+
+	stats := &ReminderStats{
+		UsersTracked: len(uc.reminderState),
+		Config:       *uc.config,
+	}
 
-	todayReminders := 0
+	now := time.Now()
 	for _, state := range uc.reminderState {
-		if isSameDay(state.LastCheckDate, time.Now()) {
-			todayReminders += state.RemindersToday
+		if isSameDay(state.LastCheckDate, now) {
+			stats.RemindersSentToday += state.RemindersToday
 		}
 	}
-	stats["reminders_sent_today"] = todayReminders
 
 	return stats
 }
+
+// ResetReminderState forgets all in-memory reminder pacing, as if the service had just started.
+// It waits for a running reminder check to finish, so the check never sees the state swapped underneath it.
+func (uc *ReminderUseCase) ResetReminderState() {
+	uc.checkMu.Lock()
+	defer uc.checkMu.Unlock()
+	uc.stateMu.Lock()
+	defer uc.stateMu.Unlock()
+
+	uc.reminderState = make(map[user.ID]*UserReminderState)
+}
@@ -0,0 +1,732 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata" // Time zone tests must not depend on the host's zoneinfo
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+	"dutch-learning-bot/internal/infrastructure/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTelegramAPI answers every Bot API call successfully and records the methods called
+type fakeTelegramAPI struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (f *fakeTelegramAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.methods = append(f.methods, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot","message_id":1,"date":0,"chat":{"id":1,"type":"private"}}}`))
+}
+
+func (f *fakeTelegramAPI) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, m := range f.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func newTestBot(t *testing.T) (*telegram.Bot, *fakeTelegramAPI) {
+	t.Helper()
+
+	fake := &fakeTelegramAPI{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("test-token", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatalf("failed to create bot API: %v", err)
+	}
+	return telegram.NewBotWithAPI(api), fake
+}
+
+type fakePreferencesRepo struct {
+	user.PreferencesRepository
+	mu    sync.Mutex
+	prefs *user.UserPreferences
+}
+
+func (r *fakePreferencesRepo) FindPreferences(ctx context.Context, userID user.ID) (*user.UserPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.prefs, nil
+}
+
+func (r *fakePreferencesRepo) SavePreferences(ctx context.Context, preferences *user.UserPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefs = preferences
+	return nil
+}
+
+// preferencesByUserRepo keeps separate preferences for each user
+type preferencesByUserRepo struct {
+	user.PreferencesRepository
+	mu    sync.Mutex
+	prefs map[user.ID]*user.UserPreferences
+}
+
+func (r *preferencesByUserRepo) FindPreferences(ctx context.Context, userID user.ID) (*user.UserPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.prefs[userID], nil
+}
+
+func (r *preferencesByUserRepo) SavePreferences(ctx context.Context, preferences *user.UserPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefs[preferences.UserID()] = preferences
+	return nil
+}
+
+type fakeLearningRepo struct {
+	learning.Repository
+	stats       *learning.UserStats
+	reviewTimes []time.Time
+	userIDs     []user.ID
+}
+
+type fakeUserRepo struct {
+	user.Repository
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	u := user.NewUser(user.TelegramID(id), "", "Learner", "", "en")
+	u.SetID(id)
+	return u, nil
+}
+
+// concurrencyTrackingPreferencesRepo records how many preference lookups run at once
+type concurrencyTrackingPreferencesRepo struct {
+	user.PreferencesRepository
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (r *concurrencyTrackingPreferencesRepo) FindPreferences(ctx context.Context, userID user.ID) (*user.UserPreferences, error) {
+	r.mu.Lock()
+	r.running++
+	if r.running > r.peak {
+		r.peak = r.running
+	}
+	r.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+
+	// Reminders off, so the check stops after the lookup
+	prefs := user.NewUserPreferences(userID)
+	prefs.SetSmartRemindersEnabled(false)
+	return prefs, nil
+}
+
+func (r *fakeLearningRepo) GetUserStats(ctx context.Context, userID user.ID, reviewAhead time.Duration) (*learning.UserStats, error) {
+	return r.stats, nil
+}
+
+func (r *fakeLearningRepo) GetUsersWithProgress(ctx context.Context) ([]user.ID, error) {
+	return r.userIDs, nil
+}
+
+func (r *fakeLearningRepo) FindRecentReviewTimes(ctx context.Context, userID user.ID, limit int) ([]time.Time, error) {
+	return r.reviewTimes, nil
+}
+
+func newTestReminderUseCase(t *testing.T, prefs *user.UserPreferences, config *ReminderConfig) (*ReminderUseCase, *fakeTelegramAPI) {
+	t.Helper()
+
+	bot, fake := newTestBot(t)
+	uc := NewReminderUseCase(
+		bot,
+		nil,
+		&fakeLearningRepo{stats: &learning.UserStats{TotalWords: 10, DueWords: 3}},
+		&fakePreferencesRepo{prefs: prefs},
+		config,
+	)
+	return uc, fake
+}
+
+func TestSendReminderToUser_DigestWithoutPriorState(t *testing.T) {
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	prefs := user.NewUserPreferences(u.ID())
+	prefs.SetReminderMode(user.ReminderModeDailyDigest)
+	prefs.SetDigestHour(0)
+
+	uc, fake := newTestReminderUseCase(t, prefs, nil)
+
+	// Digest users never reach getReminderState, so there is no in-memory state yet
+	if !uc.shouldSendReminder(context.Background(), u) {
+		t.Fatal("expected the digest to be due")
+	}
+	if _, exists := uc.reminderState[u.ID()]; exists {
+		t.Fatal("expected no reminder state before sending")
+	}
+
+	if !uc.sendReminderToUser(context.Background(), u) {
+		t.Fatal("expected the digest to be sent")
+	}
+	if got := fake.count("sendMessage"); got != 1 {
+		t.Errorf("sendMessage called %d times, want 1", got)
+	}
+
+	state, exists := uc.reminderState[u.ID()]
+	if !exists {
+		t.Fatal("expected reminder state to be created")
+	}
+	if state.RemindersToday != 1 {
+		t.Errorf("RemindersToday = %d, want 1", state.RemindersToday)
+	}
+	if state.LastReminderSent.IsZero() {
+		t.Error("expected LastReminderSent to be recorded")
+	}
+	if _, ok := prefs.GetLastDigestAt(); !ok {
+		t.Error("expected the digest to be recorded in preferences")
+	}
+	if uc.shouldSendReminder(context.Background(), u) {
+		t.Error("expected no second digest the same day")
+	}
+}
+
+func TestSendReminderToUser_ScheduledWithoutPriorState(t *testing.T) {
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	prefs := user.NewUserPreferences(u.ID())
+	prefs.SetNextReminderAt(time.Now().Add(-time.Minute))
+
+	// No quiet hours, so the check doesn't depend on when the test runs
+	config := DefaultReminderConfig()
+	config.QuietHoursStart = 0
+	config.QuietHoursEnd = 0
+	uc, fake := newTestReminderUseCase(t, prefs, config)
+
+	// After a restart the scheduled reminder is due but nothing is tracked in memory
+	if !uc.shouldSendReminder(context.Background(), u) {
+		t.Fatal("expected the scheduled reminder to be due")
+	}
+	if _, exists := uc.reminderState[u.ID()]; exists {
+		t.Fatal("expected no reminder state before sending")
+	}
+
+	if !uc.sendReminderToUser(context.Background(), u) {
+		t.Fatal("expected the scheduled reminder to be sent")
+	}
+	if got := fake.count("sendMessage"); got != 1 {
+		t.Errorf("sendMessage called %d times, want 1", got)
+	}
+	if state, exists := uc.reminderState[u.ID()]; !exists || state.RemindersToday != 1 {
+		t.Errorf("expected reminder state with one reminder today, got %+v", state)
+	}
+	if _, ok := prefs.GetNextReminderAt(); ok {
+		t.Error("expected the scheduled reminder to be cleared")
+	}
+}
+
+func TestResetReminderState_DuringCheck(t *testing.T) {
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	prefs := user.NewUserPreferences(u.ID())
+	uc, _ := newTestReminderUseCase(t, prefs, nil)
+	uc.getReminderState(u.ID(), time.Now())
+
+	// A reset requested while a check runs waits for the check to finish
+	uc.checkMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		uc.ResetReminderState()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reset completed while a reminder check was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	uc.checkMu.Unlock()
+	<-done
+
+	if stats := uc.GetReminderStats(); stats.UsersTracked != 0 {
+		t.Errorf("UsersTracked = %d after reset, want 0", stats.UsersTracked)
+	}
+}
+
+func TestIsQuietTime_AcrossTimeZones(t *testing.T) {
+	uc := NewReminderUseCase(nil, nil, nil, nil, nil) // Quiet hours 22:00-08:00
+
+	// 21:30 UTC on a summer day
+	instant := time.Date(2024, 7, 1, 21, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		want     bool
+	}{
+		{"Europe/Amsterdam", true},   // 23:30, quiet
+		{"America/New_York", false},  // 17:30, awake
+		{"Asia/Tokyo", true},         // 06:30 the next morning, quiet until 08:00
+		{"", false},                  // Unset falls back to UTC, 21:30
+		{"Mars/Olympus_Mons", false}, // Unknown falls back to UTC, 21:30
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			prefs := user.NewUserPreferences(1)
+			prefs.SetTimezone(tt.timezone)
+
+			if got := uc.isQuietTime(instant.In(prefs.Location())); got != tt.want {
+				t.Errorf("isQuietTime in %q at %v = %v, want %v", tt.timezone, instant.In(prefs.Location()), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModalHour(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		times    []time.Time
+		location *time.Location
+		want     int
+		wantOK   bool
+	}{
+		{"no reviews", nil, time.UTC, 0, false},
+		{"single review", []time.Time{at(7, 15)}, time.UTC, 7, true},
+		{"most common hour wins", []time.Time{at(7, 0), at(19, 5), at(19, 40), at(19, 59), at(8, 0)}, time.UTC, 19, true},
+		{"ties go to the earliest hour", []time.Time{at(20, 0), at(9, 0)}, time.UTC, 9, true},
+		{"hours are taken in the location", []time.Time{at(18, 0), at(18, 30)}, amsterdam, 19, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := modalHour(tt.times, tt.location)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("modalHour() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsWithinLeadWindow(t *testing.T) {
+	tests := []struct {
+		hour, target, lead int
+		want               bool
+	}{
+		{19, 19, 2, true},
+		{17, 19, 2, true},
+		{16, 19, 2, false},
+		{20, 19, 2, false}, // Just after the active hour
+		{23, 1, 2, true},   // Across midnight
+		{22, 1, 2, false},
+	}
+	for _, tt := range tests {
+		if got := isWithinLeadWindow(tt.hour, tt.target, tt.lead); got != tt.want {
+			t.Errorf("isWithinLeadWindow(%d, %d, %d) = %v, want %v", tt.hour, tt.target, tt.lead, got, tt.want)
+		}
+	}
+}
+
+func TestIsLeadWindowReachable(t *testing.T) {
+	config := DefaultReminderConfig() // Quiet from 22:00 to 08:00, two lead hours
+	uc := NewReminderUseCase(nil, nil, nil, nil, config)
+
+	tests := []struct {
+		name         string
+		hour, target int
+		want         bool
+	}{
+		{"inside the window", 18, 19, true},
+		{"window still ahead", 9, 19, true},
+		{"window over for today", 20, 19, false},
+		{"window entirely quiet", 12, 1, false},
+		{"window partly quiet", 9, 9, false},
+		{"window ends as quiet hours start", 12, 21, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uc.isLeadWindowReachable(tt.hour, tt.target); got != tt.want {
+				t.Errorf("isLeadWindowReachable(%d, %d) = %v, want %v", tt.hour, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldSendReminder_BestHourInQuietHours(t *testing.T) {
+	// The learner always studies deep inside quiet hours, and was last active yesterday
+	now := time.Now().UTC()
+	config := DefaultReminderConfig()
+	config.BestTimeMinReviews = 3
+	config.QuietHoursStart = (now.Hour() + 2) % 24
+	config.QuietHoursEnd = (now.Hour() + 6) % 24
+	studyTime := now.Add(4 * time.Hour)
+	repo := &fakeLearningRepo{
+		stats:       &learning.UserStats{TotalWords: 10, DueWords: 3},
+		reviewTimes: []time.Time{studyTime, studyTime, studyTime},
+	}
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	u.SetLastActive(now.AddDate(0, 0, -1))
+	uc := NewReminderUseCase(nil, nil, repo, &fakePreferencesRepo{prefs: user.NewUserPreferences(u.ID())}, config)
+
+	if !uc.shouldSendReminder(context.Background(), u) {
+		t.Error("expected the usual reminder pacing when the lead window is all quiet hours")
+	}
+}
+
+func TestGetMostActiveHour_NeedsEnoughReviews(t *testing.T) {
+	config := DefaultReminderConfig()
+	config.BestTimeMinReviews = 3
+	repo := &fakeLearningRepo{}
+	uc := NewReminderUseCase(nil, nil, repo, nil, config)
+
+	evening := time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC)
+	repo.reviewTimes = []time.Time{evening, evening}
+	if _, ok := uc.GetMostActiveHour(context.Background(), 1, time.UTC); ok {
+		t.Error("expected no active hour with too few reviews")
+	}
+
+	repo.reviewTimes = append(repo.reviewTimes, evening.Add(time.Minute))
+	if hour, ok := uc.GetMostActiveHour(context.Background(), 1, time.UTC); !ok || hour != 20 {
+		t.Errorf("GetMostActiveHour() = %d, %v, want 20, true", hour, ok)
+	}
+}
+
+func TestCheckAndSendReminders_BoundsConcurrency(t *testing.T) {
+	var userIDs []user.ID
+	for id := user.ID(1); id <= 20; id++ {
+		userIDs = append(userIDs, id)
+	}
+	prefsRepo := &concurrencyTrackingPreferencesRepo{}
+	config := DefaultReminderConfig()
+	config.MaxConcurrentReminders = 3
+	uc := NewReminderUseCase(nil, &fakeUserRepo{}, &fakeLearningRepo{userIDs: userIDs}, prefsRepo, config)
+
+	result, err := uc.CheckRemindersNow(context.Background(), true)
+	if err != nil {
+		t.Fatalf("CheckRemindersNow: %v", err)
+	}
+	if result.UsersChecked != len(userIDs) {
+		t.Errorf("UsersChecked = %d, want %d", result.UsersChecked, len(userIDs))
+	}
+	if prefsRepo.peak > config.MaxConcurrentReminders {
+		t.Errorf("%d users were checked at once, want at most %d", prefsRepo.peak, config.MaxConcurrentReminders)
+	}
+	if prefsRepo.peak < 2 {
+		t.Errorf("users were checked one at a time, want up to %d in parallel", config.MaxConcurrentReminders)
+	}
+}
+
+func TestRemindableDueWords(t *testing.T) {
+	tests := []struct {
+		name             string
+		due, lowPriority int
+		want             int
+	}{
+		{"nothing due", 0, 0, 0},
+		{"only low priority due", 3, 3, 0},
+		{"some muted", 5, 2, 3},
+		{"none muted", 4, 0, 4},
+	}
+	for _, tt := range tests {
+		stats := &learning.UserStats{DueWords: tt.due, LowPriorityDueWords: tt.lowPriority}
+		if got := remindableDueWords(stats); got != tt.want {
+			t.Errorf("%s: remindableDueWords() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShouldSendReminder_OnlyLowPriorityDue(t *testing.T) {
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	prefs := user.NewUserPreferences(u.ID())
+	prefs.SetNextReminderAt(time.Now().Add(-time.Minute))
+
+	config := DefaultReminderConfig()
+	config.QuietHoursStart = 0
+	config.QuietHoursEnd = 0
+	repo := &fakeLearningRepo{stats: &learning.UserStats{DueWords: 2, LowPriorityDueWords: 2}}
+	uc := NewReminderUseCase(nil, nil, repo, &fakePreferencesRepo{prefs: prefs}, config)
+
+	if uc.shouldSendReminder(context.Background(), u) {
+		t.Error("expected no reminder when only low-priority words are due")
+	}
+
+	repo.stats = &learning.UserStats{DueWords: 3, LowPriorityDueWords: 2}
+	if !uc.shouldSendReminder(context.Background(), u) {
+		t.Error("expected a reminder when a word that isn't low priority is due")
+	}
+}
+
+func TestCreateReminderMessage_CustomTemplate(t *testing.T) {
+	config := DefaultReminderConfig()
+	config.MessageTemplate = "{{.Greeting}} {{.FirstName}}: {{.DueWords}} due, {{.ReviewWords}} mastered"
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	uc := NewReminderUseCase(nil, nil, nil, nil, config)
+
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	stats := &learning.UserStats{DueWords: 7, ReviewWords: 30}
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{9, "Good morning Anna: 7 due, 30 mastered"},
+		{14, "Good afternoon Anna: 7 due, 30 mastered"},
+		{20, "Good evening Anna: 7 due, 30 mastered"},
+	}
+	for _, tt := range tests {
+		now := time.Date(2024, 3, 20, tt.hour, 0, 0, 0, time.UTC)
+		if got := uc.createReminderMessage(u, stats, now); got != tt.want {
+			t.Errorf("message at %02d:00 = %q, want %q", tt.hour, got, tt.want)
+		}
+	}
+}
+
+func TestReminderConfigValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{"built-in messages", "", false},
+		{"all fields", "{{.Greeting}} {{.FirstName}} {{.DueWords}} {{.ReviewWords}}", false},
+		{"syntax error", "{{.Greeting", true},
+		{"unknown field", "{{.LastName}}", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultReminderConfig()
+			config.MessageTemplate = tt.template
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			// Reminders are rendered with the template exactly when it passed validation
+			uc := NewReminderUseCase(nil, nil, nil, nil, config)
+			message := uc.createReminderMessage(user.NewUser(42, "anna", "Anna", "", "en"), &learning.UserStats{DueWords: 3}, time.Now())
+			builtIn := strings.HasPrefix(message, "🇳🇱")
+			if wantBuiltIn := tt.template == "" || tt.wantErr; builtIn != wantBuiltIn {
+				t.Errorf("built-in message used = %v, want %v (message %q)", builtIn, wantBuiltIn, message)
+			}
+		})
+	}
+}
+
+func TestGetReminderStats(t *testing.T) {
+	config := DefaultReminderConfig()
+	config.MaxRemindersPerDay = 5
+	uc, _ := newTestReminderUseCase(t, nil, config)
+
+	now := time.Now()
+	uc.reminderState[1] = &UserReminderState{RemindersToday: 2, LastCheckDate: now}
+	uc.reminderState[2] = &UserReminderState{RemindersToday: 1, LastCheckDate: now}
+	// Yesterday's count is stale until the next check resets it, so it isn't counted
+	uc.reminderState[3] = &UserReminderState{RemindersToday: 4, LastCheckDate: now.AddDate(0, 0, -1)}
+
+	stats := uc.GetReminderStats()
+	if stats.UsersTracked != 3 {
+		t.Errorf("UsersTracked = %d, want 3", stats.UsersTracked)
+	}
+	if stats.RemindersSentToday != 3 {
+		t.Errorf("RemindersSentToday = %d, want 3", stats.RemindersSentToday)
+	}
+	if stats.Config.MaxRemindersPerDay != 5 {
+		t.Errorf("Config.MaxRemindersPerDay = %d, want 5", stats.Config.MaxRemindersPerDay)
+	}
+
+	uc.ResetReminderState()
+	if stats := uc.GetReminderStats(); stats.UsersTracked != 0 || stats.RemindersSentToday != 0 {
+		t.Errorf("after reset: %d users tracked, %d reminders today, want none", stats.UsersTracked, stats.RemindersSentToday)
+	}
+}
+
+func TestIsDigestDue_OncePerLocalDay(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	local := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 7, day, hour, minute, 0, 0, tokyo)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		lastSent time.Time
+		want     bool
+	}{
+		{"before the digest hour", local(2, 7, 0), time.Time{}, false},
+		{"first digest", local(2, 8, 30), time.Time{}, true},
+		{"already sent today", local(2, 20, 0), local(2, 8, 30), false},
+		// 08:30 in Tokyo is the previous UTC day, but the same local day as 23:00
+		{"same local day, different UTC day", local(2, 23, 0), local(2, 8, 30), false},
+		// 23:30 and 08:05 the next morning in Tokyo fall on the same UTC day
+		{"next local day, same UTC day", local(2, 8, 5), local(1, 23, 30), true},
+		{"next day before the digest hour", local(3, 7, 59), local(2, 8, 30), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := user.NewUserPreferences(1)
+			prefs.SetReminderMode(user.ReminderModeDailyDigest)
+			prefs.SetTimezone("Asia/Tokyo")
+			prefs.SetDigestHour(8)
+			if !tt.lastSent.IsZero() {
+				prefs.SetLastDigestAt(tt.lastSent)
+			}
+
+			if got := isDigestDue(prefs, tt.now.In(prefs.Location())); got != tt.want {
+				t.Errorf("isDigestDue at %v (last sent %v) = %v, want %v", tt.now, tt.lastSent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRemindersNow_DryRunCounts(t *testing.T) {
+	// Users 2 and 3 have a scheduled reminder due; user 1 turned reminders off
+	prefsRepo := &preferencesByUserRepo{prefs: make(map[user.ID]*user.UserPreferences)}
+	userIDs := []user.ID{1, 2, 3}
+	for _, id := range userIDs {
+		prefs := user.NewUserPreferences(id)
+		prefs.SetSmartRemindersEnabled(id != 1)
+		prefs.SetNextReminderAt(time.Now().Add(-time.Minute))
+		prefsRepo.prefs[id] = prefs
+	}
+
+	// No quiet hours, so the check doesn't depend on when the test runs
+	config := DefaultReminderConfig()
+	config.QuietHoursStart = 0
+	config.QuietHoursEnd = 0
+	bot, fake := newTestBot(t)
+	repo := &fakeLearningRepo{stats: &learning.UserStats{TotalWords: 10, DueWords: 3}, userIDs: userIDs}
+	uc := NewReminderUseCase(bot, &fakeUserRepo{}, repo, prefsRepo, config)
+
+	checks := []struct {
+		name      string
+		dryRun    bool
+		wantDue   int
+		wantSent  int
+		wantTotal int // Messages sent so far
+	}{
+		{"dry run", true, 2, 0, 0},
+		{"dry run again", true, 2, 0, 0},
+		{"real run", false, 2, 2, 2},
+		{"dry run after sending", true, 0, 0, 2},
+	}
+	for _, check := range checks {
+		result, err := uc.CheckRemindersNow(context.Background(), check.dryRun)
+		if err != nil {
+			t.Fatalf("%s: CheckRemindersNow: %v", check.name, err)
+		}
+		if result.DryRun != check.dryRun || result.UsersChecked != len(userIDs) {
+			t.Errorf("%s: result = %+v, want DryRun %v and %d users checked", check.name, result, check.dryRun, len(userIDs))
+		}
+		if result.RemindersDue != check.wantDue || result.RemindersSent != check.wantSent {
+			t.Errorf("%s: %d due, %d sent; want %d due, %d sent",
+				check.name, result.RemindersDue, result.RemindersSent, check.wantDue, check.wantSent)
+		}
+		if got := fake.count("sendMessage"); got != check.wantTotal {
+			t.Errorf("%s: sendMessage called %d times in total, want %d", check.name, got, check.wantTotal)
+		}
+	}
+}
+
+func TestCheckReminders_HonorsUserPreferences(t *testing.T) {
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	userRepo := persistence.NewUserRepository(db)
+
+	// Three learners, all away for days with words due
+	prefsRepo := &preferencesByUserRepo{prefs: make(map[user.ID]*user.UserPreferences)}
+	var userIDs []user.ID
+	for i, name := range []string{"Anna", "Bram", "Cor"} {
+		u := user.NewUser(user.TelegramID(100+i), "", name, "", "en")
+		if err := userRepo.Save(context.Background(), u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+		userIDs = append(userIDs, u.ID())
+		prefsRepo.prefs[u.ID()] = user.NewUserPreferences(u.ID())
+	}
+	if _, err := db.Exec(`UPDATE users SET last_active = ?`, time.Now().AddDate(0, 0, -4)); err != nil {
+		t.Fatalf("failed to age users: %v", err)
+	}
+	anna, bram, cor := userIDs[0], userIDs[1], userIDs[2]
+	prefsRepo.prefs[anna].SetSmartRemindersEnabled(false)
+	prefsRepo.prefs[bram].SetReminderInterval(60)
+	prefsRepo.prefs[cor].SetReminderInterval(240)
+
+	// No quiet hours, so the check doesn't depend on when the test runs
+	config := DefaultReminderConfig()
+	config.QuietHoursStart = 0
+	config.QuietHoursEnd = 0
+	bot, fake := newTestBot(t)
+	repo := &fakeLearningRepo{stats: &learning.UserStats{TotalWords: 10, DueWords: 3}, userIDs: userIDs}
+	uc := NewReminderUseCase(bot, userRepo, repo, prefsRepo, config)
+
+	// Only the learners with reminders on are reminded
+	result, err := uc.CheckRemindersNow(context.Background(), false)
+	if err != nil {
+		t.Fatalf("CheckRemindersNow: %v", err)
+	}
+	if result.RemindersSent != 2 || fake.count("sendMessage") != 2 {
+		t.Fatalf("sent %d reminders (%d messages), want 2", result.RemindersSent, fake.count("sendMessage"))
+	}
+	if _, reminded := uc.reminderState[anna]; reminded {
+		t.Error("reminded a learner who turned reminders off")
+	}
+
+	// Two hours later only the learner with a one-hour interval is due another reminder
+	for _, id := range []user.ID{bram, cor} {
+		uc.reminderState[id].LastReminderSent = time.Now().Add(-2 * time.Hour)
+	}
+	result, err = uc.CheckRemindersNow(context.Background(), true)
+	if err != nil {
+		t.Fatalf("CheckRemindersNow: %v", err)
+	}
+	if result.RemindersDue != 1 {
+		t.Errorf("%d reminders due two hours later, want 1", result.RemindersDue)
+	}
+	if !uc.shouldSendReminder(context.Background(), mustFindUser(t, userRepo, bram)) {
+		t.Error("expected a reminder after the learner's one-hour interval")
+	}
+	if uc.shouldSendReminder(context.Background(), mustFindUser(t, userRepo, cor)) {
+		t.Error("expected no reminder before the learner's four-hour interval")
+	}
+}
+
+func mustFindUser(t *testing.T, repo user.Repository, id user.ID) *user.User {
+	t.Helper()
+
+	u, err := repo.FindByID(context.Background(), id)
+	if err != nil || u == nil {
+		t.Fatalf("failed to find user %d: %v", id, err)
+	}
+	return u
+}
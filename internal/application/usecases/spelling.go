@@ -0,0 +1,65 @@
+package usecases
+
+import "strings"
+
+// britishToAmerican maps common British spellings to their American equivalents. Only words
+// whose British form can't be mistaken for a different American word are listed, so "tyre"
+// and "cheque" are left out.
+var britishToAmerican = map[string]string{
+	"aeroplane":     "airplane",
+	"aluminium":     "aluminum",
+	"analyse":       "analyze",
+	"apologise":     "apologize",
+	"behaviour":     "behavior",
+	"cancelled":     "canceled",
+	"catalogue":     "catalog",
+	"centre":        "center",
+	"colour":        "color",
+	"colourful":     "colorful",
+	"cosy":          "cozy",
+	"defence":       "defense",
+	"dialogue":      "dialog",
+	"favour":        "favor",
+	"favourite":     "favorite",
+	"fibre":         "fiber",
+	"flavour":       "flavor",
+	"grey":          "gray",
+	"harbour":       "harbor",
+	"honour":        "honor",
+	"humour":        "humor",
+	"jewellery":     "jewelry",
+	"kilometre":     "kilometer",
+	"labour":        "labor",
+	"litre":         "liter",
+	"metre":         "meter",
+	"moustache":     "mustache",
+	"mum":           "mom",
+	"neighbour":     "neighbor",
+	"neighbourhood": "neighborhood",
+	"offence":       "offense",
+	"organisation":  "organization",
+	"organise":      "organize",
+	"plough":        "plow",
+	"practise":      "practice",
+	"programme":     "program",
+	"pyjamas":       "pajamas",
+	"realise":       "realize",
+	"recognise":     "recognize",
+	"sceptical":     "skeptical",
+	"theatre":       "theater",
+	"travelled":     "traveled",
+	"traveller":     "traveler",
+	"travelling":    "traveling",
+}
+
+// americanSpelling rewrites each British-spelled word of a normalized answer in its American
+// form, so answers in either spelling compare equal
+func americanSpelling(answer string) string {
+	words := strings.Fields(answer)
+	for i, word := range words {
+		if american, ok := britishToAmerican[word]; ok {
+			words[i] = american
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+)
+
+func TestGradeAnswer_SpellingVariants(t *testing.T) {
+	f := newLearningFixture(t, nil)
+	british := f.addWord(t, "colour", "kleur", "basics")
+	american := f.addWord(t, "gray", "grijs", "basics")
+	phrase := f.addWord(t, "my favourite theatre", "mijn favoriete theater", "basics")
+
+	tests := []struct {
+		name         string
+		session      *LearningSession
+		answer       string
+		variantsOn   bool
+		wantAccepted bool
+	}{
+		{"american answer, british word", &LearningSession{Word: british}, "color", true, true},
+		{"british answer, american word", &LearningSession{Word: american}, "Grey", true, true},
+		{"every word of a phrase", &LearningSession{Word: phrase}, "my favorite theater", true, true},
+		{"mixed spellings in a phrase", &LearningSession{Word: phrase}, "my favorite theatre", true, true},
+		{"exact spelling with variants off", &LearningSession{Word: british}, "colour", false, true},
+		{"variant with variants off", &LearningSession{Word: british}, "color", false, false},
+		{"different word", &LearningSession{Word: american}, "grew", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.updatePreferences(t, func(p *user.UserPreferences) { p.SetSpellingVariants(tt.variantsOn) })
+			session := *tt.session
+			session.UserID = f.userID
+			session.QuestionType = QuestionTypeDutchToEnglish
+
+			want := learning.ScoreWrong
+			if tt.wantAccepted {
+				want = learning.ScoreCorrect
+			}
+			if got := f.uc.GradeAnswer(context.Background(), &session, tt.answer); got != want {
+				t.Errorf("GradeAnswer(%q) for %q = %v, want %v", tt.answer, session.Word.English(), got, want)
+			}
+		})
+	}
+}
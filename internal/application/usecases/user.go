@@ -2,22 +2,70 @@ package usecases
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"math/big"
+	"strconv"
+	"time"
 
+	"dutch-learning-bot/internal/domain/learning"
 	"dutch-learning-bot/internal/domain/user"
 )
 
 // UserUseCase handles user-related business operations
 type UserUseCase struct {
-	userRepo        user.Repository
-	preferencesRepo user.PreferencesRepository
+	userRepo         user.Repository
+	preferencesRepo  user.PreferencesRepository
+	preferencesCache *preferencesCache
+	config           *UserConfig
+}
+
+// UserConfig holds configuration for user management
+type UserConfig struct {
+	// Reminder interval in minutes given to new users
+	DefaultReminderInterval int
+	// How long preferences stay cached between reads (0 disables the cache)
+	PreferencesCacheTTL time.Duration
+}
+
+// DefaultUserConfig returns sensible defaults for user management
+func DefaultUserConfig() *UserConfig {
+	return &UserConfig{
+		DefaultReminderInterval: user.DefaultReminderInterval,
+		PreferencesCacheTTL:     5 * time.Minute,
+	}
 }
 
 // NewUserUseCase creates a new user use case
-func NewUserUseCase(userRepo user.Repository, preferencesRepo user.PreferencesRepository) *UserUseCase {
-	return &UserUseCase{
+func NewUserUseCase(userRepo user.Repository, preferencesRepo user.PreferencesRepository, config *UserConfig) *UserUseCase {
+	if config == nil {
+		config = DefaultUserConfig()
+	}
+
+	uc := &UserUseCase{
 		userRepo:        userRepo,
 		preferencesRepo: preferencesRepo,
+		config:          config,
+	}
+
+	if config.PreferencesCacheTTL > 0 {
+		uc.preferencesCache = newPreferencesCache(preferencesRepo, config.PreferencesCacheTTL)
+		uc.preferencesRepo = uc.preferencesCache
+	}
+
+	return uc
+}
+
+// PreferencesRepository returns the repository the use case reads preferences through.
+// Other components should use it too, so their writes invalidate the shared cache.
+func (uc *UserUseCase) PreferencesRepository() user.PreferencesRepository {
+	return uc.preferencesRepo
+}
+
+// InvalidatePreferences drops any cached preferences for a user
+func (uc *UserUseCase) InvalidatePreferences(userID user.ID) {
+	if uc.preferencesCache != nil {
+		uc.preferencesCache.Invalidate(userID)
 	}
 }
 
@@ -55,6 +103,7 @@ func (uc *UserUseCase) GetOrCreateUser(
 
 	// Initialize default preferences for new user
 	preferences := user.NewUserPreferences(newUser.ID())
+	preferences.SetReminderInterval(uc.config.DefaultReminderInterval)
 	err = uc.preferencesRepo.SavePreferences(ctx, preferences)
 	if err != nil {
 		// Log error but don't fail user creation
@@ -141,3 +190,580 @@ func (uc *UserUseCase) ToggleSmartReminders(ctx context.Context, userID user.ID)
 
 	return newState, nil
 }
+
+// ToggleShowSessionProgress toggles the running session scoreboard preference for a user
+func (uc *UserUseCase) ToggleShowSessionProgress(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleShowSessionProgress()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// SetReviewAhead sets how many minutes ahead nearly-due words count as due for a user
+func (uc *UserUseCase) SetReviewAhead(ctx context.Context, userID user.ID, minutes int) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetReviewAheadMinutes(minutes)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ToggleIgnoreArticles toggles article-insensitive answer matching for a user
+func (uc *UserUseCase) ToggleIgnoreArticles(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleIgnoreArticles()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleStrictAccents toggles whether typed answers must get accents and diacritics right
+func (uc *UserUseCase) ToggleStrictAccents(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleStrictAccents()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleSpellingVariants toggles whether British and American spellings are accepted for each other
+func (uc *UserUseCase) ToggleSpellingVariants(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleSpellingVariants()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleIntervalFuzz toggles whether a user's review intervals are spread by a small random amount
+func (uc *UserUseCase) ToggleIntervalFuzz(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleIntervalFuzz()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleSessionRecap toggles whether finishing a session shows a recap before the menu
+func (uc *UserUseCase) ToggleSessionRecap(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleSessionRecap()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleCommunityDifficulty toggles whether new words start from how hard other learners found them
+func (uc *UserUseCase) ToggleCommunityDifficulty(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleCommunityDifficulty()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleReviewsOnly toggles whether a user's sessions skip new words and only serve reviews
+func (uc *UserUseCase) ToggleReviewsOnly(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleReviewsOnly()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleRateConfidence toggles whether a user rates their confidence in each answer
+func (uc *UserUseCase) ToggleRateConfidence(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleRateConfidence()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleStagedReveal toggles the two-step answer reveal for a user
+func (uc *UserUseCase) ToggleStagedReveal(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleStagedReveal()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleAutoEasyFast toggles automatic Easy ratings for fast correct answers for a user
+func (uc *UserUseCase) ToggleAutoEasyFast(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleAutoEasyFast()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleRecognitionFirst toggles asking Dutch-to-English on a word's first reviews for a user
+func (uc *UserUseCase) ToggleRecognitionFirst(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleRecognitionFirst()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleShuffleRatings toggles randomizing the rating button positions for a user
+func (uc *UserUseCase) ToggleShuffleRatings(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleShuffleRatings()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// ToggleShowWordSense toggles showing the meaning of ambiguous words in questions for a user
+func (uc *UserUseCase) ToggleShowWordSense(ctx context.Context, userID user.ID) (bool, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	newState := preferences.ToggleShowWordSense()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return false, err
+	}
+
+	return newState, nil
+}
+
+// SetMaxSessionMinutes sets the wall-clock session cap for a user
+func (uc *UserUseCase) SetMaxSessionMinutes(ctx context.Context, userID user.ID, minutes int) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetMaxSessionMinutes(minutes)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetDailyNewLimit sets how many new words may be introduced per day for a user
+func (uc *UserUseCase) SetDailyNewLimit(ctx context.Context, userID user.ID, limit int) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetDailyNewLimit(limit)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ToggleStudyPriority switches a user's card ordering strategy for learning sessions
+func (uc *UserUseCase) ToggleStudyPriority(ctx context.Context, userID user.ID) (user.StudyPriority, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	newPriority := preferences.ToggleStudyPriority()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return "", err
+	}
+
+	return newPriority, nil
+}
+
+// CycleChoiceGrading switches how a user's correct multiple-choice answers are rated
+func (uc *UserUseCase) CycleChoiceGrading(ctx context.Context, userID user.ID) (user.ChoiceGrading, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	newGrading := preferences.CycleChoiceGrading()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return "", err
+	}
+
+	return newGrading, nil
+}
+
+// CycleQuestionDirection switches which side of a word a user's questions ask for
+func (uc *UserUseCase) CycleQuestionDirection(ctx context.Context, userID user.ID) (user.QuestionDirection, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	newDirection := preferences.CycleQuestionDirection()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return "", err
+	}
+
+	return newDirection, nil
+}
+
+// SetNewWordCategories limits the categories new words are drawn from for a user; an empty list allows every category
+func (uc *UserUseCase) SetNewWordCategories(ctx context.Context, userID user.ID, categories []string) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetNewWordCategories(categories)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetLeaderboardVisible shows or hides a user on the weekly leaderboard
+func (uc *UserUseCase) SetLeaderboardVisible(ctx context.Context, userID user.ID, visible bool) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetLeaderboardVisible(visible)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetCategoryDirection pins the question direction of one category for a user; an empty direction unpins it
+func (uc *UserUseCase) SetCategoryDirection(ctx context.Context, userID user.ID, category string, direction user.QuestionDirection) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	directions := preferences.GetCategoryDirections()
+	if direction == "" {
+		delete(directions, category)
+	} else {
+		directions[category] = direction
+	}
+	preferences.SetCategoryDirections(directions)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ClearCategoryDirections unpins the question direction of every category for a user
+func (uc *UserUseCase) ClearCategoryDirections(ctx context.Context, userID user.ID) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetCategoryDirections(nil)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetDailyMix sets a user's per-category daily plan; an empty mix clears it
+func (uc *UserUseCase) SetDailyMix(ctx context.Context, userID user.ID, mix user.DailyMix) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetDailyMix(mix)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ToggleNewWordOrder switches the order in which a user's new words are introduced
+func (uc *UserUseCase) ToggleNewWordOrder(ctx context.Context, userID user.ID) (user.NewWordOrder, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	newOrder := preferences.ToggleNewWordOrder()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return "", err
+	}
+
+	return newOrder, nil
+}
+
+// ToggleAnswerMode switches a user between multiple-choice and typed answers
+func (uc *UserUseCase) ToggleAnswerMode(ctx context.Context, userID user.ID) (user.AnswerMode, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	newMode := preferences.ToggleAnswerMode()
+
+	err = uc.UpdateUserPreferences(ctx, preferences)
+	if err != nil {
+		return "", err
+	}
+
+	return newMode, nil
+}
+
+// ReshuffleNewWords picks a fresh random order for the words a user hasn't studied yet.
+// Studied words keep their progress and schedule; only the introduction order changes.
+func (uc *UserUseCase) ReshuffleNewWords(ctx context.Context, userID user.ID) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	seed, err := rand.Int(rand.Reader, big.NewInt(user.MaxNewWordSeed))
+	if err != nil {
+		return fmt.Errorf("failed to generate shuffle seed: %w", err)
+	}
+
+	preferences.SetNewWordOrder(user.NewWordOrderRandom)
+	preferences.SetNewWordSeed(seed.Int64() + 1)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetHintType sets the hint shown alongside a user's questions
+func (uc *UserUseCase) SetHintType(ctx context.Context, userID user.ID, hintType user.HintType) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetHintType(hintType)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetFSRSWeights stores custom FSRS weights for a user; nil restores the defaults
+func (uc *UserUseCase) SetFSRSWeights(ctx context.Context, userID user.ID, params *learning.FSRSParams) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	weights := ""
+	if params != nil {
+		weights = params.String()
+	}
+	preferences.SetFSRSWeights(weights)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetLearningSteps stores custom learning steps for a user; nil restores the defaults
+func (uc *UserUseCase) SetLearningSteps(ctx context.Context, userID user.ID, steps []time.Duration) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	value := ""
+	if steps != nil {
+		value = learning.FormatLearningSteps(steps)
+	}
+	preferences.SetLearningSteps(value)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetRequestRetention stores a custom request retention for a user; 0 restores the default
+func (uc *UserUseCase) SetRequestRetention(ctx context.Context, userID user.ID, retention float64) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	value := ""
+	if retention != 0 {
+		value = strconv.FormatFloat(retention, 'f', -1, 64)
+	}
+	preferences.SetRequestRetention(value)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ExportSettings returns a user's settings in a form ImportSettings accepts
+func (uc *UserUseCase) ExportSettings(ctx context.Context, userID user.ID) (map[string]string, error) {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferences.ExportSettings(), nil
+}
+
+// ImportSettings restores a user's previously exported settings
+func (uc *UserUseCase) ImportSettings(ctx context.Context, userID user.ID, settings map[string]string) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := preferences.ImportSettings(settings); err != nil {
+		return err
+	}
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetDailyDigest switches a user to one daily summary at the given hour (0-23)
+func (uc *UserUseCase) SetDailyDigest(ctx context.Context, userID user.ID, hour int) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetReminderMode(user.ReminderModeDailyDigest)
+	preferences.SetDigestHour(hour)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetTimezone sets the time zone a user's quiet hours, daily digest and streaks follow
+func (uc *UserUseCase) SetTimezone(ctx context.Context, userID user.ID, location *time.Location) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetTimezone(location.String())
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// SetReminderMode sets how a user is reminded about due words
+func (uc *UserUseCase) SetReminderMode(ctx context.Context, userID user.ID, mode user.ReminderMode) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetReminderMode(mode)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
+
+// ScheduleReminder asks the reminder service to nudge the user once the given time has passed
+func (uc *UserUseCase) ScheduleReminder(ctx context.Context, userID user.ID, remindAt time.Time) error {
+	preferences, err := uc.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences.SetNextReminderAt(remindAt)
+
+	return uc.UpdateUserPreferences(ctx, preferences)
+}
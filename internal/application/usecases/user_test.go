@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+func TestGetOrCreateUser_DefaultReminderInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *UserConfig
+		want   int
+	}{
+		{"built-in default", nil, user.DefaultReminderInterval},
+		{"env override", &UserConfig{DefaultReminderInterval: 90}, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("failed to open test database: %v", err)
+			}
+			t.Cleanup(func() { db.Close() })
+			userRepo := persistence.NewUserRepository(db)
+			uc := NewUserUseCase(userRepo, persistence.NewUserPreferencesRepository(db), tt.config)
+
+			u, err := uc.GetOrCreateUser(ctx, 42, "anna", "Anna", "", "en")
+			if err != nil {
+				t.Fatalf("GetOrCreateUser: %v", err)
+			}
+			prefs, err := uc.GetUserPreferences(ctx, u.ID())
+			if err != nil {
+				t.Fatalf("failed to load preferences: %v", err)
+			}
+			if got := prefs.GetReminderInterval(); got != tt.want {
+				t.Errorf("new user's interval = %d, want %d", got, tt.want)
+			}
+
+			// A chosen interval survives a reload, whatever the configured default
+			prefs.SetReminderInterval(45)
+			if err := uc.UpdateUserPreferences(ctx, prefs); err != nil {
+				t.Fatalf("failed to save preferences: %v", err)
+			}
+			reloaded := NewUserUseCase(userRepo, persistence.NewUserPreferencesRepository(db), tt.config)
+			prefs, err = reloaded.GetUserPreferences(ctx, u.ID())
+			if err != nil {
+				t.Fatalf("failed to reload preferences: %v", err)
+			}
+			if got := prefs.GetReminderInterval(); got != 45 {
+				t.Errorf("reloaded interval = %d, want 45", got)
+			}
+		})
+	}
+}
+
+func TestPreferencesCache_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	backing := persistence.NewUserPreferencesRepository(db)
+	uc := NewUserUseCase(persistence.NewUserRepository(db), backing, &UserConfig{
+		DefaultReminderInterval: user.DefaultReminderInterval,
+		PreferencesCacheTTL:     time.Hour,
+	})
+
+	u, err := uc.GetOrCreateUser(ctx, 42, "anna", "Anna", "", "en")
+	if err != nil {
+		t.Fatalf("GetOrCreateUser: %v", err)
+	}
+	enabled := func() bool {
+		t.Helper()
+		prefs, err := uc.GetUserPreferences(ctx, u.ID())
+		if err != nil {
+			t.Fatalf("failed to load preferences: %v", err)
+		}
+		return prefs.GrammarTipsEnabled()
+	}
+
+	before := enabled()
+	toggled, err := uc.ToggleGrammarTips(ctx, u.ID())
+	if err != nil {
+		t.Fatalf("ToggleGrammarTips: %v", err)
+	}
+	if toggled == before || enabled() != toggled {
+		t.Fatalf("toggle from %v not reflected on the next read", before)
+	}
+
+	// Mutating a returned copy without saving must not leak into the cache
+	prefs, err := uc.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		t.Fatalf("failed to load preferences: %v", err)
+	}
+	prefs.SetGrammarTipsEnabled(!toggled)
+	if enabled() != toggled {
+		t.Error("unsaved change leaked into the cache")
+	}
+
+	// A write that bypasses the cache stays hidden until the entry is invalidated
+	if err := backing.UpdatePreference(ctx, u.ID(), user.PrefGrammarTipsEnabled, strconv.FormatBool(!toggled)); err != nil {
+		t.Fatalf("failed to update preference: %v", err)
+	}
+	if enabled() != toggled {
+		t.Fatal("expected the cached value before invalidation")
+	}
+	uc.InvalidatePreferences(u.ID())
+	if enabled() != !toggled {
+		t.Error("invalidated preferences should be reloaded from the repository")
+	}
+}
@@ -13,6 +13,8 @@ type GrammarTip struct {
 	applicableCategories []string // Vocabulary categories this tip applies to
 	wordPatterns         []string // Word patterns/endings this tip applies to
 	specificWords        []string // Specific words this tip applies to
+	imageURL             string   // Optional illustrative image (URL or local file path)
+	audioURL             string   // Optional pronunciation/audio example (URL or local file path)
 	createdAt            time.Time
 }
 
@@ -62,6 +64,8 @@ func (gt *GrammarTip) Category() Category             { return gt.category }
 func (gt *GrammarTip) ApplicableCategories() []string { return gt.applicableCategories }
 func (gt *GrammarTip) WordPatterns() []string         { return gt.wordPatterns }
 func (gt *GrammarTip) SpecificWords() []string        { return gt.specificWords }
+func (gt *GrammarTip) ImageURL() string               { return gt.imageURL }
+func (gt *GrammarTip) AudioURL() string               { return gt.audioURL }
 func (gt *GrammarTip) CreatedAt() time.Time           { return gt.createdAt }
 
 // SetID sets the grammar tip ID (used by repository)
@@ -69,6 +73,17 @@ func (gt *GrammarTip) SetID(id ID) {
 	gt.id = id
 }
 
+// SetMedia attaches optional image and audio examples to the tip
+func (gt *GrammarTip) SetMedia(imageURL, audioURL string) {
+	gt.imageURL = imageURL
+	gt.audioURL = audioURL
+}
+
+// HasMedia checks if the tip has an image or audio example
+func (gt *GrammarTip) HasMedia() bool {
+	return gt.imageURL != "" || gt.audioURL != ""
+}
+
 // IsValidCategory checks if a category is valid
 func IsValidCategory(category Category) bool {
 	switch category {
@@ -106,6 +121,25 @@ func (gt *GrammarTip) IsApplicableToWord(dutchWord, englishWord, category string
 	return false
 }
 
+// partOfSpeechCategories maps a word's part of speech to the tip categories that explain it
+var partOfSpeechCategories = map[string][]Category{
+	"noun":        {CategoryArticles, CategoryPlurals},
+	"verb":        {CategoryVerbs},
+	"adjective":   {CategoryAdjectives},
+	"pronoun":     {CategoryPronouns},
+	"preposition": {CategoryPrepositions},
+}
+
+// MatchesPartOfSpeech checks if this tip's category is about the given part of speech
+func (gt *GrammarTip) MatchesPartOfSpeech(pos string) bool {
+	for _, category := range partOfSpeechCategories[pos] {
+		if gt.category == category {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesPattern checks if a word matches a pattern
 func matchesPattern(word, pattern string) bool {
 	// Simple pattern matching - can be enhanced with regex later
@@ -4,7 +4,8 @@ import "context"
 
 // Repository defines the contract for grammar tips persistence
 type Repository interface {
-	// SaveBatch persists multiple grammar tips to storage
+	// SaveBatch persists grammar tips as the full set, replacing tips with the same title
+	// and removing stored tips that aren't in the batch
 	SaveBatch(ctx context.Context, tips []*GrammarTip) error
 
 	// FindApplicableToWord finds grammar tips that apply to a specific word
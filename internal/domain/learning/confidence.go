@@ -0,0 +1,51 @@
+package learning
+
+import "time"
+
+// Confidence is how sure the user felt about an answer, rated separately from whether it was correct
+type Confidence int
+
+const (
+	ConfidenceUnrated Confidence = iota // The user didn't rate their confidence
+	ConfidenceLow                       // Guessed
+	ConfidenceMedium                    // Fairly sure
+	ConfidenceHigh                      // Certain
+)
+
+// IsValid reports whether the confidence is one the user can pick
+func (c Confidence) IsValid() bool {
+	return c >= ConfidenceLow && c <= ConfidenceHigh
+}
+
+// IntervalMultiplier returns the factor a correct answer's interval is scaled by:
+// high confidence stretches it by boost, low confidence shrinks it by boost.
+func (c Confidence) IntervalMultiplier(boost float64) float64 {
+	switch c {
+	case ConfidenceHigh:
+		return 1 + boost
+	case ConfidenceLow:
+		return 1 - boost
+	default:
+		return 1
+	}
+}
+
+// ScaleInterval scales the time between a review card's last review and its due date,
+// keeping at least one day. It reports whether the due date moved.
+// Cards that are new or still in (re)learning keep their short-term steps.
+func (card *FSRSCard) ScaleInterval(multiplier float64) bool {
+	if card.state != StateReview || card.lastReview.IsZero() || multiplier <= 0 || multiplier == 1 {
+		return false
+	}
+
+	interval := time.Duration(float64(card.dueDate.Sub(card.lastReview)) * multiplier)
+	if interval < 24*time.Hour {
+		interval = 24 * time.Hour
+	}
+	dueDate := card.lastReview.Add(interval)
+	if dueDate.Equal(card.dueDate) {
+		return false
+	}
+	card.dueDate = dueDate
+	return true
+}
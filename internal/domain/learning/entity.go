@@ -54,6 +54,45 @@ func (up *UserProgress) Review(rating Rating) *ReviewResult {
 	return result
 }
 
+// SeedAsKnown marks the word as already known by the user
+func (up *UserProgress) SeedAsKnown() {
+	now := time.Now()
+	up.fsrsCard.Seed(now)
+	up.updatedAt = now
+}
+
+// SeedDifficulty starts an unstudied word from a known difficulty instead of the default
+func (up *UserProgress) SeedDifficulty(difficulty float64) {
+	up.fsrsCard.SeedDifficulty(difficulty)
+}
+
+// Postpone pushes the word's due date to the given duration from now without
+// touching its memory state. It never moves the due date earlier.
+func (up *UserProgress) Postpone(duration time.Duration) {
+	now := time.Now()
+	dueDate := now.Add(duration)
+	if dueDate.After(up.fsrsCard.DueDate()) {
+		up.fsrsCard.SetDueDate(dueDate)
+	}
+	up.updatedAt = now
+}
+
+// Reschedule recomputes the word's due date under the current scheduling parameters,
+// dropping any postponement. It reports whether the due date changed.
+func (up *UserProgress) Reschedule() bool {
+	if !up.fsrsCard.Reschedule() {
+		return false
+	}
+	up.updatedAt = time.Now()
+	return true
+}
+
+// RestoreCard replaces the word's scheduling state, such as with a card imported from another instance
+func (up *UserProgress) RestoreCard(card *FSRSCard) {
+	up.fsrsCard = card
+	up.updatedAt = time.Now()
+}
+
 // IsDue checks if this word is due for review
 func (up *UserProgress) IsDue() bool {
 	return up.fsrsCard.IsDue()
@@ -67,8 +106,17 @@ type ReviewHistory struct {
 	rating         Rating
 	reviewTime     time.Time
 	responseTimeMs int
+	score          float64   // Partial credit for the answer, from ScoreWrong to ScoreCorrect
+	priorCard      *FSRSCard // The card as it was before this review; nil for reviews saved before undo existed
 }
 
+// Answer scores used to give partial credit in stats
+const (
+	ScoreCorrect = 1.0
+	ScorePartial = 0.5
+	ScoreWrong   = 0.0
+)
+
 // NewReviewHistory creates a new review history entry
 func NewReviewHistory(userID user.ID, wordID vocabulary.ID, rating Rating, responseTime time.Duration) *ReviewHistory {
 	return &ReviewHistory{
@@ -87,12 +135,24 @@ func (rh *ReviewHistory) WordID() vocabulary.ID { return rh.wordID }
 func (rh *ReviewHistory) Rating() Rating        { return rh.rating }
 func (rh *ReviewHistory) ReviewTime() time.Time { return rh.reviewTime }
 func (rh *ReviewHistory) ResponseTimeMs() int   { return rh.responseTimeMs }
+func (rh *ReviewHistory) Score() float64        { return rh.score }
+func (rh *ReviewHistory) PriorCard() *FSRSCard  { return rh.priorCard }
 
 // SetID sets the review history ID (used by repository)
 func (rh *ReviewHistory) SetID(id ID) {
 	rh.id = id
 }
 
+// SetScore sets the partial credit for the answer
+func (rh *ReviewHistory) SetScore(score float64) {
+	rh.score = score
+}
+
+// SetPriorCard records the card as it was before this review, so the review can be undone
+func (rh *ReviewHistory) SetPriorCard(card *FSRSCard) {
+	rh.priorCard = card
+}
+
 // SetReviewTime sets the review time (used by repository when loading from database)
 func (rh *ReviewHistory) SetReviewTime(reviewTime time.Time) {
 	rh.reviewTime = reviewTime
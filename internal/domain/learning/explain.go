@@ -0,0 +1,55 @@
+package learning
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ExplainSchedule describes in one line why a card is due when it is, such as
+// "Last reviewed 3 days ago, stability 4.2 days, target 90% recall → due today."
+// Days are counted in now's location.
+func ExplainSchedule(card *FSRSCard, now time.Time) string {
+	if card.State() == StateNew || card.LastReview().IsZero() {
+		return fmt.Sprintf("Not reviewed yet, so there's no memory to schedule around → %s.", describeDue(card.DueDate(), now))
+	}
+
+	return fmt.Sprintf("Last reviewed %s, stability %.1f days, target %.0f%% recall, recall now about %.0f%% → %s.",
+		describeDaysAgo(card.LastReview(), now), card.Stability(), card.TargetRetention()*100,
+		card.Retrievability(now)*100, describeDue(card.DueDate(), now))
+}
+
+// describeDaysAgo describes a past time by calendar days, such as "yesterday" or "3 days ago"
+func describeDaysAgo(t, now time.Time) string {
+	switch days := calendarDaysBetween(t, now, now.Location()); {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// describeDue describes a due date relative to now, such as "due today" or "due in 5 days"
+func describeDue(dueDate, now time.Time) string {
+	switch days := calendarDaysBetween(now, dueDate, now.Location()); {
+	case !dueDate.After(now) && days < 0:
+		return fmt.Sprintf("overdue, was due %s", describeDaysAgo(dueDate, now))
+	case !dueDate.After(now):
+		return "due now"
+	case days == 0:
+		return "due later today"
+	case days == 1:
+		return "due tomorrow"
+	default:
+		return fmt.Sprintf("due in %d days", days)
+	}
+}
+
+// calendarDaysBetween counts the midnights in location from one time to another; negative when to is earlier
+func calendarDaysBetween(from, to time.Time, location *time.Location) int {
+	days := startOfDay(to.In(location)).Sub(startOfDay(from.In(location)))
+	// Round, since a day across a DST change isn't 24 hours
+	return int(math.Round(days.Hours() / 24))
+}
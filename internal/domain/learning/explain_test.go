@@ -0,0 +1,49 @@
+package learning
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainSchedule(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, cet)
+	reviewed := func(stability float64, lastReview, dueDate time.Time) *FSRSCard {
+		card := NewFSRSCard()
+		card.SetState(StateReview)
+		card.SetStability(stability)
+		card.SetLastReview(lastReview)
+		card.SetDueDate(dueDate)
+		return card
+	}
+	unreviewed := NewFSRSCard()
+	unreviewed.SetDueDate(now)
+
+	tests := []struct {
+		name string
+		card *FSRSCard
+		now  time.Time
+		want string
+	}{
+		{"not reviewed yet", unreviewed, now,
+			"Not reviewed yet, so there's no memory to schedule around → due now."},
+		{"due later today", reviewed(4.2, now.AddDate(0, 0, -3), now.Add(2*time.Hour)), now,
+			"Last reviewed 3 days ago, stability 4.2 days, target 90% recall, recall now about 93% → due later today."},
+		{"due in days", reviewed(10, now.Add(-25*time.Hour), now.AddDate(0, 0, 9)), now,
+			"Last reviewed yesterday, stability 10.0 days, target 90% recall, recall now about 99% → due in 9 days."},
+		{"overdue", reviewed(2, now.Add(-6*time.Hour), now.AddDate(0, 0, -2)), now,
+			"Last reviewed today, stability 2.0 days, target 90% recall, recall now about 99% → overdue, was due 2 days ago."},
+		// 23:00 and 00:30 in CET are two local days but the same UTC day
+		{"days follow now's location",
+			reviewed(4.2, time.Date(2024, 3, 19, 23, 0, 0, 0, cet), time.Date(2024, 3, 21, 9, 0, 0, 0, cet)),
+			time.Date(2024, 3, 20, 0, 30, 0, 0, cet),
+			"Last reviewed yesterday, stability 4.2 days, target 90% recall, recall now about 100% → due tomorrow."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExplainSchedule(tt.card, tt.now); got != tt.want {
+				t.Errorf("ExplainSchedule() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
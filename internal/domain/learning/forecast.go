@@ -0,0 +1,39 @@
+package learning
+
+import "time"
+
+// ForecastDay is how many of the user's words come due on one day
+type ForecastDay struct {
+	// Day is midnight at the start of the day, in the forecast's location
+	Day   time.Time
+	Count int
+}
+
+// ReviewForecast buckets due dates into the given number of calendar days starting today, with
+// days taken in now's location. Overdue words count towards today, and words due after the last
+// day are left out.
+func ReviewForecast(dueDates []time.Time, now time.Time, days int) []ForecastDay {
+	if days <= 0 {
+		return nil
+	}
+
+	today := startOfDay(now)
+	forecast := make([]ForecastDay, days)
+	for i := range forecast {
+		forecast[i].Day = today.AddDate(0, 0, i)
+	}
+
+	end := today.AddDate(0, 0, days)
+	for _, dueDate := range dueDates {
+		if !dueDate.Before(end) {
+			continue
+		}
+		day := 0
+		for day+1 < days && !dueDate.Before(forecast[day+1].Day) {
+			day++
+		}
+		forecast[day].Count++
+	}
+
+	return forecast
+}
@@ -1,7 +1,14 @@
 package learning
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
 	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -32,10 +39,152 @@ const (
 	decayParam = -0.5
 	// Factor for calculating next review interval
 	factor = 19.0 / 81.0
-	// Request retention (target recall probability)
-	requestRetention = 0.9
+	// Fraction of an interval that fuzz may add or remove
+	intervalFuzzFactor = 0.05
+	// Shortest interval, in days, that gets fuzzed
+	minFuzzedInterval = 3
+	// Difficulty a new card starts with before its first review
+	initialDifficulty = 5.0
 )
 
+// FSRSWeightCount is the number of weights in an FSRS parameter set
+const FSRSWeightCount = 19
+
+// Request retention is the recall probability reviews are scheduled for. A lower retention
+// means longer intervals and more forgetting; a higher one means shorter intervals and more reviews.
+const (
+	DefaultRequestRetention = 0.9
+	MinRequestRetention     = 0.85
+	MaxRequestRetention     = 0.97
+)
+
+// MaxLearningSteps is the most learning steps a card can be taken through before it graduates
+const MaxLearningSteps = 10
+
+// FSRSParams holds a set of FSRS weights, letting users with an established memory profile tune scheduling
+type FSRSParams struct {
+	Weights [FSRSWeightCount]float64
+	// LearningSteps are the delays a learning card moves through on Good answers before it
+	// graduates to review; empty uses DefaultLearningSteps
+	LearningSteps []time.Duration
+	// RequestRetention is the recall probability intervals are scheduled for, kept between
+	// MinRequestRetention and MaxRequestRetention; 0 uses DefaultRequestRetention
+	RequestRetention float64
+}
+
+// DefaultFSRSParams returns the default FSRS v4 weights
+func DefaultFSRSParams() *FSRSParams {
+	return &FSRSParams{Weights: [FSRSWeightCount]float64{
+		defaultWeight0, defaultWeight1, defaultWeight2, defaultWeight3, defaultWeight4,
+		defaultWeight5, defaultWeight6, defaultWeight7, defaultWeight8, defaultWeight9,
+		defaultWeight10, defaultWeight11, defaultWeight12, defaultWeight13, defaultWeight14,
+		defaultWeight15, defaultWeight16, defaultWeight17, defaultWeight18,
+	}, LearningSteps: DefaultLearningSteps(), RequestRetention: DefaultRequestRetention}
+}
+
+// DefaultLearningSteps returns the default learning steps: a new card comes back after a minute,
+// then after ten minutes, and graduates on the Good answer after that
+func DefaultLearningSteps() []time.Duration {
+	return []time.Duration{1 * time.Minute, 10 * time.Minute}
+}
+
+// ParseLearningSteps parses comma-separated learning step delays such as "1m,10m". Each delay
+// is a positive duration in seconds, minutes or hours, and there are at most MaxLearningSteps.
+func ParseLearningSteps(value string) ([]time.Duration, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) > MaxLearningSteps {
+		return nil, fmt.Errorf("expected at most %d steps, got %d", MaxLearningSteps, len(fields))
+	}
+
+	steps := make([]time.Duration, 0, len(fields))
+	for i, field := range fields {
+		step, err := time.ParseDuration(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("step %d is not a duration like 10m: %w", i+1, err)
+		}
+		if step <= 0 {
+			return nil, fmt.Errorf("step %d must be longer than zero", i+1)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// FormatLearningSteps formats learning steps as the comma-separated list ParseLearningSteps accepts
+func FormatLearningSteps(steps []time.Duration) string {
+	formatted := make([]string, len(steps))
+	for i, step := range steps {
+		switch {
+		case step%time.Hour == 0:
+			formatted[i] = fmt.Sprintf("%dh", step/time.Hour)
+		case step%time.Minute == 0:
+			formatted[i] = fmt.Sprintf("%dm", step/time.Minute)
+		default:
+			formatted[i] = step.String()
+		}
+	}
+	return strings.Join(formatted, ",")
+}
+
+// ParseRequestRetention parses a request retention such as "0.9", which must lie between
+// MinRequestRetention and MaxRequestRetention
+func ParseRequestRetention(value string) (float64, error) {
+	retention, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(retention) {
+		return 0, fmt.Errorf("retention must be a number like %.2f", DefaultRequestRetention)
+	}
+	if retention < MinRequestRetention || retention > MaxRequestRetention {
+		return 0, fmt.Errorf("retention must be between %.2f and %.2f", MinRequestRetention, MaxRequestRetention)
+	}
+	return retention, nil
+}
+
+// requestRetention returns the params' request retention clamped to the allowed range,
+// falling back to the default
+func (p *FSRSParams) requestRetention() float64 {
+	if p.RequestRetention == 0 {
+		return DefaultRequestRetention
+	}
+	return math.Max(math.Min(p.RequestRetention, MaxRequestRetention), MinRequestRetention)
+}
+
+// learningSteps returns the params' learning steps, falling back to the defaults
+func (p *FSRSParams) learningSteps() []time.Duration {
+	if len(p.LearningSteps) == 0 {
+		return DefaultLearningSteps()
+	}
+	return p.LearningSteps
+}
+
+// ParseFSRSParams parses a JSON array of exactly FSRSWeightCount finite weights
+func ParseFSRSParams(value string) (*FSRSParams, error) {
+	var weights []float64
+	if err := json.Unmarshal([]byte(value), &weights); err != nil {
+		return nil, fmt.Errorf("weights must be a JSON array of numbers: %w", err)
+	}
+	if len(weights) != FSRSWeightCount {
+		return nil, fmt.Errorf("expected %d weights, got %d", FSRSWeightCount, len(weights))
+	}
+
+	var params FSRSParams
+	for i, weight := range weights {
+		if math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("weight %d is not a finite number", i)
+		}
+		params.Weights[i] = weight
+	}
+	return &params, nil
+}
+
+// String formats the weights, without the learning steps or retention, as the JSON array ParseFSRSParams accepts
+func (p *FSRSParams) String() string {
+	data, err := json.Marshal(p.Weights)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
 // FSRSCard represents the state of a card in FSRS
 type FSRSCard struct {
 	dueDate     time.Time
@@ -45,6 +194,9 @@ type FSRSCard struct {
 	state       State
 	reviewCount int
 	lapses      int
+	step        int         // Index of the current learning step while the card is in learning
+	params      *FSRSParams // Optional custom weights and learning steps; nil uses the defaults
+	fuzz        bool        // Whether the next review spreads its interval by a random amount
 }
 
 // State represents the learning state of a card
@@ -87,7 +239,7 @@ func NewFSRSCard() *FSRSCard {
 	return &FSRSCard{
 		dueDate:     time.Now(),
 		stability:   1.0,
-		difficulty:  5.0,
+		difficulty:  initialDifficulty,
 		state:       StateNew,
 		reviewCount: 0,
 		lapses:      0,
@@ -102,6 +254,47 @@ func (card *FSRSCard) LastReview() time.Time { return card.lastReview }
 func (card *FSRSCard) State() State          { return card.state }
 func (card *FSRSCard) ReviewCount() int      { return card.reviewCount }
 func (card *FSRSCard) Lapses() int           { return card.lapses }
+func (card *FSRSCard) LearningStep() int     { return card.step }
+
+// Retrievability estimates the probability of recalling the card at the given time.
+// Cards that have never been reviewed have no memory yet and return 0.
+func (card *FSRSCard) Retrievability(now time.Time) float64 {
+	if card.state == StateNew || card.lastReview.IsZero() || card.stability <= 0 {
+		return 0
+	}
+
+	elapsedDays := math.Max(now.Sub(card.lastReview).Hours()/24, 0)
+	return math.Pow(1+factor*elapsedDays/card.stability, decayParam)
+}
+
+// TargetRetention is the recall probability the card's intervals are scheduled for
+func (card *FSRSCard) TargetRetention() float64 { return card.fsrsParams().requestRetention() }
+
+// SetParams sets the weights, learning steps and retention used for the card's next reviews; nil restores the defaults
+func (card *FSRSCard) SetParams(params *FSRSParams) { card.params = params }
+
+// SetIntervalFuzz sets whether the card's next reviews spread their intervals randomly,
+// so cards reviewed together don't all come due together
+func (card *FSRSCard) SetIntervalFuzz(enabled bool) { card.fuzz = enabled }
+
+// Snapshot returns a copy of the card's scheduling state, so a review can later be undone
+func (card *FSRSCard) Snapshot() *FSRSCard {
+	snapshot := *card
+	snapshot.params = nil
+	snapshot.fuzz = false
+	return &snapshot
+}
+
+// fsrsParams returns the card's weights, falling back to the defaults
+func (card *FSRSCard) fsrsParams() *FSRSParams {
+	if card.params == nil {
+		return defaultFSRSParams
+	}
+	return card.params
+}
+
+// defaultFSRSParams is shared by every card without custom weights
+var defaultFSRSParams = DefaultFSRSParams()
 
 // IsDue checks if the card is due for review
 func (card *FSRSCard) IsDue() bool {
@@ -166,23 +359,27 @@ func (card *FSRSCard) Review(rating Rating, reviewTime time.Time) *ReviewResult
 
 func (card *FSRSCard) reviewNew(rating Rating) FSRSCard {
 	newCard := *card
-	newCard.difficulty = initDifficulty(rating)
+	newCard.difficulty = card.fsrsParams().initDifficulty(rating)
+	if card.difficulty != initialDifficulty {
+		// A seeded card keeps its difficulty, nudged by the first rating
+		newCard.difficulty = card.fsrsParams().seededDifficulty(card.difficulty, rating)
+	}
 
+	steps := card.fsrsParams().learningSteps()
 	switch rating {
 	case Again:
 		newCard.state = StateLearning
-		newCard.dueDate = time.Now().Add(1 * time.Minute)
+		newCard.step = 0
+		newCard.dueDate = time.Now().Add(steps[0])
 	case Hard:
 		newCard.state = StateLearning
-		newCard.dueDate = time.Now().Add(5 * time.Minute)
+		newCard.step = 0
+		newCard.dueDate = time.Now().Add(hardStepDelay(steps, 0))
 	case Good:
-		newCard.state = StateLearning
-		newCard.dueDate = time.Now().Add(10 * time.Minute)
+		// A new card counts as sitting on the first step, so Good moves it on to the second
+		card.advanceStep(&newCard, 0)
 	case Easy:
-		newCard.state = StateReview
-		newCard.stability = initStability(rating)
-		interval := calculateInterval(newCard.stability)
-		newCard.dueDate = time.Now().Add(time.Duration(interval) * 24 * time.Hour)
+		card.graduate(&newCard, Easy)
 	}
 
 	return newCard
@@ -191,94 +388,213 @@ func (card *FSRSCard) reviewNew(rating Rating) FSRSCard {
 func (card *FSRSCard) reviewLearning(rating Rating) FSRSCard {
 	newCard := *card
 
+	steps := card.fsrsParams().learningSteps()
+	// The steps may have been shortened since the card was scheduled
+	step := min(card.step, len(steps)-1)
 	switch rating {
 	case Again:
 		newCard.state = StateLearning
-		newCard.dueDate = time.Now().Add(1 * time.Minute)
+		newCard.step = 0
+		newCard.dueDate = time.Now().Add(steps[0])
 	case Hard:
 		newCard.state = StateLearning
-		newCard.dueDate = time.Now().Add(5 * time.Minute)
+		newCard.step = step
+		newCard.dueDate = time.Now().Add(hardStepDelay(steps, step))
 	case Good:
-		newCard.state = StateReview
-		newCard.stability = initStability(Good)
-		interval := calculateInterval(newCard.stability)
-		newCard.dueDate = time.Now().Add(time.Duration(interval) * 24 * time.Hour)
+		if card.state == StateRelearning {
+			card.graduate(&newCard, Good)
+		} else {
+			card.advanceStep(&newCard, step)
+		}
 	case Easy:
-		newCard.state = StateReview
-		newCard.stability = initStability(Easy)
-		interval := calculateInterval(newCard.stability)
-		newCard.dueDate = time.Now().Add(time.Duration(interval) * 24 * time.Hour)
+		card.graduate(&newCard, Easy)
 	}
 
 	return newCard
 }
 
+// advanceStep moves a learning card on from a step after a Good answer, graduating it past the last step
+func (card *FSRSCard) advanceStep(newCard *FSRSCard, step int) {
+	steps := card.fsrsParams().learningSteps()
+	next := step + 1
+	if next >= len(steps) {
+		card.graduate(newCard, Good)
+		return
+	}
+
+	newCard.state = StateLearning
+	newCard.step = next
+	newCard.dueDate = time.Now().Add(steps[next])
+}
+
+// graduate moves a card out of learning into review, with the initial stability of the rating
+func (card *FSRSCard) graduate(newCard *FSRSCard, rating Rating) {
+	newCard.state = StateReview
+	newCard.step = 0
+	newCard.stability = card.fsrsParams().initStability(rating)
+	interval := card.nextInterval(newCard.stability)
+	newCard.dueDate = time.Now().Add(time.Duration(interval) * 24 * time.Hour)
+}
+
+// defaultHardStepDelay is how long a card waits after a Hard answer on the default learning steps,
+// as it did before the steps were configurable
+const defaultHardStepDelay = 5 * time.Minute
+
+// hardStepDelay is how long a card waits after a Hard answer on a learning step: halfway to the
+// next step's delay, or the step's own delay on the last step. The default steps keep a fixed delay.
+func hardStepDelay(steps []time.Duration, step int) time.Duration {
+	if slices.Equal(steps, DefaultLearningSteps()) {
+		return defaultHardStepDelay
+	}
+	if step+1 >= len(steps) {
+		return steps[step]
+	}
+	return (steps[step] + steps[step+1]) / 2
+}
+
 func (card *FSRSCard) reviewReview(rating Rating, elapsed int) FSRSCard {
 	newCard := *card
 
 	if rating == Again {
 		newCard.lapses++
 		newCard.state = StateRelearning
+		newCard.step = 0
 		newCard.dueDate = time.Now().Add(5 * time.Minute)
 	} else {
 		newCard.state = StateReview
-		newCard.stability = nextStability(card.difficulty, card.stability, rating)
-		newCard.difficulty = nextDifficulty(card.difficulty, rating)
-		interval := calculateInterval(newCard.stability)
+		newCard.stability = card.fsrsParams().nextStability(card.difficulty, card.stability, rating)
+		newCard.difficulty = card.fsrsParams().nextDifficulty(card.difficulty, rating)
+		interval := card.nextInterval(newCard.stability)
 		newCard.dueDate = time.Now().Add(time.Duration(interval) * 24 * time.Hour)
 	}
 
 	return newCard
 }
 
+// Seed marks a card as already known, placing it straight into review state
+// with the stability of a first "Good" answer instead of starting from scratch
+func (card *FSRSCard) Seed(seedTime time.Time) {
+	card.state = StateReview
+	card.stability = card.fsrsParams().initStability(Good)
+	card.difficulty = card.fsrsParams().initDifficulty(Good)
+	card.lastReview = seedTime
+	interval := card.fsrsParams().interval(card.stability)
+	card.dueDate = seedTime.Add(time.Duration(interval) * 24 * time.Hour)
+}
+
+// SeedDifficulty starts a new card from a known difficulty, such as how hard other learners
+// found the word, instead of the flat initial difficulty. The first review then adjusts the
+// seeded value by its rating rather than replacing it. Cards already studied are left alone.
+func (card *FSRSCard) SeedDifficulty(difficulty float64) {
+	if card.state != StateNew || card.reviewCount > 0 {
+		return
+	}
+	card.difficulty = math.Max(math.Min(difficulty, MaxDifficulty), MinDifficulty)
+}
+
+// Reschedule recomputes a review card's due date from its stability and last review
+// under the current scheduling parameters, and reports whether the due date moved.
+// Cards that are new or still in (re)learning keep their short-term steps.
+func (card *FSRSCard) Reschedule() bool {
+	if card.state != StateReview || card.lastReview.IsZero() {
+		return false
+	}
+
+	interval := card.fsrsParams().interval(card.stability)
+	dueDate := card.lastReview.Add(time.Duration(interval) * 24 * time.Hour)
+	if dueDate.Equal(card.dueDate) {
+		return false
+	}
+	card.dueDate = dueDate
+	return true
+}
+
 // initDifficulty calculates initial difficulty based on rating
-func initDifficulty(rating Rating) float64 {
-	return math.Max(defaultWeight4-defaultWeight5*float64(rating-3), 1.0)
+func (p *FSRSParams) initDifficulty(rating Rating) float64 {
+	return math.Max(p.Weights[4]-p.Weights[5]*float64(rating-3), 1.0)
+}
+
+// seededDifficulty moves a seeded difficulty by a first rating, the same way initDifficulty
+// moves the default one
+func (p *FSRSParams) seededDifficulty(difficulty float64, rating Rating) float64 {
+	return math.Max(math.Min(difficulty-p.Weights[5]*float64(rating-3), MaxDifficulty), MinDifficulty)
 }
 
 // initStability calculates initial stability based on rating
-func initStability(rating Rating) float64 {
-	return math.Max(defaultWeight0+defaultWeight1*float64(rating-1), 0.1)
+func (p *FSRSParams) initStability(rating Rating) float64 {
+	return math.Max(p.Weights[0]+p.Weights[1]*float64(rating-1), 0.1)
 }
 
 // nextStability calculates next stability value
-func nextStability(difficulty, stability float64, rating Rating) float64 {
+func (p *FSRSParams) nextStability(difficulty, stability float64, rating Rating) float64 {
 	hardPenalty := 1.0
 	if rating == Hard {
-		hardPenalty = defaultWeight6
+		hardPenalty = p.Weights[6]
 	}
 
 	easyBonus := 1.0
 	if rating == Easy {
-		easyBonus = defaultWeight7
+		easyBonus = p.Weights[7]
 	}
 
-	return stability * (1 + math.Exp(defaultWeight8)*
+	return stability * (1 + math.Exp(p.Weights[8])*
 		(11-difficulty)*
-		math.Pow(stability, defaultWeight9)*
-		(math.Exp((1-requestRetention)*defaultWeight10)-1)*
+		math.Pow(stability, p.Weights[9])*
+		(math.Exp((1-p.requestRetention())*p.Weights[10])-1)*
 		hardPenalty*
 		easyBonus)
 }
 
 // nextDifficulty calculates next difficulty value
-func nextDifficulty(difficulty float64, rating Rating) float64 {
-	deltaD := -defaultWeight11 * (float64(rating) - 3)
+func (p *FSRSParams) nextDifficulty(difficulty float64, rating Rating) float64 {
+	deltaD := -p.Weights[11] * (float64(rating) - 3)
 	newDifficulty := difficulty + deltaD
 
 	// Mean reversion to 5.0
-	meanReversion := defaultWeight12 * (5.0 - newDifficulty)
+	meanReversion := p.Weights[12] * (5.0 - newDifficulty)
 	newDifficulty += meanReversion
 
 	return math.Max(math.Min(newDifficulty, 10.0), 1.0)
 }
 
-// calculateInterval calculates review interval based on stability
-func calculateInterval(stability float64) int {
-	interval := stability * math.Log(requestRetention) / math.Log(0.9)
+// interval calculates a review interval in days from stability, scaled so the card is reviewed
+// when its recall probability falls to the request retention
+func (p *FSRSParams) interval(stability float64) int {
+	interval := stability * math.Log(p.requestRetention()) / math.Log(0.9)
 	return int(math.Max(math.Round(interval), 1))
 }
 
+// nextInterval calculates a review interval for the stability, fuzzed when the card asks for it
+func (card *FSRSCard) nextInterval(stability float64) int {
+	interval := card.fsrsParams().interval(stability)
+	if !card.fuzz {
+		return interval
+	}
+	return fuzzInterval(interval)
+}
+
+// fuzzInterval moves an interval of at least minFuzzedInterval days by a random amount of up to
+// intervalFuzzFactor of its length, and at least a day either way. Shorter intervals are returned
+// unchanged, and the result is never below a day.
+func fuzzInterval(interval int) int {
+	if interval < minFuzzedInterval {
+		return interval
+	}
+
+	spread := max(int(math.Round(float64(interval)*intervalFuzzFactor)), 1)
+	offset, err := rand.Int(rand.Reader, big.NewInt(int64(2*spread+1)))
+	if err != nil {
+		return interval
+	}
+	return max(interval+int(offset.Int64())-spread, 1)
+}
+
+// Difficulty bounds used by FSRS
+const (
+	MinDifficulty = 1.0
+	MaxDifficulty = 10.0
+)
+
 // Setters for restoring from database
 func (card *FSRSCard) SetDueDate(dueDate time.Time)       { card.dueDate = dueDate }
 func (card *FSRSCard) SetStability(stability float64)     { card.stability = stability }
@@ -287,3 +603,4 @@ func (card *FSRSCard) SetLastReview(lastReview time.Time) { card.lastReview = la
 func (card *FSRSCard) SetState(state State)               { card.state = state }
 func (card *FSRSCard) SetReviewCount(count int)           { card.reviewCount = count }
 func (card *FSRSCard) SetLapses(lapses int)               { card.lapses = lapses }
+func (card *FSRSCard) SetLearningStep(step int)           { card.step = step }
@@ -0,0 +1,295 @@
+package learning
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSeed(t *testing.T) {
+	seedTime := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	card := NewFSRSCard()
+	card.Seed(seedTime)
+
+	if card.State() != StateReview {
+		t.Errorf("state = %q, want review", card.State())
+	}
+	if !card.LastReview().Equal(seedTime) {
+		t.Errorf("last review = %v, want %v", card.LastReview(), seedTime)
+	}
+	if !card.DueDate().After(seedTime) {
+		t.Errorf("due date %v is not after the seed time", card.DueDate())
+	}
+
+	// A seeded card starts where a first "Good" answer would have left it
+	params := DefaultFSRSParams()
+	if card.Stability() != params.initStability(Good) {
+		t.Errorf("stability = %v, want %v", card.Stability(), params.initStability(Good))
+	}
+}
+
+// reviewCard returns a review card last seen five days before now
+func reviewCard(now time.Time, params *FSRSParams) *FSRSCard {
+	card := NewFSRSCard()
+	card.SetState(StateReview)
+	card.SetStability(5)
+	card.SetDifficulty(5)
+	card.SetReviewCount(3)
+	card.SetLastReview(now.Add(-5 * 24 * time.Hour))
+	card.SetDueDate(now)
+	card.SetParams(params)
+	return card
+}
+
+func TestParseFSRSParams(t *testing.T) {
+	defaults := DefaultFSRSParams().String()
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"default weights", defaults, false},
+		{"too few weights", "[0.4, 1.2, 3.1]", true},
+		{"not an array", `{"w0": 0.4}`, true},
+		{"malformed JSON", defaults[:len(defaults)-1], true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := ParseFSRSParams(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFSRSParams(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && params.String() != tt.value {
+				t.Errorf("round trip = %s, want %s", params.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestFSRSCard_DefaultParams(t *testing.T) {
+	now := time.Now()
+	for _, rating := range []Rating{Again, Hard, Good, Easy} {
+		withoutParams := reviewCard(now, nil).Review(rating, now).Card
+		withDefaults := reviewCard(now, DefaultFSRSParams()).Review(rating, now).Card
+
+		if withoutParams.Stability() != withDefaults.Stability() || withoutParams.Difficulty() != withDefaults.Difficulty() ||
+			withoutParams.State() != withDefaults.State() {
+			t.Errorf("rating %d: a card without params has stability %v, difficulty %v, state %q; want the defaults' %v, %v, %q",
+				rating, withoutParams.Stability(), withoutParams.Difficulty(), withoutParams.State(),
+				withDefaults.Stability(), withDefaults.Difficulty(), withDefaults.State())
+		}
+	}
+}
+
+func TestFSRSCard_CustomWeights(t *testing.T) {
+	now := time.Now()
+	// A larger weight 8 makes successful reviews grow stability faster
+	custom := DefaultFSRSParams()
+	custom.Weights[8] = 2.5
+
+	defaultCard := reviewCard(now, nil).Review(Good, now).Card
+	customCard := reviewCard(now, custom).Review(Good, now).Card
+
+	defaultInterval := DefaultFSRSParams().interval(defaultCard.Stability())
+	customInterval := custom.interval(customCard.Stability())
+	if customInterval <= defaultInterval {
+		t.Errorf("custom weights give a %d-day interval, want more than the default %d", customInterval, defaultInterval)
+	}
+	if !customCard.DueDate().After(defaultCard.DueDate()) {
+		t.Errorf("custom card due %v, want later than the default card's %v", customCard.DueDate(), defaultCard.DueDate())
+	}
+}
+
+func TestFuzzInterval_StaysWithinBounds(t *testing.T) {
+	const runs = 2000
+	tests := []struct {
+		interval int
+		min, max int // Bounds the fuzzed interval must stay within
+	}{
+		{1, 1, 1}, // Too short to fuzz
+		{2, 2, 2},
+		{3, 2, 4}, // At least a day either way
+		{10, 9, 11},
+		{40, 38, 42}, // 5% either way
+		{365, 347, 383},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.interval), func(t *testing.T) {
+			seen := make(map[int]bool)
+			for i := 0; i < runs; i++ {
+				got := fuzzInterval(tt.interval)
+				if got < tt.min || got > tt.max || got < 1 {
+					t.Fatalf("fuzzInterval(%d) = %d, want within [%d, %d]", tt.interval, got, tt.min, tt.max)
+				}
+				seen[got] = true
+			}
+			// Over many runs the whole range turns up, so due dates actually spread out
+			if !seen[tt.min] || !seen[tt.max] {
+				t.Errorf("fuzzInterval(%d) never reached both %d and %d in %d runs", tt.interval, tt.min, tt.max, runs)
+			}
+		})
+	}
+}
+
+func TestFuzzInterval_SkipsLearningSteps(t *testing.T) {
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		card := NewFSRSCard()
+		card.SetIntervalFuzz(true)
+		stepped := card.Review(Good, now).Card
+		if stepped.State() != StateLearning {
+			t.Fatalf("state after a first Good = %q, want learning", stepped.State())
+		}
+		if delay := stepped.DueDate().Sub(now); delay > time.Hour {
+			t.Fatalf("learning step delay = %v with fuzz on, want the unfuzzed minutes", delay)
+		}
+	}
+}
+
+func TestLearningSteps_GoodAnswersToGraduate(t *testing.T) {
+	tests := []struct {
+		steps     string
+		wantGoods int
+	}{
+		{"1m,10m", 2},
+		{"10m", 1},
+		{"1m,10m,1h", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.steps, func(t *testing.T) {
+			steps, err := ParseLearningSteps(tt.steps)
+			if err != nil {
+				t.Fatalf("ParseLearningSteps(%q): %v", tt.steps, err)
+			}
+			params := DefaultFSRSParams()
+			params.LearningSteps = steps
+
+			card := NewFSRSCard()
+			card.SetParams(params)
+			now := time.Now()
+			for i := 1; i <= tt.wantGoods; i++ {
+				card = card.Review(Good, now).Card
+				wantState := StateLearning
+				if i == tt.wantGoods {
+					wantState = StateReview
+				}
+				if card.State() != wantState {
+					t.Fatalf("after %d Good answers state = %q, want %q", i, card.State(), wantState)
+				}
+				// Each Good answer before the last moves the card on to the next step's delay
+				if wantState == StateLearning && card.DueDate().Before(now.Add(steps[i])) {
+					t.Errorf("after %d Good answers due in %v, want at least %v", i, card.DueDate().Sub(now), steps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseLearningSteps(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"1m,10m", "1m,10m", false},
+		{" 30s , 2h ", "30s,2h", false},
+		{"", "", true},
+		{"ten minutes", "", true},
+		{"1m,0s", "", true},
+		{"-5m", "", true},
+		{"1m,1m,1m,1m,1m,1m,1m,1m,1m,1m,1m", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			steps, err := ParseLearningSteps(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLearningSteps(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && FormatLearningSteps(steps) != tt.want {
+				t.Errorf("ParseLearningSteps(%q) = %s, want %s", tt.value, FormatLearningSteps(steps), tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestRetention_ScalesInterval(t *testing.T) {
+	withRetention := func(retention float64) *FSRSParams {
+		params := DefaultFSRSParams()
+		params.RequestRetention = retention
+		return params
+	}
+
+	for _, stability := range []float64{5, 20, 100} {
+		t.Run(strconv.FormatFloat(stability, 'f', -1, 64), func(t *testing.T) {
+			strict := withRetention(0.95).interval(stability)
+			normal := withRetention(DefaultRequestRetention).interval(stability)
+			lenient := withRetention(0.85).interval(stability)
+			if !(strict < normal && normal < lenient) {
+				t.Errorf("intervals at 0.95, 0.90, 0.85 retention = %d, %d, %d days, want them increasing", strict, normal, lenient)
+			}
+			if unset := withRetention(0).interval(stability); unset != normal {
+				t.Errorf("unset retention gives %d days, want the default's %d", unset, normal)
+			}
+			// Values outside the allowed range are clamped to it
+			if got, want := withRetention(0.5).interval(stability), withRetention(MinRequestRetention).interval(stability); got != want {
+				t.Errorf("0.5 retention gives %d days, want the minimum's %d", got, want)
+			}
+			if got, want := withRetention(0.99).interval(stability), withRetention(MaxRequestRetention).interval(stability); got != want {
+				t.Errorf("0.99 retention gives %d days, want the maximum's %d", got, want)
+			}
+		})
+	}
+
+	// The same review schedules an earlier due date under the stricter target
+	now := time.Now()
+	strict := reviewCard(now, withRetention(0.95)).Review(Good, now).Card
+	lenient := reviewCard(now, withRetention(0.85)).Review(Good, now).Card
+	if !strict.DueDate().Before(lenient.DueDate()) {
+		t.Errorf("0.95 retention card due %v, want before the 0.85 card's %v", strict.DueDate(), lenient.DueDate())
+	}
+}
+
+func TestDefaultLearningSteps_KeepBaselineSchedule(t *testing.T) {
+	cardIn := func(state State, step int) *FSRSCard {
+		card := NewFSRSCard()
+		card.SetState(state)
+		card.SetLearningStep(step)
+		return card
+	}
+
+	// The delays cards were scheduled with before learning steps became configurable
+	tests := []struct {
+		name      string
+		card      *FSRSCard
+		rating    Rating
+		wantState State
+		wantDelay time.Duration // 0 when the card graduates
+	}{
+		{"new, Again", NewFSRSCard(), Again, StateLearning, time.Minute},
+		{"new, Hard", NewFSRSCard(), Hard, StateLearning, 5 * time.Minute},
+		{"new, Good", NewFSRSCard(), Good, StateLearning, 10 * time.Minute},
+		{"new, Easy", NewFSRSCard(), Easy, StateReview, 0},
+		{"learning, Again", cardIn(StateLearning, 1), Again, StateLearning, time.Minute},
+		{"learning, Hard", cardIn(StateLearning, 1), Hard, StateLearning, 5 * time.Minute},
+		{"learning, Good", cardIn(StateLearning, 1), Good, StateReview, 0},
+		{"relearning, Hard", cardIn(StateRelearning, 0), Hard, StateLearning, 5 * time.Minute},
+		{"relearning, Good", cardIn(StateRelearning, 0), Good, StateReview, 0},
+		{"review, Again", reviewCard(time.Now(), nil), Again, StateRelearning, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			card := tt.card.Review(tt.rating, before).Card
+			if card.State() != tt.wantState {
+				t.Fatalf("state = %q, want %q", card.State(), tt.wantState)
+			}
+			if tt.wantDelay == 0 {
+				return
+			}
+			if delay := card.DueDate().Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Errorf("due in %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package learning
+
+import (
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// LeaderboardWindow is how far back the weekly leaderboard counts reviews
+const LeaderboardWindow = 7 * 24 * time.Hour
+
+// LeaderboardEntry is one user's place on the weekly leaderboard
+type LeaderboardEntry struct {
+	UserID    user.ID
+	FirstName string
+	Reviews   int // Reviews within LeaderboardWindow
+}
@@ -0,0 +1,39 @@
+package learning
+
+import (
+	"errors"
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// PartnerDeckTag is the tag every shared deck word carries for both partners, so /learn partner studies the deck
+const PartnerDeckTag = "partner"
+
+// ErrAlreadyLinked is returned when linking a user who already has a study partner
+var ErrAlreadyLinked = errors.New("user already has a study partner")
+
+// PartnerLink connects two study partners who share a deck.
+// Each partner keeps their own FSRS progress on the shared words.
+type PartnerLink struct {
+	ID        ID
+	UserID    user.ID // The user who created the invite
+	PartnerID user.ID // The user who accepted it
+	CreatedAt time.Time
+}
+
+// Other returns the partner of the given user in the link
+func (pl *PartnerLink) Other(userID user.ID) user.ID {
+	if pl.UserID == userID {
+		return pl.PartnerID
+	}
+	return pl.UserID
+}
+
+// DeckProgress summarizes one partner's progress on a shared deck
+type DeckProgress struct {
+	TotalWords   int
+	StudiedWords int // Words the partner has started learning
+	LearnedWords int // Words that graduated to review
+	DueWords     int
+}
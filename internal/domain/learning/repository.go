@@ -2,6 +2,7 @@ package learning
 
 import (
 	"context"
+	"time"
 
 	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/domain/vocabulary"
@@ -15,14 +16,18 @@ type Repository interface {
 	// UpdateProgress updates existing user progress
 	UpdateProgress(ctx context.Context, progress *UserProgress) error
 
+	// SaveProgressBatch updates several existing progress records in a single transaction
+	SaveProgressBatch(ctx context.Context, progress []*UserProgress) error
+
 	// FindProgress retrieves user progress for a specific word
 	FindProgress(ctx context.Context, userID user.ID, wordID vocabulary.ID) (*UserProgress, error)
 
-	// FindDueWords retrieves words that are due for review for a user
-	FindDueWords(ctx context.Context, userID user.ID, limit int) ([]*UserProgress, error)
+	// FindDueWords retrieves words that are due for review for a user,
+	// including words that become due within the reviewAhead window
+	FindDueWords(ctx context.Context, userID user.ID, reviewAhead time.Duration, limit int) ([]*UserProgress, error)
 
-	// FindNewWords retrieves words that don't have progress records yet
-	FindNewWords(ctx context.Context, userID user.ID, limit int) ([]*UserProgress, error)
+	// FindNewWords retrieves words that don't have progress records yet, in the given introduction order
+	FindNewWords(ctx context.Context, userID user.ID, selection user.NewWordSelection, limit int) ([]*UserProgress, error)
 
 	// FindProgressByUser retrieves all progress for a user
 	FindProgressByUser(ctx context.Context, userID user.ID) ([]*UserProgress, error)
@@ -33,24 +38,158 @@ type Repository interface {
 	// FindReviewHistory retrieves review history for a user and word
 	FindReviewHistory(ctx context.Context, userID user.ID, wordID vocabulary.ID) ([]*ReviewHistory, error)
 
-	// GetUserStats retrieves learning statistics for a user
-	GetUserStats(ctx context.Context, userID user.ID) (*UserStats, error)
+	// GetUserStats retrieves learning statistics for a user,
+	// counting words due within the reviewAhead window as due
+	GetUserStats(ctx context.Context, userID user.ID, reviewAhead time.Duration) (*UserStats, error)
+
+	// GetRetentionRate computes the fraction of the user's reviews within the window rated Good or Easy,
+	// or NoRetentionRate if there were none. Reviews of words still in learning are left out.
+	GetRetentionRate(ctx context.Context, userID user.ID, window time.Duration) (float64, error)
+
+	// GetUserStatsByCategory retrieves statistics for each category the user has studied words in,
+	// counting words due within the reviewAhead window as due
+	GetUserStatsByCategory(ctx context.Context, userID user.ID, reviewAhead time.Duration) (map[vocabulary.Category]*CategoryStats, error)
 
 	// GetUsersWithProgress retrieves all users who have learning progress
 	GetUsersWithProgress(ctx context.Context) ([]user.ID, error)
 
 	// SaveProgressAndHistory persists both user progress and review history
 	SaveProgressAndHistory(ctx context.Context, progress *UserProgress, history *ReviewHistory) error
+
+	// DeleteProgress deletes a progress record, such as one left behind by a deleted word
+	DeleteProgress(ctx context.Context, id ID) error
+
+	// DeleteAllProgress deletes all of the user's progress, review history and difficulty snapshots in one transaction
+	DeleteAllProgress(ctx context.Context, userID user.ID) error
+
+	// FindLastReview retrieves the user's most recent review, or nil if they have none
+	FindLastReview(ctx context.Context, userID user.ID) (*ReviewHistory, error)
+
+	// RevertReview restores the word's progress to the review's prior card and deletes the review
+	RevertReview(ctx context.Context, history *ReviewHistory) error
+
+	// FindRecentReviewTimes retrieves the most recent review timestamps for a user
+	FindRecentReviewTimes(ctx context.Context, userID user.ID, limit int) ([]time.Time, error)
+
+	// GetStreak computes the user's current and longest runs of consecutive days with at least one review,
+	// with days taken in now's location
+	GetStreak(ctx context.Context, userID user.ID, now time.Time) (current, longest int, err error)
+
+	// GetReviewForecast counts the user's words coming due on each of the next days, starting today,
+	// with days taken in now's location. Overdue words count towards today.
+	GetReviewForecast(ctx context.Context, userID user.ID, now time.Time, days int) ([]ForecastDay, error)
+
+	// SetLowPriority flags or unflags a word as low priority for reminders
+	SetLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID, lowPriority bool) error
+
+	// IsLowPriority checks if a word is flagged as low priority for reminders
+	IsLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID) (bool, error)
+
+	// ReportWord records a user's report that a word is wrong, at most once per user,
+	// and returns how many distinct users have reported the word
+	ReportWord(ctx context.Context, userID user.ID, wordID vocabulary.ID) (int, error)
+
+	// TagWord attaches a user-specific tag to a word
+	TagWord(ctx context.Context, userID user.ID, wordID vocabulary.ID, tag string) error
+
+	// FindTags retrieves the user's distinct tags
+	FindTags(ctx context.Context, userID user.ID) ([]string, error)
+
+	// FindDueWordsByTag retrieves due words carrying the user's tag
+	FindDueWordsByTag(ctx context.Context, userID user.ID, tag string, reviewAhead time.Duration, limit int) ([]*UserProgress, error)
+
+	// FindNewWordsByTag retrieves unstudied words carrying the user's tag
+	FindNewWordsByTag(ctx context.Context, userID user.ID, tag string, selection user.NewWordSelection, limit int) ([]*UserProgress, error)
+
+	// FindDueWordsByCategory retrieves due words in a vocabulary category
+	FindDueWordsByCategory(ctx context.Context, userID user.ID, category vocabulary.Category, reviewAhead time.Duration, limit int) ([]*UserProgress, error)
+
+	// FindNewWordsByCategory retrieves unstudied words in a vocabulary category
+	FindNewWordsByCategory(ctx context.Context, userID user.ID, category vocabulary.Category, selection user.NewWordSelection, limit int) ([]*UserProgress, error)
+
+	// CountIntroducedWords counts the words the user started studying since a given time
+	CountIntroducedWords(ctx context.Context, userID user.ID, since time.Time) (int, error)
+
+	// CountReviewedWordsByCategory counts the distinct words the user reviewed since a given time, per category
+	CountReviewedWordsByCategory(ctx context.Context, userID user.ID, since time.Time) (map[vocabulary.Category]int, error)
+
+	// GetWeeklyLeaderboard ranks users by their reviews in the last 7 days, most first with ties broken
+	// by user ID, leaving out users who hid themselves from the leaderboard
+	GetWeeklyLeaderboard(ctx context.Context, limit int) ([]*LeaderboardEntry, error)
+
+	// SaveSessionLog records the aggregates of a finished learning session
+	SaveSessionLog(ctx context.Context, sessionLog *SessionLog) error
+
+	// FindSessionLogs retrieves the user's finished sessions that started on or after since
+	FindSessionLogs(ctx context.Context, userID user.ID, since time.Time) ([]*SessionLog, error)
+
+	// GetCommunityDifficulty averages the difficulty of a word across the learners other than
+	// excludeUserID who have reviewed it, returning the average and how many learners it covers
+	GetCommunityDifficulty(ctx context.Context, wordID vocabulary.ID, excludeUserID user.ID) (float64, int, error)
+
+	// RecordDifficultySnapshot records the user's current average difficulty as the snapshot for a day,
+	// replacing any earlier value that day. The day is the calendar date of day in its own location.
+	// Users without progress get no snapshot.
+	RecordDifficultySnapshot(ctx context.Context, userID user.ID, day time.Time) error
+
+	// FindDifficultySnapshots retrieves the user's daily difficulty snapshots since a given day,
+	// with each snapshot dated at midnight UTC of its calendar date
+	FindDifficultySnapshots(ctx context.Context, userID user.ID, since time.Time) ([]*DifficultySnapshot, error)
+
+	// CreatePartnerLink links two users as study partners, failing with ErrAlreadyLinked if either already has one
+	CreatePartnerLink(ctx context.Context, userID, partnerID user.ID) (*PartnerLink, error)
+
+	// FindPartnerLink retrieves the user's study partner link, or nil if they have none
+	FindPartnerLink(ctx context.Context, userID user.ID) (*PartnerLink, error)
+
+	// DeletePartnerLink removes a study partner link together with its shared deck
+	DeletePartnerLink(ctx context.Context, linkID ID) error
+
+	// AddSharedDeckWord adds a word to the link's shared deck and tags it for both partners.
+	// It returns false if the word was already in the deck.
+	AddSharedDeckWord(ctx context.Context, link *PartnerLink, wordID vocabulary.ID) (bool, error)
+
+	// GetSharedDeckProgress summarizes a partner's own progress on the link's shared deck
+	GetSharedDeckProgress(ctx context.Context, linkID ID, userID user.ID) (*DeckProgress, error)
 }
 
 // UserStats represents learning statistics for a user
 type UserStats struct {
-	TotalWords     int
-	NewWords       int
-	LearningWords  int
-	ReviewWords    int
-	DueWords       int
-	AvgDifficulty  float64
-	TotalReviews   int
-	CorrectReviews int
+	TotalWords int
+	// NewWords counts words never studied, which /learn introduces once due reviews run out
+	NewWords      int
+	LearningWords int
+	ReviewWords   int
+	// DueWords counts studied words whose review is due (new words are never included)
+	DueWords int
+	// LowPriorityDueWords is the part of DueWords the user muted from reminders
+	LowPriorityDueWords int
+	AvgDifficulty       float64
+	// DifficultyTrend tells whether the average difficulty is rising or falling
+	DifficultyTrend Trend
+	TotalReviews    int
+	CorrectReviews  int
+	// WeightedAccuracy is the average answer score (0-1), giving partial credit for near misses
+	WeightedAccuracy float64
+	// RetentionRate is the fraction (0-1) of reviews in the last RetentionWindow that were recalled,
+	// or NoRetentionRate when there were none
+	RetentionRate float64
+	// CurrentStreak and LongestStreak count consecutive days with at least one review
+	CurrentStreak int
+	LongestStreak int
+}
+
+// RetentionWindow is how far back the retention rate shown in stats looks
+const RetentionWindow = 30 * 24 * time.Hour
+
+// NoRetentionRate is the retention rate reported when there are no reviews to compute it from
+const NoRetentionRate = -1.0
+
+// CategoryStats represents a user's learning statistics for one vocabulary category
+type CategoryStats struct {
+	// Studied counts the words in the category the user has progress on
+	Studied int
+	// Due counts the studied words whose review is due
+	Due           int
+	AvgDifficulty float64
 }
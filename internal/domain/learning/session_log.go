@@ -0,0 +1,37 @@
+package learning
+
+import (
+	"time"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// SessionLog is the aggregate record of a finished learning session
+type SessionLog struct {
+	UserID         user.ID
+	StartedAt      time.Time
+	EndedAt        time.Time
+	CorrectCount   int
+	IncorrectCount int
+	NewWords       int // Words seen for the first time in the session
+	ReviewWords    int // Words that had been studied before
+	Practice       bool
+}
+
+// WordCount returns how many questions were answered in the session
+func (sl *SessionLog) WordCount() int {
+	return sl.CorrectCount + sl.IncorrectCount
+}
+
+// Accuracy returns the share of correct answers (0-1)
+func (sl *SessionLog) Accuracy() float64 {
+	if sl.WordCount() == 0 {
+		return 0
+	}
+	return float64(sl.CorrectCount) / float64(sl.WordCount())
+}
+
+// Duration returns how long the session lasted
+func (sl *SessionLog) Duration() time.Duration {
+	return sl.EndedAt.Sub(sl.StartedAt)
+}
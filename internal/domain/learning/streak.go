@@ -0,0 +1,43 @@
+package learning
+
+import "time"
+
+// StudyStreak computes the user's current and longest runs of consecutive calendar days with
+// at least one review, with days taken in now's location. Several reviews on one day count once.
+// The current streak still counts when the last review was yesterday, since today isn't over yet.
+func StudyStreak(reviewTimes []time.Time, now time.Time) (current, longest int) {
+	days := make(map[time.Time]bool, len(reviewTimes))
+	for _, reviewTime := range reviewTimes {
+		days[startOfDay(reviewTime.In(now.Location()))] = true
+	}
+
+	for day := range days {
+		// Only count runs from their first day
+		if days[day.AddDate(0, 0, -1)] {
+			continue
+		}
+		length := 1
+		for days[day.AddDate(0, 0, length)] {
+			length++
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	day := startOfDay(now)
+	if !days[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return current, longest
+}
+
+// startOfDay returns midnight at the start of t's day, in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,16 @@
+package learning
+
+import "strings"
+
+// MaxTagLength bounds the length of a user-defined word tag
+const MaxTagLength = 32
+
+// NormalizeTag turns user input like "#Travel" into the stored tag form "travel".
+// It returns "" when the input isn't a usable tag.
+func NormalizeTag(input string) string {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(input), "#"))
+	if tag == "" || len(tag) > MaxTagLength || strings.ContainsAny(tag, " \t\n") {
+		return ""
+	}
+	return tag
+}
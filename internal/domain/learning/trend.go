@@ -0,0 +1,54 @@
+package learning
+
+import "time"
+
+// Trend represents the direction a metric is moving in
+type Trend string
+
+const (
+	TrendUnknown Trend = ""
+	TrendUp      Trend = "up"
+	TrendDown    Trend = "down"
+	TrendFlat    Trend = "flat"
+)
+
+// trendThreshold is the minimum change in average difficulty considered a real trend
+const trendThreshold = 0.1
+
+// DifficultySnapshot is a user's average difficulty on a given day
+type DifficultySnapshot struct {
+	Date          time.Time
+	AvgDifficulty float64
+}
+
+// DifficultyTrend compares the trailing window of snapshots against the window before it.
+// Snapshots on or after windowStart form the recent window; earlier ones form the previous window.
+func DifficultyTrend(snapshots []*DifficultySnapshot, windowStart time.Time) Trend {
+	var recentSum, earlierSum float64
+	var recentCount, earlierCount int
+
+	for _, snapshot := range snapshots {
+		if snapshot.Date.Before(windowStart) {
+			earlierSum += snapshot.AvgDifficulty
+			earlierCount++
+		} else {
+			recentSum += snapshot.AvgDifficulty
+			recentCount++
+		}
+	}
+
+	// Need data in both windows to compare
+	if recentCount == 0 || earlierCount == 0 {
+		return TrendUnknown
+	}
+
+	delta := recentSum/float64(recentCount) - earlierSum/float64(earlierCount)
+	switch {
+	case delta > trendThreshold:
+		return TrendUp
+	case delta < -trendThreshold:
+		return TrendDown
+	default:
+		return TrendFlat
+	}
+}
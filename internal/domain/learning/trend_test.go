@@ -0,0 +1,37 @@
+package learning
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDifficultyTrend(t *testing.T) {
+	windowStart := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+	earlier := windowStart.AddDate(0, 0, -3)
+	recent := windowStart.AddDate(0, 0, 2)
+
+	tests := []struct {
+		name      string
+		snapshots []*DifficultySnapshot
+		want      Trend
+	}{
+		{"no snapshots", nil, TrendUnknown},
+		{"only recent", []*DifficultySnapshot{{recent, 5}}, TrendUnknown},
+		{"only earlier", []*DifficultySnapshot{{earlier, 5}}, TrendUnknown},
+		{"rising", []*DifficultySnapshot{{earlier, 4}, {recent, 5}}, TrendUp},
+		{"falling", []*DifficultySnapshot{{earlier, 6}, {recent, 5}}, TrendDown},
+		{"within threshold", []*DifficultySnapshot{{earlier, 5}, {recent, 5.05}}, TrendFlat},
+		{"averages each window", []*DifficultySnapshot{
+			{earlier, 4}, {earlier.AddDate(0, 0, 1), 6}, // Earlier average 5
+			{recent, 5.5}, {recent.AddDate(0, 0, 1), 5.3}, // Recent average 5.4
+		}, TrendUp},
+		{"window start counts as recent", []*DifficultySnapshot{{earlier, 5}, {windowStart, 3}}, TrendDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DifficultyTrend(tt.snapshots, windowStart); got != tt.want {
+				t.Errorf("DifficultyTrend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CategoryDirections pins the question direction of individual vocabulary categories,
+// overriding the user's overall direction. Stored as "verbs:to_dutch,food:from_dutch".
+type CategoryDirections map[string]QuestionDirection
+
+// questionDirectionAliases are the shorthand names accepted alongside the stored direction values
+var questionDirectionAliases = map[string]QuestionDirection{
+	"both":  QuestionDirectionMixed,
+	"en_nl": QuestionDirectionToDutch,
+	"nl_en": QuestionDirectionFromDutch,
+}
+
+// ParseQuestionDirection parses a question direction or one of its aliases, reporting whether it is supported
+func ParseQuestionDirection(value string) (QuestionDirection, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if direction, ok := questionDirectionAliases[value]; ok {
+		return direction, true
+	}
+	for _, direction := range QuestionDirections {
+		if string(direction) == value {
+			return direction, true
+		}
+	}
+	return "", false
+}
+
+// ParseCategoryDirections parses "category:direction" pairs separated by commas or spaces
+func ParseCategoryDirections(value string) (CategoryDirections, error) {
+	directions := make(CategoryDirections)
+
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, field := range fields {
+		category, directionStr, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected category:direction, got %q", field)
+		}
+
+		category = strings.ToLower(strings.TrimSpace(category))
+		direction, ok := ParseQuestionDirection(directionStr)
+		if !ok {
+			return nil, fmt.Errorf("unknown direction %q for %q", directionStr, category)
+		}
+		directions[category] = direction
+	}
+
+	return directions, nil
+}
+
+// String formats the directions in their stored form, sorted by category
+func (d CategoryDirections) String() string {
+	categories := make([]string, 0, len(d))
+	for category := range d {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s:%s", category, d[category])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxCategoryQuota bounds how many words of one category a daily mix may ask for
+const MaxCategoryQuota = 200
+
+// CategoryQuota is how many words of one vocabulary category to study per day
+type CategoryQuota struct {
+	Category string
+	Words    int
+}
+
+// DailyMix is a per-category daily study plan, stored as "food:10,verbs:10"
+type DailyMix []CategoryQuota
+
+// ParseDailyMix parses a daily mix from "category:count" pairs separated by commas or spaces
+func ParseDailyMix(value string) (DailyMix, error) {
+	var mix DailyMix
+	seen := make(map[string]bool)
+
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, field := range fields {
+		category, countStr, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected category:count, got %q", field)
+		}
+
+		category = strings.ToLower(strings.TrimSpace(category))
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count < 1 || count > MaxCategoryQuota {
+			return nil, fmt.Errorf("count for %q must be between 1 and %d", category, MaxCategoryQuota)
+		}
+		if seen[category] {
+			return nil, fmt.Errorf("category %q is listed twice", category)
+		}
+		seen[category] = true
+
+		mix = append(mix, CategoryQuota{Category: category, Words: count})
+	}
+
+	return mix, nil
+}
+
+// String formats the mix in its stored form
+func (m DailyMix) String() string {
+	parts := make([]string, len(m))
+	for i, quota := range m {
+		parts[i] = fmt.Sprintf("%s:%d", quota.Category, quota.Words)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,31 @@
+package user
+
+import "testing"
+
+func TestParseDailyMix(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"food:10,verbs:10", "food:10,verbs:10", false},
+		{"Food:5 verbs:3", "food:5,verbs:3", false},
+		{"", "", false},
+		{"food", "", true},
+		{"food:0", "", true},
+		{"food:201", "", true},
+		{"food:ten", "", true},
+		{"food:1,FOOD:2", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			mix, err := ParseDailyMix(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDailyMix(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got := mix.String(); got != tt.want {
+				t.Errorf("ParseDailyMix(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -46,11 +46,28 @@ func (u *User) LanguageCode() string   { return u.languageCode }
 func (u *User) CreatedAt() time.Time   { return u.createdAt }
 func (u *User) LastActive() time.Time  { return u.lastActive }
 
+// DisplayName returns the name to greet the user by, falling back from
+// first name to username to a generic "there"
+func (u *User) DisplayName() string {
+	if u.firstName != "" {
+		return u.firstName
+	}
+	if u.username != "" {
+		return u.username
+	}
+	return "there"
+}
+
 // SetID sets the user ID (used by repository)
 func (u *User) SetID(id ID) {
 	u.id = id
 }
 
+// SetLastActive sets the last active timestamp (used by repository)
+func (u *User) SetLastActive(lastActive time.Time) {
+	u.lastActive = lastActive
+}
+
 // UpdateLastActive updates the last active timestamp
 func (u *User) UpdateLastActive() {
 	u.lastActive = time.Now()
@@ -0,0 +1,24 @@
+package user
+
+import "testing"
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		firstName string
+		want      string
+	}{
+		{"first name", "anna_nl", "Anna", "Anna"},
+		{"username fallback", "anna_nl", "", "anna_nl"},
+		{"generic fallback", "", "", "there"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUser(42, tt.username, tt.firstName, "", "en")
+			if got := u.DisplayName(); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
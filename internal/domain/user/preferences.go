@@ -1,22 +1,194 @@
 package user
 
 import (
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Preference keys constants
 const (
-	PrefGrammarTipsEnabled        = "grammar_tips_enabled"
-	PrefSmartRemindersEnabled     = "smart_reminders_enabled"
-	PreferenceKeyReminderInterval = "reminder_interval_minutes"
+	PrefGrammarTipsEnabled    = "grammar_tips_enabled"
+	PrefSmartRemindersEnabled = "smart_reminders_enabled"
+	PrefReminderInterval      = "reminder_interval_minutes"
+	PrefReviewAheadMinutes    = "review_ahead_minutes"
+	PrefShowSessionProgress   = "show_session_progress"
+	PrefMaxSessionMinutes     = "max_session_minutes"
+	PrefDailyNewLimit         = "daily_new_limit"
+	PrefIgnoreArticles        = "ignore_articles"
+	PrefStudyPriority         = "study_priority"
+	PrefStagedReveal          = "staged_reveal"
+	PrefHintType              = "hint_type"
+	PrefNextReminderAt        = "next_reminder_at"
+	PrefChoiceGrading         = "choice_grading"
+	PrefQuestionDirection     = "question_direction"
+	PrefDailyMix              = "daily_mix"
+	PrefAutoEasyFast          = "auto_easy_fast"
+	PrefNewWordOrder          = "new_word_order"
+	PrefNewWordSeed           = "new_word_seed"
+	PrefNewWordCategories     = "new_word_categories"
+	PrefRecognitionFirst      = "recognition_first"
+	PrefReminderMode          = "reminder_mode"
+	PrefDigestHour            = "digest_hour"
+	PrefLastDigestAt          = "last_digest_at"
+	PrefShuffleRatings        = "shuffle_ratings"
+	PrefPartnerInvite         = "partner_invite"
+	PrefShowWordSense         = "show_word_sense"
+	PrefFSRSWeights           = "fsrs_weights"
+	PrefLearningSteps         = "learning_steps"
+	PrefRequestRetention      = "request_retention"
+	PrefAnswerMode            = "answer_mode"
+	PrefStrictAccents         = "strict_accents"
+	PrefSpellingVariants      = "spelling_variants"
+	PrefIntervalFuzz          = "interval_fuzz"
+	PrefSessionRecap          = "session_recap"
+	PrefCommunityDifficulty   = "community_difficulty"
+	PrefLeaderboardVisible    = "leaderboard_visible"
+	PrefReviewsOnly           = "reviews_only"
+	PrefCategoryDirections    = "category_directions"
+	PrefRateConfidence        = "rate_confidence"
+	PrefTimezone              = "timezone"
 )
 
 // Default values
 const (
 	DefaultGrammarTipsEnabled    = true
 	DefaultSmartRemindersEnabled = true
-	DefaultReminderInterval      = 30
+	DefaultReminderInterval      = 240
+	DefaultReviewAheadMinutes    = 0
+	DefaultShowSessionProgress   = false
+	DefaultMaxSessionMinutes     = 0
+	DefaultDailyNewLimit         = 10
+	DefaultIgnoreArticles        = false
+	DefaultStrictAccents         = false
+	DefaultSpellingVariants      = true
+	DefaultIntervalFuzz          = true
+	DefaultSessionRecap          = true
+	DefaultCommunityDifficulty   = false
+	DefaultLeaderboardVisible    = true
+	DefaultReviewsOnly           = false
+	DefaultRateConfidence        = false
+	DefaultStudyPriority         = StudyPriorityBalanced
+	DefaultStagedReveal          = false
+	DefaultAutoEasyFast          = false
+	DefaultRecognitionFirst      = false
+	DefaultShuffleRatings        = false
+	DefaultShowWordSense         = true
+	DefaultReminderMode          = ReminderModeSmart
+	DefaultDigestHour            = 18
+	DefaultHintType              = HintTypeCategory
+	DefaultChoiceGrading         = ChoiceGradingSelf
+	DefaultQuestionDirection     = QuestionDirectionMixed
+	DefaultNewWordOrder          = NewWordOrderRandom
+	DefaultAnswerMode            = AnswerModeChoice
+)
+
+// HintType controls which hint accompanies a question
+type HintType string
+
+const (
+	HintTypeCategory    HintType = "category"
+	HintTypeFirstLetter HintType = "first_letter"
+	HintTypeLength      HintType = "length"
+	HintTypeNone        HintType = "none"
+)
+
+// HintTypes lists every supported hint type
+var HintTypes = []HintType{HintTypeCategory, HintTypeFirstLetter, HintTypeLength, HintTypeNone}
+
+// ParseHintType parses a hint type, reporting whether it is supported
+func ParseHintType(value string) (HintType, bool) {
+	for _, hintType := range HintTypes {
+		if string(hintType) == value {
+			return hintType, true
+		}
+	}
+	return "", false
+}
+
+// QuestionDirection controls which side of a word is the prompt and which is the answer
+type QuestionDirection string
+
+const (
+	// QuestionDirectionMixed picks a random direction for every question
+	QuestionDirectionMixed QuestionDirection = "mixed"
+	// QuestionDirectionToDutch always asks for the Dutch word
+	QuestionDirectionToDutch QuestionDirection = "to_dutch"
+	// QuestionDirectionFromDutch always shows the Dutch word and asks for its translation
+	QuestionDirectionFromDutch QuestionDirection = "from_dutch"
+)
+
+// QuestionDirections lists every supported direction in settings cycle order
+var QuestionDirections = []QuestionDirection{QuestionDirectionMixed, QuestionDirectionToDutch, QuestionDirectionFromDutch}
+
+// ChoiceGrading controls how self-ratings work after a correct multiple-choice answer,
+// where a lucky guess can otherwise be rated as if the word was known cold
+type ChoiceGrading string
+
+const (
+	// ChoiceGradingSelf offers every rating
+	ChoiceGradingSelf ChoiceGrading = "self"
+	// ChoiceGradingCapped hides Easy after a correct answer
+	ChoiceGradingCapped ChoiceGrading = "capped"
+	// ChoiceGradingAutoGood rates a correct answer as Good without asking
+	ChoiceGradingAutoGood ChoiceGrading = "auto_good"
+)
+
+// ChoiceGradings lists every supported grading mode in settings cycle order
+var ChoiceGradings = []ChoiceGrading{ChoiceGradingSelf, ChoiceGradingCapped, ChoiceGradingAutoGood}
+
+// NewWordOrder controls the order in which unstudied words are introduced
+type NewWordOrder string
+
+const (
+	// NewWordOrderRandom introduces new words in random order
+	NewWordOrderRandom NewWordOrder = "random"
+	// NewWordOrderSequential introduces new words in the order they appear in the vocabulary files
+	NewWordOrderSequential NewWordOrder = "sequential"
+)
+
+// AnswerMode controls how the user answers a question
+type AnswerMode string
+
+const (
+	// AnswerModeChoice picks the translation from multiple-choice buttons
+	AnswerModeChoice AnswerMode = "choice"
+	// AnswerModeTyped types the translation as a free-text message
+	AnswerModeTyped AnswerMode = "typed"
+)
+
+// ReminderMode controls how a user is reminded about due words
+type ReminderMode string
+
+const (
+	// ReminderModeSmart sends paced reminders throughout the day while words are due
+	ReminderModeSmart ReminderMode = "smart"
+	// ReminderModeDailyDigest sends a single summary at the user's chosen hour
+	ReminderModeDailyDigest ReminderMode = "daily_digest"
+)
+
+// MaxNewWordSeed is the largest seed accepted for a shuffled new-word order
+const MaxNewWordSeed = 2147483646
+
+// NewWordSelection describes how unstudied words are ordered when they are introduced
+type NewWordSelection struct {
+	Order NewWordOrder
+	// Seed fixes the random order until the user reshuffles; 0 picks a fresh order on every lookup
+	Seed int64
+	// Categories limits new words to these vocabulary categories; empty allows every category
+	Categories []string
+}
+
+// StudyPriority controls which due cards a learning session serves first
+type StudyPriority string
+
+const (
+	// StudyPriorityBalanced serves due reviews, then new words, spreading reviews across the deck
+	StudyPriorityBalanced StudyPriority = "balanced"
+	// StudyPriorityLearningFirst serves cards still in (re)learning first so they graduate sooner
+	StudyPriorityLearningFirst StudyPriority = "learning_first"
 )
 
 // UserPreference represents a user preference
@@ -38,9 +210,9 @@ type UserPreferences struct {
 // NewUserPreferences creates a new user preferences with default values
 func NewUserPreferences(userID ID) *UserPreferences {
 	defaultPrefs := map[string]string{
-		PrefGrammarTipsEnabled:        "true",
-		PrefSmartRemindersEnabled:     "true",
-		PreferenceKeyReminderInterval: strconv.Itoa(DefaultReminderInterval),
+		PrefGrammarTipsEnabled:    "true",
+		PrefSmartRemindersEnabled: "true",
+		PrefReminderInterval:      strconv.Itoa(DefaultReminderInterval),
 	}
 
 	return &UserPreferences{
@@ -59,7 +231,8 @@ func (up *UserPreferences) GetBoolPreference(key string) bool {
 	if !exists {
 		// Return default values for known preferences
 		switch key {
-		case PrefGrammarTipsEnabled, PrefSmartRemindersEnabled:
+		case PrefGrammarTipsEnabled, PrefSmartRemindersEnabled, PrefShowWordSense, PrefSpellingVariants, PrefIntervalFuzz, PrefSessionRecap,
+			PrefLeaderboardVisible:
 			return true
 		default:
 			return false
@@ -93,6 +266,18 @@ func (up *UserPreferences) GetAllPreferences() map[string]string {
 	return up.preferences
 }
 
+// Clone returns an independent copy of the preferences
+func (up *UserPreferences) Clone() *UserPreferences {
+	preferences := make(map[string]string, len(up.preferences))
+	for key, value := range up.preferences {
+		preferences[key] = value
+	}
+	return &UserPreferences{
+		userID:      up.userID,
+		preferences: preferences,
+	}
+}
+
 func (up *UserPreferences) SetPreferences(preferences map[string]string) {
 	up.preferences = preferences
 }
@@ -126,23 +311,650 @@ func (up *UserPreferences) ToggleSmartReminders() bool {
 	return newValue
 }
 
+func (up *UserPreferences) ShowSessionProgress() bool {
+	return up.GetBoolPreference(PrefShowSessionProgress)
+}
+
+func (up *UserPreferences) SetShowSessionProgress(enabled bool) {
+	up.SetBoolPreference(PrefShowSessionProgress, enabled)
+}
+
+func (up *UserPreferences) ToggleShowSessionProgress() bool {
+	newValue := !up.ShowSessionProgress()
+	up.SetShowSessionProgress(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) IgnoreArticles() bool {
+	return up.GetBoolPreference(PrefIgnoreArticles)
+}
+
+func (up *UserPreferences) SetIgnoreArticles(enabled bool) {
+	up.SetBoolPreference(PrefIgnoreArticles, enabled)
+}
+
+func (up *UserPreferences) ToggleIgnoreArticles() bool {
+	newValue := !up.IgnoreArticles()
+	up.SetIgnoreArticles(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) StrictAccents() bool {
+	return up.GetBoolPreference(PrefStrictAccents)
+}
+
+func (up *UserPreferences) SetStrictAccents(enabled bool) {
+	up.SetBoolPreference(PrefStrictAccents, enabled)
+}
+
+func (up *UserPreferences) ToggleStrictAccents() bool {
+	newValue := !up.StrictAccents()
+	up.SetStrictAccents(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) SpellingVariants() bool {
+	return up.GetBoolPreference(PrefSpellingVariants)
+}
+
+func (up *UserPreferences) SetSpellingVariants(enabled bool) {
+	up.SetBoolPreference(PrefSpellingVariants, enabled)
+}
+
+func (up *UserPreferences) ToggleSpellingVariants() bool {
+	newValue := !up.SpellingVariants()
+	up.SetSpellingVariants(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) IntervalFuzz() bool {
+	return up.GetBoolPreference(PrefIntervalFuzz)
+}
+
+func (up *UserPreferences) SetIntervalFuzz(enabled bool) {
+	up.SetBoolPreference(PrefIntervalFuzz, enabled)
+}
+
+func (up *UserPreferences) ToggleIntervalFuzz() bool {
+	newValue := !up.IntervalFuzz()
+	up.SetIntervalFuzz(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) SessionRecap() bool {
+	return up.GetBoolPreference(PrefSessionRecap)
+}
+
+func (up *UserPreferences) SetSessionRecap(enabled bool) {
+	up.SetBoolPreference(PrefSessionRecap, enabled)
+}
+
+func (up *UserPreferences) ToggleSessionRecap() bool {
+	newValue := !up.SessionRecap()
+	up.SetSessionRecap(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) CommunityDifficulty() bool {
+	return up.GetBoolPreference(PrefCommunityDifficulty)
+}
+
+func (up *UserPreferences) SetCommunityDifficulty(enabled bool) {
+	up.SetBoolPreference(PrefCommunityDifficulty, enabled)
+}
+
+func (up *UserPreferences) ToggleCommunityDifficulty() bool {
+	newValue := !up.CommunityDifficulty()
+	up.SetCommunityDifficulty(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) LeaderboardVisible() bool {
+	return up.GetBoolPreference(PrefLeaderboardVisible)
+}
+
+func (up *UserPreferences) SetLeaderboardVisible(visible bool) {
+	up.SetBoolPreference(PrefLeaderboardVisible, visible)
+}
+
+func (up *UserPreferences) ReviewsOnly() bool {
+	return up.GetBoolPreference(PrefReviewsOnly)
+}
+
+func (up *UserPreferences) SetReviewsOnly(enabled bool) {
+	up.SetBoolPreference(PrefReviewsOnly, enabled)
+}
+
+func (up *UserPreferences) ToggleReviewsOnly() bool {
+	newValue := !up.ReviewsOnly()
+	up.SetReviewsOnly(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) RateConfidence() bool {
+	return up.GetBoolPreference(PrefRateConfidence)
+}
+
+func (up *UserPreferences) SetRateConfidence(enabled bool) {
+	up.SetBoolPreference(PrefRateConfidence, enabled)
+}
+
+func (up *UserPreferences) ToggleRateConfidence() bool {
+	newValue := !up.RateConfidence()
+	up.SetRateConfidence(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) StagedReveal() bool {
+	return up.GetBoolPreference(PrefStagedReveal)
+}
+
+func (up *UserPreferences) SetStagedReveal(enabled bool) {
+	up.SetBoolPreference(PrefStagedReveal, enabled)
+}
+
+func (up *UserPreferences) ToggleStagedReveal() bool {
+	newValue := !up.StagedReveal()
+	up.SetStagedReveal(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) AutoEasyFast() bool {
+	return up.GetBoolPreference(PrefAutoEasyFast)
+}
+
+func (up *UserPreferences) SetAutoEasyFast(enabled bool) {
+	up.SetBoolPreference(PrefAutoEasyFast, enabled)
+}
+
+func (up *UserPreferences) ToggleAutoEasyFast() bool {
+	newValue := !up.AutoEasyFast()
+	up.SetAutoEasyFast(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) RecognitionFirst() bool {
+	return up.GetBoolPreference(PrefRecognitionFirst)
+}
+
+func (up *UserPreferences) SetRecognitionFirst(enabled bool) {
+	up.SetBoolPreference(PrefRecognitionFirst, enabled)
+}
+
+func (up *UserPreferences) ToggleRecognitionFirst() bool {
+	newValue := !up.RecognitionFirst()
+	up.SetRecognitionFirst(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) ShuffleRatings() bool {
+	return up.GetBoolPreference(PrefShuffleRatings)
+}
+
+func (up *UserPreferences) SetShuffleRatings(enabled bool) {
+	up.SetBoolPreference(PrefShuffleRatings, enabled)
+}
+
+func (up *UserPreferences) ToggleShuffleRatings() bool {
+	newValue := !up.ShuffleRatings()
+	up.SetShuffleRatings(newValue)
+	return newValue
+}
+
+func (up *UserPreferences) ShowWordSense() bool {
+	return up.GetBoolPreference(PrefShowWordSense)
+}
+
+func (up *UserPreferences) SetShowWordSense(enabled bool) {
+	up.SetBoolPreference(PrefShowWordSense, enabled)
+}
+
+func (up *UserPreferences) ToggleShowWordSense() bool {
+	newValue := !up.ShowWordSense()
+	up.SetShowWordSense(newValue)
+	return newValue
+}
+
+// MinReminderInterval is the shortest reminder interval, in minutes, a user can choose
+const MinReminderInterval = 1
+
 // GetReminderInterval gets the reminder interval in minutes
 func (p *UserPreferences) GetReminderInterval() int {
-	value, exists := p.preferences[PreferenceKeyReminderInterval]
+	value, exists := p.preferences[PrefReminderInterval]
 	if !exists {
 		return DefaultReminderInterval
 	}
 	interval, err := strconv.Atoi(value)
-	if err != nil || interval < 1 {
+	if err != nil || interval < MinReminderInterval {
 		return DefaultReminderInterval
 	}
 	return interval
 }
 
-// SetReminderInterval sets the reminder interval in minutes
+// SetReminderInterval sets the reminder interval in minutes; values below the minimum restore the default
 func (p *UserPreferences) SetReminderInterval(minutes int) {
-	if minutes < 1 {
+	if minutes < MinReminderInterval {
 		minutes = DefaultReminderInterval
 	}
-	p.preferences[PreferenceKeyReminderInterval] = strconv.Itoa(minutes)
+	p.preferences[PrefReminderInterval] = strconv.Itoa(minutes)
+}
+
+// GetReviewAheadMinutes gets how many minutes ahead nearly-due words count as due
+func (p *UserPreferences) GetReviewAheadMinutes() int {
+	value, exists := p.preferences[PrefReviewAheadMinutes]
+	if !exists {
+		return DefaultReviewAheadMinutes
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < 0 {
+		return DefaultReviewAheadMinutes
+	}
+	return minutes
+}
+
+// SetReviewAheadMinutes sets how many minutes ahead nearly-due words count as due
+func (p *UserPreferences) SetReviewAheadMinutes(minutes int) {
+	if minutes < 0 {
+		minutes = DefaultReviewAheadMinutes
+	}
+	p.preferences[PrefReviewAheadMinutes] = strconv.Itoa(minutes)
+}
+
+// ReviewAheadWindow returns the review-ahead window as a duration
+func (p *UserPreferences) ReviewAheadWindow() time.Duration {
+	return time.Duration(p.GetReviewAheadMinutes()) * time.Minute
+}
+
+// GetMaxSessionMinutes gets the wall-clock session cap in minutes (0 means no cap)
+func (p *UserPreferences) GetMaxSessionMinutes() int {
+	value, exists := p.preferences[PrefMaxSessionMinutes]
+	if !exists {
+		return DefaultMaxSessionMinutes
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < 0 {
+		return DefaultMaxSessionMinutes
+	}
+	return minutes
+}
+
+// SetMaxSessionMinutes sets the wall-clock session cap in minutes (0 means no cap)
+func (p *UserPreferences) SetMaxSessionMinutes(minutes int) {
+	if minutes < 0 {
+		minutes = DefaultMaxSessionMinutes
+	}
+	p.preferences[PrefMaxSessionMinutes] = strconv.Itoa(minutes)
+}
+
+// MaxSessionDuration returns the session cap as a duration (0 means no cap)
+func (p *UserPreferences) MaxSessionDuration() time.Duration {
+	return time.Duration(p.GetMaxSessionMinutes()) * time.Minute
+}
+
+// GetDailyNewLimit gets how many new words may be introduced per day (0 means no limit)
+func (p *UserPreferences) GetDailyNewLimit() int {
+	value, exists := p.preferences[PrefDailyNewLimit]
+	if !exists {
+		return DefaultDailyNewLimit
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return DefaultDailyNewLimit
+	}
+	return limit
+}
+
+// SetDailyNewLimit sets how many new words may be introduced per day (0 means no limit)
+func (p *UserPreferences) SetDailyNewLimit(limit int) {
+	if limit < 0 {
+		limit = DefaultDailyNewLimit
+	}
+	p.preferences[PrefDailyNewLimit] = strconv.Itoa(limit)
+}
+
+// GetStudyPriority gets the card ordering strategy for learning sessions
+func (p *UserPreferences) GetStudyPriority() StudyPriority {
+	switch StudyPriority(p.preferences[PrefStudyPriority]) {
+	case StudyPriorityLearningFirst:
+		return StudyPriorityLearningFirst
+	default:
+		return DefaultStudyPriority
+	}
+}
+
+// SetStudyPriority sets the card ordering strategy for learning sessions
+func (p *UserPreferences) SetStudyPriority(priority StudyPriority) {
+	p.preferences[PrefStudyPriority] = string(priority)
+}
+
+// ToggleStudyPriority switches between the balanced and learning-first strategies
+func (p *UserPreferences) ToggleStudyPriority() StudyPriority {
+	newValue := StudyPriorityLearningFirst
+	if p.GetStudyPriority() == StudyPriorityLearningFirst {
+		newValue = StudyPriorityBalanced
+	}
+	p.SetStudyPriority(newValue)
+	return newValue
+}
+
+// GetHintType gets the hint shown alongside questions
+func (p *UserPreferences) GetHintType() HintType {
+	hintType, ok := ParseHintType(p.preferences[PrefHintType])
+	if !ok {
+		return DefaultHintType
+	}
+	return hintType
+}
+
+// SetHintType sets the hint shown alongside questions
+func (p *UserPreferences) SetHintType(hintType HintType) {
+	p.preferences[PrefHintType] = string(hintType)
+}
+
+// GetNextReminderAt gets the time the user asked to be reminded at, if any
+func (p *UserPreferences) GetNextReminderAt() (time.Time, bool) {
+	value := p.preferences[PrefNextReminderAt]
+	if value == "" {
+		return time.Time{}, false
+	}
+	remindAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return remindAt, true
+}
+
+// SetNextReminderAt schedules a one-off reminder for the given time
+func (p *UserPreferences) SetNextReminderAt(remindAt time.Time) {
+	p.preferences[PrefNextReminderAt] = remindAt.UTC().Format(time.RFC3339)
+}
+
+// ClearNextReminderAt removes a scheduled one-off reminder
+func (p *UserPreferences) ClearNextReminderAt() {
+	p.preferences[PrefNextReminderAt] = ""
+}
+
+// GetPartnerInvite gets the user's pending study partner invite code, if any
+func (p *UserPreferences) GetPartnerInvite() string {
+	return p.preferences[PrefPartnerInvite]
+}
+
+// SetPartnerInvite stores the user's pending study partner invite code
+func (p *UserPreferences) SetPartnerInvite(code string) {
+	p.preferences[PrefPartnerInvite] = code
+}
+
+// GetFSRSWeights gets the user's custom FSRS weights as a JSON array, or "" for the defaults
+func (p *UserPreferences) GetFSRSWeights() string {
+	return p.preferences[PrefFSRSWeights]
+}
+
+// SetFSRSWeights stores custom FSRS weights as a JSON array; "" restores the defaults
+func (p *UserPreferences) SetFSRSWeights(weights string) {
+	p.preferences[PrefFSRSWeights] = weights
+}
+
+// GetLearningSteps gets the user's custom learning steps as a list like "1m,10m", or "" for the defaults
+func (p *UserPreferences) GetLearningSteps() string {
+	return p.preferences[PrefLearningSteps]
+}
+
+// SetLearningSteps stores custom learning steps as a list like "1m,10m"; "" restores the defaults
+func (p *UserPreferences) SetLearningSteps(steps string) {
+	p.preferences[PrefLearningSteps] = steps
+}
+
+// GetRequestRetention gets the user's custom request retention such as "0.9", or "" for the default
+func (p *UserPreferences) GetRequestRetention() string {
+	return p.preferences[PrefRequestRetention]
+}
+
+// SetRequestRetention stores a custom request retention such as "0.9"; "" restores the default
+func (p *UserPreferences) SetRequestRetention(retention string) {
+	p.preferences[PrefRequestRetention] = retention
+}
+
+// GetChoiceGrading gets how correct multiple-choice answers are rated
+func (p *UserPreferences) GetChoiceGrading() ChoiceGrading {
+	value := ChoiceGrading(p.preferences[PrefChoiceGrading])
+	for _, grading := range ChoiceGradings {
+		if grading == value {
+			return grading
+		}
+	}
+	return DefaultChoiceGrading
+}
+
+// SetChoiceGrading sets how correct multiple-choice answers are rated
+func (p *UserPreferences) SetChoiceGrading(grading ChoiceGrading) {
+	p.preferences[PrefChoiceGrading] = string(grading)
+}
+
+// CycleChoiceGrading switches to the next grading mode
+func (p *UserPreferences) CycleChoiceGrading() ChoiceGrading {
+	current := p.GetChoiceGrading()
+	newValue := ChoiceGradings[0]
+	for i, grading := range ChoiceGradings {
+		if grading == current {
+			newValue = ChoiceGradings[(i+1)%len(ChoiceGradings)]
+			break
+		}
+	}
+	p.SetChoiceGrading(newValue)
+	return newValue
+}
+
+// GetQuestionDirection gets which side of a word questions ask for
+func (p *UserPreferences) GetQuestionDirection() QuestionDirection {
+	value := QuestionDirection(p.preferences[PrefQuestionDirection])
+	for _, direction := range QuestionDirections {
+		if direction == value {
+			return direction
+		}
+	}
+	return DefaultQuestionDirection
+}
+
+// SetQuestionDirection sets which side of a word questions ask for
+func (p *UserPreferences) SetQuestionDirection(direction QuestionDirection) {
+	p.preferences[PrefQuestionDirection] = string(direction)
+}
+
+// CycleQuestionDirection switches to the next question direction
+func (p *UserPreferences) CycleQuestionDirection() QuestionDirection {
+	current := p.GetQuestionDirection()
+	newValue := QuestionDirections[0]
+	for i, direction := range QuestionDirections {
+		if direction == current {
+			newValue = QuestionDirections[(i+1)%len(QuestionDirections)]
+			break
+		}
+	}
+	p.SetQuestionDirection(newValue)
+	return newValue
+}
+
+// GetCategoryDirections gets the question directions pinned for individual categories
+func (p *UserPreferences) GetCategoryDirections() CategoryDirections {
+	directions, err := ParseCategoryDirections(p.preferences[PrefCategoryDirections])
+	if err != nil {
+		return CategoryDirections{}
+	}
+	return directions
+}
+
+// SetCategoryDirections sets the question directions pinned for individual categories
+func (p *UserPreferences) SetCategoryDirections(directions CategoryDirections) {
+	p.preferences[PrefCategoryDirections] = directions.String()
+}
+
+// QuestionDirectionFor gets the direction for questions about a word in the given category,
+// preferring a direction pinned for that category over the overall one
+func (p *UserPreferences) QuestionDirectionFor(category string) QuestionDirection {
+	if direction, ok := p.GetCategoryDirections()[category]; ok {
+		return direction
+	}
+	return p.GetQuestionDirection()
+}
+
+// GetDailyMix gets the user's per-category daily plan (empty when not set)
+func (p *UserPreferences) GetDailyMix() DailyMix {
+	mix, err := ParseDailyMix(p.preferences[PrefDailyMix])
+	if err != nil {
+		return nil
+	}
+	return mix
+}
+
+// SetDailyMix sets the user's per-category daily plan; an empty mix clears it
+func (p *UserPreferences) SetDailyMix(mix DailyMix) {
+	p.preferences[PrefDailyMix] = mix.String()
+}
+
+// GetNewWordOrder gets the order in which new words are introduced
+func (p *UserPreferences) GetNewWordOrder() NewWordOrder {
+	switch NewWordOrder(p.preferences[PrefNewWordOrder]) {
+	case NewWordOrderSequential:
+		return NewWordOrderSequential
+	default:
+		return DefaultNewWordOrder
+	}
+}
+
+// SetNewWordOrder sets the order in which new words are introduced
+func (p *UserPreferences) SetNewWordOrder(order NewWordOrder) {
+	p.preferences[PrefNewWordOrder] = string(order)
+}
+
+// ToggleNewWordOrder switches between random and sequential introduction
+func (p *UserPreferences) ToggleNewWordOrder() NewWordOrder {
+	newValue := NewWordOrderSequential
+	if p.GetNewWordOrder() == NewWordOrderSequential {
+		newValue = NewWordOrderRandom
+	}
+	p.SetNewWordOrder(newValue)
+	return newValue
+}
+
+// GetAnswerMode gets how the user answers questions
+func (p *UserPreferences) GetAnswerMode() AnswerMode {
+	switch AnswerMode(p.preferences[PrefAnswerMode]) {
+	case AnswerModeTyped:
+		return AnswerModeTyped
+	default:
+		return DefaultAnswerMode
+	}
+}
+
+// SetAnswerMode sets how the user answers questions
+func (p *UserPreferences) SetAnswerMode(mode AnswerMode) {
+	p.preferences[PrefAnswerMode] = string(mode)
+}
+
+// ToggleAnswerMode switches between multiple-choice and typed answers
+func (p *UserPreferences) ToggleAnswerMode() AnswerMode {
+	newValue := AnswerModeTyped
+	if p.GetAnswerMode() == AnswerModeTyped {
+		newValue = AnswerModeChoice
+	}
+	p.SetAnswerMode(newValue)
+	return newValue
+}
+
+// GetNewWordSeed gets the seed of the user's shuffled new-word order, or 0 when none is set
+func (p *UserPreferences) GetNewWordSeed() int64 {
+	seed, err := strconv.ParseInt(p.preferences[PrefNewWordSeed], 10, 64)
+	if err != nil || seed < 1 || seed > MaxNewWordSeed {
+		return 0
+	}
+	return seed
+}
+
+// SetNewWordSeed sets the seed of the user's shuffled new-word order
+func (p *UserPreferences) SetNewWordSeed(seed int64) {
+	p.preferences[PrefNewWordSeed] = strconv.FormatInt(seed, 10)
+}
+
+// GetNewWordSelection gets how the user's new words are ordered
+func (p *UserPreferences) GetNewWordSelection() NewWordSelection {
+	return NewWordSelection{Order: p.GetNewWordOrder(), Seed: p.GetNewWordSeed(), Categories: p.GetNewWordCategories()}
+}
+
+// GetNewWordCategories gets the categories new words are drawn from (empty means every category)
+func (p *UserPreferences) GetNewWordCategories() []string {
+	return strings.FieldsFunc(p.preferences[PrefNewWordCategories], func(r rune) bool { return r == ',' })
+}
+
+// SetNewWordCategories sets the categories new words are drawn from; an empty list allows every category
+func (p *UserPreferences) SetNewWordCategories(categories []string) {
+	sorted := append([]string(nil), categories...)
+	sort.Strings(sorted)
+	p.preferences[PrefNewWordCategories] = strings.Join(slices.Compact(sorted), ",")
+}
+
+// GetReminderMode gets how the user is reminded about due words
+func (p *UserPreferences) GetReminderMode() ReminderMode {
+	switch ReminderMode(p.preferences[PrefReminderMode]) {
+	case ReminderModeDailyDigest:
+		return ReminderModeDailyDigest
+	default:
+		return DefaultReminderMode
+	}
+}
+
+// SetReminderMode sets how the user is reminded about due words
+func (p *UserPreferences) SetReminderMode(mode ReminderMode) {
+	p.preferences[PrefReminderMode] = string(mode)
+}
+
+// GetDigestHour gets the hour of day (0-23) the daily digest is sent at
+func (p *UserPreferences) GetDigestHour() int {
+	hour, err := strconv.Atoi(p.preferences[PrefDigestHour])
+	if err != nil || hour < 0 || hour > 23 {
+		return DefaultDigestHour
+	}
+	return hour
+}
+
+// SetDigestHour sets the hour of day (0-23) the daily digest is sent at
+func (p *UserPreferences) SetDigestHour(hour int) {
+	p.preferences[PrefDigestHour] = strconv.Itoa(hour)
+}
+
+// GetLastDigestAt gets when the last daily digest was sent, if ever
+func (p *UserPreferences) GetLastDigestAt() (time.Time, bool) {
+	sentAt, err := time.Parse(time.RFC3339, p.preferences[PrefLastDigestAt])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return sentAt, true
+}
+
+// SetLastDigestAt records when the daily digest was sent
+func (p *UserPreferences) SetLastDigestAt(sentAt time.Time) {
+	p.preferences[PrefLastDigestAt] = sentAt.UTC().Format(time.RFC3339)
+}
+
+// GetTimezone gets the IANA name of the user's time zone, or "" when unset
+func (p *UserPreferences) GetTimezone() string {
+	return p.preferences[PrefTimezone]
+}
+
+// SetTimezone sets the IANA name of the user's time zone, such as "Europe/Amsterdam"; "" resets it to UTC
+func (p *UserPreferences) SetTimezone(name string) {
+	p.preferences[PrefTimezone] = name
+}
+
+// Location gets the user's time zone for quiet hours, digests and streaks, falling back to UTC
+// when none is set or the stored name is unknown
+func (p *UserPreferences) Location() *time.Location {
+	name := p.GetTimezone()
+	if name == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
 }
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"testing"
+	_ "time/tzdata" // Time zone tests must not depend on the host's zoneinfo
+)
+
+func TestReminderInterval(t *testing.T) {
+	prefs := NewUserPreferences(1)
+	if got := prefs.GetReminderInterval(); got != DefaultReminderInterval {
+		t.Errorf("default interval = %d, want %d", got, DefaultReminderInterval)
+	}
+
+	tests := []struct {
+		name    string
+		minutes int
+		want    int
+	}{
+		{"minimum", MinReminderInterval, MinReminderInterval},
+		{"hourly", 60, 60},
+		{"zero restores default", 0, DefaultReminderInterval},
+		{"negative restores default", -5, DefaultReminderInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs.SetReminderInterval(tt.minutes)
+			if got := prefs.GetReminderInterval(); got != tt.want {
+				t.Errorf("SetReminderInterval(%d) then GetReminderInterval() = %d, want %d", tt.minutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderInterval_InvalidStoredValue(t *testing.T) {
+	for _, value := range []string{"", "soon", "0", "-10"} {
+		prefs := NewUserPreferences(1)
+		prefs.SetPreferences(map[string]string{PrefReminderInterval: value})
+		if got := prefs.GetReminderInterval(); got != DefaultReminderInterval {
+			t.Errorf("stored %q: GetReminderInterval() = %d, want %d", value, got, DefaultReminderInterval)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{"", "UTC"},
+		{"Europe/Amsterdam", "Europe/Amsterdam"},
+		{"Not/A_Zone", "UTC"},
+	}
+	for _, tt := range tests {
+		prefs := NewUserPreferences(1)
+		prefs.SetTimezone(tt.timezone)
+		if got := prefs.Location().String(); got != tt.want {
+			t.Errorf("Location() with timezone %q = %q, want %q", tt.timezone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+)
+
+// portablePreferenceKeys lists the settings users can export and import.
+// Scheduling state such as the next reminder time is deliberately left out.
+var portablePreferenceKeys = map[string]bool{
+	PrefGrammarTipsEnabled:    true,
+	PrefSmartRemindersEnabled: true,
+	PrefReminderInterval:      true,
+	PrefReviewAheadMinutes:    true,
+	PrefShowSessionProgress:   true,
+	PrefSessionRecap:          true,
+	PrefMaxSessionMinutes:     true,
+	PrefDailyNewLimit:         true,
+	PrefIgnoreArticles:        true,
+	PrefStrictAccents:         true,
+	PrefSpellingVariants:      true,
+	PrefReviewsOnly:           true,
+	PrefRateConfidence:        true,
+	PrefStudyPriority:         true,
+	PrefStagedReveal:          true,
+	PrefHintType:              true,
+	PrefChoiceGrading:         true,
+	PrefQuestionDirection:     true,
+	PrefCategoryDirections:    true,
+	PrefDailyMix:              true,
+	PrefAutoEasyFast:          true,
+	PrefNewWordOrder:          true,
+	PrefNewWordSeed:           true,
+	PrefNewWordCategories:     true,
+	PrefRecognitionFirst:      true,
+	PrefShuffleRatings:        true,
+	PrefShowWordSense:         true,
+	PrefFSRSWeights:           true,
+	PrefLearningSteps:         true,
+	PrefRequestRetention:      true,
+	PrefIntervalFuzz:          true,
+	PrefCommunityDifficulty:   true,
+	PrefLeaderboardVisible:    true,
+	PrefAnswerMode:            true,
+	PrefReminderMode:          true,
+	PrefDigestHour:            true,
+	PrefTimezone:              true,
+}
+
+// ExportSettings returns a copy of the user's portable settings
+func (up *UserPreferences) ExportSettings() map[string]string {
+	settings := make(map[string]string)
+	for key, value := range up.GetAllPreferences() {
+		if portablePreferenceKeys[key] {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+// ImportSettings applies previously exported settings on top of the current ones.
+// Nothing is changed if any key is not a known setting.
+func (up *UserPreferences) ImportSettings(settings map[string]string) error {
+	var unknown []string
+	for key := range settings {
+		if !portablePreferenceKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown settings: %v", unknown)
+	}
+
+	merged := make(map[string]string, len(up.GetAllPreferences())+len(settings))
+	for key, value := range up.GetAllPreferences() {
+		merged[key] = value
+	}
+	for key, value := range settings {
+		merged[key] = value
+	}
+	up.SetPreferences(merged)
+
+	return nil
+}
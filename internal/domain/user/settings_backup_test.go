@@ -0,0 +1,74 @@
+package user
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSettings_RoundTrip(t *testing.T) {
+	source := NewUserPreferences(1)
+	source.SetGrammarTipsEnabled(false)
+	source.SetReminderInterval(90)
+	source.SetQuestionDirection(QuestionDirectionFromDutch)
+	source.SetNextReminderAt(time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC))
+
+	exported := source.ExportSettings()
+	if _, ok := exported[PrefNextReminderAt]; ok {
+		t.Errorf("export includes scheduling state %q", PrefNextReminderAt)
+	}
+
+	// Settings travel as JSON between the two commands
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("failed to marshal settings: %v", err)
+	}
+	var imported map[string]string
+	if err := json.Unmarshal(data, &imported); err != nil {
+		t.Fatalf("failed to unmarshal settings: %v", err)
+	}
+
+	target := NewUserPreferences(2)
+	if err := target.ImportSettings(imported); err != nil {
+		t.Fatalf("ImportSettings: %v", err)
+	}
+	if target.GrammarTipsEnabled() {
+		t.Error("grammar tips should stay disabled after the round trip")
+	}
+	if got := target.GetReminderInterval(); got != 90 {
+		t.Errorf("reminder interval = %d, want 90", got)
+	}
+	if got := target.GetQuestionDirection(); got != QuestionDirectionFromDutch {
+		t.Errorf("question direction = %q, want %q", got, QuestionDirectionFromDutch)
+	}
+}
+
+func TestImportSettings_RejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+	}{
+		{"unknown key", map[string]string{"dark_mode": "true"}},
+		{"unknown key beside a known one", map[string]string{PrefReminderInterval: "30", "dark_mode": "true"}},
+		{"scheduling state", map[string]string{PrefNextReminderAt: "2026-10-15T09:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := NewUserPreferences(1)
+			before := prefs.Clone().GetAllPreferences()
+
+			if err := prefs.ImportSettings(tt.settings); err == nil {
+				t.Fatal("expected an error for unknown settings")
+			}
+			after := prefs.GetAllPreferences()
+			if len(after) != len(before) {
+				t.Fatalf("a rejected import changed the settings: %v", after)
+			}
+			for key, value := range before {
+				if after[key] != value {
+					t.Errorf("%s = %q after a rejected import, want %q", key, after[key], value)
+				}
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package vocabulary
 
+import "slices"
+
 // Word represents a vocabulary word with its translation
 type Word struct {
 	id       ID
 	english  string
 	dutch    string
 	category Category
+	pos      PartOfSpeech
+	sense    string
 }
 
 // ID represents the word's unique identifier
@@ -32,6 +36,20 @@ const (
 	CategoryRoadSigns       Category = "road_signs"
 )
 
+// PartOfSpeech represents a word's grammatical class; empty when unknown
+type PartOfSpeech string
+
+const (
+	PartOfSpeechNoun        PartOfSpeech = "noun"
+	PartOfSpeechVerb        PartOfSpeech = "verb"
+	PartOfSpeechAdjective   PartOfSpeech = "adjective"
+	PartOfSpeechAdverb      PartOfSpeech = "adverb"
+	PartOfSpeechPronoun     PartOfSpeech = "pronoun"
+	PartOfSpeechPreposition PartOfSpeech = "preposition"
+	PartOfSpeechConjunction PartOfSpeech = "conjunction"
+	PartOfSpeechOther       PartOfSpeech = "other"
+)
+
 // NewWord creates a new vocabulary word
 func NewWord(english, dutch string, category Category) *Word {
 	return &Word{
@@ -47,19 +65,52 @@ func (w *Word) English() string    { return w.english }
 func (w *Word) Dutch() string      { return w.dutch }
 func (w *Word) Category() Category { return w.category }
 
+// PartOfSpeech returns the word's grammatical class, or "" when unknown
+func (w *Word) PartOfSpeech() PartOfSpeech { return w.pos }
+
+// SetPartOfSpeech sets the word's grammatical class
+func (w *Word) SetPartOfSpeech(pos PartOfSpeech) {
+	w.pos = pos
+}
+
+// Sense tells which meaning of the English word is meant, such as "correct" for "right",
+// or "" when the word is unambiguous
+func (w *Word) Sense() string { return w.sense }
+
+// SetSense sets the meaning the English word is used in
+func (w *Word) SetSense(sense string) {
+	w.sense = sense
+}
+
 // SetID sets the word ID (used by repository)
 func (w *Word) SetID(id ID) {
 	w.id = id
 }
 
+// categories lists every valid category, in display order
+var categories = []Category{
+	CategoryFamily, CategoryBody, CategoryColors, CategoryFood,
+	CategoryAnimals, CategoryHome, CategoryObjects, CategoryPeople,
+	CategoryAdjectives, CategoryVerbs, CategoryParticles,
+	CategoryPrepositions, CategoryVerbsAction, CategoryVerbsInfinitive,
+	CategoryRoadSigns,
+}
+
+// Categories returns every valid category, in display order
+func Categories() []Category {
+	return slices.Clone(categories)
+}
+
 // IsValidCategory checks if a category is valid
 func IsValidCategory(category string) bool {
-	switch Category(category) {
-	case CategoryFamily, CategoryBody, CategoryColors, CategoryFood,
-		CategoryAnimals, CategoryHome, CategoryObjects, CategoryPeople,
-		CategoryAdjectives, CategoryVerbs, CategoryParticles,
-		CategoryPrepositions, CategoryVerbsAction, CategoryVerbsInfinitive,
-		CategoryRoadSigns:
+	return slices.Contains(categories, Category(category))
+}
+
+// IsValidPartOfSpeech checks if a part of speech is valid
+func IsValidPartOfSpeech(pos string) bool {
+	switch PartOfSpeech(pos) {
+	case PartOfSpeechNoun, PartOfSpeechVerb, PartOfSpeechAdjective, PartOfSpeechAdverb,
+		PartOfSpeechPronoun, PartOfSpeechPreposition, PartOfSpeechConjunction, PartOfSpeechOther:
 		return true
 	default:
 		return false
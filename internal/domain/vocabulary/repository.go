@@ -7,8 +7,9 @@ type Repository interface {
 	// Save persists a word to storage
 	Save(ctx context.Context, word *Word) error
 
-	// SaveBatch persists multiple words to storage
-	SaveBatch(ctx context.Context, words []*Word) error
+	// SaveBatch persists multiple words to storage, reporting how many were new
+	// and how many were skipped as already present
+	SaveBatch(ctx context.Context, words []*Word) (added, skipped int, err error)
 
 	// FindByID retrieves a word by its ID
 	FindByID(ctx context.Context, id ID) (*Word, error)
@@ -19,6 +20,19 @@ type Repository interface {
 	// FindByCategory retrieves words by category
 	FindByCategory(ctx context.Context, category Category) ([]*Word, error)
 
+	// FindByTerm retrieves an active word whose Dutch or English text matches the term, ignoring case
+	FindByTerm(ctx context.Context, term string) (*Word, error)
+
+	// Search retrieves up to limit active words whose Dutch or English text contains the query, ignoring case.
+	// Exact matches come first, then words starting with the query.
+	Search(ctx context.Context, query string, limit int) ([]*Word, error)
+
 	// Exists checks if a word already exists
 	Exists(ctx context.Context, english, dutch string) (bool, error)
+
+	// ArchiveWord hides a word from study without deleting its history
+	ArchiveWord(ctx context.Context, id ID) error
+
+	// MergeWords merges a duplicate word into another, archiving the loser
+	MergeWords(ctx context.Context, winnerID, loserID ID) error
 }
@@ -31,6 +31,8 @@ type GrammarTipEntry struct {
 	ApplicableCategories []string `json:"applicable_categories"`
 	WordPatterns         []string `json:"word_patterns"`
 	SpecificWords        []string `json:"specific_words"`
+	ImageURL             string   `json:"image_url,omitempty"`
+	AudioURL             string   `json:"audio_url,omitempty"`
 }
 
 // LoadFromFile loads grammar tips from a JSON file
@@ -63,6 +65,7 @@ func (gl *GrammarLoader) LoadFromFile(filename string) ([]*grammar.GrammarTip, e
 			entry.WordPatterns,
 			entry.SpecificWords,
 		)
+		tip.SetMedia(entry.ImageURL, entry.AudioURL)
 
 		tips = append(tips, tip)
 	}
@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestGrammarLoader_Media(t *testing.T) {
+	path := writeTestFile(t, "grammar_tips.json", `{"grammar_tips": [
+		{"title": "De vs Het", "explanation": "Most nouns use de.", "category": "articles",
+		 "image_url": "https://example.com/articles.png", "audio_url": "media/articles.mp3"},
+		{"title": "Plurals", "explanation": "Add -en.", "category": "plurals"}
+	]}`)
+
+	tips, err := NewGrammarLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if len(tips) != 2 {
+		t.Fatalf("loaded %d tips, want 2", len(tips))
+	}
+
+	withMedia, withoutMedia := tips[0], tips[1]
+	if got := withMedia.ImageURL(); got != "https://example.com/articles.png" {
+		t.Errorf("ImageURL() = %q", got)
+	}
+	if got := withMedia.AudioURL(); got != "media/articles.mp3" {
+		t.Errorf("AudioURL() = %q", got)
+	}
+	if !withMedia.HasMedia() {
+		t.Error("tip with media reports no media")
+	}
+	if withoutMedia.HasMedia() || withoutMedia.ImageURL() != "" || withoutMedia.AudioURL() != "" {
+		t.Error("tip without media fields has media")
+	}
+}
+
+func TestGrammarLoader_InvalidCategory(t *testing.T) {
+	path := writeTestFile(t, "grammar_tips.json", `{"grammar_tips": [{"title": "Odd", "category": "spelling"}]}`)
+
+	if _, err := NewGrammarLoader().LoadFromFile(path); err == nil {
+		t.Error("LoadFromFile succeeded with an unknown category, want an error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"dutch-learning-bot/internal/domain/vocabulary"
 )
@@ -26,6 +27,10 @@ type VocabularyEntry struct {
 	Word        string `json:"word"`
 	Translation string `json:"translation"`
 	Category    string `json:"category"`
+	// Optional part of speech, e.g. "noun" or "verb"
+	PartOfSpeech string `json:"pos,omitempty"`
+	// Optional meaning of an ambiguous English word, e.g. "direction" for "right"
+	Sense string `json:"sense,omitempty"`
 }
 
 // LoadFromFile loads vocabulary from a JSON file
@@ -48,11 +53,17 @@ func (vl *VocabularyLoader) LoadFromFile(filename string) ([]*vocabulary.Word, e
 			return nil, fmt.Errorf("invalid category: %s", entry.Category)
 		}
 
+		if entry.PartOfSpeech != "" && !vocabulary.IsValidPartOfSpeech(entry.PartOfSpeech) {
+			return nil, fmt.Errorf("invalid part of speech %q for word %s", entry.PartOfSpeech, entry.Word)
+		}
+
 		word := vocabulary.NewWord(
 			entry.Word,
 			entry.Translation,
 			vocabulary.Category(entry.Category),
 		)
+		word.SetPartOfSpeech(vocabulary.PartOfSpeech(entry.PartOfSpeech))
+		word.SetSense(strings.TrimSpace(entry.Sense))
 		words = append(words, word)
 	}
 
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"testing"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestVocabularyLoader_PartOfSpeech(t *testing.T) {
+	path := writeTestFile(t, "vocabulary.json", `{"english_dutch": [
+		{"word": "house", "translation": "huis", "category": "home", "pos": "noun"},
+		{"word": "to sleep", "translation": "slapen", "category": "verbs", "pos": "verb"},
+		{"word": "red", "translation": "rood", "category": "colors"}
+	]}`)
+
+	words, err := NewVocabularyLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	want := []vocabulary.PartOfSpeech{vocabulary.PartOfSpeechNoun, vocabulary.PartOfSpeechVerb, ""}
+	if len(words) != len(want) {
+		t.Fatalf("loaded %d words, want %d", len(words), len(want))
+	}
+	for i, word := range words {
+		if got := word.PartOfSpeech(); got != want[i] {
+			t.Errorf("%s: PartOfSpeech() = %q, want %q", word.English(), got, want[i])
+		}
+	}
+}
+
+func TestVocabularyLoader_InvalidPartOfSpeech(t *testing.T) {
+	path := writeTestFile(t, "vocabulary.json", `{"english_dutch": [
+		{"word": "house", "translation": "huis", "category": "home", "pos": "thing"}
+	]}`)
+
+	if _, err := NewVocabularyLoader().LoadFromFile(path); err == nil {
+		t.Error("expected an error for an unknown part of speech")
+	}
+}
+
+func TestVocabularyLoader_Sense(t *testing.T) {
+	path := writeTestFile(t, "vocabulary.json", `{"english_dutch": [
+		{"word": "right", "translation": "rechts", "category": "prepositions", "sense": "direction"},
+		{"word": "right", "translation": "juist", "category": "adjectives", "sense": "correct"},
+		{"word": "house", "translation": "huis", "category": "home"}
+	]}`)
+
+	words, err := NewVocabularyLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	want := []string{"direction", "correct", ""}
+	if len(words) != len(want) {
+		t.Fatalf("loaded %d words, want %d", len(words), len(want))
+	}
+	for i, word := range words {
+		if got := word.Sense(); got != want[i] {
+			t.Errorf("%s/%s: Sense() = %q, want %q", word.English(), word.Dutch(), got, want[i])
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish once the server is stopping
+const shutdownTimeout = 5 * time.Second
+
+// Server serves the health and metrics endpoints used by deployment tooling
+type Server struct {
+	db              *sql.DB
+	reminderUseCase *usecases.ReminderUseCase
+	server          *http.Server
+}
+
+// NewServer creates a health and metrics server listening on addr
+func NewServer(addr string, db *sql.DB, reminderUseCase *usecases.ReminderUseCase) *Server {
+	s := &Server{
+		db:              db,
+		reminderUseCase: reminderUseCase,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// Start serves requests until the context is cancelled, then shuts down gracefully
+func (s *Server) Start(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP server shutdown failed: %v", err)
+		}
+	}()
+
+	log.Printf("Starting HTTP server on %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("HTTP server error: %v", err)
+	}
+}
+
+// handleHealth reports whether the database is reachable
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if err := s.db.PingContext(r.Context()); err != nil {
+		log.Printf("Health check failed: %v", err)
+		http.Error(w, "database unreachable", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+// metricsResponse is the JSON body of the metrics endpoint
+type metricsResponse struct {
+	TotalUsers         int `json:"total_users"`
+	ReviewsToday       int `json:"reviews_today"`
+	DueWords           int `json:"due_words"`
+	UsersWithDueWords  int `json:"users_with_due_words"`
+	RemindersSentToday int `json:"reminders_sent_today"`
+	UsersTracked       int `json:"reminder_users_tracked"`
+}
+
+// handleMetrics reports usage and reminder counts as JSON
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := persistence.CollectMetrics(r.Context(), s.db)
+	if err != nil {
+		log.Printf("Failed to collect metrics: %v", err)
+		http.Error(w, "failed to collect metrics", http.StatusInternalServerError)
+		return
+	}
+
+	response := metricsResponse{
+		TotalUsers:        metrics.TotalUsers,
+		ReviewsToday:      metrics.ReviewsToday,
+		DueWords:          metrics.DueWords,
+		UsersWithDueWords: metrics.UsersWithDue,
+	}
+	if s.reminderUseCase != nil {
+		reminderStats := s.reminderUseCase.GetReminderStats()
+		response.RemindersSentToday = reminderStats.RemindersSentToday
+		response.UsersTracked = reminderStats.UsersTracked
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
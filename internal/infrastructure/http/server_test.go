@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+func TestHealthz(t *testing.T) {
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	server := httptest.NewServer(NewServer("", db, nil).server.Handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", resp.StatusCode)
+	}
+
+	// Once the database is gone the check fails
+	db.Close()
+	resp, err = http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/healthz status with a closed database = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+	server := httptest.NewServer(NewServer("", db, nil).server.Handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/metrics status = %d, want 200", resp.StatusCode)
+	}
+
+	var metrics map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		t.Fatalf("failed to decode metrics: %v", err)
+	}
+	for _, key := range []string{"total_users", "reviews_today", "due_words", "users_with_due_words", "reminders_sent_today", "reminder_users_tracked"} {
+		if _, ok := metrics[key]; !ok {
+			t.Errorf("metrics missing %q", key)
+		}
+	}
+}
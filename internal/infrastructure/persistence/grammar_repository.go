@@ -19,42 +19,67 @@ func NewGrammarRepository(db *sql.DB) grammar.Repository {
 	return &grammarRepository{db: db}
 }
 
-// SaveBatch saves multiple grammar tips
+// SaveBatch stores the given grammar tips as the full set: tips are matched by title and updated
+// in place, and stored tips missing from the batch are removed
 func (r *grammarRepository) SaveBatch(ctx context.Context, tips []*grammar.GrammarTip) error {
-	for _, tip := range tips {
-		query := `
-			INSERT INTO grammar_tips (title, explanation, dutch_example, english_example, category, applicable_categories, word_patterns, specific_words, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO grammar_tips (title, explanation, dutch_example, english_example, category, applicable_categories, word_patterns, specific_words, image_url, audio_url, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(title) DO UPDATE SET
+			explanation = excluded.explanation, dutch_example = excluded.dutch_example,
+			english_example = excluded.english_example, category = excluded.category,
+			applicable_categories = excluded.applicable_categories, word_patterns = excluded.word_patterns,
+			specific_words = excluded.specific_words, image_url = excluded.image_url, audio_url = excluded.audio_url
+		RETURNING id
+	`
 
+	titles := make([]string, 0, len(tips))
+	for _, tip := range tips {
 		// Convert slices to JSON strings
 		applicableCategoriesJSON, _ := json.Marshal(tip.ApplicableCategories())
 		wordPatternsJSON, _ := json.Marshal(tip.WordPatterns())
 		specificWordsJSON, _ := json.Marshal(tip.SpecificWords())
 
-		result, err := r.db.ExecContext(ctx, query,
+		var id int64
+		err := tx.QueryRowContext(ctx, query,
 			tip.Title(), tip.Explanation(), tip.DutchExample(), tip.EnglishExample(),
 			string(tip.Category()),
 			string(applicableCategoriesJSON), string(wordPatternsJSON), string(specificWordsJSON),
-			tip.CreatedAt())
+			tip.ImageURL(), tip.AudioURL(),
+			tip.CreatedAt()).Scan(&id)
 		if err != nil {
 			return fmt.Errorf("failed to save grammar tip %s: %w", tip.Title(), err)
 		}
 
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get grammar tip ID: %w", err)
-		}
-
 		tip.SetID(grammar.ID(id))
+		titles = append(titles, tip.Title())
+	}
+
+	// Tips dropped from the source file shouldn't linger
+	titlesJSON, _ := json.Marshal(titles)
+	_, err = tx.ExecContext(ctx, `DELETE FROM grammar_tips WHERE title NOT IN (SELECT value FROM json_each(?))`, string(titlesJSON))
+	if err != nil {
+		return fmt.Errorf("failed to remove old grammar tips: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+
 	return nil
 }
 
 // FindApplicableToWord finds grammar tips that apply to a specific word
 func (r *grammarRepository) FindApplicableToWord(ctx context.Context, dutchWord, englishWord, category string) ([]*grammar.GrammarTip, error) {
 	query := `
-		SELECT id, title, explanation, dutch_example, english_example, category, applicable_categories, word_patterns, specific_words, created_at
+		SELECT id, title, explanation, dutch_example, english_example, category, applicable_categories, word_patterns, specific_words,
+		       image_url, audio_url, created_at
 		FROM grammar_tips
 		WHERE 
 			JSON_EXTRACT(applicable_categories, '$') LIKE '%"' || ? || '"%' OR
@@ -74,10 +99,11 @@ func (r *grammarRepository) FindApplicableToWord(ctx context.Context, dutchWord,
 		var id grammar.ID
 		var title, explanation, dutchExample, englishExample, cat string
 		var applicableCategoriesJSON, wordPatternsJSON, specificWordsJSON string
+		var imageURL, audioURL sql.NullString
 		var createdAt time.Time
 
 		err := rows.Scan(&id, &title, &explanation, &dutchExample, &englishExample, &cat,
-			&applicableCategoriesJSON, &wordPatternsJSON, &specificWordsJSON, &createdAt)
+			&applicableCategoriesJSON, &wordPatternsJSON, &specificWordsJSON, &imageURL, &audioURL, &createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan grammar tip: %w", err)
 		}
@@ -94,6 +120,7 @@ func (r *grammarRepository) FindApplicableToWord(ctx context.Context, dutchWord,
 			grammar.Category(cat),
 			applicableCategories, wordPatterns, specificWords)
 		tip.SetID(id)
+		tip.SetMedia(imageURL.String, audioURL.String)
 
 		if tip.IsApplicableToWord(dutchWord, englishWord, category) {
 			tips = append(tips, tip)
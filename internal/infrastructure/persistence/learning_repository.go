@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"dutch-learning-bot/internal/domain/learning"
@@ -20,31 +21,36 @@ func NewLearningRepository(db *sql.DB) learning.Repository {
 	return &learningRepository{db: db}
 }
 
-// SaveProgress persists user progress
+// upsertProgressQuery inserts a progress record, or overwrites the card of the user's existing record
+// for the word when another request created it first, returning the record's ID either way
+const upsertProgressQuery = `
+	INSERT INTO user_progress 
+	(user_id, word_id, stability, difficulty, last_review, due_date, review_count, lapses, state, learning_step, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(user_id, word_id) DO UPDATE SET
+		stability = excluded.stability, difficulty = excluded.difficulty,
+		last_review = excluded.last_review, due_date = excluded.due_date,
+		review_count = excluded.review_count, lapses = excluded.lapses,
+		state = excluded.state, learning_step = excluded.learning_step, updated_at = excluded.updated_at
+	RETURNING id
+`
+
+// SaveProgress persists user progress. If the user already has progress for the word, such as
+// when two sessions start the same new word at once, that record is updated instead.
 func (r *learningRepository) SaveProgress(ctx context.Context, progress *learning.UserProgress) error {
-	query := `
-		INSERT INTO user_progress 
-		(user_id, word_id, stability, difficulty, last_review, due_date, review_count, lapses, state, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
 	fsrsCard := progress.FSRSCard()
-	result, err := r.db.ExecContext(ctx, query,
+	var id int64
+	err := r.db.QueryRowContext(ctx, upsertProgressQuery,
 		int64(progress.UserID()), int64(progress.WordID()),
 		fsrsCard.Stability(), fsrsCard.Difficulty(),
 		fsrsCard.LastReview(), fsrsCard.DueDate(),
-		fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()),
-		progress.CreatedAt(), progress.UpdatedAt())
+		fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()), fsrsCard.LearningStep(),
+		progress.CreatedAt(), progress.UpdatedAt()).Scan(&id)
 
 	if err != nil {
 		return fmt.Errorf("failed to save progress: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get progress ID: %w", err)
-	}
-
 	progress.SetID(learning.ID(id))
 	return nil
 }
@@ -54,7 +60,7 @@ func (r *learningRepository) UpdateProgress(ctx context.Context, progress *learn
 	query := `
 		UPDATE user_progress 
 		SET stability = ?, difficulty = ?, last_review = ?, due_date = ?, 
-		    review_count = ?, lapses = ?, state = ?, updated_at = ?
+		    review_count = ?, lapses = ?, state = ?, learning_step = ?, updated_at = ?
 		WHERE id = ?
 	`
 
@@ -62,7 +68,7 @@ func (r *learningRepository) UpdateProgress(ctx context.Context, progress *learn
 	_, err := r.db.ExecContext(ctx, query,
 		fsrsCard.Stability(), fsrsCard.Difficulty(),
 		fsrsCard.LastReview(), fsrsCard.DueDate(),
-		fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()),
+		fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()), fsrsCard.LearningStep(),
 		progress.UpdatedAt(), int64(progress.ID()))
 
 	if err != nil {
@@ -72,11 +78,52 @@ func (r *learningRepository) UpdateProgress(ctx context.Context, progress *learn
 	return nil
 }
 
+// SaveProgressBatch updates several existing progress records in a single transaction
+func (r *learningRepository) SaveProgressBatch(ctx context.Context, progress []*learning.UserProgress) error {
+	if len(progress) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE user_progress 
+		SET stability = ?, difficulty = ?, last_review = ?, due_date = ?, 
+		    review_count = ?, lapses = ?, state = ?, learning_step = ?, updated_at = ?
+		WHERE id = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare progress update: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range progress {
+		fsrsCard := p.FSRSCard()
+		if _, err := stmt.ExecContext(ctx,
+			fsrsCard.Stability(), fsrsCard.Difficulty(),
+			fsrsCard.LastReview(), fsrsCard.DueDate(),
+			fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()), fsrsCard.LearningStep(),
+			p.UpdatedAt(), int64(p.ID())); err != nil {
+			return fmt.Errorf("failed to update progress %d: %w", p.ID(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // FindProgress retrieves user progress for a specific word
 func (r *learningRepository) FindProgress(ctx context.Context, userID user.ID, wordID vocabulary.ID) (*learning.UserProgress, error) {
 	query := `
 		SELECT id, user_id, word_id, stability, difficulty, last_review, due_date, 
-		       review_count, lapses, state, created_at, updated_at
+		       review_count, lapses, state, learning_step, created_at, updated_at
 		FROM user_progress 
 		WHERE user_id = ? AND word_id = ?
 	`
@@ -86,12 +133,12 @@ func (r *learningRepository) FindProgress(ctx context.Context, userID user.ID, w
 	var wID vocabulary.ID
 	var stability, difficulty float64
 	var lastReviewStr, dueDateStr, createdAtStr, updatedAtStr sql.NullString
-	var reviewCount, lapses int
+	var reviewCount, lapses, learningStep int
 	var state string
 
 	err := r.db.QueryRowContext(ctx, query, int64(userID), int64(wordID)).Scan(
 		&id, &uID, &wID, &stability, &difficulty, &lastReviewStr, &dueDateStr,
-		&reviewCount, &lapses, &state, &createdAtStr, &updatedAtStr)
+		&reviewCount, &lapses, &state, &learningStep, &createdAtStr, &updatedAtStr)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -127,23 +174,24 @@ func (r *learningRepository) FindProgress(ctx context.Context, userID user.ID, w
 
 	// Reconstruct FSRS card from database data
 	fsrsCard := progress.FSRSCard()
-	r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, state)
+	r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, learningStep, state)
 
 	return progress, nil
 }
 
 // FindDueWords retrieves words that are due for review for a user
-func (r *learningRepository) FindDueWords(ctx context.Context, userID user.ID, limit int) ([]*learning.UserProgress, error) {
+func (r *learningRepository) FindDueWords(ctx context.Context, userID user.ID, reviewAhead time.Duration, limit int) ([]*learning.UserProgress, error) {
 	query := `
-		SELECT id, user_id, word_id, stability, difficulty, last_review, due_date, 
-		       review_count, lapses, state, created_at, updated_at
-		FROM user_progress 
-		WHERE user_id = ? AND due_date <= CURRENT_TIMESTAMP
-		ORDER BY due_date ASC
+		SELECT up.id, up.user_id, up.word_id, up.stability, up.difficulty, up.last_review, up.due_date,
+		       up.review_count, up.lapses, up.state, up.learning_step, up.created_at, up.updated_at
+		FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.archived = 0 AND up.due_date <= DATETIME('now', ?)
+		ORDER BY up.due_date ASC
 		LIMIT ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, int64(userID), limit)
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), reviewAheadModifier(reviewAhead), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query due progress words: %w", err)
 	}
@@ -162,16 +210,17 @@ func (r *learningRepository) FindDueWords(ctx context.Context, userID user.ID, l
 }
 
 // FindNewWords gets words that don't have progress records yet
-func (r *learningRepository) FindNewWords(ctx context.Context, userID user.ID, limit int) ([]*learning.UserProgress, error) {
+func (r *learningRepository) FindNewWords(ctx context.Context, userID user.ID, selection user.NewWordSelection, limit int) ([]*learning.UserProgress, error) {
 	query := `
 		SELECT w.id as word_id
 		FROM words w
-		WHERE w.id NOT IN (SELECT word_id FROM user_progress WHERE user_id = ?)
-		ORDER BY RANDOM()
-		LIMIT ?
+		WHERE w.archived = 0 AND w.id NOT IN (SELECT word_id FROM user_progress WHERE user_id = ?1)
+		  AND ` + newWordCategoryCondition(2) + `
+		ORDER BY ` + newWordOrderBy(selection) + `
+		LIMIT ?3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, int64(userID), limit)
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), strings.Join(selection.Categories, ","), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query new words: %w", err)
 	}
@@ -192,6 +241,128 @@ func (r *learningRepository) FindNewWords(ctx context.Context, userID user.ID, l
 	return progressList, rows.Err()
 }
 
+// TagWord attaches a user-specific tag to a word
+func (r *learningRepository) TagWord(ctx context.Context, userID user.ID, wordID vocabulary.ID, tag string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO word_tags (user_id, word_id, tag) VALUES (?, ?, ?)
+	`, int64(userID), int64(wordID), tag)
+	if err != nil {
+		return fmt.Errorf("failed to tag word: %w", err)
+	}
+	return nil
+}
+
+// FindTags retrieves the user's distinct tags
+func (r *learningRepository) FindTags(ctx context.Context, userID user.ID) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT tag FROM word_tags WHERE user_id = ? ORDER BY tag
+	`, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// FindDueWordsByTag retrieves due words carrying the user's tag
+func (r *learningRepository) FindDueWordsByTag(ctx context.Context, userID user.ID, tag string, reviewAhead time.Duration, limit int) ([]*learning.UserProgress, error) {
+	query := `
+		SELECT up.id, up.user_id, up.word_id, up.stability, up.difficulty, up.last_review, up.due_date,
+		       up.review_count, up.lapses, up.state, up.learning_step, up.created_at, up.updated_at
+		FROM user_progress up
+		JOIN word_tags wt ON wt.user_id = up.user_id AND wt.word_id = up.word_id
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND wt.tag = ? AND w.archived = 0 AND up.due_date <= DATETIME('now', ?)
+		ORDER BY up.due_date ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), tag, reviewAheadModifier(reviewAhead), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due tagged words: %w", err)
+	}
+	defer rows.Close()
+
+	var progressList []*learning.UserProgress
+	for rows.Next() {
+		progress, err := r.scanProgressRow(rows, userID)
+		if err != nil {
+			return nil, err
+		}
+		progressList = append(progressList, progress)
+	}
+
+	return progressList, rows.Err()
+}
+
+// FindNewWordsByTag retrieves unstudied words carrying the user's tag
+func (r *learningRepository) FindNewWordsByTag(ctx context.Context, userID user.ID, tag string, selection user.NewWordSelection, limit int) ([]*learning.UserProgress, error) {
+	query := `
+		SELECT w.id
+		FROM words w
+		JOIN word_tags wt ON wt.word_id = w.id
+		WHERE wt.user_id = ?1 AND wt.tag = ?2 AND w.archived = 0
+		  AND w.id NOT IN (SELECT word_id FROM user_progress WHERE user_id = ?1)
+		  AND ` + newWordCategoryCondition(4) + `
+		ORDER BY ` + newWordOrderBy(selection) + `
+		LIMIT ?3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), tag, limit, strings.Join(selection.Categories, ","))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new tagged words: %w", err)
+	}
+	defer rows.Close()
+
+	var progressList []*learning.UserProgress
+	for rows.Next() {
+		var wordID vocabulary.ID
+		if err := rows.Scan(&wordID); err != nil {
+			return nil, fmt.Errorf("failed to scan word ID: %w", err)
+		}
+		progressList = append(progressList, learning.NewUserProgress(userID, wordID))
+	}
+
+	return progressList, rows.Err()
+}
+
+// newWordShuffleModulus is the prime the seeded new-word shuffle hashes word IDs modulo.
+// Every intermediate product stays below 2^63, so the hash never overflows SQLite integers.
+const newWordShuffleModulus = 2147483647
+
+// newWordShuffleMultiplier scatters neighbouring word IDs before the hash is squared
+const newWordShuffleMultiplier = 1103515245
+
+// newWordOrderBy returns the ORDER BY expression for introducing new words from words aliased as w
+func newWordOrderBy(selection user.NewWordSelection) string {
+	switch {
+	case selection.Order == user.NewWordOrderSequential:
+		return "w.id ASC"
+	case selection.Seed > 0:
+		// A stable pseudo-random order: square a seeded multiplicative hash of the ID
+		hash := fmt.Sprintf("(((w.id + %d) * %d) %% %d)", selection.Seed, newWordShuffleMultiplier, newWordShuffleModulus)
+		return fmt.Sprintf("(%s * %s) %% %d, w.id", hash, hash, newWordShuffleModulus)
+	default:
+		return "RANDOM()"
+	}
+}
+
+// newWordCategoryCondition returns a condition keeping words aliased as w to the categories
+// bound, comma-separated, to the numbered parameter; an empty list keeps every word
+func newWordCategoryCondition(param int) string {
+	return fmt.Sprintf("(?%[1]d = '' OR INSTR(',' || ?%[1]d || ',', ',' || w.category || ',') > 0)", param)
+}
+
 // scanProgressRow scans a progress row from the database
 func (r *learningRepository) scanProgressRow(rows *sql.Rows, userID user.ID) (*learning.UserProgress, error) {
 	var id learning.ID
@@ -199,11 +370,11 @@ func (r *learningRepository) scanProgressRow(rows *sql.Rows, userID user.ID) (*l
 	var wID vocabulary.ID
 	var stability, difficulty float64
 	var lastReviewStr, dueDateStr, createdAtStr, updatedAtStr sql.NullString
-	var reviewCount, lapses int
+	var reviewCount, lapses, learningStep int
 	var state string
 
 	err := rows.Scan(&id, &uID, &wID, &stability, &difficulty, &lastReviewStr, &dueDateStr,
-		&reviewCount, &lapses, &state, &createdAtStr, &updatedAtStr)
+		&reviewCount, &lapses, &state, &learningStep, &createdAtStr, &updatedAtStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan progress: %w", err)
 	}
@@ -235,7 +406,7 @@ func (r *learningRepository) scanProgressRow(rows *sql.Rows, userID user.ID) (*l
 
 	// Set FSRS card data
 	fsrsCard := progress.FSRSCard()
-	r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, state)
+	r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, learningStep, state)
 
 	return progress, nil
 }
@@ -244,7 +415,7 @@ func (r *learningRepository) scanProgressRow(rows *sql.Rows, userID user.ID) (*l
 func (r *learningRepository) FindProgressByUser(ctx context.Context, userID user.ID) ([]*learning.UserProgress, error) {
 	query := `
 		SELECT id, user_id, word_id, stability, difficulty, last_review, due_date, 
-		       review_count, lapses, state, created_at, updated_at
+		       review_count, lapses, state, learning_step, created_at, updated_at
 		FROM user_progress 
 		WHERE user_id = ?
 		ORDER BY updated_at DESC
@@ -264,11 +435,11 @@ func (r *learningRepository) FindProgressByUser(ctx context.Context, userID user
 		var wID vocabulary.ID
 		var stability, difficulty float64
 		var lastReviewStr, dueDateStr, createdAtStr, updatedAtStr sql.NullString
-		var reviewCount, lapses int
+		var reviewCount, lapses, learningStep int
 		var state string
 
 		err := rows.Scan(&id, &uID, &wID, &stability, &difficulty, &lastReviewStr, &dueDateStr,
-			&reviewCount, &lapses, &state, &createdAtStr, &updatedAtStr)
+			&reviewCount, &lapses, &state, &learningStep, &createdAtStr, &updatedAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan progress: %w", err)
 		}
@@ -300,7 +471,7 @@ func (r *learningRepository) FindProgressByUser(ctx context.Context, userID user
 
 		// Set FSRS card data
 		fsrsCard := progress.FSRSCard()
-		r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, state)
+		r.setFSRSCardFromDB(fsrsCard, stability, difficulty, lastReview, dueDate, reviewCount, lapses, learningStep, state)
 
 		progressList = append(progressList, progress)
 	}
@@ -315,13 +486,16 @@ func (r *learningRepository) FindProgressByUser(ctx context.Context, userID user
 // SaveReviewHistory persists review history
 func (r *learningRepository) SaveReviewHistory(ctx context.Context, history *learning.ReviewHistory) error {
 	query := `
-		INSERT INTO review_history (user_id, word_id, rating, review_time, response_time_ms)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO review_history (user_id, word_id, rating, review_time, response_time_ms, score,
+			prior_stability, prior_difficulty, prior_last_review, prior_due_date,
+			prior_review_count, prior_lapses, prior_state, prior_learning_step)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		int64(history.UserID()), int64(history.WordID()),
-		int(history.Rating()), history.ReviewTime(), history.ResponseTimeMs())
+	args := append([]interface{}{int64(history.UserID()), int64(history.WordID()),
+		int(history.Rating()), history.ReviewTime(), history.ResponseTimeMs(), history.Score()},
+		priorCardValues(history.PriorCard())...)
+	result, err := r.db.ExecContext(ctx, query, args...)
 
 	if err != nil {
 		return fmt.Errorf("failed to save review history: %w", err)
@@ -339,7 +513,7 @@ func (r *learningRepository) SaveReviewHistory(ctx context.Context, history *lea
 // FindReviewHistory retrieves review history for a user and word
 func (r *learningRepository) FindReviewHistory(ctx context.Context, userID user.ID, wordID vocabulary.ID) ([]*learning.ReviewHistory, error) {
 	query := `
-		SELECT id, user_id, word_id, rating, review_time, response_time_ms
+		SELECT id, user_id, word_id, rating, review_time, response_time_ms, score
 		FROM review_history 
 		WHERE user_id = ? AND word_id = ?
 		ORDER BY review_time DESC
@@ -360,8 +534,9 @@ func (r *learningRepository) FindReviewHistory(ctx context.Context, userID user.
 		var rating int
 		var reviewTimeStr sql.NullString
 		var responseTimeMs int
+		var score sql.NullFloat64
 
-		err := rows.Scan(&id, &uID, &wID, &rating, &reviewTimeStr, &responseTimeMs)
+		err := rows.Scan(&id, &uID, &wID, &rating, &reviewTimeStr, &responseTimeMs, &score)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan review history: %w", err)
 		}
@@ -375,6 +550,9 @@ func (r *learningRepository) FindReviewHistory(ctx context.Context, userID user.
 		history := learning.NewReviewHistory(userID, wordID, learning.Rating(rating), time.Duration(responseTimeMs)*time.Millisecond)
 		history.SetID(id)
 		history.SetReviewTime(reviewTime)
+		if score.Valid {
+			history.SetScore(score.Float64)
+		}
 
 		historyList = append(historyList, history)
 	}
@@ -387,13 +565,13 @@ func (r *learningRepository) FindReviewHistory(ctx context.Context, userID user.
 }
 
 // GetUserStats retrieves learning statistics for a user
-func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID) (*learning.UserStats, error) {
+func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID, reviewAhead time.Duration) (*learning.UserStats, error) {
 	stats := &learning.UserStats{}
 
 	// Total words in vocabulary
 	var totalVocabularyWords int
 	err := r.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM words
+		SELECT COUNT(*) FROM words WHERE archived = 0
 	`).Scan(&totalVocabularyWords)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total vocabulary words: %w", err)
@@ -408,12 +586,19 @@ func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID) (
 		return nil, fmt.Errorf("failed to get studied words: %w", err)
 	}
 
-	// Calculate new words (vocabulary words minus studied words)
-	newWords := totalVocabularyWords - studiedWords
-
-	// Set totals
 	stats.TotalWords = totalVocabularyWords
-	stats.NewWords = newWords
+
+	// New words available to introduce - counted directly rather than derived from the totals,
+	// since progress on archived words would otherwise skew the difference
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM words w
+		WHERE w.archived = 0 AND NOT EXISTS (
+			SELECT 1 FROM user_progress up WHERE up.user_id = ? AND up.word_id = w.id
+		)
+	`, int64(userID)).Scan(&stats.NewWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new words: %w", err)
+	}
 
 	// Words by state (only for words that have been studied)
 	err = r.db.QueryRowContext(ctx, `
@@ -431,10 +616,13 @@ func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID) (
 	}
 
 	// Due words - only count words that are actually due according to FSRS schedule
+	// and still in the vocabulary, matching what FindDueWords will hand out
 	var dueProgressWords int
 	err = r.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM user_progress WHERE user_id = ? AND due_date <= CURRENT_TIMESTAMP
-	`, int64(userID)).Scan(&dueProgressWords)
+		SELECT COUNT(*) FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.archived = 0 AND up.due_date <= DATETIME('now', ?)
+	`, int64(userID), reviewAheadModifier(reviewAhead)).Scan(&dueProgressWords)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get due progress words: %w", err)
 	}
@@ -442,6 +630,17 @@ func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID) (
 	// Only count actually due words, don't artificially inflate with new words
 	stats.DueWords = dueProgressWords
 
+	// Due words the user flagged as low priority (muted from reminders)
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM user_progress up
+		JOIN low_priority_words lp ON lp.user_id = up.user_id AND lp.word_id = up.word_id
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.archived = 0 AND up.due_date <= DATETIME('now', ?)
+	`, int64(userID), reviewAheadModifier(reviewAhead)).Scan(&stats.LowPriorityDueWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low priority due words: %w", err)
+	}
+
 	// Average difficulty (only for words that have been studied)
 	if studiedWords > 0 {
 		err = r.db.QueryRowContext(ctx, `
@@ -470,6 +669,14 @@ func (r *learningRepository) GetUserStats(ctx context.Context, userID user.ID) (
 		return nil, fmt.Errorf("failed to get correct reviews: %w", err)
 	}
 
+	// Weighted accuracy (reviews recorded before partial credit have no score and are skipped)
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(AVG(score), 0) FROM review_history WHERE user_id = ? AND score IS NOT NULL
+	`, int64(userID)).Scan(&stats.WeightedAccuracy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weighted accuracy: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -503,119 +710,902 @@ func (r *learningRepository) GetUsersWithProgress(ctx context.Context) ([]user.I
 	return userIDs, nil
 }
 
-// Helper method to set FSRS card data from database values
-func (r *learningRepository) setFSRSCardFromDB(card *learning.FSRSCard, stability, difficulty float64,
-	lastReview, dueDate time.Time, reviewCount, lapses int, state string) {
-	card.SetStability(stability)
-	card.SetDifficulty(difficulty)
-	card.SetLastReview(lastReview)
-	card.SetDueDate(dueDate)
-	card.SetReviewCount(reviewCount)
-	card.SetLapses(lapses)
-	card.SetState(learning.State(state))
-}
+// FindRecentReviewTimes retrieves the most recent review timestamps for a user
+func (r *learningRepository) FindRecentReviewTimes(ctx context.Context, userID user.ID, limit int) ([]time.Time, error) {
+	query := `
+		SELECT review_time
+		FROM review_history
+		WHERE user_id = ?
+		ORDER BY review_time DESC
+		LIMIT ?
+	`
 
-// Helper method to parse datetime strings
-func (r *learningRepository) parseDateTime(str sql.NullString) (time.Time, error) {
-	if !str.Valid {
-		return time.Time{}, nil
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review times: %w", err)
 	}
+	defer rows.Close()
 
-	// Try different SQLite datetime formats
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05.000",
-		"2006-01-02T15:04:05.000",
-		"2006-01-02 15:04:05+00:00",     // SQLite with timezone
-		"2006-01-02 15:04:05.000+00:00", // SQLite with milliseconds and timezone
-		"2006-01-02 15:04:05-07:00",     // SQLite with different timezone
-		"2006-01-02 15:04:05.000-07:00", // SQLite with milliseconds and different timezone
-	}
+	var reviewTimes []time.Time
+	for rows.Next() {
+		var reviewTimeStr sql.NullString
+		if err := rows.Scan(&reviewTimeStr); err != nil {
+			return nil, fmt.Errorf("failed to scan review time: %w", err)
+		}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, str.String); err == nil {
-			return t, nil
+		reviewTime, err := r.parseDateTime(reviewTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse review_time: %w", err)
 		}
+		reviewTimes = append(reviewTimes, reviewTime)
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse datetime: %s", str.String)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return reviewTimes, nil
 }
 
-// SaveProgressAndHistory saves both progress and review history in a single transaction
-func (r *learningRepository) SaveProgressAndHistory(ctx context.Context, progress *learning.UserProgress, history *learning.ReviewHistory) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+// GetStreak computes the user's current and longest runs of consecutive days with at least one review,
+// with days taken in now's location
+func (r *learningRepository) GetStreak(ctx context.Context, userID user.ID, now time.Time) (int, int, error) {
+	query := `
+		SELECT review_time
+		FROM review_history
+		WHERE user_id = ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID))
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, 0, fmt.Errorf("failed to query review times: %w", err)
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Save or update progress
-	fsrsCard := progress.FSRSCard()
-	if progress.ID() == 0 {
-		query := `
-			INSERT INTO user_progress 
-			(user_id, word_id, stability, difficulty, last_review, due_date, review_count, lapses, state, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`
-		result, err := tx.ExecContext(ctx, query,
-			int64(progress.UserID()), int64(progress.WordID()),
-			fsrsCard.Stability(), fsrsCard.Difficulty(),
-			fsrsCard.LastReview(), fsrsCard.DueDate(),
-			fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()),
-			progress.CreatedAt(), progress.UpdatedAt())
+	var reviewTimes []time.Time
+	for rows.Next() {
+		var reviewTimeStr sql.NullString
+		if err := rows.Scan(&reviewTimeStr); err != nil {
+			return 0, 0, fmt.Errorf("failed to scan review time: %w", err)
+		}
 
+		reviewTime, err := r.parseDateTime(reviewTimeStr)
 		if err != nil {
-			return fmt.Errorf("failed to save progress: %w", err)
+			return 0, 0, fmt.Errorf("failed to parse review_time: %w", err)
 		}
+		reviewTimes = append(reviewTimes, reviewTime)
+	}
 
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get progress ID: %w", err)
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	current, longest := learning.StudyStreak(reviewTimes, now)
+	return current, longest, nil
+}
+
+// GetReviewForecast counts the user's words coming due on each of the next days, starting today
+func (r *learningRepository) GetReviewForecast(ctx context.Context, userID user.ID, now time.Time, days int) ([]learning.ForecastDay, error) {
+	query := `
+		SELECT up.due_date
+		FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.archived = 0
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due dates: %w", err)
+	}
+	defer rows.Close()
+
+	var dueDates []time.Time
+	for rows.Next() {
+		var dueDateStr sql.NullString
+		if err := rows.Scan(&dueDateStr); err != nil {
+			return nil, fmt.Errorf("failed to scan due date: %w", err)
 		}
-		progress.SetID(learning.ID(id))
-	} else {
-		query := `
-			UPDATE user_progress 
-			SET stability = ?, difficulty = ?, last_review = ?, due_date = ?, 
-				review_count = ?, lapses = ?, state = ?, updated_at = ?
-			WHERE id = ?
-		`
-		_, err = tx.ExecContext(ctx, query,
-			fsrsCard.Stability(), fsrsCard.Difficulty(),
-			fsrsCard.LastReview(), fsrsCard.DueDate(),
-			fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()),
-			progress.UpdatedAt(), int64(progress.ID()))
 
+		dueDate, err := r.parseDateTime(dueDateStr)
 		if err != nil {
-			return fmt.Errorf("failed to update progress: %w", err)
+			return nil, fmt.Errorf("failed to parse due_date: %w", err)
 		}
+		dueDates = append(dueDates, dueDate)
 	}
 
-	// Save review history
-	query := `
-		INSERT INTO review_history (user_id, word_id, rating, review_time, response_time_ms)
-		VALUES (?, ?, ?, ?, ?)
-	`
-	result, err := tx.ExecContext(ctx, query,
-		int64(history.UserID()), int64(history.WordID()),
-		int(history.Rating()), history.ReviewTime(), history.ResponseTimeMs())
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return learning.ReviewForecast(dueDates, now, days), nil
+}
+
+// SetLowPriority flags or unflags a word as low priority for reminders
+func (r *learningRepository) SetLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID, lowPriority bool) error {
+	query := `DELETE FROM low_priority_words WHERE user_id = ? AND word_id = ?`
+	if lowPriority {
+		query = `INSERT OR IGNORE INTO low_priority_words (user_id, word_id) VALUES (?, ?)`
+	}
 
+	_, err := r.db.ExecContext(ctx, query, int64(userID), int64(wordID))
 	if err != nil {
-		return fmt.Errorf("failed to save review history: %w", err)
+		return fmt.Errorf("failed to update low priority flag: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	return nil
+}
+
+// ReportWord records a user's report that a word is wrong and returns the distinct reporter count
+func (r *learningRepository) ReportWord(ctx context.Context, userID user.ID, wordID vocabulary.ID) (int, error) {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO word_reports (user_id, word_id) VALUES (?, ?)
+	`, int64(userID), int64(wordID))
 	if err != nil {
-		return fmt.Errorf("failed to get review history ID: %w", err)
+		return 0, fmt.Errorf("failed to save word report: %w", err)
 	}
-	history.SetID(learning.ID(id))
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	var reporters int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT user_id) FROM word_reports WHERE word_id = ?
+	`, int64(wordID)).Scan(&reporters)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count word reports: %w", err)
 	}
 
-	return nil
+	return reporters, nil
+}
+
+// IsLowPriority checks if a word is flagged as low priority for reminders
+func (r *learningRepository) IsLowPriority(ctx context.Context, userID user.ID, wordID vocabulary.ID) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM low_priority_words WHERE user_id = ? AND word_id = ?
+	`, int64(userID), int64(wordID)).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check low priority flag: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetCommunityDifficulty averages the difficulty of a word across the other learners who have reviewed it
+func (r *learningRepository) GetCommunityDifficulty(ctx context.Context, wordID vocabulary.ID, excludeUserID user.ID) (float64, int, error) {
+	var avgDifficulty float64
+	var learners int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(AVG(difficulty), 0), COUNT(*) FROM user_progress
+		WHERE word_id = ? AND user_id != ? AND review_count > 0
+	`, int64(wordID), int64(excludeUserID)).Scan(&avgDifficulty, &learners)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get community difficulty: %w", err)
+	}
+
+	return avgDifficulty, learners, nil
+}
+
+// snapshotDateFormat is the day format used for difficulty snapshots
+const snapshotDateFormat = "2006-01-02"
+
+// FindDueWordsByCategory retrieves due words in a vocabulary category
+func (r *learningRepository) FindDueWordsByCategory(ctx context.Context, userID user.ID, category vocabulary.Category, reviewAhead time.Duration, limit int) ([]*learning.UserProgress, error) {
+	query := `
+		SELECT up.id, up.user_id, up.word_id, up.stability, up.difficulty, up.last_review, up.due_date,
+		       up.review_count, up.lapses, up.state, up.learning_step, up.created_at, up.updated_at
+		FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.category = ? AND w.archived = 0 AND up.due_date <= DATETIME('now', ?)
+		ORDER BY up.due_date ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), string(category), reviewAheadModifier(reviewAhead), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due words by category: %w", err)
+	}
+	defer rows.Close()
+
+	var progressList []*learning.UserProgress
+	for rows.Next() {
+		progress, err := r.scanProgressRow(rows, userID)
+		if err != nil {
+			return nil, err
+		}
+		progressList = append(progressList, progress)
+	}
+
+	return progressList, rows.Err()
+}
+
+// FindNewWordsByCategory retrieves unstudied words in a vocabulary category
+func (r *learningRepository) FindNewWordsByCategory(ctx context.Context, userID user.ID, category vocabulary.Category, selection user.NewWordSelection, limit int) ([]*learning.UserProgress, error) {
+	query := `
+		SELECT w.id
+		FROM words w
+		WHERE w.category = ? AND w.archived = 0
+		  AND w.id NOT IN (SELECT word_id FROM user_progress WHERE user_id = ?)
+		ORDER BY ` + newWordOrderBy(selection) + `
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(category), int64(userID), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new words by category: %w", err)
+	}
+	defer rows.Close()
+
+	var progressList []*learning.UserProgress
+	for rows.Next() {
+		var wordID vocabulary.ID
+		if err := rows.Scan(&wordID); err != nil {
+			return nil, fmt.Errorf("failed to scan word ID: %w", err)
+		}
+		progressList = append(progressList, learning.NewUserProgress(userID, wordID))
+	}
+
+	return progressList, rows.Err()
+}
+
+// CountIntroducedWords counts the words the user started studying since a given time
+func (r *learningRepository) CountIntroducedWords(ctx context.Context, userID user.ID, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM user_progress WHERE user_id = ? AND created_at >= ?`
+
+	// Timestamps are stored as text with their UTC offset and compared as text, so a bound in
+	// the user's time zone has to be converted to UTC to compare against the right instant
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, int64(userID), since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count introduced words: %w", err)
+	}
+	return count, nil
+}
+
+// GetRetentionRate computes the fraction of the user's reviews within the window rated Good or Easy.
+// Only reviews of cards in review state count, along with older reviews that didn't record the state.
+func (r *learningRepository) GetRetentionRate(ctx context.Context, userID user.ID, window time.Duration) (float64, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN rating >= 3 THEN 1 ELSE 0 END), 0)
+		FROM review_history
+		WHERE user_id = ? AND review_time >= ? AND (prior_state IS NULL OR prior_state = 'review')
+	`
+
+	since := time.Now().Add(-window)
+	var reviews, recalled int
+	if err := r.db.QueryRowContext(ctx, query, int64(userID), since).Scan(&reviews, &recalled); err != nil {
+		return 0, fmt.Errorf("failed to get retention rate: %w", err)
+	}
+	if reviews == 0 {
+		return learning.NoRetentionRate, nil
+	}
+	return float64(recalled) / float64(reviews), nil
+}
+
+// GetUserStatsByCategory retrieves statistics for each category the user has studied words in.
+// Archived words are left out, so categories only holding those don't appear.
+func (r *learningRepository) GetUserStatsByCategory(ctx context.Context, userID user.ID, reviewAhead time.Duration) (map[vocabulary.Category]*learning.CategoryStats, error) {
+	query := `
+		SELECT w.category, COUNT(*),
+		       SUM(CASE WHEN up.due_date <= DATETIME('now', ?) THEN 1 ELSE 0 END),
+		       AVG(up.difficulty)
+		FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE up.user_id = ? AND w.archived = 0
+		GROUP BY w.category
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewAheadModifier(reviewAhead), int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[vocabulary.Category]*learning.CategoryStats)
+	for rows.Next() {
+		var category string
+		categoryStats := &learning.CategoryStats{}
+		if err := rows.Scan(&category, &categoryStats.Studied, &categoryStats.Due, &categoryStats.AvgDifficulty); err != nil {
+			return nil, fmt.Errorf("failed to scan category stats: %w", err)
+		}
+		stats[vocabulary.Category(category)] = categoryStats
+	}
+
+	return stats, rows.Err()
+}
+
+// CountReviewedWordsByCategory counts the distinct words the user reviewed since a given time, per category
+func (r *learningRepository) CountReviewedWordsByCategory(ctx context.Context, userID user.ID, since time.Time) (map[vocabulary.Category]int, error) {
+	query := `
+		SELECT w.category, COUNT(DISTINCT rh.word_id)
+		FROM review_history rh
+		JOIN words w ON w.id = rh.word_id
+		WHERE rh.user_id = ? AND rh.review_time >= ?
+		GROUP BY w.category
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reviewed words by category: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[vocabulary.Category]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		counts[vocabulary.Category(category)] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetWeeklyLeaderboard ranks users by their reviews in the last 7 days, leaving out users who hid themselves
+func (r *learningRepository) GetWeeklyLeaderboard(ctx context.Context, limit int) ([]*learning.LeaderboardEntry, error) {
+	query := `
+		SELECT u.id, COALESCE(u.first_name, ''), COUNT(*) AS reviews
+		FROM review_history rh
+		JOIN users u ON u.id = rh.user_id
+		WHERE rh.review_time >= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_preferences p
+			WHERE p.user_id = u.id AND p.preference_key = ? AND p.preference_value = 'false'
+		  )
+		GROUP BY u.id
+		ORDER BY reviews DESC, u.id ASC
+		LIMIT ?
+	`
+
+	since := time.Now().Add(-learning.LeaderboardWindow)
+	rows, err := r.db.QueryContext(ctx, query, since, user.PrefLeaderboardVisible, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*learning.LeaderboardEntry
+	for rows.Next() {
+		var entry learning.LeaderboardEntry
+		var userID int64
+		if err := rows.Scan(&userID, &entry.FirstName, &entry.Reviews); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entry.UserID = user.ID(userID)
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// SaveSessionLog records the aggregates of a finished learning session
+func (r *learningRepository) SaveSessionLog(ctx context.Context, sessionLog *learning.SessionLog) error {
+	query := `
+		INSERT INTO sessions_log (user_id, started_at, ended_at, words, correct, incorrect,
+		                          accuracy, duration_seconds, new_words, review_words, practice)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		int64(sessionLog.UserID), sessionLog.StartedAt, sessionLog.EndedAt,
+		sessionLog.WordCount(), sessionLog.CorrectCount, sessionLog.IncorrectCount,
+		sessionLog.Accuracy(), int(sessionLog.Duration().Seconds()),
+		sessionLog.NewWords, sessionLog.ReviewWords, sessionLog.Practice)
+	if err != nil {
+		return fmt.Errorf("failed to save session log: %w", err)
+	}
+
+	return nil
+}
+
+// FindSessionLogs retrieves the user's finished sessions that started on or after since
+func (r *learningRepository) FindSessionLogs(ctx context.Context, userID user.ID, since time.Time) ([]*learning.SessionLog, error) {
+	query := `
+		SELECT started_at, ended_at, correct, incorrect, new_words, review_words, practice
+		FROM sessions_log
+		WHERE user_id = ? AND started_at >= ?
+		ORDER BY started_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session logs: %w", err)
+	}
+	defer rows.Close()
+
+	var sessionLogs []*learning.SessionLog
+	for rows.Next() {
+		var startedAtStr, endedAtStr sql.NullString
+		sessionLog := &learning.SessionLog{UserID: userID}
+		if err := rows.Scan(&startedAtStr, &endedAtStr, &sessionLog.CorrectCount, &sessionLog.IncorrectCount,
+			&sessionLog.NewWords, &sessionLog.ReviewWords, &sessionLog.Practice); err != nil {
+			return nil, fmt.Errorf("failed to scan session log: %w", err)
+		}
+
+		if sessionLog.StartedAt, err = r.parseDateTime(startedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse started_at: %w", err)
+		}
+		if sessionLog.EndedAt, err = r.parseDateTime(endedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse ended_at: %w", err)
+		}
+
+		sessionLogs = append(sessionLogs, sessionLog)
+	}
+
+	return sessionLogs, rows.Err()
+}
+
+// RecordDifficultySnapshot records the user's current average difficulty as the snapshot for a day
+func (r *learningRepository) RecordDifficultySnapshot(ctx context.Context, userID user.ID, day time.Time) error {
+	query := `
+		INSERT OR REPLACE INTO difficulty_snapshots (user_id, snapshot_date, avg_difficulty)
+		SELECT user_id, ?, AVG(difficulty)
+		FROM user_progress
+		WHERE user_id = ?
+		GROUP BY user_id
+	`
+
+	_, err := r.db.ExecContext(ctx, query, day.Format(snapshotDateFormat), int64(userID))
+	if err != nil {
+		return fmt.Errorf("failed to save difficulty snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// FindDifficultySnapshots retrieves the user's daily difficulty snapshots since a given day
+func (r *learningRepository) FindDifficultySnapshots(ctx context.Context, userID user.ID, since time.Time) ([]*learning.DifficultySnapshot, error) {
+	query := `
+		SELECT snapshot_date, avg_difficulty
+		FROM difficulty_snapshots
+		WHERE user_id = ? AND snapshot_date >= ?
+		ORDER BY snapshot_date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int64(userID), since.Format(snapshotDateFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query difficulty snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*learning.DifficultySnapshot
+	for rows.Next() {
+		var dateStr string
+		var avgDifficulty float64
+		if err := rows.Scan(&dateStr, &avgDifficulty); err != nil {
+			return nil, fmt.Errorf("failed to scan difficulty snapshot: %w", err)
+		}
+
+		date, err := time.ParseInLocation(snapshotDateFormat, dateStr, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot_date: %w", err)
+		}
+
+		snapshots = append(snapshots, &learning.DifficultySnapshot{Date: date, AvgDifficulty: avgDifficulty})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// reviewAheadModifier converts a review-ahead window into an SQLite datetime modifier
+func reviewAheadModifier(reviewAhead time.Duration) string {
+	if reviewAhead < 0 {
+		reviewAhead = 0
+	}
+	return fmt.Sprintf("+%d minutes", int(reviewAhead.Minutes()))
+}
+
+// Helper method to set FSRS card data from database values
+func (r *learningRepository) setFSRSCardFromDB(card *learning.FSRSCard, stability, difficulty float64,
+	lastReview, dueDate time.Time, reviewCount, lapses, learningStep int, state string) {
+	card.SetStability(stability)
+	card.SetDifficulty(difficulty)
+	card.SetLastReview(lastReview)
+	card.SetDueDate(dueDate)
+	card.SetReviewCount(reviewCount)
+	card.SetLapses(lapses)
+	card.SetLearningStep(learningStep)
+	card.SetState(learning.State(state))
+}
+
+// Helper method to parse datetime strings
+func (r *learningRepository) parseDateTime(str sql.NullString) (time.Time, error) {
+	if !str.Valid {
+		return time.Time{}, nil
+	}
+
+	// Try different SQLite datetime formats
+	formats := []string{
+		time.RFC3339,
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05.000",
+		"2006-01-02T15:04:05.000",
+		"2006-01-02 15:04:05+00:00",     // SQLite with timezone
+		"2006-01-02 15:04:05.000+00:00", // SQLite with milliseconds and timezone
+		"2006-01-02 15:04:05-07:00",     // SQLite with different timezone
+		"2006-01-02 15:04:05.000-07:00", // SQLite with milliseconds and different timezone
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, str.String); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse datetime: %s", str.String)
+}
+
+// SaveProgressAndHistory saves both progress and review history in a single transaction
+func (r *learningRepository) SaveProgressAndHistory(ctx context.Context, progress *learning.UserProgress, history *learning.ReviewHistory) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Save or update progress
+	fsrsCard := progress.FSRSCard()
+	if progress.ID() == 0 {
+		var id int64
+		err := tx.QueryRowContext(ctx, upsertProgressQuery,
+			int64(progress.UserID()), int64(progress.WordID()),
+			fsrsCard.Stability(), fsrsCard.Difficulty(),
+			fsrsCard.LastReview(), fsrsCard.DueDate(),
+			fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()), fsrsCard.LearningStep(),
+			progress.CreatedAt(), progress.UpdatedAt()).Scan(&id)
+
+		if err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+		progress.SetID(learning.ID(id))
+	} else {
+		query := `
+			UPDATE user_progress 
+			SET stability = ?, difficulty = ?, last_review = ?, due_date = ?, 
+				review_count = ?, lapses = ?, state = ?, learning_step = ?, updated_at = ?
+			WHERE id = ?
+		`
+		_, err = tx.ExecContext(ctx, query,
+			fsrsCard.Stability(), fsrsCard.Difficulty(),
+			fsrsCard.LastReview(), fsrsCard.DueDate(),
+			fsrsCard.ReviewCount(), fsrsCard.Lapses(), string(fsrsCard.State()), fsrsCard.LearningStep(),
+			progress.UpdatedAt(), int64(progress.ID()))
+
+		if err != nil {
+			return fmt.Errorf("failed to update progress: %w", err)
+		}
+	}
+
+	// Save review history
+	query := `
+		INSERT INTO review_history (user_id, word_id, rating, review_time, response_time_ms, score,
+			prior_stability, prior_difficulty, prior_last_review, prior_due_date,
+			prior_review_count, prior_lapses, prior_state, prior_learning_step)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	args := append([]interface{}{int64(history.UserID()), int64(history.WordID()),
+		int(history.Rating()), history.ReviewTime(), history.ResponseTimeMs(), history.Score()},
+		priorCardValues(history.PriorCard())...)
+	result, err := tx.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return fmt.Errorf("failed to save review history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get review history ID: %w", err)
+	}
+	history.SetID(learning.ID(id))
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// priorCardValues returns the prior_* column values for a review's prior card, all NULL when there is none
+func priorCardValues(card *learning.FSRSCard) []interface{} {
+	if card == nil {
+		return []interface{}{nil, nil, nil, nil, nil, nil, nil, nil}
+	}
+	return []interface{}{card.Stability(), card.Difficulty(), card.LastReview(), card.DueDate(),
+		card.ReviewCount(), card.Lapses(), string(card.State()), card.LearningStep()}
+}
+
+// DeleteProgress deletes a progress record, such as one left behind by a deleted word
+func (r *learningRepository) DeleteProgress(ctx context.Context, id learning.ID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_progress WHERE id = ?`, int64(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete progress: %w", err)
+	}
+	return nil
+}
+
+// FindLastReview retrieves the user's most recent review, or nil if they have none
+func (r *learningRepository) FindLastReview(ctx context.Context, userID user.ID) (*learning.ReviewHistory, error) {
+	query := `
+		SELECT id, word_id, rating, review_time, response_time_ms, score,
+			prior_stability, prior_difficulty, prior_last_review, prior_due_date,
+			prior_review_count, prior_lapses, prior_state, prior_learning_step
+		FROM review_history
+		WHERE user_id = ?
+		ORDER BY review_time DESC, id DESC
+		LIMIT 1
+	`
+
+	var id learning.ID
+	var wordID vocabulary.ID
+	var rating, responseTimeMs int
+	var reviewTimeStr, priorLastReviewStr, priorDueDateStr, priorState sql.NullString
+	var score, priorStability, priorDifficulty sql.NullFloat64
+	var priorReviewCount, priorLapses, priorLearningStep sql.NullInt64
+
+	err := r.db.QueryRowContext(ctx, query, int64(userID)).Scan(&id, &wordID, &rating, &reviewTimeStr,
+		&responseTimeMs, &score, &priorStability, &priorDifficulty, &priorLastReviewStr, &priorDueDateStr,
+		&priorReviewCount, &priorLapses, &priorState, &priorLearningStep)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last review: %w", err)
+	}
+
+	reviewTime, err := r.parseDateTime(reviewTimeStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse review_time: %w", err)
+	}
+
+	history := learning.NewReviewHistory(userID, wordID, learning.Rating(rating), time.Duration(responseTimeMs)*time.Millisecond)
+	history.SetID(id)
+	history.SetReviewTime(reviewTime)
+	if score.Valid {
+		history.SetScore(score.Float64)
+	}
+
+	if priorState.Valid {
+		priorLastReview, err := r.parseDateTime(priorLastReviewStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prior_last_review: %w", err)
+		}
+		priorDueDate, err := r.parseDateTime(priorDueDateStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prior_due_date: %w", err)
+		}
+
+		card := learning.NewFSRSCard()
+		r.setFSRSCardFromDB(card, priorStability.Float64, priorDifficulty.Float64, priorLastReview, priorDueDate,
+			int(priorReviewCount.Int64), int(priorLapses.Int64), int(priorLearningStep.Int64), priorState.String)
+		history.SetPriorCard(card)
+	}
+
+	return history, nil
+}
+
+// RevertReview restores the word's progress to the review's prior card and deletes the review
+func (r *learningRepository) RevertReview(ctx context.Context, history *learning.ReviewHistory) error {
+	card := history.PriorCard()
+	if card == nil {
+		return fmt.Errorf("review %d has no prior card to restore", history.ID())
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_progress
+		SET stability = ?, difficulty = ?, last_review = ?, due_date = ?,
+			review_count = ?, lapses = ?, state = ?, learning_step = ?, updated_at = ?
+		WHERE user_id = ? AND word_id = ?
+	`, card.Stability(), card.Difficulty(), card.LastReview(), card.DueDate(),
+		card.ReviewCount(), card.Lapses(), string(card.State()), card.LearningStep(), time.Now(),
+		int64(history.UserID()), int64(history.WordID()))
+	if err != nil {
+		return fmt.Errorf("failed to restore progress: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM review_history WHERE id = ?`, int64(history.ID()))
+	if err != nil {
+		return fmt.Errorf("failed to delete review history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAllProgress deletes all of the user's progress, review history and difficulty snapshots in one transaction
+func (r *learningRepository) DeleteAllProgress(ctx context.Context, userID user.ID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM review_history WHERE user_id = ?`, int64(userID))
+	if err != nil {
+		return fmt.Errorf("failed to delete review history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_progress WHERE user_id = ?`, int64(userID))
+	if err != nil {
+		return fmt.Errorf("failed to delete progress: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM difficulty_snapshots WHERE user_id = ?`, int64(userID))
+	if err != nil {
+		return fmt.Errorf("failed to delete difficulty snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePartnerLink links two users as study partners, failing with ErrAlreadyLinked if either already has one
+func (r *learningRepository) CreatePartnerLink(ctx context.Context, userID, partnerID user.ID) (*learning.PartnerLink, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existing int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM linked_users
+		WHERE user_id IN (?1, ?2) OR partner_id IN (?1, ?2)
+	`, int64(userID), int64(partnerID)).Scan(&existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing partner links: %w", err)
+	}
+	if existing > 0 {
+		return nil, learning.ErrAlreadyLinked
+	}
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO linked_users (user_id, partner_id, created_at) VALUES (?, ?, ?)
+	`, int64(userID), int64(partnerID), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save partner link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner link ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &learning.PartnerLink{
+		ID:        learning.ID(id),
+		UserID:    userID,
+		PartnerID: partnerID,
+		CreatedAt: now,
+	}, nil
+}
+
+// FindPartnerLink retrieves the user's study partner link, or nil if they have none
+func (r *learningRepository) FindPartnerLink(ctx context.Context, userID user.ID) (*learning.PartnerLink, error) {
+	var id learning.ID
+	var linkUserID, partnerID user.ID
+	var createdAtStr sql.NullString
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, partner_id, created_at FROM linked_users
+		WHERE user_id = ?1 OR partner_id = ?1
+	`, int64(userID)).Scan(&id, &linkUserID, &partnerID, &createdAtStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partner link: %w", err)
+	}
+
+	createdAt, err := r.parseDateTime(createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse partner link created_at: %w", err)
+	}
+
+	return &learning.PartnerLink{
+		ID:        id,
+		UserID:    linkUserID,
+		PartnerID: partnerID,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// DeletePartnerLink removes a study partner link together with its shared deck.
+// Partners keep their progress and tags on the words they studied together.
+func (r *learningRepository) DeletePartnerLink(ctx context.Context, linkID learning.ID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM shared_deck_words WHERE link_id = ?`, int64(linkID)); err != nil {
+		return fmt.Errorf("failed to delete shared deck: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM linked_users WHERE id = ?`, int64(linkID)); err != nil {
+		return fmt.Errorf("failed to delete partner link: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddSharedDeckWord adds a word to the link's shared deck and tags it for both partners.
+// It returns false if the word was already in the deck.
+func (r *learningRepository) AddSharedDeckWord(ctx context.Context, link *learning.PartnerLink, wordID vocabulary.ID) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO shared_deck_words (link_id, word_id) VALUES (?, ?)
+	`, int64(link.ID), int64(wordID))
+	if err != nil {
+		return false, fmt.Errorf("failed to add shared deck word: %w", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	for _, userID := range []user.ID{link.UserID, link.PartnerID} {
+		_, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO word_tags (user_id, word_id, tag) VALUES (?, ?, ?)
+		`, int64(userID), int64(wordID), learning.PartnerDeckTag)
+		if err != nil {
+			return false, fmt.Errorf("failed to tag shared deck word: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added > 0, nil
+}
+
+// GetSharedDeckProgress summarizes a partner's own progress on the link's shared deck
+func (r *learningRepository) GetSharedDeckProgress(ctx context.Context, linkID learning.ID, userID user.ID) (*learning.DeckProgress, error) {
+	var progress learning.DeckProgress
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COUNT(up.id),
+		       COALESCE(SUM(CASE WHEN up.state = ? THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN up.due_date <= DATETIME('now') THEN 1 ELSE 0 END), 0)
+		FROM shared_deck_words d
+		JOIN words w ON w.id = d.word_id
+		LEFT JOIN user_progress up ON up.word_id = d.word_id AND up.user_id = ?
+		WHERE d.link_id = ? AND w.archived = 0
+	`, string(learning.StateReview), int64(userID), int64(linkID)).Scan(
+		&progress.TotalWords, &progress.StudiedWords, &progress.LearnedWords, &progress.DueWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared deck progress: %w", err)
+	}
+
+	return &progress, nil
 }
@@ -0,0 +1,744 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func saveTestUser(t *testing.T, db *sql.DB) user.ID {
+	t.Helper()
+
+	u := user.NewUser(42, "anna", "Anna", "", "en")
+	if err := NewUserRepository(db).Save(context.Background(), u); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	return u.ID()
+}
+
+func saveTestWord(t *testing.T, db *sql.DB, english, dutch string, category vocabulary.Category) vocabulary.ID {
+	t.Helper()
+
+	word := vocabulary.NewWord(english, dutch, category)
+	if err := NewVocabularyRepository(db).Save(context.Background(), word); err != nil {
+		t.Fatalf("failed to save word: %v", err)
+	}
+	return word.ID()
+}
+
+// saveDueProgress stores a review card for the word that became due at dueDate
+func saveDueProgress(t *testing.T, repo learning.Repository, userID user.ID, wordID vocabulary.ID, dueDate time.Time) {
+	t.Helper()
+
+	progress := learning.NewUserProgress(userID, wordID)
+	card := progress.FSRSCard()
+	card.SetState(learning.StateReview)
+	card.SetReviewCount(1)
+	card.SetLastReview(dueDate.Add(-24 * time.Hour))
+	card.SetDueDate(dueDate)
+	if err := repo.SaveProgress(context.Background(), progress); err != nil {
+		t.Fatalf("failed to save progress: %v", err)
+	}
+}
+
+func TestDueWordsExcludeArchivedWords(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	kept := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	archived := saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics"))
+	yesterday := time.Now().UTC().Add(-24 * time.Hour)
+	saveDueProgress(t, repo, userID, kept, yesterday)
+	saveDueProgress(t, repo, userID, archived, yesterday)
+	for _, wordID := range []vocabulary.ID{kept, archived} {
+		if err := repo.SetLowPriority(ctx, userID, wordID, true); err != nil {
+			t.Fatalf("failed to flag word: %v", err)
+		}
+	}
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	due, err := repo.FindDueWords(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("FindDueWords: %v", err)
+	}
+	if len(due) != 1 || due[0].WordID() != kept {
+		t.Errorf("FindDueWords returned %d words, want only word %d", len(due), kept)
+	}
+
+	stats, err := repo.GetUserStats(ctx, userID, 0)
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if stats.DueWords != 1 {
+		t.Errorf("DueWords = %d, want 1", stats.DueWords)
+	}
+	if stats.LowPriorityDueWords != 1 {
+		t.Errorf("LowPriorityDueWords = %d, want 1", stats.LowPriorityDueWords)
+	}
+}
+
+func TestFindDueWordsByTagExcludesArchivedWords(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	kept := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	archived := saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics"))
+	yesterday := time.Now().UTC().Add(-24 * time.Hour)
+	for _, wordID := range []vocabulary.ID{kept, archived} {
+		saveDueProgress(t, repo, userID, wordID, yesterday)
+		if err := repo.TagWord(ctx, userID, wordID, "exam"); err != nil {
+			t.Fatalf("failed to tag word: %v", err)
+		}
+	}
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	due, err := repo.FindDueWordsByTag(ctx, userID, "exam", 0, 10)
+	if err != nil {
+		t.Fatalf("FindDueWordsByTag: %v", err)
+	}
+	if len(due) != 1 || due[0].WordID() != kept {
+		t.Errorf("FindDueWordsByTag returned %d words, want only word %d", len(due), kept)
+	}
+}
+
+func TestGetReviewForecast(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Now().In(amsterdam)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, amsterdam)
+	at := func(days, hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days, hour, 0, 0, 0, amsterdam)
+	}
+
+	dueDates := []time.Time{
+		at(-3, 9), // Overdue, counts towards today
+		at(0, 23), // Late today in Amsterdam, already tomorrow in UTC
+		at(1, 8),
+		at(1, 20),
+		at(3, 12),
+		at(9, 12), // Beyond the forecast
+		at(0, 10), // Archived below, so left out
+	}
+	var archived vocabulary.ID
+	for i, dueDate := range dueDates {
+		wordID := saveTestWord(t, db, fmt.Sprintf("word %d", i), fmt.Sprintf("woord %d", i), vocabulary.Category("basics"))
+		saveDueProgress(t, repo, userID, wordID, dueDate.UTC())
+		archived = wordID
+	}
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	forecast, err := repo.GetReviewForecast(ctx, userID, now, 7)
+	if err != nil {
+		t.Fatalf("GetReviewForecast: %v", err)
+	}
+
+	want := []int{2, 2, 0, 1, 0, 0, 0}
+	if len(forecast) != len(want) {
+		t.Fatalf("forecast has %d days, want %d", len(forecast), len(want))
+	}
+	for i, day := range forecast {
+		if !day.Day.Equal(today.AddDate(0, 0, i)) {
+			t.Errorf("day %d starts at %v, want %v", i, day.Day, today.AddDate(0, 0, i))
+		}
+		if day.Count != want[i] {
+			t.Errorf("day %d count = %d, want %d", i, day.Count, want[i])
+		}
+	}
+}
+
+// saveReview records a review of the word at reviewTime, made while the card was in priorState
+func saveReview(t *testing.T, repo learning.Repository, userID user.ID, wordID vocabulary.ID, rating learning.Rating, priorState learning.State, reviewTime time.Time) {
+	t.Helper()
+
+	prior := learning.NewFSRSCard()
+	prior.SetState(priorState)
+	history := learning.NewReviewHistory(userID, wordID, rating, 2*time.Second)
+	history.SetPriorCard(prior)
+	history.SetReviewTime(reviewTime)
+	if err := repo.SaveReviewHistory(context.Background(), history); err != nil {
+		t.Fatalf("failed to save review: %v", err)
+	}
+}
+
+func TestGetRetentionRate(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+
+	window := 30 * 24 * time.Hour
+	now := time.Now()
+
+	rate, err := repo.GetRetentionRate(ctx, userID, window)
+	if err != nil {
+		t.Fatalf("GetRetentionRate: %v", err)
+	}
+	if rate != learning.NoRetentionRate {
+		t.Errorf("retention without reviews = %v, want NoRetentionRate", rate)
+	}
+
+	// Just outside the window: not counted
+	saveReview(t, repo, userID, wordID, learning.Again, learning.StateReview, now.Add(-window-time.Minute))
+	rate, err = repo.GetRetentionRate(ctx, userID, window)
+	if err != nil {
+		t.Fatalf("GetRetentionRate: %v", err)
+	}
+	if rate != learning.NoRetentionRate {
+		t.Errorf("retention with only older reviews = %v, want NoRetentionRate", rate)
+	}
+
+	// Just inside the window, plus reviews of every rating
+	saveReview(t, repo, userID, wordID, learning.Good, learning.StateReview, now.Add(-window+time.Minute))
+	saveReview(t, repo, userID, wordID, learning.Easy, learning.StateReview, now.Add(-time.Hour))
+	saveReview(t, repo, userID, wordID, learning.Hard, learning.StateReview, now.Add(-time.Hour))
+	saveReview(t, repo, userID, wordID, learning.Again, learning.StateReview, now.Add(-time.Hour))
+	// Learning-state reviews are left out
+	saveReview(t, repo, userID, wordID, learning.Again, learning.StateLearning, now.Add(-time.Hour))
+
+	rate, err = repo.GetRetentionRate(ctx, userID, window)
+	if err != nil {
+		t.Fatalf("GetRetentionRate: %v", err)
+	}
+	if rate != 0.5 {
+		t.Errorf("retention = %v, want 0.5 (Good and Easy out of four review-state reviews)", rate)
+	}
+
+	// A shorter window only sees the last hour
+	rate, err = repo.GetRetentionRate(ctx, userID, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("GetRetentionRate: %v", err)
+	}
+	if want := 1.0 / 3.0; rate != want {
+		t.Errorf("retention over two hours = %v, want %v", rate, want)
+	}
+}
+
+func TestRecordDifficultySnapshot(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	// Late evening in Amsterdam is already the next day in UTC; the snapshot keeps the local date
+	day := time.Date(2024, 3, 20, 23, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	// Without progress there is nothing to snapshot
+	if err := repo.RecordDifficultySnapshot(ctx, userID, day); err != nil {
+		t.Fatalf("RecordDifficultySnapshot: %v", err)
+	}
+	snapshots, err := repo.FindDifficultySnapshots(ctx, userID, day.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("FindDifficultySnapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("got %d snapshots without progress, want 0", len(snapshots))
+	}
+
+	for i, difficulty := range []float64{4, 6} {
+		wordID := saveTestWord(t, db, fmt.Sprintf("word %d", i), fmt.Sprintf("woord %d", i), vocabulary.Category("basics"))
+		progress := learning.NewUserProgress(userID, wordID)
+		progress.FSRSCard().SetDifficulty(difficulty)
+		if err := repo.SaveProgress(ctx, progress); err != nil {
+			t.Fatalf("failed to save progress: %v", err)
+		}
+	}
+
+	// A second snapshot on the same day replaces the first
+	if err := repo.RecordDifficultySnapshot(ctx, userID, day); err != nil {
+		t.Fatalf("RecordDifficultySnapshot: %v", err)
+	}
+	if err := repo.RecordDifficultySnapshot(ctx, userID, day.Add(-time.Hour)); err != nil {
+		t.Fatalf("RecordDifficultySnapshot: %v", err)
+	}
+
+	snapshots, err = repo.FindDifficultySnapshots(ctx, userID, day.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("FindDifficultySnapshots: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("got %d snapshots, want 1", len(snapshots))
+	}
+	if snapshots[0].AvgDifficulty != 5 {
+		t.Errorf("snapshot difficulty = %v, want 5", snapshots[0].AvgDifficulty)
+	}
+	if want := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC); !snapshots[0].Date.Equal(want) {
+		t.Errorf("snapshot date = %v, want %v", snapshots[0].Date, want)
+	}
+}
+
+func TestFindDueWords_ReviewAhead(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	saveDueProgress(t, repo, userID, wordID, time.Now().UTC().Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		reviewAhead time.Duration
+		wantDue     int
+	}{
+		{"no window", 0, 0},
+		{"window too short", 30 * time.Minute, 0},
+		{"two hour window", 2 * time.Hour, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, err := repo.FindDueWords(ctx, userID, tt.reviewAhead, 10)
+			if err != nil {
+				t.Fatalf("FindDueWords: %v", err)
+			}
+			if len(due) != tt.wantDue {
+				t.Errorf("FindDueWords returned %d words, want %d", len(due), tt.wantDue)
+			}
+
+			stats, err := repo.GetUserStats(ctx, userID, tt.reviewAhead)
+			if err != nil {
+				t.Fatalf("GetUserStats: %v", err)
+			}
+			if stats.DueWords != tt.wantDue {
+				t.Errorf("DueWords = %d, want %d", stats.DueWords, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestGetUserStats_WeightedAccuracy(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+
+	for _, review := range []struct {
+		rating learning.Rating
+		score  float64
+	}{
+		{learning.Good, learning.ScoreCorrect},
+		{learning.Hard, learning.ScorePartial},
+		{learning.Again, learning.ScoreWrong},
+		{learning.Easy, learning.ScoreCorrect},
+	} {
+		history := learning.NewReviewHistory(userID, wordID, review.rating, 2*time.Second)
+		history.SetScore(review.score)
+		if err := repo.SaveReviewHistory(ctx, history); err != nil {
+			t.Fatalf("failed to save review: %v", err)
+		}
+	}
+	// A review recorded before partial credit existed has no score and is left out
+	if _, err := db.Exec(`UPDATE review_history SET score = NULL WHERE id = (SELECT MAX(id) FROM review_history)`); err != nil {
+		t.Fatalf("failed to clear score: %v", err)
+	}
+
+	stats, err := repo.GetUserStats(ctx, userID, 0)
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if want := 0.5; math.Abs(stats.WeightedAccuracy-want) > 1e-9 {
+		t.Errorf("WeightedAccuracy = %v, want %v", stats.WeightedAccuracy, want)
+	}
+	if stats.TotalReviews != 4 {
+		t.Errorf("TotalReviews = %d, want 4", stats.TotalReviews)
+	}
+}
+
+func TestGetUserStats_DueAndNewCounts(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	saveDueProgress(t, repo, userID, saveTestWord(t, db, "house", "huis", vocabulary.Category("basics")), time.Now().UTC().Add(-time.Hour))
+	saveDueProgress(t, repo, userID, saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics")), time.Now().UTC().Add(48*time.Hour))
+	saveTestWord(t, db, "cat", "kat", vocabulary.Category("animals"))
+	saveTestWord(t, db, "dog", "hond", vocabulary.Category("animals"))
+	archived := saveTestWord(t, db, "bird", "vogel", vocabulary.Category("animals"))
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	stats, err := repo.GetUserStats(ctx, userID, 0)
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if stats.DueWords != 1 {
+		t.Errorf("DueWords = %d, want 1", stats.DueWords)
+	}
+	if stats.NewWords != 2 {
+		t.Errorf("NewWords = %d, want the 2 unstudied, unarchived words", stats.NewWords)
+	}
+}
+
+func TestFindNewWords_SequentialOrder(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	first := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	studied := saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics"))
+	second := saveTestWord(t, db, "cat", "kat", vocabulary.Category("animals"))
+	archived := saveTestWord(t, db, "bird", "vogel", vocabulary.Category("animals"))
+	third := saveTestWord(t, db, "dog", "hond", vocabulary.Category("animals"))
+	saveDueProgress(t, repo, userID, studied, time.Now().UTC().Add(48*time.Hour))
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	selection := user.NewWordSelection{Order: user.NewWordOrderSequential}
+	for i := 0; i < 3; i++ {
+		words, err := repo.FindNewWords(ctx, userID, selection, 10)
+		if err != nil {
+			t.Fatalf("FindNewWords: %v", err)
+		}
+		want := []vocabulary.ID{first, second, third}
+		if len(words) != len(want) {
+			t.Fatalf("FindNewWords returned %d words, want %d", len(words), len(want))
+		}
+		for j, progress := range words {
+			if progress.WordID() != want[j] {
+				t.Errorf("word %d = %d, want %d", j, progress.WordID(), want[j])
+			}
+		}
+	}
+
+	limited, err := repo.FindNewWords(ctx, userID, selection, 1)
+	if err != nil {
+		t.Fatalf("FindNewWords: %v", err)
+	}
+	if len(limited) != 1 || limited[0].WordID() != first {
+		t.Errorf("FindNewWords with limit 1 should return the lowest ID %d", first)
+	}
+}
+
+func TestGetStreak(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, amsterdam)
+	// at is a review time the given number of days before now's day, at hour:minute in Amsterdam
+	at := func(daysAgo, hour, minute int) time.Time {
+		return time.Date(2024, 3, 20-daysAgo, hour, minute, 0, 0, amsterdam)
+	}
+
+	tests := []struct {
+		name        string
+		reviews     []time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{"no reviews", nil, 0, 0},
+		{"several reviews on one day count once", []time.Time{at(0, 8, 0), at(0, 9, 0), at(0, 11, 30)}, 1, 1},
+		{"consecutive days", []time.Time{at(0, 8, 0), at(1, 20, 0), at(2, 7, 0)}, 3, 3},
+		{"a streak ending yesterday still counts", []time.Time{at(1, 8, 0), at(2, 8, 0)}, 2, 2},
+		{"a missed day breaks the current streak", []time.Time{at(2, 8, 0), at(3, 8, 0)}, 0, 2},
+		{"the longest run can lie in the past", []time.Time{
+			at(0, 8, 0), at(1, 8, 0),
+			at(4, 8, 0), at(5, 8, 0), at(6, 8, 0), at(7, 8, 0),
+		}, 2, 4},
+		// 23:30 and 00:30 in Amsterdam fall on the same UTC day, but on two local days
+		{"days follow the local time zone", []time.Time{at(1, 23, 30), at(0, 0, 30)}, 2, 2},
+		{"just before and after local midnight", []time.Time{at(1, 0, 0), at(1, 23, 59)}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			repo := NewLearningRepository(db)
+			userID := saveTestUser(t, db)
+			wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+			for _, reviewTime := range tt.reviews {
+				saveReview(t, repo, userID, wordID, learning.Good, learning.StateReview, reviewTime)
+			}
+
+			current, longest, err := repo.GetStreak(context.Background(), userID, now)
+			if err != nil {
+				t.Fatalf("GetStreak: %v", err)
+			}
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("streak = %d (best %d), want %d (best %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestSaveProgress_UpsertsExistingPair(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+
+	first := learning.NewUserProgress(userID, wordID)
+	first.FSRSCard().SetStability(2)
+	if err := repo.SaveProgress(ctx, first); err != nil {
+		t.Fatalf("first SaveProgress: %v", err)
+	}
+
+	// A second new progress record for the same word, as a concurrent session would create
+	dueDate := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	second := learning.NewUserProgress(userID, wordID)
+	second.FSRSCard().SetState(learning.StateReview)
+	second.FSRSCard().SetStability(9)
+	second.FSRSCard().SetReviewCount(1)
+	second.FSRSCard().SetDueDate(dueDate)
+	if err := repo.SaveProgress(ctx, second); err != nil {
+		t.Fatalf("second SaveProgress: %v", err)
+	}
+	if second.ID() != first.ID() {
+		t.Errorf("second save got ID %d, want the existing record's %d", second.ID(), first.ID())
+	}
+
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM user_progress WHERE user_id = ? AND word_id = ?`, int64(userID), int64(wordID)).Scan(&rows); err != nil {
+		t.Fatalf("failed to count progress: %v", err)
+	}
+	if rows != 1 {
+		t.Errorf("%d progress rows for the pair, want 1", rows)
+	}
+
+	stored, err := repo.FindProgress(ctx, userID, wordID)
+	if err != nil {
+		t.Fatalf("FindProgress: %v", err)
+	}
+	card := stored.FSRSCard()
+	if card.Stability() != 9 || card.State() != learning.StateReview || card.ReviewCount() != 1 || !card.DueDate().Equal(dueDate) {
+		t.Errorf("stored card = stability %v, state %q, %d reviews, due %v; want the second save's 9, review, 1, %v",
+			card.Stability(), card.State(), card.ReviewCount(), card.DueDate(), dueDate)
+	}
+}
+
+func TestGetWeeklyLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userRepo := NewUserRepository(db)
+	prefsRepo := NewUserPreferencesRepository(db)
+	wordID := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	now := time.Now()
+
+	users := []struct {
+		name    string
+		recent  int // reviews inside the window
+		old     int // reviews before the window
+		visible bool
+	}{
+		{"Anna", 3, 0, true},
+		{"Bram", 5, 0, true},
+		{"Cas", 3, 4, true},
+		{"Dirk", 6, 0, false},
+		{"Eva", 0, 9, true},
+	}
+	ids := make(map[string]user.ID)
+	for i, tu := range users {
+		u := user.NewUser(user.TelegramID(100+i), "", tu.name, "", "en")
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+		ids[tu.name] = u.ID()
+		for j := 0; j < tu.recent; j++ {
+			saveReview(t, repo, u.ID(), wordID, learning.Good, learning.StateReview, now.Add(-time.Duration(j+1)*time.Hour))
+		}
+		for j := 0; j < tu.old; j++ {
+			saveReview(t, repo, u.ID(), wordID, learning.Good, learning.StateReview, now.Add(-learning.LeaderboardWindow-time.Duration(j+1)*time.Hour))
+		}
+		if !tu.visible {
+			prefs := user.NewUserPreferences(u.ID())
+			prefs.SetLeaderboardVisible(false)
+			if err := prefsRepo.SavePreferences(ctx, prefs); err != nil {
+				t.Fatalf("failed to save preferences: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		// Anna and Cas tie on 3 reviews; the earlier user ranks first
+		{"active visible users", 10, []string{"Bram 5", "Anna 3", "Cas 3"}},
+		{"limited", 2, []string{"Bram 5", "Anna 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.GetWeeklyLeaderboard(ctx, tt.limit)
+			if err != nil {
+				t.Fatalf("GetWeeklyLeaderboard: %v", err)
+			}
+			var got []string
+			for _, entry := range entries {
+				if entry.UserID != ids[entry.FirstName] {
+					t.Errorf("%s has user ID %d, want %d", entry.FirstName, entry.UserID, ids[entry.FirstName])
+				}
+				got = append(got, fmt.Sprintf("%s %d", entry.FirstName, entry.Reviews))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("leaderboard = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUserStatsByCategory(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	other := user.NewUser(43, "bram", "Bram", "", "en")
+	if err := NewUserRepository(db).Save(ctx, other); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	now := time.Now()
+
+	// saveCard stores a review card with the given difficulty, due at dueDate
+	saveCard := func(userID user.ID, wordID vocabulary.ID, difficulty float64, dueDate time.Time) {
+		t.Helper()
+		saveDueProgress(t, repo, userID, wordID, dueDate)
+		if _, err := db.Exec(`UPDATE user_progress SET difficulty = ? WHERE user_id = ? AND word_id = ?`,
+			difficulty, int64(userID), int64(wordID)); err != nil {
+			t.Fatalf("failed to set difficulty: %v", err)
+		}
+	}
+
+	house := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	tree := saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics"))
+	bread := saveTestWord(t, db, "bread", "brood", vocabulary.Category("food"))
+	saveTestWord(t, db, "train", "trein", vocabulary.Category("travel")) // Never studied
+	old := saveTestWord(t, db, "wireless", "draadloos", vocabulary.Category("dated"))
+	saveCard(userID, house, 4, now.Add(-time.Hour))
+	saveCard(userID, tree, 6, now.Add(72*time.Hour))
+	saveCard(userID, bread, 3, now.Add(time.Hour))
+	saveCard(userID, old, 5, now.Add(-time.Hour))
+	saveCard(other.ID(), bread, 9, now.Add(-time.Hour))
+	if err := NewVocabularyRepository(db).ArchiveWord(ctx, old); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		reviewAhead time.Duration
+		want        map[vocabulary.Category]learning.CategoryStats
+	}{
+		{"due now", 0, map[vocabulary.Category]learning.CategoryStats{
+			"basics": {Studied: 2, Due: 1, AvgDifficulty: 5},
+			"food":   {Studied: 1, Due: 0, AvgDifficulty: 3},
+		}},
+		{"reviewing ahead", 2 * time.Hour, map[vocabulary.Category]learning.CategoryStats{
+			"basics": {Studied: 2, Due: 1, AvgDifficulty: 5},
+			"food":   {Studied: 1, Due: 1, AvgDifficulty: 3},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetUserStatsByCategory(ctx, userID, tt.reviewAhead)
+			if err != nil {
+				t.Fatalf("GetUserStatsByCategory: %v", err)
+			}
+			if len(stats) != len(tt.want) {
+				t.Errorf("stats for %d categories, want %d: %v", len(stats), len(tt.want), stats)
+			}
+			for category, want := range tt.want {
+				got, ok := stats[category]
+				if !ok {
+					t.Errorf("no stats for %s", category)
+					continue
+				}
+				if got.Studied != want.Studied || got.Due != want.Due || math.Abs(got.AvgDifficulty-want.AvgDifficulty) > 1e-9 {
+					t.Errorf("%s stats = %+v, want %+v", category, *got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCountsSinceDayStartInUserTimeZone(t *testing.T) {
+	ctx := context.Background()
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 14, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		dayStart time.Time
+		times    []time.Time // when words were introduced and reviewed, stored in UTC
+		want     int
+	}{
+		// 00:00 in Amsterdam is 22:00 UTC the day before
+		{"east of UTC", time.Date(2026, 10, 15, 0, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			[]time.Time{at(21, 30), at(22, 30), at(23, 59)}, 2},
+		// 00:00 in New York is 04:00 UTC the same day
+		{"west of UTC", time.Date(2026, 10, 14, 0, 0, 0, 0, time.FixedZone("EDT", -4*60*60)),
+			[]time.Time{at(2, 0), at(3, 59), at(4, 0), at(12, 0)}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			repo := NewLearningRepository(db)
+			userID := saveTestUser(t, db)
+			for i, when := range tt.times {
+				wordID := saveTestWord(t, db, "word", string(rune('a'+i)), vocabulary.Category("basics"))
+				if err := repo.SaveProgress(ctx, learning.NewUserProgress(userID, wordID)); err != nil {
+					t.Fatalf("failed to save progress: %v", err)
+				}
+				if _, err := db.Exec(`UPDATE user_progress SET created_at = ? WHERE user_id = ? AND word_id = ?`,
+					when, int64(userID), int64(wordID)); err != nil {
+					t.Fatalf("failed to set created_at: %v", err)
+				}
+				saveReview(t, repo, userID, wordID, learning.Good, learning.StateNew, when)
+			}
+
+			introduced, err := repo.CountIntroducedWords(ctx, userID, tt.dayStart)
+			if err != nil {
+				t.Fatalf("CountIntroducedWords: %v", err)
+			}
+			if introduced != tt.want {
+				t.Errorf("%d words introduced since %v, want %d", introduced, tt.dayStart, tt.want)
+			}
+
+			reviewed, err := repo.CountReviewedWordsByCategory(ctx, userID, tt.dayStart)
+			if err != nil {
+				t.Fatalf("CountReviewedWordsByCategory: %v", err)
+			}
+			if got := reviewed[vocabulary.Category("basics")]; got != tt.want {
+				t.Errorf("%d words reviewed since %v, want %d", got, tt.dayStart, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MaintenanceConfig holds configuration for the periodic database maintenance job
+type MaintenanceConfig struct {
+	// How often to run maintenance (0 disables the job)
+	Interval time.Duration
+	// VACUUM only runs when at least this fraction of the file is free pages,
+	// since it rewrites the whole database and blocks writers while it runs
+	VacuumFreeRatio float64
+	// VACUUM is skipped for databases smaller than this, where reclaiming space isn't worth the lock
+	VacuumMinBytes int64
+}
+
+// DefaultMaintenanceConfig returns sensible defaults for database maintenance
+func DefaultMaintenanceConfig() *MaintenanceConfig {
+	return &MaintenanceConfig{
+		Interval:        0, // Off by default
+		VacuumFreeRatio: 0.25,
+		VacuumMinBytes:  10 * 1024 * 1024,
+	}
+}
+
+// StartMaintenance runs database maintenance on the configured interval until the context is cancelled
+func StartMaintenance(ctx context.Context, db *sql.DB, config *MaintenanceConfig) {
+	if config == nil || config.Interval <= 0 {
+		return
+	}
+
+	log.Printf("Starting database maintenance (interval: %v)", config.Interval)
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Database maintenance stopping...")
+			return
+		case <-ticker.C:
+			if err := RunMaintenance(ctx, db, config); err != nil {
+				log.Printf("Database maintenance failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunMaintenance refreshes query planner statistics and vacuums the database if it is fragmented enough
+func RunMaintenance(ctx context.Context, db *sql.DB, config *MaintenanceConfig) error {
+	if _, err := db.ExecContext(ctx, "ANALYZE"); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+
+	shouldVacuum, err := needsVacuum(ctx, db, config)
+	if err != nil {
+		return err
+	}
+	if !shouldVacuum {
+		return nil
+	}
+
+	log.Printf("Vacuuming fragmented database...")
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+
+	return nil
+}
+
+// needsVacuum checks whether the database is large and fragmented enough to be worth vacuuming
+func needsVacuum(ctx context.Context, db *sql.DB, config *MaintenanceConfig) (bool, error) {
+	var pageCount, freePages, pageSize int64
+
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return false, fmt.Errorf("failed to get page count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return false, fmt.Errorf("failed to get free page count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return false, fmt.Errorf("failed to get page size: %w", err)
+	}
+
+	if pageCount == 0 || pageCount*pageSize < config.VacuumMinBytes {
+		return false, nil
+	}
+
+	return float64(freePages)/float64(pageCount) >= config.VacuumFreeRatio, nil
+}
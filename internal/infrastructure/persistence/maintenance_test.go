@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestRunMaintenance(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+	for i := 0; i < 200; i++ {
+		wordID := saveTestWord(t, db, fmt.Sprintf("word %d", i), fmt.Sprintf("woord %d", i), vocabulary.Category("basics"))
+		saveDueProgress(t, repo, userID, wordID, time.Now().UTC())
+	}
+	// Leave free pages behind so the vacuum check has something to find
+	if _, err := db.Exec(`DELETE FROM user_progress`); err != nil {
+		t.Fatalf("failed to delete progress: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		config     *MaintenanceConfig
+		wantVacuum bool
+	}{
+		{"default config skips small databases", DefaultMaintenanceConfig(), false},
+		{"fragmented database is vacuumed", &MaintenanceConfig{VacuumFreeRatio: 0.01}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shouldVacuum, err := needsVacuum(ctx, db, tt.config)
+			if err != nil {
+				t.Fatalf("needsVacuum: %v", err)
+			}
+			if shouldVacuum != tt.wantVacuum {
+				t.Errorf("needsVacuum = %v, want %v", shouldVacuum, tt.wantVacuum)
+			}
+
+			if err := RunMaintenance(ctx, db, tt.config); err != nil {
+				t.Fatalf("RunMaintenance: %v", err)
+			}
+		})
+	}
+
+	// The vacuum reclaimed the free pages
+	var freePages int64
+	if err := db.QueryRow("PRAGMA freelist_count").Scan(&freePages); err != nil {
+		t.Fatalf("failed to get free page count: %v", err)
+	}
+	if freePages != 0 {
+		t.Errorf("%d free pages left after vacuuming, want 0", freePages)
+	}
+}
@@ -0,0 +1,44 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Metrics holds bot-wide counts for monitoring
+type Metrics struct {
+	TotalUsers   int
+	ReviewsToday int
+	DueWords     int // Studied words due for review, across all users
+	UsersWithDue int // Users with at least one due word
+}
+
+// CollectMetrics counts users, today's reviews and due words across all users
+func CollectMetrics(ctx context.Context, db *sql.DB) (*Metrics, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	metrics := &Metrics{}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&metrics.TotalUsers); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `SELECT COUNT(*) FROM review_history WHERE review_time >= ?`
+	if err := db.QueryRowContext(ctx, query, startOfDay).Scan(&metrics.ReviewsToday); err != nil {
+		return nil, fmt.Errorf("failed to count today's reviews: %w", err)
+	}
+
+	query = `
+		SELECT COUNT(*), COUNT(DISTINCT up.user_id)
+		FROM user_progress up
+		JOIN words w ON w.id = up.word_id
+		WHERE w.archived = 0 AND up.due_date <= DATETIME('now')
+	`
+	if err := db.QueryRowContext(ctx, query).Scan(&metrics.DueWords, &metrics.UsersWithDue); err != nil {
+		return nil, fmt.Errorf("failed to count due words: %w", err)
+	}
+
+	return metrics, nil
+}
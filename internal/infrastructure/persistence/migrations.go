@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migration is one versioned step of the database schema. Migrations run in order, each in its
+// own transaction, and are recorded in schema_migrations so they never run twice.
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations lists every schema change in the order it is applied. Append new steps with the
+// next version; never edit or reorder a step that has shipped.
+var migrations = []migration{
+	{1, "initial schema", createInitialSchema},
+	{2, "grammar tip matching and media columns", addGrammarTipColumns},
+	{3, "learning step columns", addLearningStepColumns},
+}
+
+// runMigrations applies the migrations the database hasn't had yet
+func runMigrations(db *sql.DB) error {
+	_, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.description, err)
+		}
+	}
+
+	return nil
+}
+
+// schemaVersion returns the version of the newest applied migration, or 0 for a fresh database
+func schemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// applyMigration runs a migration and records it in one transaction
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version, description) VALUES (?, ?)`, m.version, m.description)
+	if err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// addGrammarTipColumns is migration 2. Grammar tips used to be dropped and recreated on every
+// start to pick up these columns; adding them in place keeps the table instead.
+func addGrammarTipColumns(tx *sql.Tx) error {
+	columns := []struct{ name, definition string }{
+		{"applicable_categories", "TEXT DEFAULT '[]'"},
+		{"word_patterns", "TEXT DEFAULT '[]'"},
+		{"specific_words", "TEXT DEFAULT '[]'"},
+		{"image_url", "TEXT DEFAULT ''"},
+		{"audio_url", "TEXT DEFAULT ''"},
+	}
+	for _, column := range columns {
+		if err := addColumnIfMissing(tx, "grammar_tips", column.name, column.definition); err != nil {
+			return fmt.Errorf("failed to add %s column to grammar_tips table: %w", column.name, err)
+		}
+	}
+
+	// Tables from before titles were unique get the constraint SaveBatch relies on
+	_, err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_grammar_tips_title ON grammar_tips(title);")
+	if err != nil {
+		return fmt.Errorf("failed to create grammar tip title index: %w", err)
+	}
+
+	return nil
+}
+
+// addLearningStepColumns is migration 3. It records which learning step a card is on, and the
+// step before each review so undo can restore it. Cards already in learning start on the first step.
+func addLearningStepColumns(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "user_progress", "learning_step", "INTEGER DEFAULT 0"); err != nil {
+		return fmt.Errorf("failed to add learning_step column to user_progress table: %w", err)
+	}
+	if err := addColumnIfMissing(tx, "review_history", "prior_learning_step", "INTEGER"); err != nil {
+		return fmt.Errorf("failed to add prior_learning_step column to review_history table: %w", err)
+	}
+	return nil
+}
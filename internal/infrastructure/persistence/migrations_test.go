@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// migrationCount returns the schema version and how many migrations are recorded
+func migrationCount(t *testing.T, db *sql.DB) (version, applied int) {
+	t.Helper()
+
+	version, err := schemaVersion(db)
+	if err != nil {
+		t.Fatalf("schemaVersion: %v", err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatalf("failed to count migrations: %v", err)
+	}
+	return version, applied
+}
+
+func TestRunMigrations_Idempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	latest := migrations[len(migrations)-1].version
+
+	db, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB: %v", err)
+	}
+	userID := saveTestUser(t, db)
+	if version, applied := migrationCount(t, db); version != latest || applied != len(migrations) {
+		t.Fatalf("fresh database at version %d with %d migrations, want %d and %d", version, applied, latest, len(migrations))
+	}
+
+	// Running again on the open database changes nothing
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("second runMigrations: %v", err)
+	}
+	db.Close()
+
+	// Nor does reopening it, as every restart of the bot does
+	db, err = NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("reopening the database: %v", err)
+	}
+	defer db.Close()
+	if version, applied := migrationCount(t, db); version != latest || applied != len(migrations) {
+		t.Errorf("reopened database at version %d with %d migrations, want %d and %d", version, applied, latest, len(migrations))
+	}
+	if u, err := NewUserRepository(db).FindByID(context.Background(), userID); err != nil || u == nil {
+		t.Errorf("user saved before reopening is gone: %v", err)
+	}
+}
+
+func TestRunMigrations_UpgradesOlderSchema(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// A database that only has the initial schema, with a word in it
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, description TEXT NOT NULL, applied_at DATETIME DEFAULT CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("failed to create schema_migrations: %v", err)
+	}
+	if err := applyMigration(db, migrations[0]); err != nil {
+		t.Fatalf("failed to apply the initial schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO words (english, dutch, category) VALUES ('house', 'huis', 'basics')`); err != nil {
+		t.Fatalf("failed to save word: %v", err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	latest := migrations[len(migrations)-1].version
+	if version, applied := migrationCount(t, db); version != latest || applied != len(migrations) {
+		t.Errorf("upgraded database at version %d with %d migrations, want %d and %d", version, applied, latest, len(migrations))
+	}
+	var words int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM words WHERE dutch = 'huis'`).Scan(&words); err != nil {
+		t.Fatalf("failed to count words: %v", err)
+	}
+	if words != 1 {
+		t.Errorf("%d words after the upgrade, want the 1 saved before", words)
+	}
+}
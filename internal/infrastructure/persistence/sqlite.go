@@ -3,19 +3,32 @@ package persistence
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// sqliteConnParams configure every connection: WAL lets readers carry on while a write is in
+// progress, the busy timeout makes a writer wait up to 5s for the lock instead of failing with
+// "database is locked", and immediate transactions take that lock up front so a transaction
+// that reads before writing can't be refused the lock halfway through
+const sqliteConnParams = "_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
 // NewSQLiteDB creates a new SQLite database connection
 func NewSQLiteDB(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+
+	db, err := sql.Open("sqlite3", dbPath+separator+sqliteConnParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Configure connection pool
+	// Configure connection pool. SQLite still allows a single writer at a time; the pool is kept
+	// large so reads run in parallel under WAL, while concurrent writes queue on the busy timeout.
 	db.SetMaxOpenConns(25)                 // Maximum number of open connections
 	db.SetMaxIdleConns(5)                  // Maximum number of idle connections
 	db.SetConnMaxLifetime(5 * time.Minute) // Maximum lifetime of a connection
@@ -26,14 +39,16 @@ func NewSQLiteDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	if err := createTables(db); err != nil {
-		return nil, fmt.Errorf("failed to create tables: %w", err)
+	if err := runMigrations(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	return db, nil
 }
 
-func createTables(db *sql.DB) error {
+// createInitialSchema is migration 1. Every statement tolerates existing tables and columns, so
+// databases created before migrations were tracked are brought up to date without losing data.
+func createInitialSchema(tx *sql.Tx) error {
 	// Users table
 	usersTable := `
 	CREATE TABLE IF NOT EXISTS users (
@@ -47,7 +62,7 @@ func createTables(db *sql.DB) error {
 		last_active DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`
 
-	_, err := db.Exec(usersTable)
+	_, err := tx.Exec(usersTable)
 	if err != nil {
 		return fmt.Errorf("failed to create users table: %w", err)
 	}
@@ -64,7 +79,7 @@ func createTables(db *sql.DB) error {
 		UNIQUE(user_id, preference_key)
 	);`
 
-	_, err = db.Exec(userPreferencesTable)
+	_, err = tx.Exec(userPreferencesTable)
 	if err != nil {
 		return fmt.Errorf("failed to create user_preferences table: %w", err)
 	}
@@ -76,14 +91,33 @@ func createTables(db *sql.DB) error {
 		english TEXT NOT NULL,
 		dutch TEXT NOT NULL,
 		category TEXT NOT NULL,
+		archived INTEGER DEFAULT 0,
 		UNIQUE(english, dutch)
 	);`
 
-	_, err = db.Exec(wordsTable)
+	_, err = tx.Exec(wordsTable)
 	if err != nil {
 		return fmt.Errorf("failed to create words table: %w", err)
 	}
 
+	// Databases created before words could be archived lack the column
+	err = addColumnIfMissing(tx, "words", "archived", "INTEGER DEFAULT 0")
+	if err != nil {
+		return fmt.Errorf("failed to add archived column to words table: %w", err)
+	}
+
+	// Part of speech is optional vocabulary metadata added later
+	err = addColumnIfMissing(tx, "words", "pos", "TEXT")
+	if err != nil {
+		return fmt.Errorf("failed to add pos column to words table: %w", err)
+	}
+
+	// Sense disambiguates English words with several meanings, such as "right"
+	err = addColumnIfMissing(tx, "words", "sense", "TEXT")
+	if err != nil {
+		return fmt.Errorf("failed to add sense column to words table: %w", err)
+	}
+
 	// User progress table with FSRS parameters
 	userProgressTable := `
 	CREATE TABLE IF NOT EXISTS user_progress (
@@ -104,7 +138,7 @@ func createTables(db *sql.DB) error {
 		UNIQUE(user_id, word_id)
 	);`
 
-	_, err = db.Exec(userProgressTable)
+	_, err = tx.Exec(userProgressTable)
 	if err != nil {
 		return fmt.Errorf("failed to create user_progress table: %w", err)
 	}
@@ -122,33 +156,173 @@ func createTables(db *sql.DB) error {
 		FOREIGN KEY (word_id) REFERENCES words (id)
 	);`
 
-	_, err = db.Exec(reviewHistoryTable)
+	_, err = tx.Exec(reviewHistoryTable)
 	if err != nil {
 		return fmt.Errorf("failed to create review_history table: %w", err)
 	}
 
-	// Drop and recreate grammar tips table with correct schema
-	_, err = db.Exec("DROP TABLE IF EXISTS grammar_tips")
+	// Databases created before partial credit lack the score column; old reviews keep NULL scores
+	err = addColumnIfMissing(tx, "review_history", "score", "REAL")
+	if err != nil {
+		return fmt.Errorf("failed to add score column to review_history table: %w", err)
+	}
+
+	// The card as it was before each review, so the last review can be undone.
+	// Reviews saved before these columns existed keep NULLs and can't be undone.
+	priorCardColumns := []struct{ name, definition string }{
+		{"prior_stability", "REAL"},
+		{"prior_difficulty", "REAL"},
+		{"prior_last_review", "DATETIME"},
+		{"prior_due_date", "DATETIME"},
+		{"prior_review_count", "INTEGER"},
+		{"prior_lapses", "INTEGER"},
+		{"prior_state", "TEXT"},
+	}
+	for _, column := range priorCardColumns {
+		err = addColumnIfMissing(tx, "review_history", column.name, column.definition)
+		if err != nil {
+			return fmt.Errorf("failed to add %s column to review_history table: %w", column.name, err)
+		}
+	}
+
+	// Low-priority words table (words the user doesn't want reminders about)
+	lowPriorityWordsTable := `
+	CREATE TABLE IF NOT EXISTS low_priority_words (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (word_id) REFERENCES words (id),
+		UNIQUE(user_id, word_id)
+	);`
+
+	_, err = tx.Exec(lowPriorityWordsTable)
+	if err != nil {
+		return fmt.Errorf("failed to create low_priority_words table: %w", err)
+	}
+
+	// Daily snapshots of average difficulty, used to compute difficulty trends
+	difficultySnapshotsTable := `
+	CREATE TABLE IF NOT EXISTS difficulty_snapshots (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		snapshot_date TEXT NOT NULL,
+		avg_difficulty REAL NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		UNIQUE(user_id, snapshot_date)
+	);`
+
+	_, err = tx.Exec(difficultySnapshotsTable)
+	if err != nil {
+		return fmt.Errorf("failed to create difficulty_snapshots table: %w", err)
+	}
+
+	// Word reports table (users flagging a word's translation as wrong)
+	wordReportsTable := `
+	CREATE TABLE IF NOT EXISTS word_reports (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (word_id) REFERENCES words (id),
+		UNIQUE(user_id, word_id)
+	);`
+
+	_, err = tx.Exec(wordReportsTable)
+	if err != nil {
+		return fmt.Errorf("failed to create word_reports table: %w", err)
+	}
+
+	// Word tags table (free-form, user-specific tags such as "exam" or "travel")
+	wordTagsTable := `
+	CREATE TABLE IF NOT EXISTS word_tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (word_id) REFERENCES words (id),
+		UNIQUE(user_id, word_id, tag)
+	);`
+
+	_, err = tx.Exec(wordTagsTable)
+	if err != nil {
+		return fmt.Errorf("failed to create word_tags table: %w", err)
+	}
+
+	// Sessions log table (one row per finished learning session, for long-term analytics)
+	sessionsLogTable := `
+	CREATE TABLE IF NOT EXISTS sessions_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		started_at DATETIME NOT NULL,
+		ended_at DATETIME NOT NULL,
+		words INTEGER NOT NULL,
+		correct INTEGER NOT NULL,
+		incorrect INTEGER NOT NULL,
+		accuracy REAL NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		new_words INTEGER NOT NULL,
+		review_words INTEGER NOT NULL,
+		practice BOOLEAN NOT NULL DEFAULT 0,
+		FOREIGN KEY (user_id) REFERENCES users (id)
+	);`
+
+	_, err = tx.Exec(sessionsLogTable)
+	if err != nil {
+		return fmt.Errorf("failed to create sessions_log table: %w", err)
+	}
+
+	// Study partner links (each user has at most one partner)
+	linkedUsersTable := `
+	CREATE TABLE IF NOT EXISTS linked_users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL UNIQUE,
+		partner_id INTEGER NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (partner_id) REFERENCES users (id)
+	);`
+
+	_, err = tx.Exec(linkedUsersTable)
+	if err != nil {
+		return fmt.Errorf("failed to create linked_users table: %w", err)
+	}
+
+	// Words in a study partnership's shared deck
+	sharedDeckWordsTable := `
+	CREATE TABLE IF NOT EXISTS shared_deck_words (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		link_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (link_id) REFERENCES linked_users (id),
+		FOREIGN KEY (word_id) REFERENCES words (id),
+		UNIQUE(link_id, word_id)
+	);`
+
+	_, err = tx.Exec(sharedDeckWordsTable)
 	if err != nil {
-		return fmt.Errorf("failed to drop grammar_tips table: %w", err)
+		return fmt.Errorf("failed to create shared_deck_words table: %w", err)
 	}
 
+	// Grammar tips table; the matching and media columns are added by migration 2
 	grammarTipsTable := `
-	CREATE TABLE grammar_tips (
+	CREATE TABLE IF NOT EXISTS grammar_tips (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		title TEXT NOT NULL,
 		explanation TEXT NOT NULL,
 		dutch_example TEXT,
 		english_example TEXT,
 		category TEXT NOT NULL,
-		applicable_categories TEXT DEFAULT '[]',
-		word_patterns TEXT DEFAULT '[]',
-		specific_words TEXT DEFAULT '[]',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		UNIQUE(title)
 	);`
 
-	_, err = db.Exec(grammarTipsTable)
+	_, err = tx.Exec(grammarTipsTable)
 	if err != nil {
 		return fmt.Errorf("failed to create grammar_tips table: %w", err)
 	}
@@ -173,12 +347,14 @@ func createTables(db *sql.DB) error {
 		// Add composite indexes for common query patterns
 		"CREATE INDEX IF NOT EXISTS idx_user_progress_user_word ON user_progress(user_id, word_id);",
 		"CREATE INDEX IF NOT EXISTS idx_review_history_user_time ON review_history(user_id, review_time);",
+		"CREATE INDEX IF NOT EXISTS idx_word_tags_user_tag ON word_tags(user_id, tag);",
+		"CREATE INDEX IF NOT EXISTS idx_sessions_log_user_started ON sessions_log(user_id, started_at);",
 		"CREATE INDEX IF NOT EXISTS idx_user_progress_user_state ON user_progress(user_id, state);",
 		"CREATE INDEX IF NOT EXISTS idx_user_progress_due_state ON user_progress(due_date, state);",
 	}
 
 	for _, idx := range indexes {
-		_, err = db.Exec(idx)
+		_, err = tx.Exec(idx)
 		if err != nil {
 			return fmt.Errorf("failed to create index: %w", err)
 		}
@@ -186,3 +362,43 @@ func createTables(db *sql.DB) error {
 
 	return nil
 }
+
+// addColumnIfMissing adds a column to an existing table if it doesn't have it yet
+func addColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	exists, err := columnExists(tx, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// columnExists reports whether a table has a column, closing its rows before the caller alters the table
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to read table info: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to iterate table info: %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestNewSQLiteDB_ConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewLearningRepository(db)
+	userID := saveTestUser(t, db)
+
+	var journalMode string
+	if err := db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode); err != nil {
+		t.Fatalf("failed to read journal mode: %v", err)
+	}
+	if journalMode != "wal" {
+		t.Errorf("journal mode = %q, want wal", journalMode)
+	}
+
+	const writers = 20
+	const reviewsEach = 10
+	wordIDs := make([]vocabulary.ID, writers)
+	for i := range wordIDs {
+		wordIDs[i] = saveTestWord(t, db, "word", string(rune('a'+i)), vocabulary.Category("basics"))
+	}
+
+	// Each writer reviews its own word over and over, as the goroutine-per-update handler would
+	errs := make(chan error, writers*reviewsEach)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, wordID := range wordIDs {
+		wordID := wordID
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			progress := learning.NewUserProgress(userID, wordID)
+			for i := 0; i < reviewsEach; i++ {
+				progress.Review(learning.Good)
+				history := learning.NewReviewHistory(userID, wordID, learning.Good, time.Second)
+				if err := repo.SaveProgressAndHistory(ctx, progress, history); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+	var reviews int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM review_history WHERE user_id = ?`, int64(userID)).Scan(&reviews); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	if reviews != writers*reviewsEach {
+		t.Errorf("%d reviews saved, want %d", reviews, writers*reviewsEach)
+	}
+}
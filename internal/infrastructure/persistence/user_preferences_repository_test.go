@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+func TestReminderIntervalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewUserPreferencesRepository(db)
+	userID := saveTestUser(t, db)
+
+	prefs, err := repo.FindPreferences(ctx, userID)
+	if err != nil {
+		t.Fatalf("FindPreferences: %v", err)
+	}
+	if got := prefs.GetReminderInterval(); got != user.DefaultReminderInterval {
+		t.Errorf("interval before saving = %d, want default %d", got, user.DefaultReminderInterval)
+	}
+
+	prefs.SetReminderInterval(90)
+	if err := repo.SavePreferences(ctx, prefs); err != nil {
+		t.Fatalf("SavePreferences: %v", err)
+	}
+
+	reloaded, err := repo.FindPreferences(ctx, userID)
+	if err != nil {
+		t.Fatalf("FindPreferences: %v", err)
+	}
+	if got := reloaded.GetReminderInterval(); got != 90 {
+		t.Errorf("reloaded interval = %d, want 90", got)
+	}
+}
@@ -64,6 +64,7 @@ func (r *userRepository) FindByID(ctx context.Context, id user.ID) (*user.User,
 
 	u := user.NewUser(user.TelegramID(telegramID), username, firstName, lastName, languageCode)
 	u.SetID(id)
+	u.SetLastActive(lastActive)
 
 	return u, nil
 }
@@ -92,6 +93,7 @@ func (r *userRepository) FindByTelegramID(ctx context.Context, telegramID user.T
 
 	u := user.NewUser(user.TelegramID(tgID), username, firstName, lastName, languageCode)
 	u.SetID(id)
+	u.SetLastActive(lastActive)
 
 	return u, nil
 }
@@ -156,6 +158,7 @@ func (r *userRepository) GetAllUsers(ctx context.Context) ([]*user.User, error)
 
 		u := user.NewUser(user.TelegramID(telegramID), username, firstName, lastName, languageCode)
 		u.SetID(id)
+		u.SetLastActive(lastActive)
 		users = append(users, u)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"dutch-learning-bot/internal/domain/vocabulary"
 )
@@ -20,11 +21,11 @@ func NewVocabularyRepository(db *sql.DB) vocabulary.Repository {
 // Save persists a word to storage
 func (r *vocabularyRepository) Save(ctx context.Context, word *vocabulary.Word) error {
 	query := `
-		INSERT OR IGNORE INTO words (english, dutch, category)
-		VALUES (?, ?, ?)
+		INSERT OR IGNORE INTO words (english, dutch, category, pos, sense)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))
 	`
 
-	result, err := r.db.ExecContext(ctx, query, word.English(), word.Dutch(), string(word.Category()))
+	result, err := r.db.ExecContext(ctx, query, word.English(), word.Dutch(), string(word.Category()), string(word.PartOfSpeech()), word.Sense())
 	if err != nil {
 		return fmt.Errorf("failed to save word: %w", err)
 	}
@@ -41,47 +42,74 @@ func (r *vocabularyRepository) Save(ctx context.Context, word *vocabulary.Word)
 	return nil
 }
 
-// SaveBatch persists multiple words to storage
-func (r *vocabularyRepository) SaveBatch(ctx context.Context, words []*vocabulary.Word) error {
+// SaveBatch persists multiple words to storage, reporting how many were new
+// and how many were skipped as already present. Running it again is safe.
+func (r *vocabularyRepository) SaveBatch(ctx context.Context, words []*vocabulary.Word) (int, int, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT OR IGNORE INTO words (english, dutch, category)
-		VALUES (?, ?, ?)
+	insertStmt, err := tx.PrepareContext(ctx, `
+		INSERT OR IGNORE INTO words (english, dutch, category, pos, sense)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer insertStmt.Close()
+
+	// Existing words pick up part-of-speech and sense changes from the vocabulary file
+	metadataStmt, err := tx.PrepareContext(ctx, `
+		UPDATE words SET pos = NULLIF(?1, ''), sense = NULLIF(?4, '')
+		WHERE english = ?2 AND dutch = ?3
+		  AND (pos IS NOT NULLIF(?1, '') OR sense IS NOT NULLIF(?4, ''))
 	`)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return 0, 0, fmt.Errorf("failed to prepare statement: %w", err)
 	}
-	defer stmt.Close()
+	defer metadataStmt.Close()
 
+	added, skipped := 0, 0
 	for _, word := range words {
-		_, err := stmt.ExecContext(ctx, word.English(), word.Dutch(), string(word.Category()))
+		result, err := insertStmt.ExecContext(ctx, word.English(), word.Dutch(), string(word.Category()), string(word.PartOfSpeech()), word.Sense())
 		if err != nil {
-			return fmt.Errorf("failed to save word %s: %w", word.English(), err)
+			return 0, 0, fmt.Errorf("failed to save word %s: %w", word.English(), err)
+		}
+
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to check saved word %s: %w", word.English(), err)
+		}
+		if inserted > 0 {
+			added++
+			continue
+		}
+
+		skipped++
+		if _, err := metadataStmt.ExecContext(ctx, string(word.PartOfSpeech()), word.English(), word.Dutch(), word.Sense()); err != nil {
+			return 0, 0, fmt.Errorf("failed to update word %s: %w", word.English(), err)
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return added, skipped, nil
 }
 
 // FindByID retrieves a word by its ID
 func (r *vocabularyRepository) FindByID(ctx context.Context, id vocabulary.ID) (*vocabulary.Word, error) {
 	query := `
-		SELECT id, english, dutch, category
+		SELECT id, english, dutch, category, COALESCE(pos, ''), COALESCE(sense, '')
 		FROM words WHERE id = ?
 	`
 
-	var english, dutch, category string
+	var english, dutch, category, pos, sense string
 
-	err := r.db.QueryRowContext(ctx, query, int64(id)).Scan(&id, &english, &dutch, &category)
+	err := r.db.QueryRowContext(ctx, query, int64(id)).Scan(&id, &english, &dutch, &category, &pos, &sense)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -91,15 +119,95 @@ func (r *vocabularyRepository) FindByID(ctx context.Context, id vocabulary.ID) (
 
 	word := vocabulary.NewWord(english, dutch, vocabulary.Category(category))
 	word.SetID(id)
+	word.SetPartOfSpeech(vocabulary.PartOfSpeech(pos))
+	word.SetSense(sense)
+
+	return word, nil
+}
+
+// FindByTerm retrieves an active word whose Dutch or English text matches the term, ignoring case
+func (r *vocabularyRepository) FindByTerm(ctx context.Context, term string) (*vocabulary.Word, error) {
+	query := `
+		SELECT id, english, dutch, category, COALESCE(pos, ''), COALESCE(sense, '')
+		FROM words
+		WHERE archived = 0 AND (LOWER(dutch) = LOWER(?1) OR LOWER(english) = LOWER(?1))
+		ORDER BY CASE WHEN LOWER(dutch) = LOWER(?1) THEN 0 ELSE 1 END, id
+		LIMIT 1
+	`
+
+	var id vocabulary.ID
+	var english, dutch, category, pos, sense string
+
+	err := r.db.QueryRowContext(ctx, query, term).Scan(&id, &english, &dutch, &category, &pos, &sense)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find word by term: %w", err)
+	}
+
+	word := vocabulary.NewWord(english, dutch, vocabulary.Category(category))
+	word.SetID(id)
+	word.SetPartOfSpeech(vocabulary.PartOfSpeech(pos))
+	word.SetSense(sense)
 
 	return word, nil
 }
 
+// Search retrieves up to limit active words whose Dutch or English text contains the query, ignoring case
+func (r *vocabularyRepository) Search(ctx context.Context, query string, limit int) ([]*vocabulary.Word, error) {
+	sqlQuery := `
+		SELECT id, english, dutch, category, COALESCE(pos, ''), COALESCE(sense, '')
+		FROM words
+		WHERE archived = 0 AND (LOWER(dutch) LIKE ?2 ESCAPE '\' OR LOWER(english) LIKE ?2 ESCAPE '\')
+		ORDER BY CASE
+			WHEN LOWER(dutch) = ?1 OR LOWER(english) = ?1 THEN 0
+			WHEN LOWER(dutch) LIKE ?3 ESCAPE '\' OR LOWER(english) LIKE ?3 ESCAPE '\' THEN 1
+			ELSE 2
+		END, dutch
+		LIMIT ?4
+	`
+
+	// Match the query literally, even when it contains LIKE wildcards
+	term := strings.ToLower(query)
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, term, "%"+escaped+"%", escaped+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search words: %w", err)
+	}
+	defer rows.Close()
+
+	var words []*vocabulary.Word
+
+	for rows.Next() {
+		var id vocabulary.ID
+		var english, dutch, category, pos, sense string
+
+		if err := rows.Scan(&id, &english, &dutch, &category, &pos, &sense); err != nil {
+			return nil, fmt.Errorf("failed to scan word: %w", err)
+		}
+
+		word := vocabulary.NewWord(english, dutch, vocabulary.Category(category))
+		word.SetID(id)
+		word.SetPartOfSpeech(vocabulary.PartOfSpeech(pos))
+		word.SetSense(sense)
+		words = append(words, word)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return words, nil
+}
+
 // FindAll retrieves all words
 func (r *vocabularyRepository) FindAll(ctx context.Context) ([]*vocabulary.Word, error) {
 	query := `
-		SELECT id, english, dutch, category
+		SELECT id, english, dutch, category, COALESCE(pos, ''), COALESCE(sense, '')
 		FROM words
+		WHERE archived = 0
 		ORDER BY category, english
 	`
 
@@ -113,14 +221,16 @@ func (r *vocabularyRepository) FindAll(ctx context.Context) ([]*vocabulary.Word,
 
 	for rows.Next() {
 		var id vocabulary.ID
-		var english, dutch, category string
+		var english, dutch, category, pos, sense string
 
-		if err := rows.Scan(&id, &english, &dutch, &category); err != nil {
+		if err := rows.Scan(&id, &english, &dutch, &category, &pos, &sense); err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
 		}
 
 		word := vocabulary.NewWord(english, dutch, vocabulary.Category(category))
 		word.SetID(id)
+		word.SetPartOfSpeech(vocabulary.PartOfSpeech(pos))
+		word.SetSense(sense)
 		words = append(words, word)
 	}
 
@@ -134,8 +244,8 @@ func (r *vocabularyRepository) FindAll(ctx context.Context) ([]*vocabulary.Word,
 // FindByCategory retrieves words by category
 func (r *vocabularyRepository) FindByCategory(ctx context.Context, category vocabulary.Category) ([]*vocabulary.Word, error) {
 	query := `
-		SELECT id, english, dutch, category
-		FROM words WHERE category = ?
+		SELECT id, english, dutch, category, COALESCE(pos, ''), COALESCE(sense, '')
+		FROM words WHERE category = ? AND archived = 0
 		ORDER BY english
 	`
 
@@ -149,14 +259,16 @@ func (r *vocabularyRepository) FindByCategory(ctx context.Context, category voca
 
 	for rows.Next() {
 		var id vocabulary.ID
-		var english, dutch, cat string
+		var english, dutch, cat, pos, sense string
 
-		if err := rows.Scan(&id, &english, &dutch, &cat); err != nil {
+		if err := rows.Scan(&id, &english, &dutch, &cat, &pos, &sense); err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
 		}
 
 		word := vocabulary.NewWord(english, dutch, vocabulary.Category(cat))
 		word.SetID(id)
+		word.SetPartOfSpeech(vocabulary.PartOfSpeech(pos))
+		word.SetSense(sense)
 		words = append(words, word)
 	}
 
@@ -182,3 +294,82 @@ func (r *vocabularyRepository) Exists(ctx context.Context, english, dutch string
 
 	return count > 0, nil
 }
+
+// ArchiveWord hides a word from study without deleting its history
+func (r *vocabularyRepository) ArchiveWord(ctx context.Context, id vocabulary.ID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE words SET archived = 1 WHERE id = ?`, int64(id))
+	if err != nil {
+		return fmt.Errorf("failed to archive word: %w", err)
+	}
+	return nil
+}
+
+// MergeWords merges a duplicate word into another within a single transaction.
+// Progress, review history and flags are repointed from the loser to the winner;
+// when a user has progress on both, the stronger progress (higher stability) is kept.
+// The loser is archived rather than deleted.
+func (r *vocabularyRepository) MergeWords(ctx context.Context, winnerID, loserID vocabulary.ID) error {
+	if winnerID == loserID {
+		return fmt.Errorf("cannot merge word %d into itself", winnerID)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Both words must exist and be active
+	var activeCount int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM words WHERE id IN (?, ?) AND archived = 0
+	`, int64(winnerID), int64(loserID)).Scan(&activeCount)
+	if err != nil {
+		return fmt.Errorf("failed to check words: %w", err)
+	}
+	if activeCount != 2 {
+		return fmt.Errorf("both words must exist and not be archived")
+	}
+
+	statements := []struct {
+		description string
+		query       string
+	}{
+		{
+			// Drop the winner's progress where the loser's progress is stronger
+			"drop weaker winner progress",
+			`DELETE FROM user_progress WHERE word_id = ?1 AND user_id IN (
+				SELECT l.user_id FROM user_progress l
+				JOIN user_progress w ON w.user_id = l.user_id AND w.word_id = ?1
+				WHERE l.word_id = ?2 AND (l.stability > w.stability OR
+					(l.stability = w.stability AND l.review_count > w.review_count)))`,
+		},
+		{
+			// Drop the loser's progress where the winner's progress is kept
+			"drop weaker loser progress",
+			`DELETE FROM user_progress WHERE word_id = ?2 AND user_id IN (
+				SELECT user_id FROM user_progress WHERE word_id = ?1)`,
+		},
+		{"repoint progress", `UPDATE user_progress SET word_id = ?1 WHERE word_id = ?2`},
+		{"repoint review history", `UPDATE review_history SET word_id = ?1 WHERE word_id = ?2`},
+		{
+			"repoint low priority flags",
+			`INSERT OR IGNORE INTO low_priority_words (user_id, word_id, created_at)
+				SELECT user_id, ?1, created_at FROM low_priority_words WHERE word_id = ?2`,
+		},
+		{"drop loser low priority flags", `DELETE FROM low_priority_words WHERE word_id = ?2`},
+		{"archive loser", `UPDATE words SET archived = 1 WHERE id = ?2`},
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, int64(winnerID), int64(loserID)); err != nil {
+			return fmt.Errorf("failed to %s: %w", stmt.description, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// saveProgressWithStability stores a review card for the word with the given stability
+func saveProgressWithStability(t *testing.T, repo learning.Repository, userID user.ID, wordID vocabulary.ID, stability float64) {
+	t.Helper()
+
+	progress := learning.NewUserProgress(userID, wordID)
+	card := progress.FSRSCard()
+	card.SetState(learning.StateReview)
+	card.SetReviewCount(3)
+	card.SetStability(stability)
+	card.SetDueDate(time.Now().UTC().Add(24 * time.Hour))
+	if err := repo.SaveProgress(context.Background(), progress); err != nil {
+		t.Fatalf("failed to save progress: %v", err)
+	}
+}
+
+func TestMergeWords(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	vocabRepo := NewVocabularyRepository(db)
+	learningRepo := NewLearningRepository(db)
+
+	weakWinner := saveTestUser(t, db)
+	strongWinner := user.NewUser(43, "ben", "Ben", "", "en")
+	loserOnly := user.NewUser(44, "cor", "Cor", "", "en")
+	for _, u := range []*user.User{strongWinner, loserOnly} {
+		if err := NewUserRepository(db).Save(ctx, u); err != nil {
+			t.Fatalf("failed to save user: %v", err)
+		}
+	}
+
+	winner := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	loser := saveTestWord(t, db, "home", "huis", vocabulary.Category("home"))
+
+	// Both words studied: the loser's progress is stronger for one user, weaker for the other
+	saveProgressWithStability(t, learningRepo, weakWinner, winner, 2)
+	saveProgressWithStability(t, learningRepo, weakWinner, loser, 20)
+	saveProgressWithStability(t, learningRepo, strongWinner.ID(), winner, 30)
+	saveProgressWithStability(t, learningRepo, strongWinner.ID(), loser, 5)
+	// Only the loser studied
+	saveProgressWithStability(t, learningRepo, loserOnly.ID(), loser, 8)
+	saveReview(t, learningRepo, loserOnly.ID(), loser, learning.Good, learning.StateReview, time.Now().UTC())
+
+	if err := vocabRepo.MergeWords(ctx, winner, loser); err != nil {
+		t.Fatalf("MergeWords: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		userID        user.ID
+		wantStability float64
+	}{
+		{"loser progress stronger", weakWinner, 20},
+		{"winner progress stronger", strongWinner.ID(), 30},
+		{"only loser studied", loserOnly.ID(), 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress, err := learningRepo.FindProgress(ctx, tt.userID, winner)
+			if err != nil || progress == nil {
+				t.Fatalf("FindProgress(winner) = %v, %v", progress, err)
+			}
+			if got := progress.FSRSCard().Stability(); got != tt.wantStability {
+				t.Errorf("stability = %v, want %v", got, tt.wantStability)
+			}
+
+			left, err := learningRepo.FindProgress(ctx, tt.userID, loser)
+			if err != nil {
+				t.Fatalf("FindProgress(loser): %v", err)
+			}
+			if left != nil {
+				t.Error("progress left on the merged word")
+			}
+		})
+	}
+
+	history, err := learningRepo.FindReviewHistory(ctx, loserOnly.ID(), winner)
+	if err != nil {
+		t.Fatalf("FindReviewHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("winner has %d reviews, want the loser's review repointed", len(history))
+	}
+
+	var archived bool
+	if err := db.QueryRow(`SELECT archived FROM words WHERE id = ?`, int64(loser)).Scan(&archived); err != nil {
+		t.Fatalf("failed to read loser: %v", err)
+	}
+	if !archived {
+		t.Error("merged word was not archived")
+	}
+}
+
+func TestMergeWords_Rejects(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewVocabularyRepository(db)
+
+	word := saveTestWord(t, db, "house", "huis", vocabulary.Category("basics"))
+	archived := saveTestWord(t, db, "tree", "boom", vocabulary.Category("basics"))
+	if err := repo.ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		winner, loser vocabulary.ID
+	}{
+		{"same word", word, word},
+		{"archived loser", word, archived},
+		{"missing winner", vocabulary.ID(999), word},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.MergeWords(ctx, tt.winner, tt.loser); err == nil {
+				t.Error("MergeWords succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestSaveBatch_ReportsSkippedDuplicates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewVocabularyRepository(newTestDB(t))
+	newWord := func(english, dutch string, pos vocabulary.PartOfSpeech) *vocabulary.Word {
+		word := vocabulary.NewWord(english, dutch, vocabulary.CategoryHome)
+		word.SetPartOfSpeech(pos)
+		return word
+	}
+
+	tests := []struct {
+		name        string
+		words       []*vocabulary.Word
+		wantAdded   int
+		wantSkipped int
+		wantTotal   int
+	}{
+		{"duplicate within the batch", []*vocabulary.Word{
+			newWord("house", "huis", ""), newWord("door", "deur", ""), newWord("house", "huis", ""),
+		}, 2, 1, 2},
+		{"retried batch with one new word", []*vocabulary.Word{
+			newWord("house", "huis", vocabulary.PartOfSpeechNoun), newWord("door", "deur", ""), newWord("window", "raam", ""),
+		}, 1, 2, 3},
+		{"same english, different dutch", []*vocabulary.Word{
+			newWord("house", "woning", ""),
+		}, 1, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, skipped, err := repo.SaveBatch(ctx, tt.words)
+			if err != nil {
+				t.Fatalf("SaveBatch: %v", err)
+			}
+			if added != tt.wantAdded || skipped != tt.wantSkipped {
+				t.Errorf("SaveBatch = added %d, skipped %d; want added %d, skipped %d", added, skipped, tt.wantAdded, tt.wantSkipped)
+			}
+			all, err := repo.FindAll(ctx)
+			if err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			if len(all) != tt.wantTotal {
+				t.Errorf("%d words stored, want %d", len(all), tt.wantTotal)
+			}
+		})
+	}
+
+	// A skipped word still picks up metadata changes from the batch
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	for _, word := range all {
+		if word.Dutch() == "huis" && word.PartOfSpeech() != vocabulary.PartOfSpeechNoun {
+			t.Errorf("skipped word's part of speech = %q, want %q", word.PartOfSpeech(), vocabulary.PartOfSpeechNoun)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewVocabularyRepository(db)
+	for _, pair := range [][2]string{
+		{"house", "huis"}, {"home", "thuis"}, {"greenhouse", "kas"}, {"tree", "boom"}, {"100%", "honderd procent"},
+	} {
+		saveTestWord(t, db, pair[0], pair[1], vocabulary.CategoryHome)
+	}
+	archived := saveTestWord(t, db, "cottage", "huisje", vocabulary.CategoryHome)
+	if err := repo.ArchiveWord(ctx, archived); err != nil {
+		t.Fatalf("failed to archive word: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string // Dutch words, in order
+	}{
+		{"dutch partial match", "uis", 10, []string{"huis", "thuis"}},
+		{"english partial match", "HOUSE", 10, []string{"huis", "kas"}},
+		{"exact match ranks first, archived words left out", "huis", 10, []string{"huis", "thuis"}},
+		{"prefix match before infix", "hu", 10, []string{"huis", "thuis"}},
+		{"either language", "boo", 10, []string{"boom"}},
+		{"wildcards match literally", "%", 10, []string{"honderd procent"}},
+		{"results are capped", "h", 2, []string{"honderd procent", "huis"}},
+		{"no match", "fiets", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := repo.Search(ctx, tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []string
+			for _, word := range words {
+				got = append(got, word.Dutch())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,13 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"dutch-learning-bot/internal/interfaces/telegram"
 
@@ -29,6 +34,14 @@ func NewBot(token string) (*Bot, error) {
 	}, nil
 }
 
+// NewBotWithAPI creates a bot around an existing API client, e.g. one pointed at a custom endpoint
+func NewBotWithAPI(api *tgbotapi.BotAPI) *Bot {
+	return &Bot{
+		api:        api,
+		dispatcher: newDefaultDispatcher(),
+	}
+}
+
 // GetAPI returns the underlying bot API
 func (b *Bot) GetAPI() *tgbotapi.BotAPI {
 	return b.api
@@ -62,8 +75,7 @@ func (b *Bot) SendMessage(chatID int64, text string) error {
 func (b *Bot) SendMessageWithMarkdown(chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = tgbotapi.ModeMarkdown
-	_, err := b.api.Send(msg)
-	return err
+	return b.sendFormattedMessage(msg)
 }
 
 // SendMessageWithKeyboard sends a message with inline keyboard
@@ -71,10 +83,142 @@ func (b *Bot) SendMessageWithKeyboard(chatID int64, text string, keyboard tgbota
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = tgbotapi.ModeMarkdown
 	msg.ReplyMarkup = keyboard
+	return b.sendFormattedMessage(msg)
+}
+
+// sendFormattedMessage sends a formatted message. If Telegram can't parse the formatting
+// (typically unescaped user content), the text is resent as plain text so it isn't lost.
+func (b *Bot) sendFormattedMessage(msg tgbotapi.MessageConfig) error {
 	_, err := b.api.Send(msg)
+	if isParseError(err) {
+		log.Printf("Failed to parse message formatting, resending as plain text: %v", err)
+		msg.ParseMode = ""
+		_, err = b.api.Send(msg)
+	}
 	return err
 }
 
+// sendFormattedEdit edits a message with formatted text, falling back to plain text like sendFormattedMessage
+func (b *Bot) sendFormattedEdit(edit tgbotapi.EditMessageTextConfig) error {
+	_, err := b.api.Send(edit)
+	if isParseError(err) {
+		log.Printf("Failed to parse message formatting, editing as plain text: %v", err)
+		edit.ParseMode = ""
+		_, err = b.api.Send(edit)
+	}
+	return err
+}
+
+// isParseError checks if Telegram rejected a message because its formatting is malformed
+func isParseError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return strings.Contains(apiErr.Message, "can't parse entities")
+}
+
+// maxSendAttempts is how many times RetryRateLimited tries a send that Telegram keeps rate limiting
+const maxSendAttempts = 3
+
+// maxRetryWait caps how long RetryRateLimited waits before retrying, whatever Telegram asks for
+const maxRetryWait = 30 * time.Second
+
+// rateLimitBackoff is the first wait before retrying when Telegram doesn't say how long to wait.
+// It doubles with each further attempt.
+var rateLimitBackoff = time.Second
+
+// RetryRateLimited calls send, and calls it again when Telegram answers 429 Too Many Requests,
+// after the wait Telegram asked for. Other errors, and the last rate limit error, are returned as is.
+func RetryRateLimited(send func() error) error {
+	backoff := rateLimitBackoff
+	for attempt := 1; ; attempt++ {
+		err := send()
+		var apiErr *tgbotapi.Error
+		if attempt == maxSendAttempts || !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+			return err
+		}
+
+		wait := backoff
+		if apiErr.RetryAfter > 0 {
+			wait = time.Duration(apiErr.RetryAfter) * time.Second
+		}
+		wait = min(wait, maxRetryWait)
+		log.Printf("Rate limited by Telegram, retrying in %v", wait)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// SendDocument sends an in-memory file as a document
+func (b *Bot) SendDocument(chatID int64, fileName string, data []byte, caption string) error {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	doc.Caption = caption
+	_, err := b.api.Send(doc)
+	if err != nil {
+		return fmt.Errorf("failed to send document: %w", err)
+	}
+	return nil
+}
+
+// DownloadFile fetches the contents of a file a user sent, such as a document
+func (b *Bot) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	url, err := b.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// SendPhoto sends an image from a URL or local file path
+func (b *Bot) SendPhoto(chatID int64, ref string, caption string) error {
+	photo := tgbotapi.NewPhoto(chatID, mediaFile(ref))
+	photo.Caption = caption
+	_, err := b.api.Send(photo)
+	if err != nil {
+		return fmt.Errorf("failed to send photo: %w", err)
+	}
+	return nil
+}
+
+// SendAudio sends an audio file from a URL or local file path
+func (b *Bot) SendAudio(chatID int64, ref string, caption string) error {
+	audio := tgbotapi.NewAudio(chatID, mediaFile(ref))
+	audio.Caption = caption
+	_, err := b.api.Send(audio)
+	if err != nil {
+		return fmt.Errorf("failed to send audio: %w", err)
+	}
+	return nil
+}
+
+// mediaFile turns a media reference into a file Telegram can fetch (URL) or we upload (local path)
+func mediaFile(ref string) tgbotapi.RequestFileData {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return tgbotapi.FileURL(ref)
+	}
+	return tgbotapi.FilePath(ref)
+}
+
 // EditMessage edits a message
 func (b *Bot) EditMessage(chatID int64, messageID int, text string) error {
 	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
@@ -85,12 +229,17 @@ func (b *Bot) EditMessage(chatID int64, messageID int, text string) error {
 	return nil
 }
 
-// EditMessageWithKeyboard edits an existing message and adds a keyboard
+// EditMessageWithKeyboard edits an existing message and adds a keyboard.
+// Messages Telegram no longer lets us edit (older than 48 hours, or deleted) are replaced
+// by a fresh message, so buttons on an old message still lead somewhere.
 func (b *Bot) EditMessageWithKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 	edit.ParseMode = tgbotapi.ModeMarkdown
 	edit.ReplyMarkup = &keyboard
-	_, err := b.api.Send(edit)
+	err := b.sendFormattedEdit(edit)
+	if isUneditableMessageError(err) {
+		return b.SendMessageWithKeyboard(chatID, text, keyboard)
+	}
 	if err != nil {
 		log.Printf("Failed to edit message with keyboard: %v", err)
 		return fmt.Errorf("failed to edit message with keyboard: %w", err)
@@ -98,6 +247,26 @@ func (b *Bot) EditMessageWithKeyboard(chatID int64, messageID int, text string,
 	return err
 }
 
+// isUneditableMessageError checks if Telegram refused an edit because the message is too old or gone
+func isUneditableMessageError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return strings.Contains(apiErr.Message, "message can't be edited") ||
+		strings.Contains(apiErr.Message, "message to edit not found")
+}
+
+// EditMessageReplyMarkup replaces the inline keyboard of an existing message
+func (b *Bot) EditMessageReplyMarkup(chatID int64, messageID int, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, keyboard)
+	_, err := b.api.Send(edit)
+	if err != nil {
+		return fmt.Errorf("failed to edit message keyboard: %w", err)
+	}
+	return nil
+}
+
 // AnswerCallbackQuery answers a callback query
 func (b *Bot) AnswerCallbackQuery(callbackID string, text string) error {
 	callback := tgbotapi.NewCallback(callbackID, text)
@@ -108,13 +277,54 @@ func (b *Bot) AnswerCallbackQuery(callbackID string, text string) error {
 	return nil
 }
 
+// AnswerInlineQuery answers an inline query with results Telegram may cache for cacheSeconds
+func (b *Bot) AnswerInlineQuery(queryID string, results []interface{}, cacheSeconds int) error {
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: queryID,
+		Results:       results,
+		CacheTime:     cacheSeconds,
+	}
+	_, err := b.api.Request(answer)
+	if err != nil {
+		return fmt.Errorf("failed to answer inline query: %w", err)
+	}
+	return nil
+}
+
 // SetupCommands sets up bot commands
 func (b *Bot) SetupCommands() error {
 	commands := []tgbotapi.BotCommand{
 		{Command: "start", Description: "Start the bot"},
 		{Command: "menu", Description: "Show main menu"},
 		{Command: "learn", Description: "Start learning session"},
+		{Command: "focus", Description: "Start a learning session without grammar tips"},
 		{Command: "stats", Description: "Show your learning statistics"},
+		{Command: "assess", Description: "Mark words you already know"},
+		{Command: "define", Description: "Look up a word without starting a review"},
+		{Command: "card", Description: "Show scheduling details for a word"},
+		{Command: "why", Description: "Explain why a word is due when it is"},
+		{Command: "tag", Description: "Tag a word, or list your tags"},
+		{Command: "mix", Description: "Set per-category daily quotas"},
+		{Command: "direction", Description: "Pin the question direction of a category"},
+		{Command: "sources", Description: "Choose which categories new words come from"},
+		{Command: "reshuffle", Description: "Shuffle the order of words you haven't studied"},
+		{Command: "reschedule", Description: "Recalculate review dates with current settings"},
+		{Command: "reset", Description: "Delete all your progress and start over"},
+		{Command: "leaderboard", Description: "See who reviewed the most this week"},
+		{Command: "forecast", Description: "See your review load for the next 7 days"},
+		{Command: "undo", Description: "Undo your last review"},
+		{Command: "partner", Description: "Share a deck with a study partner"},
+		{Command: "setdifficulty", Description: "Override a word's difficulty (1-10)"},
+		{Command: "export", Description: "Download your learning data"},
+		{Command: "export_stats", Description: "Download your word stats as CSV"},
+		{Command: "export_settings", Description: "Back up your settings"},
+		{Command: "import_settings", Description: "Restore settings from a backup"},
+		{Command: "hint", Description: "Choose the hint shown with questions"},
+		{Command: "fsrs_weights", Description: "Tune the FSRS scheduling weights"},
+		{Command: "learning_steps", Description: "Set the learning steps for new words"},
+		{Command: "retention", Description: "Set the target retention for review intervals"},
+		{Command: "digest", Description: "Get one daily summary instead of reminders"},
+		{Command: "set_timezone", Description: "Set your time zone for reminders and streaks"},
 		{Command: "settings", Description: "Show settings"},
 		{Command: "help", Description: "Show help"},
 	}
@@ -0,0 +1,204 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTelegramAPI answers Bot API calls, failing edits with editError when it is set and
+// formatted text when rejectFormatting is set, and records the methods and parse modes used
+type fakeTelegramAPI struct {
+	mu               sync.Mutex
+	methods          []string
+	parseModes       []string
+	editError        string
+	rejectFormatting bool
+}
+
+func (f *fakeTelegramAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	parseMode := r.FormValue("parse_mode")
+	f.mu.Lock()
+	f.methods = append(f.methods, method)
+	f.parseModes = append(f.parseModes, parseMode)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if method == "editMessageText" && f.editError != "" {
+		fmt.Fprintf(w, `{"ok":false,"error_code":400,"description":%q}`, f.editError)
+		return
+	}
+	if f.rejectFormatting && parseMode != "" {
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 5"}`))
+		return
+	}
+	w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot","message_id":1,"date":0,"chat":{"id":1,"type":"private"}}}`))
+}
+
+func (f *fakeTelegramAPI) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, m := range f.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func newTestBot(t *testing.T, fake *fakeTelegramAPI) *Bot {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("test-token", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatalf("failed to create bot API: %v", err)
+	}
+	return NewBotWithAPI(api)
+}
+
+func TestIsUneditableMessageError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"too old", &tgbotapi.Error{Code: 400, Message: "Bad Request: message can't be edited"}, true},
+		{"deleted", &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"}, true},
+		{"wrapped", fmt.Errorf("edit: %w", &tgbotapi.Error{Code: 400, Message: "Bad Request: message can't be edited"}), true},
+		{"not modified", &tgbotapi.Error{Code: 400, Message: "Bad Request: message is not modified"}, false},
+		{"not an API error", errors.New("message can't be edited"), false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUneditableMessageError(tt.err); got != tt.want {
+				t.Errorf("isUneditableMessageError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditMessageWithKeyboard_SendFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		editError string
+		wantSend  bool
+		wantErr   bool
+	}{
+		{"edited", "", false, false},
+		{"too old", "Bad Request: message can't be edited", true, false},
+		{"deleted", "Bad Request: message to edit not found", true, false},
+		{"not modified", "Bad Request: message is not modified", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTelegramAPI{editError: tt.editError}
+			bot := newTestBot(t, fake)
+			keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("Learn", "learn"),
+			))
+
+			err := bot.EditMessageWithKeyboard(1, 10, "Ready?", keyboard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EditMessageWithKeyboard error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := fake.count("editMessageText"); got != 1 {
+				t.Errorf("editMessageText called %d times, want 1", got)
+			}
+			if sent := fake.count("sendMessage") == 1; sent != tt.wantSend {
+				t.Errorf("sent a fresh message = %v, want %v", sent, tt.wantSend)
+			}
+		})
+	}
+}
+
+func TestSendFormatted_PlainTextRetry(t *testing.T) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Learn", "learn"),
+	))
+	tests := []struct {
+		name             string
+		method           string
+		rejectFormatting bool
+		send             func(bot *Bot) error
+		wantParseModes   []string
+	}{
+		{"message accepted", "sendMessage", false,
+			func(bot *Bot) error { return bot.SendMessageWithMarkdown(1, "*huis*") }, []string{tgbotapi.ModeMarkdown}},
+		{"message retried", "sendMessage", true,
+			func(bot *Bot) error { return bot.SendMessageWithMarkdown(1, "*huis_") }, []string{tgbotapi.ModeMarkdown, ""}},
+		{"keyboard message retried", "sendMessage", true,
+			func(bot *Bot) error { return bot.SendMessageWithKeyboard(1, "*huis_", keyboard) }, []string{tgbotapi.ModeMarkdown, ""}},
+		{"edit retried", "editMessageText", true,
+			func(bot *Bot) error { return bot.EditMessageWithKeyboard(1, 10, "*huis_", keyboard) }, []string{tgbotapi.ModeMarkdown, ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTelegramAPI{rejectFormatting: tt.rejectFormatting}
+			bot := newTestBot(t, fake)
+			fake.methods, fake.parseModes = nil, nil // Forget the getMe call made by the constructor
+
+			if err := tt.send(bot); err != nil {
+				t.Fatalf("send error = %v, want the plain text retry to succeed", err)
+			}
+			if got := fake.count(tt.method); got != len(tt.wantParseModes) {
+				t.Fatalf("%s called %d times, want %d", tt.method, got, len(tt.wantParseModes))
+			}
+			if strings.Join(fake.parseModes, ",") != strings.Join(tt.wantParseModes, ",") {
+				t.Errorf("parse modes = %q, want %q", fake.parseModes, tt.wantParseModes)
+			}
+		})
+	}
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	backoff := rateLimitBackoff
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = backoff })
+
+	rateLimited := &tgbotapi.Error{Code: http.StatusTooManyRequests, Message: "Too Many Requests: retry after 0"}
+	tests := []struct {
+		name      string
+		failures  int // Sends that fail before one succeeds
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"sent at once", 0, nil, 1, false},
+		{"rate limited once", 1, rateLimited, 2, false},
+		{"wrapped rate limit error", 1, fmt.Errorf("failed to send message: %w", rateLimited), 2, false},
+		{"rate limited throughout", 5, rateLimited, maxSendAttempts, true},
+		{"other errors aren't retried", 5, &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := RetryRateLimited(func() error {
+				calls++
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RetryRateLimited() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("send called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
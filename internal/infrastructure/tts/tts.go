@@ -0,0 +1,189 @@
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAudioSize caps the audio we accept from the TTS endpoint for a single word
+const maxAudioSize = 5 << 20
+
+// Client synthesizes speech for a piece of Dutch text
+type Client interface {
+	// Synthesize returns encoded audio, such as MP3 or OGG, of the text being spoken
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// HTTPClient synthesizes speech with a TTS HTTP endpoint. It requests
+// GET <endpoint>?text=<text>&lang=<language> and expects the audio file as the response body.
+type HTTPClient struct {
+	endpoint string
+	language string
+	client   *http.Client
+}
+
+// NewHTTPClient creates a client for the TTS endpoint, speaking the given language code such as "nl"
+func NewHTTPClient(endpoint, language string) *HTTPClient {
+	return &HTTPClient{
+		endpoint: endpoint,
+		language: language,
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Synthesize fetches the audio for the text from the endpoint
+func (c *HTTPClient) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TTS endpoint: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("text", text)
+	query.Set("lang", c.language)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TTS request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call TTS endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("TTS endpoint returned status %d", resp.StatusCode)
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TTS audio: %w", err)
+	}
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("TTS endpoint returned no audio")
+	}
+	if len(audio) > maxAudioSize {
+		return nil, fmt.Errorf("TTS audio is larger than %d bytes", maxAudioSize)
+	}
+	return audio, nil
+}
+
+// Cache keeps synthesized audio on disk, so each word is only synthesized once
+type Cache struct {
+	client Client
+	dir    string
+	format string
+
+	mu       sync.Mutex
+	inflight map[string]*synthesis // Syntheses running per cache key, so two taps on one word don't fetch it twice
+}
+
+// synthesis is one running synthesis that other requests for the same word wait on
+type synthesis struct {
+	done chan struct{}
+	err  error
+}
+
+// NewCache creates an audio cache in dir, creating the directory if needed.
+// Files are saved with the format as their extension, such as "mp3" or "ogg".
+func NewCache(client Client, dir, format string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create TTS cache directory: %w", err)
+	}
+	return &Cache{
+		client:   client,
+		dir:      dir,
+		format:   strings.TrimPrefix(format, "."),
+		inflight: make(map[string]*synthesis),
+	}, nil
+}
+
+// AudioFile returns the path of an audio file of the text being spoken, synthesizing it on first use.
+// Only requests for the same text wait for each other; other words are synthesized in parallel.
+func (c *Cache) AudioFile(ctx context.Context, text string) (string, error) {
+	key := cacheKey(text)
+	path := filepath.Join(c.dir, key+"."+c.format)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	c.mu.Lock()
+	if running, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-running.done:
+			if running.err != nil {
+				return "", running.err
+			}
+			return path, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	// Another request may have finished the file since it was checked above
+	if _, err := os.Stat(path); err == nil {
+		c.mu.Unlock()
+		return path, nil
+	}
+	running := &synthesis{done: make(chan struct{})}
+	c.inflight[key] = running
+	c.mu.Unlock()
+
+	running.err = c.synthesize(ctx, text, path)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(running.done)
+
+	if running.err != nil {
+		return "", running.err
+	}
+	return path, nil
+}
+
+// synthesize fetches the audio of the text and saves it at path
+func (c *Cache) synthesize(ctx context.Context, text, path string) error {
+	audio, err := c.client.Synthesize(ctx, strings.TrimSpace(text))
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a crash never leaves a truncated file behind
+	tmp, err := os.CreateTemp(c.dir, "tts-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create TTS cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(audio); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write TTS cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write TTS cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save TTS cache file: %w", err)
+	}
+
+	return nil
+}
+
+// cacheKey names the cache file of a text. Case and surrounding spaces don't change the
+// pronunciation, so they don't change the key; hashing keeps any text a safe file name.
+func cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(text))))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,197 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient returns the text as its audio, counting calls per text. Texts in block wait
+// until their channel is closed.
+type fakeClient struct {
+	mu    sync.Mutex
+	calls map[string]int
+	block map[string]chan struct{}
+	err   error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{calls: make(map[string]int), block: make(map[string]chan struct{})}
+}
+
+func (f *fakeClient) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	f.mu.Lock()
+	f.calls[text]++
+	wait := f.block[text]
+	f.mu.Unlock()
+
+	if wait != nil {
+		<-wait
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []byte("audio:" + text), nil
+}
+
+func (f *fakeClient) callCount(text string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[text]
+}
+
+func TestCacheKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"identical", "huis", "huis", true},
+		{"case", "Huis", "huis", true},
+		{"surrounding spaces", "  huis ", "huis", true},
+		{"different words", "huis", "boom", false},
+		{"inner spaces matter", "de man", "deman", false},
+		{"accents matter", "één", "een", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheKey(tt.a) == cacheKey(tt.b); got != tt.same {
+				t.Errorf("cacheKey(%q) == cacheKey(%q) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+
+	// Keys must be safe file names whatever the text
+	if key := cacheKey("../../etc/passwd"); filepath.Base(key) != key || len(key) != 32 {
+		t.Errorf("cacheKey produced unsafe file name %q", key)
+	}
+}
+
+func TestCacheAudioFile(t *testing.T) {
+	client := newFakeClient()
+	cache, err := NewCache(client, t.TempDir(), ".mp3")
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+
+	path, err := cache.AudioFile(context.Background(), " Huis ")
+	if err != nil {
+		t.Fatalf("AudioFile: %v", err)
+	}
+	if filepath.Ext(path) != ".mp3" {
+		t.Errorf("cached file %q should have the .mp3 extension", path)
+	}
+	audio, err := os.ReadFile(path)
+	if err != nil || string(audio) != "audio:Huis" {
+		t.Errorf("cached audio = %q (%v), want the synthesized audio", audio, err)
+	}
+
+	// The same word in another case is served from disk
+	again, err := cache.AudioFile(context.Background(), "huis")
+	if err != nil || again != path {
+		t.Errorf("second AudioFile = %q (%v), want %q", again, err, path)
+	}
+	if got := client.callCount("Huis") + client.callCount("huis"); got != 1 {
+		t.Errorf("word synthesized %d times, want 1", got)
+	}
+}
+
+func TestCacheAudioFile_Error(t *testing.T) {
+	client := newFakeClient()
+	client.err = errors.New("endpoint down")
+	cache, err := NewCache(client, t.TempDir(), "ogg")
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+
+	if _, err := cache.AudioFile(context.Background(), "huis"); err == nil {
+		t.Fatal("expected the synthesis error")
+	}
+
+	// A failure isn't cached, so the next request tries again
+	client.err = nil
+	if _, err := cache.AudioFile(context.Background(), "huis"); err != nil {
+		t.Fatalf("AudioFile after recovery: %v", err)
+	}
+	if got := client.callCount("huis"); got != 2 {
+		t.Errorf("word synthesized %d times, want 2", got)
+	}
+}
+
+func TestCacheAudioFile_Concurrent(t *testing.T) {
+	client := newFakeClient()
+	slow := make(chan struct{})
+	client.block["huis"] = slow
+	cache, err := NewCache(client, t.TempDir(), "mp3")
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+
+	// Several taps on a word that is slow to synthesize
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.AudioFile(context.Background(), "huis")
+			errs <- err
+		}()
+	}
+
+	// Another word isn't held up by it
+	done := make(chan error, 1)
+	go func() {
+		_, err := cache.AudioFile(context.Background(), "boom")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("AudioFile(boom): %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("a slow synthesis blocked another word")
+	}
+
+	close(slow)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("AudioFile(huis): %v", err)
+		}
+	}
+	if got := client.callCount("huis"); got != 1 {
+		t.Errorf("word synthesized %d times, want 1", got)
+	}
+}
+
+func TestHTTPClientSynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("text") != "de man" || r.URL.Query().Get("lang") != "nl" {
+			http.Error(w, "unexpected query "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("mp3 data"))
+	}))
+	defer server.Close()
+
+	audio, err := NewHTTPClient(server.URL, "nl").Synthesize(context.Background(), "de man")
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if string(audio) != "mp3 data" {
+		t.Errorf("audio = %q, want %q", audio, "mp3 data")
+	}
+
+	if _, err := NewHTTPClient(server.URL, "en").Synthesize(context.Background(), "de man"); err == nil {
+		t.Error("expected an error for a non-200 response")
+	}
+}
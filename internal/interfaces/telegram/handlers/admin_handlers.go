@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// handleMerge processes the admin /merge <winner_id> <loser_id> command
+func (h *BotHandler) handleMerge(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if !h.isAdmin(user) {
+		h.bot.SendMessage(message.Chat.ID, "This command is only available to admins.")
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) != 2 {
+		h.bot.SendMessage(message.Chat.ID, "Usage: /merge <winner_word_id> <loser_word_id>")
+		return
+	}
+
+	winnerID, errWinner := strconv.ParseInt(args[0], 10, 64)
+	loserID, errLoser := strconv.ParseInt(args[1], 10, 64)
+	if errWinner != nil || errLoser != nil {
+		h.bot.SendMessage(message.Chat.ID, "Word IDs must be numbers. Usage: /merge <winner_word_id> <loser_word_id>")
+		return
+	}
+
+	err := h.learningUseCase.MergeWords(ctx, vocabulary.ID(winnerID), vocabulary.ID(loserID))
+	if err != nil {
+		log.Printf("Failed to merge word %d into %d: %v", loserID, winnerID, err)
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Failed to merge words: %v", err))
+		return
+	}
+
+	log.Printf("Admin %d merged word %d into %d", user.TelegramID(), loserID, winnerID)
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("✅ Merged word %d into %d. Word %d is now archived.", loserID, winnerID, loserID))
+}
+
+// handleReminderStats processes the admin /reminder_stats [reset] command
+func (h *BotHandler) handleReminderStats(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if !h.isAdmin(user) {
+		h.bot.SendMessage(message.Chat.ID, "This command is only available to admins.")
+		return
+	}
+
+	if strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "reset") {
+		h.reminderUseCase.ResetReminderState()
+		log.Printf("Admin %d reset reminder state", user.TelegramID())
+		h.bot.SendMessage(message.Chat.ID, "✅ Reminder state reset. Pacing starts fresh on the next check.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, formatReminderStats(h.reminderUseCase.GetReminderStats()))
+}
+
+// handleCheckReminders processes the admin /check_reminders [dry] command, running a reminder check right away
+func (h *BotHandler) handleCheckReminders(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if !h.isAdmin(user) {
+		h.bot.SendMessage(message.Chat.ID, "This command is only available to admins.")
+		return
+	}
+
+	dryRun := strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "dry")
+	result, err := h.reminderUseCase.CheckRemindersNow(ctx, dryRun)
+	if err != nil {
+		log.Printf("Failed to check reminders: %v", err)
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Failed to check reminders: %v", err))
+		return
+	}
+
+	log.Printf("Admin %d ran a reminder check (dry run: %t)", user.TelegramID(), dryRun)
+	h.bot.SendMessage(message.Chat.ID, formatReminderCheck(result))
+}
+
+// formatReminderCheck formats the outcome of a manual reminder check for admins
+func formatReminderCheck(result *usecases.ReminderCheckResult) string {
+	if result.DryRun {
+		return fmt.Sprintf("🧪 Reminder check (dry run)\n\n"+
+			"Users checked: %d\n"+
+			"Reminders that would be sent: %d\n\n"+
+			"Nothing was sent. Use /check_reminders without \"dry\" to send them.",
+			result.UsersChecked, result.RemindersDue)
+	}
+	return fmt.Sprintf("⏰ Reminder check\n\n"+
+		"Users checked: %d\n"+
+		"Reminders due: %d\n"+
+		"Reminders sent: %d",
+		result.UsersChecked, result.RemindersDue, result.RemindersSent)
+}
+
+// formatReminderStats formats reminder service statistics for admins
+func formatReminderStats(stats *usecases.ReminderStats) string {
+	config := stats.Config
+	return fmt.Sprintf("⏰ Reminder stats\n\n"+
+		"Users tracked: %d\n"+
+		"Reminders sent today: %d\n\n"+
+		"Check interval: %v\n"+
+		"Reminder interval: per user (see /settings)\n"+
+		"Quiet hours: %02d:00-%02d:00\n"+
+		"Max reminders per day: %d\n"+
+		"Max concurrent sends: %d\n"+
+		"Custom template: %t\n\n"+
+		"Use /reminder_stats reset to clear the in-memory state.",
+		stats.UsersTracked, stats.RemindersSentToday,
+		config.CheckInterval, config.QuietHoursStart, config.QuietHoursEnd,
+		config.MaxRemindersPerDay, config.MaxConcurrentReminders, config.MessageTemplate != "")
+}
+
+// notifyAdmins sends a message to every configured admin
+func (h *BotHandler) notifyAdmins(text string) {
+	for _, adminID := range h.config.AdminTelegramIDs {
+		if err := h.bot.SendMessage(int64(adminID), text); err != nil {
+			log.Printf("Failed to notify admin %d: %v", adminID, err)
+		}
+	}
+}
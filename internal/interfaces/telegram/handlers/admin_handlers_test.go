@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
+)
+
+func TestFormatReminderStats(t *testing.T) {
+	config := usecases.DefaultReminderConfig()
+	config.CheckInterval = 5 * time.Minute
+	config.QuietHoursStart, config.QuietHoursEnd = 22, 8
+	config.MaxRemindersPerDay = 3
+
+	text := formatReminderStats(&usecases.ReminderStats{UsersTracked: 4, RemindersSentToday: 7, Config: *config})
+
+	for _, line := range []string{
+		"Users tracked: 4\n",
+		"Reminders sent today: 7\n",
+		"Check interval: 5m0s\n",
+		"Quiet hours: 22:00-08:00\n",
+		"Max reminders per day: 3\n",
+		"Custom template: false\n",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("stats text lacks %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestReminderStats_AdminOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		admins   []user.TelegramID
+		wantText string
+	}{
+		{"admin", []user.TelegramID{1001}, "Users tracked: 0"},
+		{"not an admin", nil, "only available to admins"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultHandlerConfig()
+			config.AdminTelegramIDs = tt.admins
+			h, fake := newTestBotHandler(t, config)
+
+			h.handleMessage(context.Background(), newTestCommand("reminder_stats"))
+
+			sends := fake.callsTo("sendMessage")
+			if len(sends) != 1 || !strings.Contains(sends[0].params.Get("text"), tt.wantText) {
+				t.Errorf("sent %v, want one message containing %q", sends, tt.wantText)
+			}
+		})
+	}
+}
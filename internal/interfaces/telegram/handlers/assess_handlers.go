@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleAssess processes the /assess command
+func (h *BotHandler) handleAssess(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	h.handleAssessFlow(ctx, message.Chat.ID, message.MessageID, user, false)
+}
+
+// handleAssessFlow shows the next unstudied word so the user can mark it as known or unknown
+func (h *BotHandler) handleAssessFlow(ctx context.Context, chatID int64, messageID int, user *user.User, isCallback bool) {
+	word, err := h.learningUseCase.GetNextWordToAssess(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get word to assess: %v", err)
+		if isCallback {
+			h.bot.EditMessage(chatID, messageID, "Sorry, there was an error getting your words. Please try again.")
+		} else {
+			h.bot.SendMessage(chatID, "Sorry, there was an error getting your words. Please try again.")
+		}
+		return
+	}
+
+	if word == nil {
+		doneText := "🎉 You've assessed every word in the vocabulary!"
+		keyboard := shared.CreateNoWordsKeyboard()
+		if isCallback {
+			h.bot.EditMessageWithKeyboard(chatID, messageID, doneText, keyboard)
+		} else {
+			h.bot.SendMessageWithKeyboard(chatID, doneText, keyboard)
+		}
+		return
+	}
+
+	assessText := fmt.Sprintf("🧐 *Do you already know this word?*\n\n🇳🇱 %s\n🇬🇧 %s\n\n"+
+		"_Known words skip ahead to review, unknown words are learned from scratch._",
+		shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English()))
+	keyboard := createAssessKeyboard(word.ID())
+
+	if isCallback {
+		h.bot.EditMessageWithKeyboard(chatID, messageID, assessText, keyboard)
+	} else {
+		h.bot.SendMessageWithKeyboard(chatID, assessText, keyboard)
+	}
+}
+
+// handleAssessAnswer records the user's self-assessment and shows the next word
+func (h *BotHandler) handleAssessAnswer(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, answer, wordIDStr string) {
+	wordID, err := strconv.ParseInt(wordIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Invalid assess word ID: %s", wordIDStr)
+		return
+	}
+
+	if err := h.learningUseCase.AssessWord(ctx, user.ID(), vocabulary.ID(wordID), answer == "known"); err != nil {
+		log.Printf("Failed to assess word: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error saving your answer. Please try again with /assess")
+		return
+	}
+
+	h.handleAssessFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
+}
+
+// createAssessKeyboard creates the known/unknown keyboard for a word
+func createAssessKeyboard(wordID vocabulary.ID) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ I know it", fmt.Sprintf("assess_known_%d", wordID)),
+			tgbotapi.NewInlineKeyboardButtonData("🆕 New to me", fmt.Sprintf("assess_unknown_%d", wordID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Back to Menu", "back_menu"),
+		),
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -11,15 +12,47 @@ import (
 	"dutch-learning-bot/internal/application/usecases"
 	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/infrastructure/telegram"
+	"dutch-learning-bot/internal/infrastructure/tts"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
 )
 
+// HandlerConfig holds configuration for the bot handler
+type HandlerConfig struct {
+	// Telegram IDs of users allowed to run admin commands
+	AdminTelegramIDs []user.TelegramID
+	// Replace messages with stale or unknown buttons by the main menu
+	RecoverUnknownCallbacks bool
+	// Ask before /learn replaces a question that is still in progress
+	ConfirmSessionRestart bool
+	// Reviews saved in the background at once, across all users
+	MaxConcurrentReviews int
+	// Ratings a single user may have waiting to be saved; extra taps are dropped
+	MaxPendingReviewsPerUser int
+	// Pronunciation audio for Dutch words; nil hides the Listen button
+	Speech *tts.Cache
+}
+
+// DefaultHandlerConfig returns sensible defaults for the bot handler
+func DefaultHandlerConfig() *HandlerConfig {
+	return &HandlerConfig{
+		AdminTelegramIDs:         nil, // No admins unless configured
+		RecoverUnknownCallbacks:  true,
+		ConfirmSessionRestart:    true,
+		MaxConcurrentReviews:     8,
+		MaxPendingReviewsPerUser: 2,
+	}
+}
+
 // BotHandler handles Telegram bot interactions
 type BotHandler struct {
 	bot             *telegram.Bot
 	userUseCase     *usecases.UserUseCase
 	learningUseCase *usecases.LearningUseCase
+	reminderUseCase *usecases.ReminderUseCase
 	preferencesRepo user.PreferencesRepository
+	config          *HandlerConfig
 	activeSessions  map[int64]*usecases.LearningSession
+	reviewWorkers   *reviewWorkers
 }
 
 // NewBotHandler creates a new bot handler
@@ -27,17 +60,36 @@ func NewBotHandler(
 	bot *telegram.Bot,
 	userUseCase *usecases.UserUseCase,
 	learningUseCase *usecases.LearningUseCase,
+	reminderUseCase *usecases.ReminderUseCase,
 	preferencesRepo user.PreferencesRepository,
+	config *HandlerConfig,
 ) *BotHandler {
+	if config == nil {
+		config = DefaultHandlerConfig()
+	}
+
 	return &BotHandler{
 		bot:             bot,
 		userUseCase:     userUseCase,
 		learningUseCase: learningUseCase,
+		reminderUseCase: reminderUseCase,
 		preferencesRepo: preferencesRepo,
+		config:          config,
 		activeSessions:  make(map[int64]*usecases.LearningSession),
+		reviewWorkers:   newReviewWorkers(config.MaxConcurrentReviews, config.MaxPendingReviewsPerUser),
 	}
 }
 
+// isAdmin checks if a user is allowed to run admin commands
+func (h *BotHandler) isAdmin(u *user.User) bool {
+	for _, id := range h.config.AdminTelegramIDs {
+		if id == u.TelegramID() {
+			return true
+		}
+	}
+	return false
+}
+
 // Start starts the bot and handles updates
 func (h *BotHandler) Start(ctx context.Context) error {
 	updates := h.bot.GetUpdatesChan()
@@ -63,6 +115,8 @@ func (h *BotHandler) handleUpdate(update tgbotapi.Update) {
 		h.handleMessage(ctx, update.Message)
 	} else if update.CallbackQuery != nil {
 		h.handleCallbackQuery(ctx, update.CallbackQuery)
+	} else if update.InlineQuery != nil {
+		h.handleInlineQuery(ctx, update.InlineQuery)
 	}
 }
 
@@ -74,6 +128,17 @@ func (h *BotHandler) handleMessage(ctx context.Context, message *tgbotapi.Messag
 		return
 	}
 
+	// A document is a progress CSV to import
+	if message.Document != nil {
+		h.handleImportProgress(ctx, message, user)
+		return
+	}
+
+	// A plain text reply answers a pending typed question; commands are handled as usual
+	if !message.IsCommand() && h.handleTypedAnswer(ctx, message, user) {
+		return
+	}
+
 	switch message.Command() {
 	case "start":
 		h.handleStart(ctx, message, user)
@@ -81,16 +146,72 @@ func (h *BotHandler) handleMessage(ctx context.Context, message *tgbotapi.Messag
 		h.handleMenu(ctx, message, user)
 	case "learn":
 		h.handleLearn(ctx, message, user)
+	case "focus":
+		h.handleFocus(ctx, message, user)
 	case "stats":
 		h.handleStats(ctx, message, user)
 	case "help":
 		h.handleHelp(ctx, message, user)
+	case "assess":
+		h.handleAssess(ctx, message, user)
+	case "merge":
+		h.handleMerge(ctx, message, user)
+	case "reminder_stats":
+		h.handleReminderStats(ctx, message, user)
+	case "check_reminders":
+		h.handleCheckReminders(ctx, message, user)
+	case "define":
+		h.handleDefine(ctx, message, user)
+	case "card":
+		h.handleCard(ctx, message, user)
+	case "why":
+		h.handleWhy(ctx, message, user)
+	case "setdifficulty":
+		h.handleSetDifficulty(ctx, message, user)
+	case "tag":
+		h.handleTag(ctx, message, user)
+	case "mix":
+		h.handleMix(ctx, message, user)
+	case "direction":
+		h.handleDirection(ctx, message, user)
+	case "sources":
+		h.handleSources(ctx, message, user)
+	case "reshuffle":
+		h.handleReshuffle(ctx, message, user)
+	case "reschedule":
+		h.handleReschedule(ctx, message, user)
+	case "reset":
+		h.handleReset(ctx, message, user)
+	case "leaderboard":
+		h.handleLeaderboard(ctx, message, user)
+	case "forecast":
+		h.handleForecast(ctx, message, user)
+	case "undo":
+		h.handleUndo(ctx, message, user)
+	case "partner":
+		h.handleStudyPartner(ctx, message, user)
+	case "export":
+		h.handleExport(ctx, message, user)
+	case "export_stats":
+		h.handleExportStats(ctx, message, user)
+	case "export_settings":
+		h.handleExportSettings(ctx, message, user)
+	case "import_settings":
+		h.handleImportSettings(ctx, message, user)
+	case "hint":
+		h.handleHint(ctx, message, user)
+	case "fsrs_weights":
+		h.handleFSRSWeights(ctx, message, user)
+	case "learning_steps":
+		h.handleLearningSteps(ctx, message, user)
+	case "retention":
+		h.handleRetention(ctx, message, user)
+	case "digest":
+		h.handleDigest(ctx, message, user)
+	case "set_timezone":
+		h.handleSetTimezone(ctx, message, user)
 	case "settings":
-		// Redirect /settings command to menu settings
-		h.handleMenuSettings(ctx, &tgbotapi.CallbackQuery{
-			Message: message,
-			From:    message.From,
-		}, user)
+		h.handleSettings(ctx, message, user)
 	default:
 		h.bot.SendMessage(message.Chat.ID, "Use /menu to see available options, or /help for detailed help.")
 	}
@@ -101,87 +222,274 @@ func (h *BotHandler) handleCallbackQuery(ctx context.Context, callback *tgbotapi
 	user, err := h.getOrCreateUser(ctx, callback.From)
 	if err != nil {
 		log.Printf("Failed to get/create user: %v", err)
+		h.answerCallback(callback, "Sorry, something went wrong. Please try again.")
 		return
 	}
 
-	// Answer the callback to remove loading state
-	if err := h.bot.AnswerCallbackQuery(callback.ID, ""); err != nil {
-		log.Printf("Failed to answer callback query: %v", err)
-	}
-
 	data := callback.Data
 	parts := strings.Split(data, "_")
 
-	log.Printf("Processing callback: data=%s, parts=%v, message_id=%d", data, parts, callback.Message.MessageID)
-
-	if len(parts) < 1 {
-		log.Printf("Invalid callback data format: %s", data)
+	// Always answer the callback so the button stops spinning, even for data we can't handle
+	route, known := callbackRoutes[parts[0]]
+	if !known {
+		log.Printf("Unknown callback type: %s", parts[0])
+		h.answerCallback(callback, "This button is no longer available.")
+		if h.config.RecoverUnknownCallbacks && callback.Message != nil {
+			h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID,
+				"🤔 That button is out of date. Here's the main menu:", shared.CreateMainMenuKeyboard())
+		}
 		return
 	}
 
-	// Handle noop callback (do nothing)
-	if data == "noop" {
-		return
+	// Answer the callback to remove loading state. Routes that answer it themselves do so with
+	// a toast, since a callback can only be answered once.
+	if !route.answersItself {
+		h.answerCallback(callback, "")
 	}
 
-	switch parts[0] {
-	case "menu":
-		if len(parts) >= 2 {
-			log.Printf("Handling menu selection: %s", data)
-			h.handleMenuSelection(ctx, callback, user, data)
+	log.Printf("Processing callback: data=%s, parts=%v, message_id=%d", data, parts, callback.Message.MessageID)
+
+	route.handle(h, ctx, callbackArgs{callback: callback, user: user, data: data, parts: parts})
+}
+
+// callbackArgs is a callback query along with its data split on "_"
+type callbackArgs struct {
+	callback *tgbotapi.CallbackQuery
+	user     *user.User
+	data     string
+	parts    []string
+}
+
+// callbackRoute is how handleCallbackQuery dispatches one callback prefix
+type callbackRoute struct {
+	handle func(h *BotHandler, ctx context.Context, c callbackArgs)
+	// answersItself marks handlers that answer the callback query themselves, on every path,
+	// instead of handleCallbackQuery answering it upfront
+	answersItself bool
+}
+
+// callbackRoutes maps each callback prefix, the data up to the first "_", to its handler.
+// Callbacks with any other prefix are answered as out of date.
+var callbackRoutes = map[string]callbackRoute{
+	"noop": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {}},
+	"menu": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			log.Printf("Handling menu selection: %s", c.data)
+			h.handleMenuSelection(ctx, c.callback, c.user, c.data)
+		} else {
+			log.Printf("Invalid menu callback format: %s", c.data)
+		}
+	}},
+	"choice": {answersItself: true, handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleMultipleChoice(ctx, c.callback, c.user, c.parts[1])
 		} else {
-			log.Printf("Invalid menu callback format: %s", data)
+			h.answerCallback(c.callback, "")
+		}
+	}},
+	"rating": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleRating(ctx, c.callback, c.user, c.parts[1])
 		}
-	case "choice":
-		if len(parts) >= 2 {
-			h.handleMultipleChoice(ctx, callback, user, parts[1])
+	}},
+	"reveal": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "answer" {
+			h.handleRevealAnswer(ctx, c.callback, c.user)
 		}
-	case "rating":
-		if len(parts) >= 2 {
-			h.handleRating(ctx, callback, user, parts[1])
+	}},
+	"confidence": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleConfidence(ctx, c.callback, c.user, c.parts[1])
 		}
-	case "continue":
-		if len(parts) >= 2 && parts[1] == "learning" {
-			h.handleContinueLearning(ctx, callback, user)
+	}},
+	"resume": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "question" {
+			h.handleResumeQuestion(ctx, c.callback, c.user)
 		}
-	case "view":
-		if len(parts) >= 2 && parts[1] == "stats" {
-			h.handleViewStats(ctx, callback, user)
+	}},
+	"restart": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "learning" {
+			h.handleRestartLearning(ctx, c.callback, c.user)
 		}
-	case "finish":
-		if len(parts) >= 2 && parts[1] == "session" {
-			h.handleFinishSession(ctx, callback, user)
+	}},
+	"continue": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "learning" {
+			h.handleContinueLearning(ctx, c.callback, c.user)
 		}
-	case "back":
-		if len(parts) >= 2 && parts[1] == "menu" {
-			h.handleBackToMenu(ctx, callback, user)
+	}},
+	"view": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "stats" {
+			h.handleViewStats(ctx, c.callback, c.user)
 		}
-	case "toggle":
-		if len(parts) >= 2 {
+	}},
+	"finish": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "session" {
+			h.handleFinishSession(ctx, c.callback, c.user)
+		}
+	}},
+	"assess": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 3 && (c.parts[1] == "known" || c.parts[1] == "unknown") {
+			h.handleAssessAnswer(ctx, c.callback, c.user, c.parts[1], c.parts[2])
+		}
+	}},
+	"study": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			// Categories such as verbs_action contain underscores themselves
+			h.handleStudyCategory(ctx, c.callback, c.user, strings.Join(c.parts[1:], "_"))
+		}
+	}},
+	"remind": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if c.data == usecases.ReminderLearnCallback {
+			// Serve a question right away instead of going through the menu
+			h.handleLearningFlow(ctx, c.callback.Message.Chat.ID, c.callback.Message.MessageID, c.user, true)
+		}
+	}},
+	"practice": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "more" {
+			h.handlePractice(ctx, c.callback, c.user)
+		}
+	}},
+	"snooze": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleSnooze(ctx, c.callback, c.user, c.parts[1])
+		}
+	}},
+	"report": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleReportWord(ctx, c.callback, c.user, c.parts[1])
+		}
+	}},
+	"postpone": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 3 {
+			h.handlePostponeWord(ctx, c.callback, c.user, c.parts[1], c.parts[2])
+		}
+	}},
+	"speak": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleSpeakWord(ctx, c.callback, c.user, c.parts[1])
+		}
+	}},
+	"mute":   {handle: muteWordCallback(true)},
+	"unmute": {handle: muteWordCallback(false)},
+	"reschedule": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "confirm" {
+			h.handleRescheduleConfirm(ctx, c.callback, c.user)
+		}
+	}},
+	"confirm": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "reset" {
+			h.handleResetConfirm(ctx, c.callback, c.user)
+		}
+	}},
+	"cancel": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "reset" {
+			h.handleResetCancel(ctx, c.callback, c.user)
+		}
+	}},
+	"back": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 && c.parts[1] == "menu" {
+			h.handleBackToMenu(ctx, c.callback, c.user)
+		}
+	}},
+	"toggle": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
 			// Join the remaining parts with underscore to handle multi-part identifiers
-			identifier := strings.Join(parts[1:], "_")
+			identifier := strings.Join(c.parts[1:], "_")
 			switch identifier {
 			case "grammar_tips":
-				h.handleToggleGrammarTips(ctx, callback, user)
+				h.handleToggleGrammarTips(ctx, c.callback, c.user)
 			case "smart_reminders":
-				h.handleToggleSmartReminders(ctx, callback, user)
+				h.handleToggleSmartReminders(ctx, c.callback, c.user)
+			case "session_progress":
+				h.handleToggleSessionProgress(ctx, c.callback, c.user)
+			case "ignore_articles":
+				h.handleToggleIgnoreArticles(ctx, c.callback, c.user)
+			case "strict_accents":
+				h.handleToggleStrictAccents(ctx, c.callback, c.user)
+			case "spelling_variants":
+				h.handleToggleSpellingVariants(ctx, c.callback, c.user)
+			case "interval_fuzz":
+				h.handleToggleIntervalFuzz(ctx, c.callback, c.user)
+			case "session_recap":
+				h.handleToggleSessionRecap(ctx, c.callback, c.user)
+			case "community_difficulty":
+				h.handleToggleCommunityDifficulty(ctx, c.callback, c.user)
+			case "study_priority":
+				h.handleToggleStudyPriority(ctx, c.callback, c.user)
+			case "staged_reveal":
+				h.handleToggleStagedReveal(ctx, c.callback, c.user)
+			case "auto_easy":
+				h.handleToggleAutoEasy(ctx, c.callback, c.user)
+			case "recognition_first":
+				h.handleToggleRecognitionFirst(ctx, c.callback, c.user)
+			case "shuffle_ratings":
+				h.handleToggleShuffleRatings(ctx, c.callback, c.user)
+			case "word_sense":
+				h.handleToggleShowWordSense(ctx, c.callback, c.user)
+			case "choice_grading":
+				h.handleToggleChoiceGrading(ctx, c.callback, c.user)
+			case "question_direction":
+				h.handleToggleQuestionDirection(ctx, c.callback, c.user)
+			case "new_word_order":
+				h.handleToggleNewWordOrder(ctx, c.callback, c.user)
+			case "reviews_only":
+				h.handleToggleReviewsOnly(ctx, c.callback, c.user)
+			case "answer_mode":
+				h.handleToggleAnswerMode(ctx, c.callback, c.user)
+			case "rate_confidence":
+				h.handleToggleRateConfidence(ctx, c.callback, c.user)
 			}
 		}
-	case "set":
-		if len(parts) >= 3 && parts[1] == "interval" {
-			// Split the last part by hyphen to get the direction and amount
-			intervalParts := strings.Split(parts[2], "-")
-			if len(intervalParts) == 2 && intervalParts[1] == "15" {
-				switch intervalParts[0] {
-				case "minus":
-					h.handleAdjustInterval(ctx, callback, user, -15)
-				case "plus":
-					h.handleAdjustInterval(ctx, callback, user, 15)
-				}
+	}},
+	"set": {handle: func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 3 && c.parts[1] == "ahead" {
+			h.handleSetReviewAhead(ctx, c.callback, c.user, c.parts[2])
+		}
+		if len(c.parts) >= 3 && c.parts[1] == "sessionmax" {
+			h.handleSetSessionLimit(ctx, c.callback, c.user, c.parts[2])
+		}
+		if len(c.parts) >= 3 && c.parts[1] == "newmax" {
+			h.handleSetDailyNewLimit(ctx, c.callback, c.user, c.parts[2])
+		}
+		if len(c.parts) >= 3 && c.parts[1] == "interval" {
+			step, err := parseIntervalStep(c.parts[2])
+			if err != nil {
+				log.Printf("Invalid interval callback %q: %v", c.data, err)
+				return
 			}
+			h.handleAdjustInterval(ctx, c.callback, c.user, step)
 		}
-	default:
-		log.Printf("Unknown callback type: %s", parts[0])
+	}},
+}
+
+// muteWordCallback handles mute_<wordID> and unmute_<wordID> callbacks
+func muteWordCallback(mute bool) func(h *BotHandler, ctx context.Context, c callbackArgs) {
+	return func(h *BotHandler, ctx context.Context, c callbackArgs) {
+		if len(c.parts) >= 2 {
+			h.handleMuteWord(ctx, c.callback, c.user, c.parts[1], mute)
+		}
+	}
+}
+
+// maxIntervalStep bounds a single reminder interval adjustment, in minutes
+const maxIntervalStep = 24 * 60
+
+// parseIntervalStep parses the signed minute step of a set_interval_<step> callback
+func parseIntervalStep(value string) (int, error) {
+	step, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("step is not a number: %w", err)
+	}
+	if step == 0 || step < -maxIntervalStep || step > maxIntervalStep {
+		return 0, fmt.Errorf("step %d out of range", step)
+	}
+	return step, nil
+}
+
+// answerCallback answers a callback query, optionally with a short toast
+func (h *BotHandler) answerCallback(callback *tgbotapi.CallbackQuery, text string) {
+	if err := h.bot.AnswerCallbackQuery(callback.ID, text); err != nil {
+		log.Printf("Failed to answer callback query: %v", err)
 	}
 }
 
@@ -221,51 +529,317 @@ func (h *BotHandler) handleAdjustInterval(ctx context.Context, callback *tgbotap
 		return
 	}
 
-	// Get updated preferences to ensure we have the latest state
-	prefs, err = h.userUseCase.GetUserPreferences(ctx, user.ID())
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleSetReviewAhead sets how far ahead nearly-due words count as due
+func (h *BotHandler) handleSetReviewAhead(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, minutesStr string) {
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes < 0 {
+		log.Printf("Invalid review ahead value: %s", minutesStr)
+		return
+	}
+
+	if err := h.userUseCase.SetReviewAhead(ctx, user.ID(), minutes); err != nil {
+		log.Printf("Failed to set review ahead: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleIgnoreArticles handles toggling article-insensitive answer matching
+func (h *BotHandler) handleToggleIgnoreArticles(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleIgnoreArticles(ctx, user.ID())
 	if err != nil {
-		log.Printf("Failed to get updated preferences: %v", err)
-		return
-	}
-
-	// Construct new message with updated state
-	message := fmt.Sprintf("*Settings*\n\n"+
-		"Grammar Tips: %s\n"+
-		"Smart Reminders: %s\n"+
-		"Reminder Interval: %d minutes\n\n"+
-		"Use the buttons below to adjust your settings.",
-		getToggleEmoji(prefs.GrammarTipsEnabled()),
-		getToggleEmoji(prefs.SmartRemindersEnabled()),
-		prefs.GetReminderInterval())
-
-	// Create keyboard with updated state
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("Grammar Tips %s", getToggleEmoji(prefs.GrammarTipsEnabled())),
-				"toggle_grammar_tips",
-			),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("Smart Reminders %s", getToggleEmoji(prefs.SmartRemindersEnabled())),
-				"toggle_smart_reminders",
-			),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⏪ -15", "set_interval_minus-15"),
-			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⏱ %d min", prefs.GetReminderInterval()), "noop"),
-			tgbotapi.NewInlineKeyboardButtonData("+15 ⏩", "set_interval_plus-15"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("« Back to Menu", "back_menu"),
-		),
-	)
-
-	// Edit the message with new content and keyboard
-	if err := h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, message, keyboard); err != nil {
-		log.Printf("Failed to update settings message: %v", err)
+		log.Printf("Failed to toggle ignore articles: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
 	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleStrictAccents handles toggling accent-sensitive answer matching
+func (h *BotHandler) handleToggleStrictAccents(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleStrictAccents(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle strict accents: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleSpellingVariants handles toggling whether British and American spellings are interchangeable
+func (h *BotHandler) handleToggleSpellingVariants(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleSpellingVariants(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle spelling variants: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleIntervalFuzz handles toggling the random spread of review intervals
+func (h *BotHandler) handleToggleIntervalFuzz(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleIntervalFuzz(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle interval fuzz: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleSessionRecap handles toggling the recap shown when a session is finished
+func (h *BotHandler) handleToggleSessionRecap(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleSessionRecap(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle session recap: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleCommunityDifficulty handles toggling community-seeded difficulty for new words
+func (h *BotHandler) handleToggleCommunityDifficulty(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleCommunityDifficulty(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle community difficulty: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleStudyPriority handles switching the card ordering strategy
+func (h *BotHandler) handleToggleStudyPriority(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleStudyPriority(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle study priority: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleNewWordOrder handles switching the order in which new words are introduced
+func (h *BotHandler) handleToggleNewWordOrder(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleNewWordOrder(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle new word order: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleRateConfidence handles toggling the confidence rating step after answers
+func (h *BotHandler) handleToggleRateConfidence(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleRateConfidence(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle confidence rating: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleReviewsOnly handles toggling sessions that skip new words
+func (h *BotHandler) handleToggleReviewsOnly(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleReviewsOnly(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle reviews only: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleAnswerMode handles switching between multiple-choice and typed answers
+func (h *BotHandler) handleToggleAnswerMode(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleAnswerMode(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle answer mode: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleChoiceGrading handles cycling how correct multiple-choice answers are rated
+func (h *BotHandler) handleToggleChoiceGrading(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.CycleChoiceGrading(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to change choice grading: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleQuestionDirection handles cycling which side of a word questions ask for
+func (h *BotHandler) handleToggleQuestionDirection(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.CycleQuestionDirection(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to change question direction: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleAutoEasy handles toggling automatic Easy ratings for fast correct answers
+func (h *BotHandler) handleToggleAutoEasy(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleAutoEasyFast(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle auto-Easy: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleRecognitionFirst handles toggling recognition questions on a word's first reviews
+func (h *BotHandler) handleToggleRecognitionFirst(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleRecognitionFirst(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle recognition-first: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleShuffleRatings handles toggling randomized rating button positions
+func (h *BotHandler) handleToggleShuffleRatings(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleShuffleRatings(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle rating shuffle: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleShowWordSense handles toggling the meaning shown with ambiguous words
+func (h *BotHandler) handleToggleShowWordSense(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleShowWordSense(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle word sense: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleToggleStagedReveal handles toggling the two-step answer reveal
+func (h *BotHandler) handleToggleStagedReveal(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleStagedReveal(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle staged reveal: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleSetSessionLimit sets the wall-clock cap for learning sessions
+func (h *BotHandler) handleSetSessionLimit(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, minutesStr string) {
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes < 0 {
+		log.Printf("Invalid session limit value: %s", minutesStr)
+		return
+	}
+
+	if err := h.userUseCase.SetMaxSessionMinutes(ctx, user.ID(), minutes); err != nil {
+		log.Printf("Failed to set session limit: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
+}
+
+// handleSetDailyNewLimit sets how many new words may be introduced per day
+func (h *BotHandler) handleSetDailyNewLimit(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, limitStr string) {
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		log.Printf("Invalid daily new-word limit: %s", limitStr)
+		return
+	}
+
+	if err := h.userUseCase.SetDailyNewLimit(ctx, user.ID(), limit); err != nil {
+		log.Printf("Failed to set daily new-word limit: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
 }
 
 // handleToggleGrammarTips handles toggling grammar tips
@@ -298,10 +872,16 @@ func (h *BotHandler) handleToggleSmartReminders(ctx context.Context, callback *t
 	h.handleMenuSettings(ctx, callback, user)
 }
 
-// getToggleEmoji returns the appropriate emoji for a toggle state
-func getToggleEmoji(enabled bool) string {
-	if enabled {
-		return "✅"
+// handleToggleSessionProgress handles toggling the running session scoreboard
+func (h *BotHandler) handleToggleSessionProgress(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	_, err := h.userUseCase.ToggleShowSessionProgress(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to toggle session progress: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error updating your settings. Please try again.")
+		return
 	}
-	return "❌"
+
+	// Show updated settings
+	h.handleMenuSettings(ctx, callback, user)
 }
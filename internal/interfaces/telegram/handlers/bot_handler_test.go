@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+	"dutch-learning-bot/internal/infrastructure/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// apiCall is one Bot API request received by fakeTelegramAPI
+type apiCall struct {
+	method string
+	params url.Values
+}
+
+// fakeTelegramAPI answers every Bot API call successfully and records the calls
+type fakeTelegramAPI struct {
+	mu    sync.Mutex
+	calls []apiCall
+}
+
+func (f *fakeTelegramAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseMultipartForm(1 << 20)
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{method: method, params: r.Form})
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "answerCallbackQuery", "answerInlineQuery":
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	default:
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot","message_id":1,"date":0,"chat":{"id":1,"type":"private"}}}`))
+	}
+}
+
+// callsTo returns the recorded calls of one Bot API method
+func (f *fakeTelegramAPI) callsTo(method string) []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []apiCall
+	for _, call := range f.calls {
+		if call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// newTestBotHandler creates a handler backed by a fresh database and a fake Bot API
+func newTestBotHandler(t *testing.T, config *HandlerConfig) (*BotHandler, *fakeTelegramAPI) {
+	t.Helper()
+
+	h, fake, _ := newTestBotHandlerWithDB(t, config)
+	return h, fake
+}
+
+// newTestBotHandlerWithDB is newTestBotHandler for tests that also need to seed the database
+func newTestBotHandlerWithDB(t *testing.T, config *HandlerConfig) (*BotHandler, *fakeTelegramAPI, *sql.DB) {
+	t.Helper()
+
+	fake := &fakeTelegramAPI{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("test-token", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatalf("failed to create bot API: %v", err)
+	}
+	bot := telegram.NewBotWithAPI(api)
+
+	db, err := persistence.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	userRepo := persistence.NewUserRepository(db)
+	learningRepo := persistence.NewLearningRepository(db)
+	userUseCase := usecases.NewUserUseCase(userRepo, persistence.NewUserPreferencesRepository(db), nil)
+	preferencesRepo := userUseCase.PreferencesRepository()
+	learningUseCase := usecases.NewLearningUseCase(learningRepo, persistence.NewVocabularyRepository(db), userRepo,
+		persistence.NewGrammarRepository(db), preferencesRepo, nil)
+	reminderUseCase := usecases.NewReminderUseCase(bot, userRepo, learningRepo, preferencesRepo, nil)
+
+	return NewBotHandler(bot, userUseCase, learningUseCase, reminderUseCase, preferencesRepo, config), fake, db
+}
+
+// newTestCallback creates a callback query from a test user on a bot message
+func newTestCallback(data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "callback-1",
+		From:    &tgbotapi.User{ID: 1001, FirstName: "Anna"},
+		Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: 1001}},
+		Data:    data,
+	}
+}
+
+func TestHandleCallbackQuery_UnknownIsAcknowledged(t *testing.T) {
+	tests := []struct {
+		name      string
+		recover   bool
+		wantEdits int
+	}{
+		{"with recovery keyboard", true, 1},
+		{"without recovery keyboard", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultHandlerConfig()
+			config.RecoverUnknownCallbacks = tt.recover
+			h, fake := newTestBotHandler(t, config)
+
+			h.handleCallbackQuery(context.Background(), newTestCallback("bogus_42"))
+
+			answers := fake.callsTo("answerCallbackQuery")
+			if len(answers) != 1 {
+				t.Fatalf("callback answered %d times, want 1", len(answers))
+			}
+			if got := answers[0].params.Get("text"); got != "This button is no longer available." {
+				t.Errorf("answer text = %q, want the out-of-date toast", got)
+			}
+			if got := len(fake.callsTo("editMessageText")); got != tt.wantEdits {
+				t.Errorf("message edited %d times, want %d", got, tt.wantEdits)
+			}
+		})
+	}
+}
+
+func TestHandleCallbackQuery_KnownIsAnsweredOnce(t *testing.T) {
+	// Routes that answer the callback themselves must not be answered upfront as well
+	for _, data := range []string{"noop", "choice", "rating", "back_nowhere"} {
+		t.Run(data, func(t *testing.T) {
+			h, fake := newTestBotHandler(t, nil)
+
+			h.handleCallbackQuery(context.Background(), newTestCallback(data))
+
+			if got := len(fake.callsTo("answerCallbackQuery")); got != 1 {
+				t.Errorf("callback %q answered %d times, want 1", data, got)
+			}
+		})
+	}
+}
+
+func TestCallbackRoutes_CoverKeyboardButtons(t *testing.T) {
+	// Callback data built by the keyboards must reach a route
+	for _, data := range []string{
+		"menu_learn", "choice_2", "rating_3", "reveal_answer", "confidence_sure", "resume_question",
+		"restart_learning", "continue_learning", "view_stats", "finish_session", "assess_known_5",
+		"study_verbs_action", usecases.ReminderLearnCallback, "practice_more", "snooze_5", "report_5",
+		"postpone_5_1d", "speak_5", "mute_5", "unmute_5", "reschedule_confirm", "confirm_reset",
+		"cancel_reset", "back_menu", "toggle_grammar_tips", "set_interval_15",
+	} {
+		prefix := strings.Split(data, "_")[0]
+		if _, ok := callbackRoutes[prefix]; !ok {
+			t.Errorf("no callback route for %q", data)
+		}
+	}
+}
+
+func TestReminderLearnCallback_ServesQuestion(t *testing.T) {
+	h, fake, db := newTestBotHandlerWithDB(t, nil)
+	vocabRepo := persistence.NewVocabularyRepository(db)
+	for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+		if err := vocabRepo.Save(context.Background(), vocabulary.NewWord(pair[0], pair[1], vocabulary.Category("basics"))); err != nil {
+			t.Fatalf("failed to save word: %v", err)
+		}
+	}
+
+	h.handleCallbackQuery(context.Background(), newTestCallback(usecases.ReminderLearnCallback))
+
+	edits := fake.callsTo("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("message edited %d times, want 1", len(edits))
+	}
+	if !strings.Contains(edits[0].params.Get("reply_markup"), "choice_") {
+		t.Errorf("reminder tap showed %q, want a question with answer options", edits[0].params.Get("text"))
+	}
+	if len(h.activeSessions) != 1 {
+		t.Errorf("%d active sessions, want the question's session", len(h.activeSessions))
+	}
+}
+
+func TestParseIntervalStep(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"15", 15, false},
+		{"-15", -15, false},
+		{"60", 60, false},
+		{"-60", -60, false},
+		{"1440", 1440, false},
+		{"-1440", -1440, false},
+		{"0", 0, true},
+		{"1441", 0, true},
+		{"-1441", 0, true},
+		{"", 0, true},
+		{"15min", 0, true},
+		{"--15", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseIntervalStep(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIntervalStep(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseIntervalStep(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustIntervalCallback(t *testing.T) {
+	ctx := context.Background()
+	h, _ := newTestBotHandler(t, nil)
+	u, err := h.userUseCase.GetOrCreateUser(ctx, 1001, "anna", "Anna", "", "en")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	interval := func() int {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+		if err != nil {
+			t.Fatalf("failed to load preferences: %v", err)
+		}
+		return prefs.GetReminderInterval()
+	}
+	start := interval()
+
+	for _, data := range []string{"set_interval_60", "set_interval_-15", "set_interval_bogus"} {
+		h.handleCallbackQuery(ctx, newTestCallback(data))
+	}
+
+	if got, want := interval(), start+45; got != want {
+		t.Errorf("reminder interval = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleCard processes the /card <term> command, showing FSRS scheduling details for a word
+func (h *BotHandler) handleCard(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	term := strings.TrimSpace(message.CommandArguments())
+	if term == "" {
+		h.bot.SendMessage(message.Chat.ID, "Usage: /card <dutch or english word>")
+		return
+	}
+
+	word, progress, err := h.learningUseCase.GetCardDetails(ctx, user.ID(), term)
+	if err != nil {
+		log.Printf("Failed to get card details for %q: %v", term, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error looking up that word. Please try again.")
+		return
+	}
+
+	if word == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 No word found matching \"%s\".", shared.EscapeMarkdown(term)))
+		return
+	}
+
+	if progress == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🆕 You haven't studied *%s* (%s) yet.",
+			shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English())))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, formatCardDetails(word, progress.FSRSCard(), time.Now()))
+}
+
+// handleWhy processes the /why <term> command, explaining why a word is due when it is
+func (h *BotHandler) handleWhy(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	term := strings.TrimSpace(message.CommandArguments())
+	if term == "" {
+		h.bot.SendMessage(message.Chat.ID, "Usage: /why <dutch or english word>")
+		return
+	}
+
+	word, explanation, err := h.learningUseCase.ExplainSchedule(ctx, user.ID(), term)
+	if err != nil {
+		log.Printf("Failed to explain schedule for %q: %v", term, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error looking up that word. Please try again.")
+		return
+	}
+
+	if word == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 No word found matching \"%s\".", shared.EscapeMarkdown(term)))
+		return
+	}
+
+	if explanation == "" {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🆕 You haven't studied *%s* (%s) yet, so it has no schedule.",
+			shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English())))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🧠 *%s* (%s)\n\n%s",
+		shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English()), explanation))
+}
+
+// handleSetDifficulty processes the /setdifficulty <term> <1-10> command
+func (h *BotHandler) handleSetDifficulty(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	const usage = "Usage: /setdifficulty <dutch or english word> <1-10>"
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) < 2 {
+		h.bot.SendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	// The term may contain spaces, so the difficulty is always the last argument.
+	// ParseFloat accepts "NaN", which fails every comparison, so the range check is written to reject it.
+	difficulty, err := strconv.ParseFloat(args[len(args)-1], 64)
+	if err != nil || !(difficulty >= learning.MinDifficulty && difficulty <= learning.MaxDifficulty) {
+		h.bot.SendMessage(message.Chat.ID, "Difficulty must be a number from 1 to 10. "+usage)
+		return
+	}
+	term := strings.Join(args[:len(args)-1], " ")
+
+	word, err := h.learningUseCase.SetWordDifficulty(ctx, user.ID(), term, difficulty)
+	if err != nil {
+		log.Printf("Failed to set difficulty for %q: %v", term, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating that word. Please try again.")
+		return
+	}
+
+	if word == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 You haven't studied a word matching \"%s\" yet.", shared.EscapeMarkdown(term)))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("✅ Difficulty of *%s* set to %.1f. It will shape the next interval after your next review.",
+		shared.EscapeMarkdown(word.Dutch()), difficulty))
+}
+
+// formatCardDetails formats a word's FSRS card state for display
+func formatCardDetails(word *vocabulary.Word, card *learning.FSRSCard, now time.Time) string {
+	lastReview := "never"
+	if !card.LastReview().IsZero() {
+		lastReview = card.LastReview().Format("2006-01-02 15:04")
+	}
+
+	due := card.DueDate().Format("2006-01-02 15:04")
+	if wait := card.DueDate().Sub(now); wait > 0 {
+		due += " (in " + shared.FormatInterval(wait) + ")"
+	} else {
+		due += " (now)"
+	}
+
+	stability := time.Duration(card.Stability() * float64(24*time.Hour))
+
+	return fmt.Sprintf("🗂 *Card: %s* (%s)\n\n"+
+		"State: %s\n"+
+		"Stability: %s\n"+
+		"Difficulty: %.2f / 10\n"+
+		"Retrievability: %.0f%%\n"+
+		"Reviews: %d\n"+
+		"Lapses: %d\n"+
+		"Last review: %s\n"+
+		"Due: %s",
+		shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English()),
+		card.State(), shared.FormatInterval(stability), card.Difficulty(), card.Retrievability(now)*100,
+		card.ReviewCount(), card.Lapses(), lastReview, due)
+}
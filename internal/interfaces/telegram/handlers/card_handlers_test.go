@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestFormatCardDetails(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	word := vocabulary.NewWord("house", "het_huis", vocabulary.Category("basics"))
+
+	card := learning.NewFSRSCard()
+	card.SetState(learning.StateReview)
+	card.SetStability(10)
+	card.SetDifficulty(4.25)
+	card.SetReviewCount(5)
+	card.SetLapses(1)
+	card.SetLastReview(now.Add(-10 * 24 * time.Hour))
+	card.SetDueDate(now.Add(2 * 24 * time.Hour))
+
+	text := formatCardDetails(word, card, now)
+	for _, want := range []string{
+		"*Card: het\\_huis* (house)",
+		"State: review",
+		"Stability: 10d",
+		"Difficulty: 4.25 / 10",
+		"Retrievability: 90%", // Elapsed time equals stability, so recall is at 90%
+		"Reviews: 5",
+		"Lapses: 1",
+		"Last review: 2024-03-10 12:00",
+		"Due: 2024-03-22 12:00 (in 2d)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("card details missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestFormatCardDetails_Unreviewed(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	word := vocabulary.NewWord("tree", "boom", vocabulary.Category("basics"))
+
+	card := learning.NewFSRSCard()
+	card.SetDueDate(now.Add(-time.Minute))
+
+	text := formatCardDetails(word, card, now)
+	for _, want := range []string{"State: new", "Retrievability: 0%", "Last review: never", "(now)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("card details missing %q:\n%s", want, text)
+		}
+	}
+}
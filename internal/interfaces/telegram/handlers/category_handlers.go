@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// categoryPickerText invites the user to pick a category, listing the names /learn accepts
+var categoryPickerText = func() string {
+	var names []string
+	for _, category := range vocabulary.Categories() {
+		names = append(names, shared.EscapeMarkdown(string(category)))
+	}
+	return "📂 Pick a category to drill, or send /learn <category> with one of: " + strings.Join(names, ", ")
+}()
+
+// handleMenuCategories shows the category picker from the main menu
+func (h *BotHandler) handleMenuCategories(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, categoryPickerText, shared.CreateCategoryKeyboard())
+}
+
+// handleStudyCategory starts a category session from a study_<category> button
+func (h *BotHandler) handleStudyCategory(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, category string) {
+	if !vocabulary.IsValidCategory(category) {
+		h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, categoryPickerText, shared.CreateCategoryKeyboard())
+		return
+	}
+	h.startCategoryLearning(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, vocabulary.Category(category), true, false)
+}
+
+// startCategoryLearning starts a session that only studies due and new words in one category;
+// a focus session shows no grammar tips
+func (h *BotHandler) startCategoryLearning(ctx context.Context, chatID int64, messageID int, user *user.User, category vocabulary.Category, isCallback, focus bool) {
+	if h.confirmActiveSession(chatID, messageID, user, isCallback) {
+		return
+	}
+
+	session, err := h.learningUseCase.GetNextDueWordInCategory(ctx, user.ID(), category, focus)
+	text := ""
+	switch {
+	case errors.Is(err, usecases.ErrTakeBreak):
+		text = takeBreakText
+	case err != nil:
+		log.Printf("Failed to get next word in category %s: %v", category, err)
+		text = "Sorry, there was an error getting your words. Please try again."
+	case session == nil:
+		text = fmt.Sprintf("🎉 No %s words are due right now. Pick another category, or check back later.",
+			shared.FormatCategory(category))
+	}
+	if text != "" {
+		if isCallback {
+			h.bot.EditMessageWithKeyboard(chatID, messageID, text, shared.CreateCategoryKeyboard())
+		} else {
+			h.bot.SendMessageWithKeyboard(chatID, text, shared.CreateCategoryKeyboard())
+		}
+		return
+	}
+
+	h.activeSessions[int64(user.ID())] = session
+	if isCallback {
+		h.sendQuestionAsEdit(chatID, messageID, session)
+	} else {
+		h.sendQuestion(chatID, session)
+	}
+	h.startQuestionTimeout(chatID, user, session)
+}
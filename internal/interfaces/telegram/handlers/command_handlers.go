@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
 	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
 )
 
@@ -16,7 +18,7 @@ func (h *BotHandler) handleStart(ctx context.Context, message *tgbotapi.Message,
 		"🇳🇱 Welcome to Dutch Learning Bot, %s!\n\n"+
 			"I'll help you learn Dutch using spaced repetition (FSRS algorithm).\n\n"+
 			"Choose an option below to get started:",
-		user.FirstName())
+		shared.EscapeMarkdown(user.DisplayName()))
 
 	h.bot.SendMessageWithKeyboard(message.Chat.ID, welcomeText, shared.CreateMainMenuKeyboard())
 }
@@ -27,11 +29,34 @@ func (h *BotHandler) handleMenu(ctx context.Context, message *tgbotapi.Message,
 	h.bot.SendMessageWithKeyboard(message.Chat.ID, menuText, shared.CreateMainMenuKeyboard())
 }
 
-// handleLearn processes the /learn command
+// handleLearn processes the /learn [tag] command
 func (h *BotHandler) handleLearn(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		h.startScopedLearning(ctx, message, user, args, false)
+		return
+	}
 	h.handleLearningFlow(ctx, message.Chat.ID, message.MessageID, user, false)
 }
 
+// startScopedLearning starts a session limited to the category or tag named in args
+func (h *BotHandler) startScopedLearning(ctx context.Context, message *tgbotapi.Message, user *user.User, args string, focus bool) {
+	// A category name wins over a tag of the same name
+	if category := strings.ToLower(args); vocabulary.IsValidCategory(category) {
+		h.startCategoryLearning(ctx, message.Chat.ID, message.MessageID, user, vocabulary.Category(category), false, focus)
+		return
+	}
+	h.handleTaggedLearning(ctx, message, user, args, focus)
+}
+
+// handleFocus processes the /focus [category|tag] command, starting a learning session without grammar tips
+func (h *BotHandler) handleFocus(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		h.startScopedLearning(ctx, message, user, args, true)
+		return
+	}
+	h.startLearningFlow(ctx, message.Chat.ID, message.MessageID, user, false, true)
+}
+
 // handleStats processes the /stats command
 func (h *BotHandler) handleStats(ctx context.Context, message *tgbotapi.Message, user *user.User) {
 	h.handleStatsFlow(ctx, message.Chat.ID, message.MessageID, user, false)
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// maxTipsPerDefinition caps the grammar tips shown under each word, to keep the reply short
+const maxTipsPerDefinition = 2
+
+// handleDefine processes the /define <word> command, looking up words without starting a review
+func (h *BotHandler) handleDefine(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	query := strings.TrimSpace(message.CommandArguments())
+	if query == "" {
+		h.bot.SendMessage(message.Chat.ID, "Usage: /define <dutch or english word>")
+		return
+	}
+
+	definitions, err := h.learningUseCase.DefineWord(ctx, query)
+	if err != nil {
+		log.Printf("Failed to define %q: %v", query, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error looking up that word. Please try again.")
+		return
+	}
+
+	if len(definitions) == 0 {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 No word found matching \"%s\".", shared.EscapeMarkdown(query)))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, formatDefinitions(definitions))
+}
+
+// formatDefinitions formats looked-up words with their category and grammar tips
+func formatDefinitions(definitions []*usecases.WordDefinition) string {
+	var b strings.Builder
+	b.WriteString("📖 **Dictionary**")
+
+	for _, definition := range definitions {
+		word := definition.Word
+		fmt.Fprintf(&b, "\n\n🇳🇱 *%s* — %s", shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English()))
+		if word.Sense() != "" {
+			fmt.Fprintf(&b, " (%s)", shared.EscapeMarkdown(word.Sense()))
+		}
+		fmt.Fprintf(&b, "\nCategory: %s", shared.EscapeMarkdown(string(word.Category())))
+
+		for i, tip := range definition.Tips {
+			if i == maxTipsPerDefinition {
+				break
+			}
+			fmt.Fprintf(&b, "\n🎯 **%s**: %s", tip.Title(), tip.Explanation())
+		}
+	}
+
+	return b.String()
+}
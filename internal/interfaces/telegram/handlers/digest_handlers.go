@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// handleDigest processes the /digest [hour|off] command, switching between smart reminders and a daily digest
+func (h *BotHandler) handleDigest(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	const usage = "Usage: /digest <hour 0-23> for one daily summary, or /digest off for smart reminders"
+
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if arg == "" {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+		if err != nil {
+			log.Printf("Failed to get preferences: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading your settings. Please try again.")
+			return
+		}
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("⏰ Reminders: %s\n\n%s", formatReminderMode(prefs), usage))
+		return
+	}
+
+	if arg == "off" {
+		if err := h.userUseCase.SetReminderMode(ctx, u.ID(), user.ReminderModeSmart); err != nil {
+			log.Printf("Failed to set reminder mode: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+			return
+		}
+		h.bot.SendMessage(message.Chat.ID, "⏰ Back to smart reminders throughout the day.")
+		return
+	}
+
+	hour, err := strconv.Atoi(strings.TrimSuffix(arg, ":00"))
+	if err != nil || hour < 0 || hour > 23 {
+		h.bot.SendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	if err := h.userUseCase.SetDailyDigest(ctx, u.ID(), hour); err != nil {
+		log.Printf("Failed to set daily digest: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🗞 You'll get one daily summary at %02d:00 instead of reminders throughout the day.", hour))
+}
+
+// formatReminderMode formats a user's reminder mode for display
+func formatReminderMode(prefs *user.UserPreferences) string {
+	if prefs.GetReminderMode() == user.ReminderModeDailyDigest {
+		return fmt.Sprintf("Daily digest at %02d:00", prefs.GetDigestHour())
+	}
+	return "Smart"
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// directionUsage explains the /direction command
+const directionUsage = "Usage: /direction <category> <mixed|to_dutch|from_dutch|off> (or /direction off)"
+
+// handleDirection processes the /direction [category direction|category off|off] command,
+// pinning the question direction of individual categories
+func (h *BotHandler) handleDirection(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	args := strings.Fields(strings.ToLower(message.CommandArguments()))
+
+	switch {
+	case len(args) == 0:
+		h.sendCategoryDirections(ctx, message.Chat.ID, u)
+		return
+	case len(args) == 1 && args[0] == "off":
+		if err := h.userUseCase.ClearCategoryDirections(ctx, u.ID()); err != nil {
+			log.Printf("Failed to clear category directions: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error saving your question directions. Please try again.")
+			return
+		}
+	case len(args) == 2:
+		category := args[0]
+		if !vocabulary.IsValidCategory(category) {
+			h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Unknown category %q.\n\n%s", category, directionUsage))
+			return
+		}
+
+		var direction user.QuestionDirection
+		if args[1] != "off" {
+			parsed, ok := user.ParseQuestionDirection(args[1])
+			if !ok {
+				h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Unknown direction %q.\n\n%s", args[1], directionUsage))
+				return
+			}
+			direction = parsed
+		}
+
+		if err := h.userUseCase.SetCategoryDirection(ctx, u.ID(), category, direction); err != nil {
+			log.Printf("Failed to set category direction: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error saving your question directions. Please try again.")
+			return
+		}
+	default:
+		h.bot.SendMessage(message.Chat.ID, directionUsage)
+		return
+	}
+
+	h.sendCategoryDirections(ctx, message.Chat.ID, u)
+}
+
+// sendCategoryDirections shows the user's overall question direction and any per-category overrides
+func (h *BotHandler) sendCategoryDirections(ctx context.Context, chatID int64, u *user.User) {
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error loading your question directions. Please try again.")
+		return
+	}
+
+	text := fmt.Sprintf("🔁 Question direction: %s", formatQuestionDirectionSetting(prefs.GetQuestionDirection()))
+
+	directions := prefs.GetCategoryDirections()
+	if len(directions) == 0 {
+		text += "\n\nNo categories are pinned. Pin one with /direction verbs to_dutch"
+		h.bot.SendMessage(chatID, text)
+		return
+	}
+
+	categories := make([]string, 0, len(directions))
+	for category := range directions {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	text += "\n\nPinned categories:"
+	for _, category := range categories {
+		text += fmt.Sprintf("\n• %s: %s", category, formatQuestionDirectionSetting(directions[category]))
+	}
+	text += "\n\nUnpin one with /direction <category> off, or all with /direction off"
+	h.bot.SendMessage(chatID, text)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// maxProgressImportSize caps the size of a progress CSV we download for import
+const maxProgressImportSize = 5 << 20
+
+// handleExport processes the /export [words|csv] command, sending the user's data as a JSON file,
+// or as a progress CSV that can be imported on another instance
+func (h *BotHandler) handleExport(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	args := strings.TrimSpace(message.CommandArguments())
+	if strings.EqualFold(args, "csv") {
+		h.handleExportProgressCSV(ctx, message, user)
+		return
+	}
+	includeWords := strings.EqualFold(args, "words")
+
+	export, err := h.learningUseCase.ExportUserData(ctx, user.ID(), includeWords)
+	if err != nil {
+		log.Printf("Failed to export user data: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your data. Please try again.")
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode user export: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your data. Please try again.")
+		return
+	}
+
+	caption := fmt.Sprintf("📦 Your learning data (%d words)", len(export.Progress))
+	if err := h.bot.SendDocument(message.Chat.ID, "dutch-learning-export.json", data, caption); err != nil {
+		log.Printf("Failed to send user export: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error sending your export. Please try again.")
+	}
+}
+
+// handleExportProgressCSV sends the user's FSRS cards as a CSV file for /export csv
+func (h *BotHandler) handleExportProgressCSV(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	data, err := h.learningUseCase.ExportProgressCSV(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to export progress CSV: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your progress. Please try again.")
+		return
+	}
+
+	caption := "📦 Your progress - send this file to the bot on another instance to restore it"
+	if err := h.bot.SendDocument(message.Chat.ID, "dutch-learning-progress.csv", data, caption); err != nil {
+		log.Printf("Failed to send progress CSV: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error sending your export. Please try again.")
+	}
+}
+
+// handleImportProgress restores progress from a CSV file made with /export csv
+func (h *BotHandler) handleImportProgress(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	doc := message.Document
+	if !strings.HasSuffix(strings.ToLower(doc.FileName), ".csv") {
+		h.bot.SendMessage(message.Chat.ID, "To restore your progress, send the CSV file from /export csv.")
+		return
+	}
+	if doc.FileSize > maxProgressImportSize {
+		h.bot.SendMessage(message.Chat.ID, "That file is too large to be a progress export.")
+		return
+	}
+
+	data, err := h.bot.DownloadFile(ctx, doc.FileID)
+	if err != nil {
+		log.Printf("Failed to download progress CSV: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error reading your file. Please try again.")
+		return
+	}
+
+	result, err := h.learningUseCase.ImportProgressCSV(ctx, user.ID(), data)
+	if err != nil {
+		log.Printf("Failed to import progress CSV: %v", err)
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Sorry, that progress couldn't be restored: %v", err))
+		return
+	}
+
+	text := fmt.Sprintf("✅ Restored progress for %d words.", result.Imported)
+	if result.Skipped > 0 {
+		text += fmt.Sprintf("\n%d words aren't in this bot's vocabulary and were skipped.", result.Skipped)
+	}
+	h.bot.SendMessage(message.Chat.ID, text)
+}
+
+// handleExportStats processes the /export_stats command, sending per-word progress as a CSV file
+func (h *BotHandler) handleExportStats(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	export, err := h.learningUseCase.ExportUserData(ctx, user.ID(), true)
+	if err != nil {
+		log.Printf("Failed to export stats: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your stats. Please try again.")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteCSV(&buf); err != nil {
+		log.Printf("Failed to encode stats export: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your stats. Please try again.")
+		return
+	}
+
+	caption := fmt.Sprintf("📊 Your word stats (%d words)", len(export.Progress))
+	if err := h.bot.SendDocument(message.Chat.ID, "dutch-learning-stats.csv", buf.Bytes(), caption); err != nil {
+		log.Printf("Failed to send stats export: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error sending your stats. Please try again.")
+	}
+}
+
+// handleExportSettings processes the /export_settings command, sending the user's settings as JSON
+func (h *BotHandler) handleExportSettings(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	settings, err := h.userUseCase.ExportSettings(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to export settings: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your settings. Please try again.")
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		log.Printf("Failed to encode settings export: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error exporting your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf(
+		"⚙️ Your settings - to restore them, send:\n\n```\n/import_settings %s\n```", data))
+}
+
+// handleImportSettings processes the /import_settings <json> command, restoring exported settings
+func (h *BotHandler) handleImportSettings(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	const usage = "Usage: /import_settings <settings JSON from /export_settings>"
+
+	// Some clients turn straight quotes into curly ones when pasting
+	args := strings.NewReplacer("“", "\"", "”", "\"").Replace(strings.TrimSpace(message.CommandArguments()))
+	if args == "" {
+		h.bot.SendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	var settings map[string]string
+	if err := json.Unmarshal([]byte(args), &settings); err != nil {
+		h.bot.SendMessage(message.Chat.ID, "That doesn't look like exported settings. "+usage)
+		return
+	}
+
+	if err := h.userUseCase.ImportSettings(ctx, user.ID(), settings); err != nil {
+		log.Printf("Failed to import settings: %v", err)
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Sorry, those settings couldn't be restored: %v", err))
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("✅ Restored %d settings. Check them with /settings", len(settings)))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// forecastDays is how many days /forecast looks ahead, starting today
+const forecastDays = 7
+
+// forecastBarWidth is the length of the bar for the busiest day
+const forecastBarWidth = 12
+
+// handleForecast processes the /forecast command, charting how many reviews come due each day this week
+func (h *BotHandler) handleForecast(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	forecast, err := h.learningUseCase.GetReviewForecast(ctx, user.ID(), forecastDays)
+	if err != nil {
+		log.Printf("Failed to get review forecast: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error getting your forecast. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, formatForecast(forecast))
+}
+
+// formatForecast renders the forecast as a bar chart with one line per day, the bars scaled to the busiest day
+func formatForecast(forecast []learning.ForecastDay) string {
+	busiest, total := 0, 0
+	for _, day := range forecast {
+		busiest = max(busiest, day.Count)
+		total += day.Count
+	}
+	if total == 0 {
+		return "📅 Nothing comes due in the next week. Use /learn to start on new words!"
+	}
+
+	var chart strings.Builder
+	for _, day := range forecast {
+		bar := strings.Repeat("█", (day.Count*forecastBarWidth+busiest-1)/busiest)
+		fmt.Fprintf(&chart, "%s %-*s %d\n", day.Day.Format("Mon"), forecastBarWidth, bar, day.Count)
+	}
+
+	return fmt.Sprintf("📅 **Review forecast**\n\n```\n%s```\n_The first line is today, including overdue reviews._", chart.String())
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// fsrsWeightsUsage explains the /fsrs_weights arguments
+var fsrsWeightsUsage = fmt.Sprintf("Usage: /fsrs_weights [w0, w1, ..., w%d] to set your own weights, or /fsrs_weights reset", learning.FSRSWeightCount-1)
+
+// handleFSRSWeights processes the /fsrs_weights command, showing or replacing the user's FSRS weights
+func (h *BotHandler) handleFSRSWeights(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		h.sendFSRSWeights(ctx, message.Chat.ID, u)
+		return
+	}
+
+	var params *learning.FSRSParams
+	if !strings.EqualFold(arg, "reset") {
+		parsed, err := learning.ParseFSRSParams(arg)
+		if err != nil {
+			h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid weights: %v\n\n%s", err, fsrsWeightsUsage))
+			return
+		}
+		params = parsed
+	}
+
+	if err := h.userUseCase.SetFSRSWeights(ctx, u.ID(), params); err != nil {
+		log.Printf("Failed to set FSRS weights: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	if params == nil {
+		h.bot.SendMessage(message.Chat.ID, "🧮 Your reviews are scheduled with the default FSRS weights again.")
+		return
+	}
+	h.bot.SendMessage(message.Chat.ID, "🧮 Your next reviews will be scheduled with your custom FSRS weights.")
+}
+
+// sendFSRSWeights shows the weights the user's reviews are scheduled with
+func (h *BotHandler) sendFSRSWeights(ctx context.Context, chatID int64, u *user.User) {
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error loading your settings. Please try again.")
+		return
+	}
+
+	label := "default"
+	params := learning.DefaultFSRSParams()
+	if custom, err := learning.ParseFSRSParams(prefs.GetFSRSWeights()); prefs.GetFSRSWeights() != "" && err == nil {
+		label = "custom"
+		params = custom
+	}
+
+	h.bot.SendMessage(chatID, fmt.Sprintf("🧮 Your reviews use the %s FSRS weights:\n\n%s\n\n%s",
+		label, params, fsrsWeightsUsage))
+}
+
+// learningStepsUsage explains the /learning_steps arguments
+var learningStepsUsage = fmt.Sprintf("Usage: /learning_steps 1m,10m to set up to %d steps, or /learning_steps reset", learning.MaxLearningSteps)
+
+// handleLearningSteps processes the /learning_steps command, showing or replacing the user's learning steps
+func (h *BotHandler) handleLearningSteps(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		h.sendLearningSteps(ctx, message.Chat.ID, u)
+		return
+	}
+
+	var steps []time.Duration
+	if !strings.EqualFold(arg, "reset") {
+		parsed, err := learning.ParseLearningSteps(arg)
+		if err != nil {
+			h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid learning steps: %v\n\n%s", err, learningStepsUsage))
+			return
+		}
+		steps = parsed
+	}
+
+	if err := h.userUseCase.SetLearningSteps(ctx, u.ID(), steps); err != nil {
+		log.Printf("Failed to set learning steps: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	if steps == nil {
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🪜 New words go through the default learning steps again: %s",
+			learning.FormatLearningSteps(learning.DefaultLearningSteps())))
+		return
+	}
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🪜 New words now go through %s before their first review.",
+		learning.FormatLearningSteps(steps)))
+}
+
+// sendLearningSteps shows the learning steps the user's new words go through
+func (h *BotHandler) sendLearningSteps(ctx context.Context, chatID int64, u *user.User) {
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error loading your settings. Please try again.")
+		return
+	}
+
+	label := "default"
+	steps := learning.DefaultLearningSteps()
+	if custom, err := learning.ParseLearningSteps(prefs.GetLearningSteps()); prefs.GetLearningSteps() != "" && err == nil {
+		label = "custom"
+		steps = custom
+	}
+
+	h.bot.SendMessage(chatID, fmt.Sprintf("🪜 Your new words use the %s learning steps: %s\n\n"+
+		"Each Good answer moves a word to the next step, and a Good answer on the last step schedules its first review.\n\n%s",
+		label, learning.FormatLearningSteps(steps), learningStepsUsage))
+}
+
+// retentionUsage explains the /retention arguments
+var retentionUsage = fmt.Sprintf("Usage: /retention %.2f-%.2f to set your target retention, or /retention reset. "+
+	"A lower retention means longer intervals and fewer reviews, but more forgotten words.",
+	learning.MinRequestRetention, learning.MaxRequestRetention)
+
+// handleRetention processes the /retention command, showing or replacing the user's target retention
+func (h *BotHandler) handleRetention(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		h.sendRetention(ctx, message.Chat.ID, u)
+		return
+	}
+
+	var retention float64
+	if !strings.EqualFold(arg, "reset") {
+		parsed, err := learning.ParseRequestRetention(arg)
+		if err != nil {
+			h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid retention: %v\n\n%s", err, retentionUsage))
+			return
+		}
+		retention = parsed
+	}
+
+	if err := h.userUseCase.SetRequestRetention(ctx, u.ID(), retention); err != nil {
+		log.Printf("Failed to set request retention: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	if retention == 0 {
+		retention = learning.DefaultRequestRetention
+	}
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🎯 Your next reviews will be scheduled for %.0f%% retention. "+
+		"Send /reschedule to apply it to the words you've already learned.", retention*100))
+}
+
+// sendRetention shows the target retention the user's reviews are scheduled for
+func (h *BotHandler) sendRetention(ctx context.Context, chatID int64, u *user.User) {
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error loading your settings. Please try again.")
+		return
+	}
+
+	label := "default"
+	retention := learning.DefaultRequestRetention
+	if custom, err := learning.ParseRequestRetention(prefs.GetRequestRetention()); prefs.GetRequestRetention() != "" && err == nil {
+		label = "custom"
+		retention = custom
+	}
+
+	h.bot.SendMessage(chatID, fmt.Sprintf("🎯 Your reviews are scheduled for the %s retention of %.0f%%.\n\n%s",
+		label, retention*100, retentionUsage))
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	"dutch-learning-bot/internal/application/usecases"
 	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
 )
@@ -41,9 +43,51 @@ func (h *BotHandler) handleHelpFlow(ctx context.Context, chatID int64, messageID
 	}
 }
 
+// takeBreakText is shown when only just-reviewed words are left to study
+const takeBreakText = "☕ You've just reviewed everything that's due. Take a short break and come back in a few minutes!"
+
+// sessionInProgressText is shown when starting a session would replace an unfinished question
+const sessionInProgressText = "✋ You have a question in progress. Continue where you left off, or start over with a new question?"
+
+// confirmActiveSession asks the user what to do with an unfinished question instead of silently replacing it.
+// It returns true if the prompt was shown and the caller should not start a new session.
+func (h *BotHandler) confirmActiveSession(chatID int64, messageID int, user *user.User, isCallback bool) bool {
+	if !h.config.ConfirmSessionRestart {
+		return false
+	}
+	if _, exists := h.activeSessions[int64(user.ID())]; !exists {
+		return false
+	}
+
+	keyboard := shared.CreateSessionInProgressKeyboard()
+	if isCallback {
+		h.bot.EditMessageWithKeyboard(chatID, messageID, sessionInProgressText, keyboard)
+	} else {
+		h.bot.SendMessageWithKeyboard(chatID, sessionInProgressText, keyboard)
+	}
+	return true
+}
+
 // handleLearningFlow handles starting learning for both commands and callbacks
 func (h *BotHandler) handleLearningFlow(ctx context.Context, chatID int64, messageID int, user *user.User, isCallback bool) {
-	session, err := h.learningUseCase.GetNextDueWord(ctx, user.ID())
+	h.startLearningFlow(ctx, chatID, messageID, user, isCallback, false)
+}
+
+// startLearningFlow starts a learning session; a focus session shows no grammar tips
+func (h *BotHandler) startLearningFlow(ctx context.Context, chatID int64, messageID int, user *user.User, isCallback, focus bool) {
+	if h.confirmActiveSession(chatID, messageID, user, isCallback) {
+		return
+	}
+
+	session, err := h.learningUseCase.GetNextDueWord(ctx, user.ID(), focus)
+	if errors.Is(err, usecases.ErrTakeBreak) {
+		if isCallback {
+			h.bot.EditMessageWithKeyboard(chatID, messageID, takeBreakText, shared.CreateNoWordsKeyboard())
+		} else {
+			h.bot.SendMessageWithKeyboard(chatID, takeBreakText, shared.CreateNoWordsKeyboard())
+		}
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to get next due word: %v", err)
 		if isCallback {
@@ -56,6 +100,11 @@ func (h *BotHandler) handleLearningFlow(ctx context.Context, chatID int64, messa
 
 	if session == nil {
 		noWordsText := "🎉 Great job! You have no words due for review right now. Check back later!"
+		if prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID()); err == nil && prefs.ReviewsOnly() {
+			noWordsText += "\n\nReviews Only is on, so no new words are introduced. Turn it off in /settings to learn new words."
+		} else if h.learningUseCase.DailyNewLimitReached(ctx, user.ID()) {
+			noWordsText += "\n\nYou've met today's limit for new words, so more come tomorrow. You can raise the limit in /settings."
+		}
 		keyboard := shared.CreateNoWordsKeyboard()
 
 		if isCallback {
@@ -75,4 +124,5 @@ func (h *BotHandler) handleLearningFlow(ctx context.Context, chatID int64, messa
 	} else {
 		h.sendQuestion(chatID, session)
 	}
+	h.startQuestionTimeout(chatID, user, session)
 }
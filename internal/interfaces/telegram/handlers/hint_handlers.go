@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// handleHint processes the /hint <type> command, choosing the hint shown with questions
+func (h *BotHandler) handleHint(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+
+	hintType, ok := user.ParseHintType(arg)
+	if !ok {
+		options := make([]string, 0, len(user.HintTypes))
+		for _, t := range user.HintTypes {
+			options = append(options, string(t))
+		}
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Usage: /hint <%s>", strings.Join(options, "|")))
+		return
+	}
+
+	if err := h.userUseCase.SetHintType(ctx, u.ID(), hintType); err != nil {
+		log.Printf("Failed to set hint type: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("💡 Questions will now show this hint: %s", formatHintType(hintType)))
+}
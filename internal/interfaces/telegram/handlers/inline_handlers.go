@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// maxInlineResults caps how many words an inline query answers with
+const maxInlineResults = 10
+
+// inlineCacheSeconds is how long Telegram may reuse an inline answer for the same query.
+// The vocabulary rarely changes, so a short cache saves a search on every keystroke.
+const inlineCacheSeconds = 300
+
+// handleInlineQuery answers an "@bot word" query from any chat with matching translations.
+// Lookups don't need an account, so the user isn't created here.
+func (h *BotHandler) handleInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery) {
+	text := strings.TrimSpace(query.Query)
+	var words []*vocabulary.Word
+	if text != "" {
+		var err error
+		words, err = h.learningUseCase.SearchWords(ctx, text, maxInlineResults)
+		if err != nil {
+			log.Printf("Failed to search words for inline query %q: %v", text, err)
+			return
+		}
+	}
+
+	if err := h.bot.AnswerInlineQuery(query.ID, buildInlineResults(words), inlineCacheSeconds); err != nil {
+		log.Printf("Failed to answer inline query: %v", err)
+	}
+}
+
+// buildInlineResults turns matched words into inline articles that each send the translation as plain text
+func buildInlineResults(words []*vocabulary.Word) []interface{} {
+	results := make([]interface{}, 0, len(words))
+	for _, word := range words {
+		translation := word.English()
+		if word.Sense() != "" {
+			translation += fmt.Sprintf(" (%s)", word.Sense())
+		}
+		category := shared.FormatCategory(word.Category())
+
+		article := tgbotapi.NewInlineQueryResultArticle(fmt.Sprintf("word_%d", word.ID()),
+			fmt.Sprintf("%s — %s", word.Dutch(), translation),
+			fmt.Sprintf("🇳🇱 %s — 🇬🇧 %s\nCategory: %s", word.Dutch(), translation, category))
+		article.Description = "Category: " + category
+		results = append(results, article)
+	}
+	return results
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+func TestBuildInlineResults(t *testing.T) {
+	newWord := func(id vocabulary.ID, english, dutch, sense string, category vocabulary.Category) *vocabulary.Word {
+		word := vocabulary.NewWord(english, dutch, category)
+		word.SetID(id)
+		word.SetSense(sense)
+		return word
+	}
+
+	tests := []struct {
+		name            string
+		word            *vocabulary.Word
+		wantID          string
+		wantTitle       string
+		wantMessage     string
+		wantDescription string
+	}{
+		{"plain word", newWord(7, "house", "huis", "", "basics"),
+			"word_7", "huis — house", "🇳🇱 huis — 🇬🇧 house\nCategory: basics", "Category: basics"},
+		{"word with a sense", newWord(12, "bank", "oever", "of a river", "nature"),
+			"word_12", "oever — bank (of a river)", "🇳🇱 oever — 🇬🇧 bank (of a river)\nCategory: nature", "Category: nature"},
+		{"multi-word category", newWord(3, "ticket", "kaartje", "", "public_transport"),
+			"word_3", "kaartje — ticket", "🇳🇱 kaartje — 🇬🇧 ticket\nCategory: public transport", "Category: public transport"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := buildInlineResults([]*vocabulary.Word{tt.word})
+			if len(results) != 1 {
+				t.Fatalf("%d results, want 1", len(results))
+			}
+			article, ok := results[0].(tgbotapi.InlineQueryResultArticle)
+			if !ok {
+				t.Fatalf("result is a %T, want an article", results[0])
+			}
+			if article.ID != tt.wantID || article.Title != tt.wantTitle || article.Description != tt.wantDescription {
+				t.Errorf("article = %q, %q, %q; want %q, %q, %q",
+					article.ID, article.Title, article.Description, tt.wantID, tt.wantTitle, tt.wantDescription)
+			}
+			content, ok := article.InputMessageContent.(tgbotapi.InputTextMessageContent)
+			if !ok {
+				t.Fatalf("message content is a %T, want text", article.InputMessageContent)
+			}
+			if content.Text != tt.wantMessage || content.ParseMode != "" {
+				t.Errorf("message = %q with parse mode %q, want plain %q", content.Text, content.ParseMode, tt.wantMessage)
+			}
+		})
+	}
+
+	t.Run("no matches", func(t *testing.T) {
+		results := buildInlineResults(nil)
+		if results == nil || len(results) != 0 {
+			t.Errorf("results = %v, want an empty list so Telegram clears the suggestions", results)
+		}
+	})
+
+	t.Run("keeps the search order", func(t *testing.T) {
+		results := buildInlineResults([]*vocabulary.Word{
+			newWord(2, "tree", "boom", "", "nature"),
+			newWord(1, "house", "huis", "", "basics"),
+		})
+		var ids []string
+		for _, result := range results {
+			ids = append(ids, result.(tgbotapi.InlineQueryResultArticle).ID)
+		}
+		if len(ids) != 2 || ids[0] != "word_2" || ids[1] != "word_1" {
+			t.Errorf("result IDs = %v, want [word_2 word_1]", ids)
+		}
+	})
+}
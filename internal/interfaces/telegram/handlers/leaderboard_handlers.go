@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// leaderboardSize is how many users /leaderboard lists
+const leaderboardSize = 10
+
+// leaderboardMedals mark the top three places
+var leaderboardMedals = []string{"🥇", "🥈", "🥉"}
+
+// handleLeaderboard processes the /leaderboard [hide|show] command, listing this week's most active
+// learners or changing whether the user appears on the list
+func (h *BotHandler) handleLeaderboard(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	switch arg {
+	case "":
+	case "hide", "show":
+		visible := arg == "show"
+		if err := h.userUseCase.SetLeaderboardVisible(ctx, user.ID(), visible); err != nil {
+			log.Printf("Failed to set leaderboard visibility: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error saving your setting. Please try again.")
+			return
+		}
+		if visible {
+			h.bot.SendMessage(message.Chat.ID, "👀 You're on the leaderboard again. Hide yourself any time with /leaderboard hide")
+		} else {
+			h.bot.SendMessage(message.Chat.ID, "🙈 You're hidden from the leaderboard. Join in again with /leaderboard show")
+		}
+		return
+	default:
+		h.bot.SendMessage(message.Chat.ID, "Usage: /leaderboard, /leaderboard hide or /leaderboard show")
+		return
+	}
+
+	entries, err := h.learningUseCase.GetWeeklyLeaderboard(ctx, leaderboardSize)
+	if err != nil {
+		log.Printf("Failed to get leaderboard: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading the leaderboard. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, formatLeaderboard(entries, user.ID()))
+}
+
+// formatLeaderboard renders the weekly leaderboard as plain text, marking the user's own place
+func formatLeaderboard(entries []*learning.LeaderboardEntry, userID user.ID) string {
+	if len(entries) == 0 {
+		return "🏆 Nobody has reviewed any words this week yet. Start with /learn and take the top spot!"
+	}
+
+	var b strings.Builder
+	b.WriteString("🏆 Most reviews in the last 7 days\n\n")
+	for i, entry := range entries {
+		place := fmt.Sprintf("%d.", i+1)
+		if i < len(leaderboardMedals) {
+			place = leaderboardMedals[i]
+		}
+
+		name := entry.FirstName
+		if name == "" {
+			name = "Anonymous learner"
+		}
+		if entry.UserID == userID {
+			name += " (you)"
+		}
+
+		fmt.Fprintf(&b, "%s %s - %d reviews\n", place, name, entry.Reviews)
+	}
+	b.WriteString("\nRather not be listed? Send /leaderboard hide")
+	return b.String()
+}
@@ -2,18 +2,24 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/grammar"
 	"dutch-learning-bot/internal/domain/learning"
 	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
 	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
 )
 
@@ -152,20 +158,55 @@ func createKeyboardForOptionsWithEscaping(options []string, isForPhrase bool) tg
 	}
 }
 
+// formatHint renders the session's hint about the expected answer, or "" when hints are off
+func formatHint(session *usecases.LearningSession) string {
+	answer := []rune(session.ExpectedAnswer())
+
+	switch session.HintType {
+	case user.HintTypeNone:
+		return ""
+	case user.HintTypeFirstLetter:
+		if len(answer) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("Starts with: %s", string(answer[0]))
+	case user.HintTypeLength:
+		letters := 0
+		for _, r := range answer {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+		return fmt.Sprintf("Letters: %d", letters)
+	default:
+		return fmt.Sprintf("Category: %s", session.Word.Category())
+	}
+}
+
+// formatQuestionDirection formats the "translate to" line for a question type
+func formatQuestionDirection(questionType usecases.QuestionType) string {
+	prompt, answer := questionType.PromptLanguage(), questionType.AnswerLanguage()
+	return fmt.Sprintf("%s➡️%s Translate to %s:", prompt.Flag, answer.Flag, answer.Name)
+}
+
 // sendQuestion sends a learning question to the user
 func (h *BotHandler) sendQuestion(chatID int64, session *usecases.LearningSession) {
+	if session.Word == nil {
+		log.Printf("Refusing to send a question without a word to chat %d", chatID)
+		h.bot.SendMessage(chatID, missingWordText)
+		return
+	}
+
 	var questionText string
 	var hintText string
 
-	if session.QuestionType == usecases.QuestionTypeEnglishToDutch {
-		questionText = fmt.Sprintf("🇬🇧➡️🇳🇱 Translate to Dutch:\n\n**%s**", session.Word.English())
-		hintText = fmt.Sprintf("Category: %s", session.Word.Category())
-	} else {
-		questionText = fmt.Sprintf("🇳🇱➡️🇬🇧 Translate to English:\n\n**%s**", session.Word.Dutch())
-		hintText = fmt.Sprintf("Category: %s", session.Word.Category())
-	}
+	questionText = fmt.Sprintf("%s\n\n**%s**", formatQuestionDirection(session.QuestionType), session.Prompt())
+	hintText = formatHint(session)
 
-	fullText := fmt.Sprintf("%s\n\n💡 %s", questionText, hintText)
+	fullText := questionText
+	if hintText != "" {
+		fullText += fmt.Sprintf("\n\n💡 %s", hintText)
+	}
 
 	// Add grammar tip if present (surprise feature!)
 	if session.GrammarTip != nil {
@@ -179,29 +220,45 @@ func (h *BotHandler) sendQuestion(chatID int64, session *usecases.LearningSessio
 		}
 	}
 
-	fullText += "\n\nChoose the correct translation:"
+	if session.Typed {
+		fullText += typedAnswerPrompt
+		h.bot.SendMessageWithMarkdown(chatID, fullText)
+	} else {
+		fullText += "\n\nChoose the correct translation:"
 
-	// Create keyboard based on whether the word is a phrase (check both English and Dutch)
-	phraseMode := isPhrase(session.Word.English()) || isPhrase(session.Word.Dutch())
-	keyboard := createKeyboardForOptions(session.Options, phraseMode)
+		// Create keyboard based on whether the word is a phrase (check both English and Dutch)
+		phraseMode := isPhrase(session.Word.English()) || isPhrase(session.Word.Dutch())
+		keyboard := createKeyboardForOptions(session.Options, phraseMode)
 
-	h.bot.SendMessageWithKeyboard(chatID, fullText, keyboard)
+		h.bot.SendMessageWithKeyboard(chatID, fullText, keyboard)
+	}
+	h.sendGrammarTipMedia(chatID, session.GrammarTip)
 }
 
+// missingWordText is shown if a question's word disappeared before it could be asked
+const missingWordText = "Sorry, that word is no longer available. Please continue with /learn"
+
+// typedAnswerPrompt closes a question answered by typing instead of picking an option
+const typedAnswerPrompt = "\n\n✍️ Type the translation and send it as a message:"
+
 // sendQuestionAsEdit sends a learning question by editing an existing message
 func (h *BotHandler) sendQuestionAsEdit(chatID int64, messageID int, session *usecases.LearningSession) {
+	if session.Word == nil {
+		log.Printf("Refusing to send a question without a word to chat %d", chatID)
+		h.bot.EditMessage(chatID, messageID, missingWordText)
+		return
+	}
+
 	var questionText string
 	var hintText string
 
-	if session.QuestionType == usecases.QuestionTypeEnglishToDutch {
-		questionText = fmt.Sprintf("🇬🇧➡️🇳🇱 Translate to Dutch:\n\n*%s*", shared.EscapeMarkdown(session.Word.English()))
-		hintText = fmt.Sprintf("Category: %s", shared.EscapeMarkdown(string(session.Word.Category())))
-	} else {
-		questionText = fmt.Sprintf("🇳🇱➡️🇬🇧 Translate to English:\n\n*%s*", shared.EscapeMarkdown(session.Word.Dutch()))
-		hintText = fmt.Sprintf("Category: %s", shared.EscapeMarkdown(string(session.Word.Category())))
-	}
+	questionText = fmt.Sprintf("%s\n\n*%s*", formatQuestionDirection(session.QuestionType), shared.EscapeMarkdown(session.Prompt()))
+	hintText = shared.EscapeMarkdown(formatHint(session))
 
-	fullText := fmt.Sprintf("%s\n\n💡 %s", questionText, hintText)
+	fullText := questionText
+	if hintText != "" {
+		fullText += fmt.Sprintf("\n\n💡 %s", hintText)
+	}
 
 	// Add grammar tip if present (surprise feature!)
 	if session.GrammarTip != nil {
@@ -217,11 +274,18 @@ func (h *BotHandler) sendQuestionAsEdit(chatID int64, messageID int, session *us
 		}
 	}
 
-	fullText += "\n\nChoose the correct translation:"
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if session.Typed {
+		fullText += typedAnswerPrompt
+		// An empty keyboard removes the buttons of the message being replaced
+		keyboard = tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	} else {
+		fullText += "\n\nChoose the correct translation:"
 
-	// Create keyboard based on whether the word is a phrase (check both English and Dutch)
-	phraseMode := isPhrase(session.Word.English()) || isPhrase(session.Word.Dutch())
-	keyboard := createKeyboardForOptionsWithEscaping(session.Options, phraseMode)
+		// Create keyboard based on whether the word is a phrase (check both English and Dutch)
+		phraseMode := isPhrase(session.Word.English()) || isPhrase(session.Word.Dutch())
+		keyboard = createKeyboardForOptionsWithEscaping(session.Options, phraseMode)
+	}
 
 	log.Printf("Sending question: %s", fullText)
 	err := h.bot.EditMessageWithKeyboard(chatID, messageID, fullText, keyboard)
@@ -229,11 +293,41 @@ func (h *BotHandler) sendQuestionAsEdit(chatID int64, messageID int, session *us
 		log.Printf("Failed to send question: %v", err)
 		// Try to send error message
 		h.bot.EditMessage(chatID, messageID, "Sorry, there was an error displaying the question. Please try again with /learn")
+		return
+	}
+	h.sendGrammarTipMedia(chatID, session.GrammarTip)
+}
+
+// sendGrammarTipMedia sends a grammar tip's optional image and audio examples after its text
+func (h *BotHandler) sendGrammarTipMedia(chatID int64, tip *grammar.GrammarTip) {
+	if tip == nil || !tip.HasMedia() {
+		return
+	}
+
+	caption := "🎯 " + tip.Title()
+	if tip.ImageURL() != "" {
+		if err := h.bot.SendPhoto(chatID, tip.ImageURL(), caption); err != nil {
+			log.Printf("Failed to send grammar tip image: %v", err)
+		}
+	}
+	if tip.AudioURL() != "" {
+		if err := h.bot.SendAudio(chatID, tip.AudioURL(), caption); err != nil {
+			log.Printf("Failed to send grammar tip audio: %v", err)
+		}
 	}
 }
 
 // handleMultipleChoice processes multiple choice selection
 func (h *BotHandler) handleMultipleChoice(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, choiceStr string) {
+	// The callback wasn't answered upfront, so the verdict can show as a toast before the message
+	// is edited; any path that returns before that still stops the button spinning
+	answered := false
+	defer func() {
+		if !answered {
+			h.answerCallback(callback, "")
+		}
+	}()
+
 	// Debounce rapid clicks
 	userID := int64(user.ID())
 	if globalClickTracker.isRecentClick(userID, "choice_"+choiceStr) {
@@ -254,15 +348,171 @@ func (h *BotHandler) handleMultipleChoice(ctx context.Context, callback *tgbotap
 		return
 	}
 
+	// Ignore answers to a question that was already answered or timed out
+	if !session.ClaimAnswer() {
+		log.Printf("Ignoring answer from user %d to an already answered question", userID)
+		return
+	}
+
 	// Check if the answer is correct
 	isCorrect := h.learningUseCase.CheckMultipleChoiceAnswer(session, choiceIndex)
+	session.SelectedIndex = choiceIndex
+	session.AnswerCorrect = isCorrect
+	session.AnswerScore = learning.ScoreWrong
+	if isCorrect {
+		session.AnswerScore = learning.ScoreCorrect
+	}
+	session.RecordAnswer(isCorrect)
+
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+	}
+
+	awaitConfidence := prefs != nil && prefs.RateConfidence() && !session.Practice
+	h.answerCallback(callback, choiceToast(isCorrect, awaitConfidence))
+	answered = true
+
+	// Ask how sure the user was before giving away whether they were right
+	if awaitConfidence {
+		session.AwaitingConfidence = true
+		h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, confidenceText, createConfidenceKeyboard())
+		return
+	}
+
+	h.continueAfterAnswer(ctx, callback, user, session, prefs)
+}
+
+// choiceToast is the toast shown on a multiple-choice button as soon as it's tapped. It's empty
+// while a confidence rating is pending, since that is asked before the verdict is given away.
+func choiceToast(isCorrect, awaitingConfidence bool) string {
+	switch {
+	case awaitingConfidence:
+		return ""
+	case isCorrect:
+		return "✅ Correct!"
+	default:
+		return "❌ Oops"
+	}
+}
+
+// confidenceText asks for the confidence rating of an answer
+const confidenceText = "🤔 How sure were you of your answer?"
+
+// createConfidenceKeyboard creates the confidence rating buttons
+func createConfidenceKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("😬 Guessed", fmt.Sprintf("confidence_%d", learning.ConfidenceLow)),
+			tgbotapi.NewInlineKeyboardButtonData("🙂 Fairly sure", fmt.Sprintf("confidence_%d", learning.ConfidenceMedium)),
+			tgbotapi.NewInlineKeyboardButtonData("😎 Certain", fmt.Sprintf("confidence_%d", learning.ConfidenceHigh)),
+		),
+	)
+}
+
+// handleConfidence records how sure the user was of their answer and shows the result
+func (h *BotHandler) handleConfidence(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, levelStr string) {
+	session, exists := h.activeSessions[int64(user.ID())]
+	if !exists || !session.AwaitingConfidence {
+		return
+	}
+
+	level, err := strconv.Atoi(levelStr)
+	if err != nil || !learning.Confidence(level).IsValid() {
+		log.Printf("Invalid confidence level: %s", levelStr)
+		return
+	}
+	session.Confidence = learning.Confidence(level)
+	session.AwaitingConfidence = false
+
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+	}
+
+	h.continueAfterAnswer(ctx, callback, user, session, prefs)
+}
+
+// continueAfterAnswer moves an answered question on to its rating step, in the message of the callback
+func (h *BotHandler) continueAfterAnswer(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, session *usecases.LearningSession, prefs *user.UserPreferences) {
+	if session.Typed {
+		h.showAnswerResult(ctx, callback, user, session, prefs)
+		return
+	}
+	isCorrect := session.AnswerCorrect
+
+	// Well-known words answered quickly skip the rating step when the user opted in.
+	// A confidence rating already adjusts the interval, and the time spent giving it would count as thinking time.
+	session.AllowedRatings = choiceRatings(prefs, isCorrect)
+	if isCorrect && prefs != nil && prefs.AutoEasyFast() && !session.Practice && !session.Confidence.IsValid() &&
+		time.Since(session.StartTime) <= h.learningUseCase.Config().FastAnswerThreshold &&
+		session.AllowsRating(learning.Easy) {
+		h.autoRate(callback, user, session, learning.Easy)
+		return
+	}
+
+	// With the staged reveal, show only the verdict and let the user recall the translation first
+	if prefs != nil && prefs.StagedReveal() {
+		session.AwaitingReveal = true
+		verdictText := "❌ **Incorrect**\n\nTry to recall the correct translation, then tap to reveal it."
+		if isCorrect {
+			verdictText = "✅ **Correct!**\n\nTake a moment to picture the word, then tap to reveal it."
+		}
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("👀 Reveal answer", "reveal_answer"),
+			),
+		)
+		h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, verdictText, keyboard)
+		return
+	}
+
+	h.showAnswerResult(ctx, callback, user, session, prefs)
+}
 
-	// Show result
+// handleRevealAnswer shows the full result of a staged reveal
+func (h *BotHandler) handleRevealAnswer(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	session, exists := h.activeSessions[int64(user.ID())]
+	if !exists || !session.AwaitingReveal {
+		return
+	}
+	session.AwaitingReveal = false
+
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+	}
+
+	h.showAnswerResult(ctx, callback, user, session, prefs)
+}
+
+// showAnswerResult shows the translation, optional scoreboard and rating buttons for an answered question
+func (h *BotHandler) showAnswerResult(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, session *usecases.LearningSession, prefs *user.UserPreferences) {
+	// Limit the ratings on offer so a lucky guess isn't rated as a word known cold
+	selectedAnswer := session.Options[session.SelectedIndex]
+	session.AllowedRatings = choiceRatings(prefs, session.AnswerCorrect)
+	if session.Typed {
+		// A typed answer can't be a lucky guess, so every rating stays on offer
+		selectedAnswer = shared.EscapeMarkdown(session.TypedAnswer)
+		session.AllowedRatings = allRatings
+	}
+
+	resultText, keyboard := h.buildAnswerResult(ctx, user, session, prefs, selectedAnswer)
+
+	// Edit the original message
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, resultText, keyboard)
+}
+
+// buildAnswerResult builds the result text and rating keyboard for an answered question.
+// The session's allowed ratings must already be set.
+func (h *BotHandler) buildAnswerResult(ctx context.Context, user *user.User, session *usecases.LearningSession, prefs *user.UserPreferences, selectedAnswer string) (string, tgbotapi.InlineKeyboardMarkup) {
 	var resultText string
-	selectedAnswer := session.Options[choiceIndex]
-	correctAnswer := session.Options[session.CorrectIndex]
+	correctAnswer := session.ExpectedAnswer()
 
-	if isCorrect {
+	if session.AnswerScore == learning.ScorePartial {
+		resultText = fmt.Sprintf("✅ **Almost!** Mind the article.\n\nYour answer: %s\nCorrect answer: %s\n\n🇬🇧 %s\n🇳🇱 %s",
+			selectedAnswer, correctAnswer, session.Word.English(), session.Word.Dutch())
+	} else if session.AnswerCorrect {
 		resultText = fmt.Sprintf("✅ **Correct!**\n\nYour answer: %s\n\n🇬🇧 %s\n🇳🇱 %s",
 			selectedAnswer, session.Word.English(), session.Word.Dutch())
 	} else {
@@ -270,23 +520,261 @@ func (h *BotHandler) handleMultipleChoice(ctx context.Context, callback *tgbotap
 			selectedAnswer, correctAnswer, session.Word.English(), session.Word.Dutch())
 	}
 
+	// Add the running scoreboard if the user wants it
+	if prefs != nil && prefs.ShowSessionProgress() {
+		resultText += fmt.Sprintf("\n\n📈 Session: %d correct / %d wrong so far", session.CorrectCount, session.IncorrectCount)
+	}
+
 	// Add rating request
 	resultText += "\n\nHow well did you know this word?"
 
+	// Keep the shuffled order on the session so refreshing the keyboard doesn't move the buttons again
+	if prefs != nil && prefs.ShuffleRatings() {
+		session.AllowedRatings = shuffleRatings(session.AllowedRatings)
+	}
+
 	// Create rating keyboard
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("😵 Again", "rating_1"),
-			tgbotapi.NewInlineKeyboardButtonData("😐 Hard", "rating_2"),
-		),
+	lowPriority, err := h.learningUseCase.IsWordLowPriority(ctx, user.ID(), session.Word.ID())
+	if err != nil {
+		log.Printf("Failed to check low priority flag: %v", err)
+	}
+	keyboard := createRatingKeyboard(session.Word.ID(), lowPriority, session.AllowedRatings, h.config.Speech != nil)
+
+	return resultText, keyboard
+}
+
+// handleTypedAnswer grades a text message as the answer to the user's pending typed question.
+// It reports false when no typed question is waiting, leaving the message to the usual handling.
+func (h *BotHandler) handleTypedAnswer(ctx context.Context, message *tgbotapi.Message, user *user.User) bool {
+	userID := int64(user.ID())
+	session, exists := h.activeSessions[userID]
+	if !exists || !session.Typed || strings.TrimSpace(message.Text) == "" || !session.ClaimAnswer() {
+		return false
+	}
+
+	answer := strings.TrimSpace(message.Text)
+	score := h.learningUseCase.GradeAnswer(ctx, session, answer)
+	isCorrect := score > learning.ScoreWrong
+	session.TypedAnswer = answer
+	session.AnswerCorrect = isCorrect
+	session.AnswerScore = score
+	session.RecordAnswer(isCorrect)
+
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+	}
+
+	// Ask how sure the user was before giving away whether they were right
+	if prefs != nil && prefs.RateConfidence() && !session.Practice {
+		session.AwaitingConfidence = true
+		h.bot.SendMessageWithKeyboard(message.Chat.ID, confidenceText, createConfidenceKeyboard())
+		return true
+	}
+
+	// A typed answer can't be a lucky guess, so every rating stays on offer
+	session.AllowedRatings = allRatings
+	resultText, keyboard := h.buildAnswerResult(ctx, user, session, prefs, shared.EscapeMarkdown(answer))
+
+	h.bot.SendMessageWithKeyboard(message.Chat.ID, resultText, keyboard)
+	return true
+}
+
+// postponeOptions are the delays offered for snoozing a single word, in minutes
+var postponeOptions = []int{1440, 7 * 1440}
+
+// allRatings are the ratings offered when grading isn't restricted
+var allRatings = []learning.Rating{learning.Again, learning.Hard, learning.Good, learning.Easy}
+
+// ratingLabels are the rating button labels
+var ratingLabels = map[learning.Rating]string{
+	learning.Again: "😵 Again",
+	learning.Hard:  "😐 Hard",
+	learning.Good:  "🙂 Good",
+	learning.Easy:  "😄 Easy",
+}
+
+// choiceRatings returns the ratings offered after a multiple-choice answer under the user's grading mode
+func choiceRatings(prefs *user.UserPreferences, correct bool) []learning.Rating {
+	if !correct || prefs == nil {
+		return allRatings
+	}
+	switch prefs.GetChoiceGrading() {
+	case user.ChoiceGradingCapped:
+		return []learning.Rating{learning.Again, learning.Hard, learning.Good}
+	case user.ChoiceGradingAutoGood:
+		return []learning.Rating{learning.Good}
+	default:
+		return allRatings
+	}
+}
+
+// shuffleRatings returns the ratings in a random order, leaving the given slice untouched
+func shuffleRatings(ratings []learning.Rating) []learning.Rating {
+	shuffled := make([]learning.Rating, len(ratings))
+	copy(shuffled, ratings)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return shuffled
+		}
+		shuffled[i], shuffled[j.Int64()] = shuffled[j.Int64()], shuffled[i]
+	}
+	return shuffled
+}
+
+// createRatingKeyboard creates the rating keyboard with a reminder mute toggle for the word.
+// A single allowed rating is shown as a "Next" button that submits it. Buttons follow the
+// order of ratings, and each one's callback carries its rating rather than its position.
+// A Listen button is added when pronunciation audio is available.
+func createRatingKeyboard(wordID vocabulary.ID, lowPriority bool, ratings []learning.Rating, speakable bool) tgbotapi.InlineKeyboardMarkup {
+	muteButton := tgbotapi.NewInlineKeyboardButtonData("🔕 Mute reminders for this word", fmt.Sprintf("mute_%d", wordID))
+	if lowPriority {
+		muteButton = tgbotapi.NewInlineKeyboardButtonData("🔔 Unmute reminders for this word", fmt.Sprintf("unmute_%d", wordID))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(ratings) == 1 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("➡️ Next (rated %s)", ratingLabels[ratings[0]]),
+				fmt.Sprintf("rating_%d", ratings[0])),
+		))
+	} else {
+		// Two rating buttons per row
+		for i := 0; i < len(ratings); i += 2 {
+			var row []tgbotapi.InlineKeyboardButton
+			for _, rating := range ratings[i:min(i+2, len(ratings))] {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(ratingLabels[rating], fmt.Sprintf("rating_%d", rating)))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if speakable {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔊 Listen", fmt.Sprintf("speak_%d", wordID)),
+		))
+	}
+
+	var postponeRow []tgbotapi.InlineKeyboardButton
+	for _, minutes := range postponeOptions {
+		postponeRow = append(postponeRow, tgbotapi.NewInlineKeyboardButtonData(
+			"💤 "+formatSnooze(minutes), fmt.Sprintf("postpone_%d_%d", wordID, minutes)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(append(rows,
+		postponeRow,
+		tgbotapi.NewInlineKeyboardRow(muteButton),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🙂 Good", "rating_3"),
-			tgbotapi.NewInlineKeyboardButtonData("😄 Easy", "rating_4"),
+			tgbotapi.NewInlineKeyboardButtonData("🚩 Report wrong translation", fmt.Sprintf("report_%d", wordID)),
 		),
-	)
+	)...)
+}
 
-	// Edit the original message
-	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, resultText, keyboard)
+// handleMuteWord flags or unflags a word as low priority for reminders
+func (h *BotHandler) handleMuteWord(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, wordIDStr string, mute bool) {
+	wordID, err := strconv.ParseInt(wordIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Invalid mute word ID: %s", wordIDStr)
+		return
+	}
+
+	if err := h.learningUseCase.SetWordLowPriority(ctx, user.ID(), vocabulary.ID(wordID), mute); err != nil {
+		log.Printf("Failed to update low priority flag: %v", err)
+		return
+	}
+
+	// Refresh the rating keyboard so the toggle reflects the new state
+	ratings := allRatings
+	if session, exists := h.activeSessions[int64(user.ID())]; exists && session.AllowedRatings != nil {
+		ratings = session.AllowedRatings
+	}
+	keyboard := createRatingKeyboard(vocabulary.ID(wordID), mute, ratings, h.config.Speech != nil)
+	if err := h.bot.EditMessageReplyMarkup(callback.Message.Chat.ID, callback.Message.MessageID, keyboard); err != nil {
+		log.Printf("Failed to update rating keyboard: %v", err)
+	}
+}
+
+// autoRate records a rating on the user's behalf and moves straight on to the next question
+func (h *BotHandler) autoRate(callback *tgbotapi.CallbackQuery, user *user.User, session *usecases.LearningSession, rating learning.Rating) {
+	userID := int64(user.ID())
+	submitted := h.reviewWorkers.submit(userID, func() {
+		if h.activeSessions[userID] != session {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		if err := h.learningUseCase.ProcessReview(ctx, session, rating, time.Since(session.StartTime)); err != nil {
+			log.Printf("Failed to process automatic review: %v", err)
+			h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+				"❌ Error processing review. Please try again with /learn")
+			return
+		}
+		delete(h.activeSessions, userID)
+
+		h.advanceSession(ctx, callback, user, session)
+	})
+	if !submitted {
+		log.Printf("Dropping automatic rating for user %d: too many ratings still being saved", userID)
+	}
+}
+
+// handlePostponeWord snoozes the current word without rating it and moves on to the next question
+func (h *BotHandler) handlePostponeWord(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, wordIDStr, minutesStr string) {
+	userID := int64(user.ID())
+
+	wordID, err := strconv.ParseInt(wordIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Invalid postpone word ID: %s", wordIDStr)
+		return
+	}
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes <= 0 {
+		log.Printf("Invalid postpone value: %s", minutesStr)
+		return
+	}
+
+	// Only the word currently on screen can be postponed
+	session, exists := h.activeSessions[userID]
+	if !exists || session.Word.ID() != vocabulary.ID(wordID) {
+		h.bot.SendMessage(callback.Message.Chat.ID, "No active session found. Use /learn to start.")
+		return
+	}
+
+	if err := h.learningUseCase.SnoozeWord(ctx, user.ID(), vocabulary.ID(wordID), time.Duration(minutes)*time.Minute); err != nil {
+		log.Printf("Failed to postpone word %d: %v", wordID, err)
+		h.bot.SendMessage(callback.Message.Chat.ID, "Sorry, there was an error snoozing this word. Please try again.")
+		return
+	}
+	delete(h.activeSessions, userID)
+
+	h.advanceSession(ctx, callback, user, session)
+}
+
+// handleReportWord records a report that a word's translation is wrong
+func (h *BotHandler) handleReportWord(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, wordIDStr string) {
+	wordID, err := strconv.ParseInt(wordIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Invalid report word ID: %s", wordIDStr)
+		return
+	}
+
+	archived, err := h.learningUseCase.ReportWord(ctx, user.ID(), vocabulary.ID(wordID))
+	if err != nil {
+		log.Printf("Failed to report word %d: %v", wordID, err)
+		h.bot.SendMessage(callback.Message.Chat.ID, "Sorry, there was an error saving your report. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(callback.Message.Chat.ID, "🚩 Thanks for the report! We'll take a look at this word.")
+
+	if archived {
+		log.Printf("Word %d archived after reaching the report threshold", wordID)
+		h.notifyAdmins(fmt.Sprintf("🚩 Word %d was archived after being reported by several users. "+
+			"Please review it.", wordID))
+	}
 }
 
 // handleRating processes rating selection
@@ -306,14 +794,32 @@ func (h *BotHandler) handleRating(ctx context.Context, callback *tgbotapi.Callba
 		return
 	}
 
+	// A rating only counts once the current question has been attempted; stale buttons from an
+	// earlier question must not grade the word that is on screen now
+	if !session.Answered() {
+		log.Printf("Ignoring rating from user %d before the question was answered", userID)
+		return
+	}
+
 	rating, err := strconv.Atoi(ratingStr)
 	if err != nil {
 		log.Printf("Invalid rating: %s", ratingStr)
 		return
 	}
+	if !session.AllowsRating(learning.Rating(rating)) {
+		log.Printf("Ignoring rating %d from user %d that isn't offered for this answer", rating, userID)
+		return
+	}
+
+	// Process in the background to improve responsiveness. A user's ratings are saved one
+	// at a time and in order, so rapid taps can't race each other.
+	submitted := h.reviewWorkers.submit(userID, func() {
+		// An earlier tap already rated this question and moved on
+		if h.activeSessions[userID] != session {
+			log.Printf("Ignoring rating from user %d for a question that was already rated", userID)
+			return
+		}
 
-	// Process in the background to improve responsiveness
-	go func() {
 		// Create a timeout context for this operation
 		bgCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 		defer cancel()
@@ -321,44 +827,136 @@ func (h *BotHandler) handleRating(ctx context.Context, callback *tgbotapi.Callba
 		// Calculate response time
 		responseTime := time.Since(session.StartTime)
 
-		// Process the review
-		err := h.learningUseCase.ProcessReview(bgCtx, session, learning.Rating(rating), responseTime)
-		if err != nil {
-			log.Printf("Failed to process review: %v", err)
-			h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
-				"❌ Error processing review. Please try again with /learn")
-			return
+		// Process the review (extra practice leaves the schedule untouched)
+		if !session.Practice {
+			err := h.learningUseCase.ProcessReview(bgCtx, session, learning.Rating(rating), responseTime)
+			if err != nil {
+				log.Printf("Failed to process review: %v", err)
+				h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+					"❌ Error processing review. Please try again with /learn")
+				return
+			}
 		}
 
 		// Clean up current session
 		delete(h.activeSessions, userID)
 
-		// Get the next word
-		nextSession, err := h.learningUseCase.GetNextDueWord(bgCtx, user.ID())
-		if err != nil {
-			log.Printf("Failed to get next word: %v", err)
-			h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
-				"❌ Error getting next word. Please try again with /learn")
+		h.advanceSession(bgCtx, callback, user, session)
+	})
+	if !submitted {
+		log.Printf("Dropping rating from user %d: too many ratings still being saved", userID)
+	}
+}
+
+// advanceSession moves a session on to its next question once the current one is done,
+// or wraps the session up when the time cap is hit or nothing is left to study
+func (h *BotHandler) advanceSession(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, session *usecases.LearningSession) {
+	// Wrap up if the session has reached the user's time cap
+	if prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID()); err == nil {
+		maxDuration := prefs.MaxSessionDuration()
+		if maxDuration > 0 && session.Elapsed() >= maxDuration {
+			h.logSession(ctx, session)
+			h.sendSessionTimeUp(callback.Message.Chat.ID, callback.Message.MessageID, session)
+			return
+		}
+	}
+
+	// Get the next word
+	var nextSession *usecases.LearningSession
+	var err error
+	switch {
+	case session.Practice:
+		nextSession, err = h.learningUseCase.GetPracticeWord(ctx, user.ID())
+	case session.Tag != "":
+		nextSession, err = h.learningUseCase.GetNextTaggedWord(ctx, user.ID(), session.Tag, session.Focus)
+	case session.Category != "":
+		nextSession, err = h.learningUseCase.GetNextDueWordInCategory(ctx, user.ID(), session.Category, session.Focus)
+	default:
+		nextSession, err = h.learningUseCase.GetNextDueWord(ctx, user.ID(), session.Focus)
+	}
+	if errors.Is(err, usecases.ErrTakeBreak) {
+		h.logSession(ctx, session)
+		h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, takeBreakText, createCompletionKeyboard())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get next word: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"❌ Error getting next word. Please try again with /learn")
+		return
+	}
+
+	if nextSession != nil {
+		nextSession.ContinueFrom(session)
+		// Store the new session
+		h.activeSessions[int64(user.ID())] = nextSession
+		// Show the next question
+		h.sendQuestionAsEdit(callback.Message.Chat.ID, callback.Message.MessageID, nextSession)
+		h.startQuestionTimeout(callback.Message.Chat.ID, user, nextSession)
+	} else {
+		// No more words to review
+		h.logSession(ctx, session)
+		resultText := "🎉 Great job! You have no more words due for review right now.\n\n" +
+			"Want some extra practice, or a nudge when it's time for your next session?"
+		h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, resultText, createCompletionKeyboard())
+	}
+}
+
+// logSession records a finished session's aggregates, logging rather than surfacing failures
+func (h *BotHandler) logSession(ctx context.Context, session *usecases.LearningSession) {
+	if err := h.learningUseCase.LogSession(ctx, session); err != nil {
+		log.Printf("Failed to log session for user %d: %v", session.UserID, err)
+	}
+}
+
+// sendSessionTimeUp ends a session that reached its time cap with a short summary
+func (h *BotHandler) sendSessionTimeUp(chatID int64, messageID int, session *usecases.LearningSession) {
+	resultText := fmt.Sprintf("⏱ *Time's up for this session!*\n\n"+
+		"You studied for %d minutes and answered %d correctly and %d incorrectly.\n\n"+
+		"Short, regular sessions beat marathon cramming - see you next time! 🌟",
+		int(session.Elapsed().Minutes()), session.CorrectCount, session.IncorrectCount)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 View Stats", "menu_stats"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Main Menu", "back_menu"),
+		),
+	)
+	h.bot.EditMessageWithKeyboard(chatID, messageID, resultText, keyboard)
+}
+
+// startQuestionTimeout auto-submits the question as Again if it is not answered in time
+func (h *BotHandler) startQuestionTimeout(chatID int64, user *user.User, session *usecases.LearningSession) {
+	// Extra practice is low stakes and never reschedules, so it isn't timed
+	timeout := h.learningUseCase.Config().QuestionTimeout
+	if timeout <= 0 || session.Practice {
+		return
+	}
+
+	session.StartTimeout(timeout, func() {
+		userID := int64(user.ID())
+
+		// The user may have moved on to another session in the meantime
+		if h.activeSessions[userID] != session {
 			return
 		}
 
-		if nextSession != nil {
-			// Store the new session
-			h.activeSessions[userID] = nextSession
-			// Show the next question
-			h.sendQuestionAsEdit(callback.Message.Chat.ID, callback.Message.MessageID, nextSession)
-		} else {
-			// No more words to review
-			resultText := "🎉 Great job! You have no more words due for review right now."
-			keyboard := tgbotapi.NewInlineKeyboardMarkup(
-				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("📊 View Stats", "menu_stats"),
-					tgbotapi.NewInlineKeyboardButtonData("🏠 Main Menu", "back_menu"),
-				),
-			)
-			h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, resultText, keyboard)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		err := h.learningUseCase.ProcessReview(ctx, session, learning.Again, time.Since(session.StartTime))
+		if err != nil {
+			log.Printf("Failed to process timed out review: %v", err)
+			return
 		}
-	}()
+		delete(h.activeSessions, userID)
+
+		timeoutText := fmt.Sprintf("⏰ *Time's up!*\n\n🇬🇧 %s\n🇳🇱 %s",
+			shared.EscapeMarkdown(session.Word.English()), shared.EscapeMarkdown(session.Word.Dutch()))
+		h.bot.SendMessageWithMarkdown(chatID, timeoutText)
+
+		// Advance to the next question
+		h.handleLearningFlow(ctx, chatID, 0, user, false)
+	})
 }
 
 // handleViewStats shows user statistics
@@ -366,16 +964,180 @@ func (h *BotHandler) handleViewStats(ctx context.Context, callback *tgbotapi.Cal
 	h.handleStatsFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
 }
 
+// snoozeOptions are the "remind me in" delays offered after finishing all due words, in minutes
+var snoozeOptions = []int{60, 240, 1440}
+
+// createCompletionKeyboard creates the keyboard shown after all due words are done
+func createCompletionKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var snoozeRow []tgbotapi.InlineKeyboardButton
+	for _, minutes := range snoozeOptions {
+		snoozeRow = append(snoozeRow, tgbotapi.NewInlineKeyboardButtonData(
+			"⏰ "+formatSnooze(minutes), fmt.Sprintf("snooze_%d", minutes)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏋️ Extra Practice", "practice_more"),
+		),
+		snoozeRow,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 View Stats", "menu_stats"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Main Menu", "back_menu"),
+		),
+	)
+}
+
+// formatSnooze formats a snooze delay for display
+func formatSnooze(minutes int) string {
+	if minutes >= 1440 && minutes%1440 == 0 {
+		if minutes == 1440 {
+			return "Tomorrow"
+		}
+		return fmt.Sprintf("In %dd", minutes/1440)
+	}
+	if minutes >= 60 && minutes%60 == 0 {
+		return fmt.Sprintf("In %dh", minutes/60)
+	}
+	return fmt.Sprintf("In %dmin", minutes)
+}
+
+// handlePractice starts extra practice on the user's weakest words without touching their schedule
+func (h *BotHandler) handlePractice(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	session, err := h.learningUseCase.GetPracticeWord(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get practice word: %v", err)
+		h.bot.EditMessage(chatID, messageID, "Sorry, there was an error getting your words. Please try again.")
+		return
+	}
+
+	if session == nil {
+		h.bot.EditMessageWithKeyboard(chatID, messageID,
+			"You haven't studied any words yet - start with /learn!", shared.CreateNoWordsKeyboard())
+		return
+	}
+
+	h.activeSessions[int64(user.ID())] = session
+	h.sendQuestionAsEdit(chatID, messageID, session)
+	h.startQuestionTimeout(chatID, user, session)
+}
+
+// handleSnooze schedules a one-off reminder for the user's next session
+func (h *BotHandler) handleSnooze(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, minutesStr string) {
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes <= 0 {
+		log.Printf("Invalid snooze value: %s", minutesStr)
+		return
+	}
+
+	if err := h.userUseCase.ScheduleReminder(ctx, user.ID(), time.Now().Add(time.Duration(minutes)*time.Minute)); err != nil {
+		log.Printf("Failed to schedule reminder: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Sorry, there was an error scheduling your reminder. Please try again.")
+		return
+	}
+
+	confirmText := fmt.Sprintf("⏰ Got it! I'll remind you (%s) once you have words to review.", strings.ToLower(formatSnooze(minutes)))
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, confirmText, shared.CreateMainMenuKeyboard())
+}
+
+// handleResumeQuestion shows the user's unfinished question again
+func (h *BotHandler) handleResumeQuestion(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	session, exists := h.activeSessions[int64(user.ID())]
+	if !exists {
+		h.handleLearningFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
+		return
+	}
+
+	// An answered question only needs its rating
+	if session.Answered() {
+		session.AwaitingReveal = false
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+		if err != nil {
+			log.Printf("Failed to get user preferences: %v", err)
+		}
+		h.showAnswerResult(ctx, callback, user, session, prefs)
+		return
+	}
+
+	h.sendQuestionAsEdit(callback.Message.Chat.ID, callback.Message.MessageID, session)
+}
+
+// handleRestartLearning drops the user's unfinished question and starts a fresh one
+func (h *BotHandler) handleRestartLearning(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	userID := int64(user.ID())
+	if session, exists := h.activeSessions[userID]; exists {
+		// Stop the pending question timeout
+		session.ClaimAnswer()
+		delete(h.activeSessions, userID)
+	}
+
+	h.handleLearningFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
+}
+
 // handleContinueLearning handles the continue learning button
 func (h *BotHandler) handleContinueLearning(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
 	h.handleLearningFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
 }
 
-// handleFinishSession handles the finish session button
+// handleFinishSession handles the finish session button, recapping the session first
+// when the user answered anything and wants a recap
 func (h *BotHandler) handleFinishSession(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
 	// Clean up session
+	session, exists := h.activeSessions[int64(user.ID())]
+	if exists {
+		h.logSession(ctx, session)
+	}
 	delete(h.activeSessions, int64(user.ID()))
 
+	if exists && session.AnswerCount() > 0 {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+		if err != nil {
+			log.Printf("Failed to get user preferences: %v", err)
+		}
+		if prefs == nil || prefs.SessionRecap() {
+			h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID,
+				formatSessionRecap(session), createSessionRecapKeyboard())
+			return
+		}
+	}
+
 	// Show main menu
 	h.handleBackToMenu(ctx, callback, user)
 }
+
+// formatSessionRecap summarizes what the user did in a session they just finished
+func formatSessionRecap(session *usecases.LearningSession) string {
+	text := fmt.Sprintf("🏁 *Session complete!*\n\n"+
+		"📝 Words studied: %d", session.AnswerCount())
+	if session.NewCount > 0 {
+		text += fmt.Sprintf(" (%d new)", session.NewCount)
+	}
+	text += fmt.Sprintf("\n🎯 Accuracy: %.0f%% (%d correct, %d wrong)\n⏱ Time: %s",
+		session.Accuracy(), session.CorrectCount, session.IncorrectCount, formatSessionDuration(session.Elapsed()))
+	return text + "\n\nNice work - see you next time! 🌟"
+}
+
+// formatSessionDuration formats a session's length in whole minutes, or seconds for very short sessions
+func formatSessionDuration(elapsed time.Duration) string {
+	if elapsed < time.Minute {
+		return fmt.Sprintf("%d seconds", int(elapsed.Seconds()))
+	}
+	minutes := int(elapsed.Round(time.Minute).Minutes())
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// createSessionRecapKeyboard offers the next steps after a session recap
+func createSessionRecapKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 View Stats", "menu_stats"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Main Menu", "back_menu"),
+		),
+	)
+}
@@ -0,0 +1,628 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/grammar"
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// newTestUser creates the user of newTestCallback and applies update to their preferences
+func newTestUser(t *testing.T, h *BotHandler, update func(*user.UserPreferences)) *user.User {
+	t.Helper()
+
+	ctx := context.Background()
+	u, err := h.userUseCase.GetOrCreateUser(ctx, 1001, "anna", "Anna", "", "en")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if update != nil {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+		if err != nil {
+			t.Fatalf("failed to load preferences: %v", err)
+		}
+		update(prefs)
+		if err := h.userUseCase.UpdateUserPreferences(ctx, prefs); err != nil {
+			t.Fatalf("failed to save preferences: %v", err)
+		}
+	}
+	return u
+}
+
+// resetClickTracker forgets earlier clicks, so the next ones aren't debounced as duplicates
+func resetClickTracker() {
+	globalClickTracker.mu.Lock()
+	globalClickTracker.lastClicks = make(map[string]time.Time)
+	globalClickTracker.mu.Unlock()
+}
+
+// startTestQuestion makes a multiple-choice question on "huis" the user's active question
+func startTestQuestion(h *BotHandler, u *user.User) *usecases.LearningSession {
+	// Each test clicks as the same user, so earlier clicks mustn't be debounced
+	resetClickTracker()
+
+	word := vocabulary.NewWord("house", "huis", vocabulary.Category("basics"))
+	word.SetID(1)
+	session := &usecases.LearningSession{
+		UserID:       u.ID(),
+		Word:         word,
+		Progress:     learning.NewUserProgress(u.ID(), word.ID()),
+		QuestionType: usecases.QuestionTypeEnglishToDutch,
+		StartTime:    time.Now().Add(-time.Minute),
+		SessionStart: time.Now().Add(-time.Minute),
+		Options:      []string{"boom", "huis", "kat", "hond"},
+		CorrectIndex: 1,
+	}
+	h.activeSessions[int64(u.ID())] = session
+	return session
+}
+
+func TestAdvanceSession_TimeCap(t *testing.T) {
+	tests := []struct {
+		name        string
+		capMinutes  int
+		elapsed     time.Duration
+		wantTimesUp bool
+	}{
+		{"under the cap", 15, 14 * time.Minute, false},
+		{"crossing the cap", 15, 16 * time.Minute, true},
+		{"no cap", 0, 2 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, fake := newTestBotHandler(t, nil)
+			u, err := h.userUseCase.GetOrCreateUser(ctx, 1001, "anna", "Anna", "", "en")
+			if err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+			if err := h.userUseCase.SetMaxSessionMinutes(ctx, u.ID(), tt.capMinutes); err != nil {
+				t.Fatalf("failed to set session cap: %v", err)
+			}
+
+			session := &usecases.LearningSession{UserID: u.ID(), SessionStart: time.Now().Add(-tt.elapsed)}
+			h.advanceSession(ctx, newTestCallback("rating_3"), u, session)
+
+			edits := fake.callsTo("editMessageText")
+			if len(edits) != 1 {
+				t.Fatalf("message edited %d times, want 1", len(edits))
+			}
+			if got := strings.Contains(edits[0].params.Get("text"), "Time's up"); got != tt.wantTimesUp {
+				t.Errorf("session wrapped up for time = %v, want %v (text %q)", got, tt.wantTimesUp, edits[0].params.Get("text"))
+			}
+		})
+	}
+}
+
+func TestStagedReveal(t *testing.T) {
+	tests := []struct {
+		name   string
+		staged bool
+	}{
+		{"staged", true},
+		{"immediate", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, fake := newTestBotHandler(t, nil)
+			u := newTestUser(t, h, func(p *user.UserPreferences) { p.SetStagedReveal(tt.staged) })
+			session := startTestQuestion(h, u)
+
+			h.handleCallbackQuery(ctx, newTestCallback("choice_1"))
+
+			edits := fake.callsTo("editMessageText")
+			if len(edits) != 1 {
+				t.Fatalf("message edited %d times after the answer, want 1", len(edits))
+			}
+			markup := edits[0].params.Get("reply_markup")
+			if got := strings.Contains(markup, "reveal_answer"); got != tt.staged {
+				t.Errorf("reveal button shown = %v, want %v", got, tt.staged)
+			}
+			if got := strings.Contains(markup, "rating_"); got == tt.staged {
+				t.Errorf("rating buttons shown = %v, want %v", got, !tt.staged)
+			}
+			if session.AwaitingReveal != tt.staged {
+				t.Errorf("AwaitingReveal = %v, want %v", session.AwaitingReveal, tt.staged)
+			}
+			if !tt.staged {
+				return
+			}
+
+			h.handleCallbackQuery(ctx, newTestCallback("reveal_answer"))
+
+			edits = fake.callsTo("editMessageText")
+			if len(edits) != 2 {
+				t.Fatalf("message edited %d times after the reveal, want 2", len(edits))
+			}
+			if !strings.Contains(edits[1].params.Get("reply_markup"), "rating_") {
+				t.Error("reveal did not show the rating buttons")
+			}
+			if !strings.Contains(edits[1].params.Get("text"), "huis") {
+				t.Errorf("reveal text = %q, want the translation", edits[1].params.Get("text"))
+			}
+			if session.AwaitingReveal {
+				t.Error("AwaitingReveal still set after the reveal")
+			}
+		})
+	}
+}
+
+func TestFormatHint(t *testing.T) {
+	word := vocabulary.NewWord("the bicycle", "de fiets", vocabulary.Category("transport"))
+
+	tests := []struct {
+		hintType     user.HintType
+		questionType usecases.QuestionType
+		want         string
+	}{
+		{user.HintTypeCategory, usecases.QuestionTypeEnglishToDutch, "Category: transport"},
+		{user.HintTypeFirstLetter, usecases.QuestionTypeEnglishToDutch, "Starts with: d"},
+		{user.HintTypeFirstLetter, usecases.QuestionTypeDutchToEnglish, "Starts with: t"},
+		{user.HintTypeLength, usecases.QuestionTypeEnglishToDutch, "Letters: 7"},
+		{user.HintTypeLength, usecases.QuestionTypeDutchToEnglish, "Letters: 10"},
+		{user.HintTypeNone, usecases.QuestionTypeEnglishToDutch, ""},
+		{user.HintType(""), usecases.QuestionTypeEnglishToDutch, "Category: transport"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.hintType)+"/"+string(tt.questionType), func(t *testing.T) {
+			session := &usecases.LearningSession{Word: word, QuestionType: tt.questionType, HintType: tt.hintType}
+			if got := formatHint(session); got != tt.want {
+				t.Errorf("formatHint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceSession_CompletionOptions(t *testing.T) {
+	ctx := context.Background()
+	h, fake := newTestBotHandler(t, nil)
+	u := newTestUser(t, h, nil)
+
+	// With nothing left to study, the user is offered extra practice and a reminder snooze
+	session := &usecases.LearningSession{UserID: u.ID(), SessionStart: time.Now()}
+	h.advanceSession(ctx, newTestCallback("rating_3"), u, session)
+
+	edits := fake.callsTo("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("message edited %d times, want 1", len(edits))
+	}
+	markup := edits[0].params.Get("reply_markup")
+	for _, data := range []string{"practice_more", "snooze_60", "snooze_240", "snooze_1440", "menu_stats", "back_menu"} {
+		if !strings.Contains(markup, `"`+data+`"`) {
+			t.Errorf("completion keyboard lacks %q", data)
+		}
+	}
+
+	// Tapping a snooze schedules the next reminder
+	before := time.Now()
+	h.handleCallbackQuery(ctx, newTestCallback("snooze_240"))
+
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+	if err != nil {
+		t.Fatalf("failed to load preferences: %v", err)
+	}
+	remindAt, ok := prefs.GetNextReminderAt()
+	if !ok {
+		t.Fatal("no reminder scheduled after snoozing")
+	}
+	if want := before.Add(4 * time.Hour); remindAt.Before(want.Add(-time.Second)) || remindAt.After(want.Add(time.Minute)) {
+		t.Errorf("reminder scheduled at %v, want about %v", remindAt, want)
+	}
+}
+
+func TestFormatSnooze(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{30, "In 30min"},
+		{60, "In 1h"},
+		{90, "In 90min"},
+		{240, "In 4h"},
+		{1440, "Tomorrow"},
+		{2880, "In 2d"},
+	}
+	for _, tt := range tests {
+		if got := formatSnooze(tt.minutes); got != tt.want {
+			t.Errorf("formatSnooze(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+// ratingCallbacks returns the rating callback data of a keyboard's buttons, in order
+func ratingCallbacks(keyboard tgbotapi.InlineKeyboardMarkup) []string {
+	var callbacks []string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && strings.HasPrefix(*button.CallbackData, "rating_") {
+				callbacks = append(callbacks, *button.CallbackData)
+			}
+		}
+	}
+	return callbacks
+}
+
+func TestChoiceRatingKeyboard(t *testing.T) {
+	tests := []struct {
+		name    string
+		grading user.ChoiceGrading
+		correct bool
+		want    []string
+	}{
+		{"self graded", user.ChoiceGradingSelf, true, []string{"rating_1", "rating_2", "rating_3", "rating_4"}},
+		{"capped after a correct answer", user.ChoiceGradingCapped, true, []string{"rating_1", "rating_2", "rating_3"}},
+		{"capped after a wrong answer", user.ChoiceGradingCapped, false, []string{"rating_1", "rating_2", "rating_3", "rating_4"}},
+		{"auto good", user.ChoiceGradingAutoGood, true, []string{"rating_3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := user.NewUserPreferences(1)
+			prefs.SetChoiceGrading(tt.grading)
+
+			keyboard := createRatingKeyboard(1, false, choiceRatings(prefs, tt.correct), false)
+
+			got := ratingCallbacks(keyboard)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("rating buttons = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatQuestionDirection(t *testing.T) {
+	tests := []struct {
+		questionType usecases.QuestionType
+		want         string
+	}{
+		{usecases.QuestionTypeEnglishToDutch, "🇬🇧➡️🇳🇱 Translate to Dutch:"},
+		{usecases.QuestionTypeDutchToEnglish, "🇳🇱➡️🇬🇧 Translate to English:"},
+	}
+	for _, tt := range tests {
+		if got := formatQuestionDirection(tt.questionType); got != tt.want {
+			t.Errorf("formatQuestionDirection(%q) = %q, want %q", tt.questionType, got, tt.want)
+		}
+	}
+}
+
+func TestLearnCommand_KeepsQuestionInProgress(t *testing.T) {
+	tests := []struct {
+		name       string
+		confirm    bool
+		wantPrompt bool
+	}{
+		{"asks first", true, true},
+		{"confirmation off", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultHandlerConfig()
+			config.ConfirmSessionRestart = tt.confirm
+			h, fake := newTestBotHandler(t, config)
+			u := newTestUser(t, h, nil)
+			session := startTestQuestion(h, u)
+
+			h.handleMessage(context.Background(), newTestCommand("learn"))
+
+			sends := fake.callsTo("sendMessage")
+			if len(sends) == 0 {
+				t.Fatal("/learn sent nothing")
+			}
+			markup := sends[0].params.Get("reply_markup")
+			prompted := strings.Contains(markup, "resume_question") && strings.Contains(markup, "restart_learning")
+			if prompted != tt.wantPrompt {
+				t.Errorf("continue/restart prompt shown = %v, want %v", prompted, tt.wantPrompt)
+			}
+			if tt.wantPrompt && h.activeSessions[int64(u.ID())] != session {
+				t.Error("/learn replaced the question in progress")
+			}
+		})
+	}
+}
+
+func TestSendGrammarTipMedia(t *testing.T) {
+	newTip := func(imageURL, audioURL string) *grammar.GrammarTip {
+		tip := grammar.NewGrammarTip("De vs Het", "Most nouns use de.", "de man", "the man",
+			grammar.CategoryArticles, nil, nil, nil)
+		tip.SetMedia(imageURL, audioURL)
+		return tip
+	}
+
+	tests := []struct {
+		name       string
+		tip        *grammar.GrammarTip
+		wantPhotos int
+		wantAudio  int
+	}{
+		{"image and audio", newTip("https://example.com/a.png", "https://example.com/a.mp3"), 1, 1},
+		{"image only", newTip("https://example.com/a.png", ""), 1, 0},
+		{"no media", newTip("", ""), 0, 0},
+		{"no tip", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestBotHandler(t, nil)
+
+			h.sendGrammarTipMedia(1001, tt.tip)
+
+			photos, audio := fake.callsTo("sendPhoto"), fake.callsTo("sendAudio")
+			if len(photos) != tt.wantPhotos || len(audio) != tt.wantAudio {
+				t.Fatalf("sent %d photos and %d audio files, want %d and %d", len(photos), len(audio), tt.wantPhotos, tt.wantAudio)
+			}
+			if len(photos) == 1 {
+				if got := photos[0].params.Get("photo"); got != "https://example.com/a.png" {
+					t.Errorf("photo = %q, want the tip's image URL", got)
+				}
+				if got := photos[0].params.Get("caption"); got != "🎯 De vs Het" {
+					t.Errorf("caption = %q, want the tip title", got)
+				}
+			}
+		})
+	}
+}
+
+// waitForReviews waits until the review work already queued for the user has run
+func waitForReviews(h *BotHandler, u *user.User) {
+	done := make(chan struct{})
+	for !h.reviewWorkers.submit(int64(u.ID()), func() { close(done) }) {
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+}
+
+func TestAutoEasyFast(t *testing.T) {
+	tests := []struct {
+		name         string
+		thinkingTime time.Duration
+		wantAutoEasy bool
+	}{
+		{"fast correct answer", 0, true},
+		{"slow correct answer", time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, fake, db := newTestBotHandlerWithDB(t, nil)
+			vocabRepo := persistence.NewVocabularyRepository(db)
+			// "huis" is saved first so it gets the ID of the test question's word
+			for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+				if err := vocabRepo.Save(ctx, vocabulary.NewWord(pair[0], pair[1], vocabulary.Category("basics"))); err != nil {
+					t.Fatalf("failed to save word: %v", err)
+				}
+			}
+			u := newTestUser(t, h, func(p *user.UserPreferences) { p.SetAutoEasyFast(true) })
+			session := startTestQuestion(h, u)
+			session.StartTime = time.Now().Add(-tt.thinkingTime)
+
+			h.handleCallbackQuery(ctx, newTestCallback("choice_1"))
+			waitForReviews(h, u)
+
+			var reviews int
+			var rating learning.Rating
+			if err := db.QueryRow(`SELECT COUNT(*), COALESCE(MAX(rating), 0) FROM review_history`).Scan(&reviews, &rating); err != nil {
+				t.Fatalf("failed to read reviews: %v", err)
+			}
+			if tt.wantAutoEasy {
+				if reviews != 1 || rating != learning.Easy {
+					t.Errorf("saved %d reviews rated %d, want one Easy review", reviews, rating)
+				}
+				return
+			}
+
+			if reviews != 0 {
+				t.Errorf("saved %d reviews before the user rated, want 0", reviews)
+			}
+			edits := fake.callsTo("editMessageText")
+			if len(edits) != 1 || !strings.Contains(edits[0].params.Get("reply_markup"), "rating_4") {
+				t.Error("slow answer didn't show the rating buttons")
+			}
+		})
+	}
+}
+
+func TestRating_IgnoredBeforeAnswer(t *testing.T) {
+	ctx := context.Background()
+	h, fake, db := newTestBotHandlerWithDB(t, nil)
+	vocabRepo := persistence.NewVocabularyRepository(db)
+	// "huis" is saved first so it gets the ID of the test question's word
+	for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+		if err := vocabRepo.Save(ctx, vocabulary.NewWord(pair[0], pair[1], vocabulary.Category("basics"))); err != nil {
+			t.Fatalf("failed to save word: %v", err)
+		}
+	}
+	u := newTestUser(t, h, nil)
+	session := startTestQuestion(h, u)
+	reviewCount := func() int {
+		t.Helper()
+		waitForReviews(h, u)
+		var reviews int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM review_history`).Scan(&reviews); err != nil {
+			t.Fatalf("failed to read reviews: %v", err)
+		}
+		return reviews
+	}
+
+	// A stale rating button pressed while the question is still open
+	h.handleCallbackQuery(ctx, newTestCallback("rating_3"))
+	if got := reviewCount(); got != 0 {
+		t.Fatalf("saved %d reviews for a premature rating, want 0", got)
+	}
+	if session.Answered() {
+		t.Fatal("a premature rating marked the question answered")
+	}
+	if got := len(fake.callsTo("editMessageText")); got != 0 {
+		t.Errorf("message edited %d times for a premature rating, want 0", got)
+	}
+
+	// Once the question is answered the same rating counts
+	resetClickTracker()
+	h.handleCallbackQuery(ctx, newTestCallback("choice_1"))
+	h.handleCallbackQuery(ctx, newTestCallback("rating_3"))
+	if got := reviewCount(); got != 1 {
+		t.Errorf("saved %d reviews after answering, want 1", got)
+	}
+}
+
+func TestShuffledRatingKeyboard_CallbacksFollowRatings(t *testing.T) {
+	labelRatings := make(map[string]string)
+	for rating, label := range ratingLabels {
+		labelRatings[label] = fmt.Sprintf("rating_%d", rating)
+	}
+
+	for i := 0; i < 50; i++ {
+		ratings := shuffleRatings(allRatings)
+		keyboard := createRatingKeyboard(1, false, ratings, false)
+
+		var position int
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				if button.CallbackData == nil || !strings.HasPrefix(*button.CallbackData, "rating_") {
+					continue
+				}
+				if want := labelRatings[button.Text]; *button.CallbackData != want {
+					t.Fatalf("button %q at position %d carries %q, want %q", button.Text, position, *button.CallbackData, want)
+				}
+				if want := fmt.Sprintf("rating_%d", ratings[position]); *button.CallbackData != want {
+					t.Fatalf("position %d carries %q, want %q", position, *button.CallbackData, want)
+				}
+				position++
+			}
+		}
+		if position != len(allRatings) {
+			t.Fatalf("keyboard has %d rating buttons, want %d", position, len(allRatings))
+		}
+	}
+
+	if got := fmt.Sprint(allRatings); got != fmt.Sprint([]learning.Rating{learning.Again, learning.Hard, learning.Good, learning.Easy}) {
+		t.Errorf("shuffling changed allRatings to %v", got)
+	}
+}
+
+func TestShuffledRatings_GoodButtonRatesGood(t *testing.T) {
+	ctx := context.Background()
+	h, fake, db := newTestBotHandlerWithDB(t, nil)
+	vocabRepo := persistence.NewVocabularyRepository(db)
+	// "huis" is saved first so it gets the ID of the test question's word
+	for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}, {"cat", "kat"}, {"dog", "hond"}} {
+		if err := vocabRepo.Save(ctx, vocabulary.NewWord(pair[0], pair[1], vocabulary.Category("basics"))); err != nil {
+			t.Fatalf("failed to save word: %v", err)
+		}
+	}
+	u := newTestUser(t, h, func(p *user.UserPreferences) { p.SetShuffleRatings(true) })
+	startTestQuestion(h, u)
+
+	h.handleCallbackQuery(ctx, newTestCallback("choice_1"))
+	edits := fake.callsTo("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("message edited %d times after the answer, want 1", len(edits))
+	}
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(edits[0].params.Get("reply_markup")), &keyboard); err != nil {
+		t.Fatalf("failed to parse the rating keyboard: %v", err)
+	}
+	var goodCallback string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.Text == ratingLabels[learning.Good] && button.CallbackData != nil {
+				goodCallback = *button.CallbackData
+			}
+		}
+	}
+	if goodCallback == "" {
+		t.Fatal("no Good button on the rating keyboard")
+	}
+
+	h.handleCallbackQuery(ctx, newTestCallback(goodCallback))
+	waitForReviews(h, u)
+
+	var rating learning.Rating
+	if err := db.QueryRow(`SELECT rating FROM review_history`).Scan(&rating); err != nil {
+		t.Fatalf("failed to read the review: %v", err)
+	}
+	if rating != learning.Good {
+		t.Errorf("pressing Good saved rating %d, want %d", rating, learning.Good)
+	}
+}
+
+func TestFinishSession_Recap(t *testing.T) {
+	tests := []struct {
+		name      string
+		recap     bool
+		answers   []bool
+		wantRecap []string // Lines of the recap, or nil for the main menu
+	}{
+		{"recap of the session", true, []bool{true, true, false}, []string{
+			"Words studied: 3 (2 new)", "Accuracy: 67% (2 correct, 1 wrong)", "Time: 12 minutes",
+		}},
+		{"recap turned off", false, []bool{true, false}, nil},
+		{"nothing answered", true, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, fake := newTestBotHandler(t, nil)
+			u := newTestUser(t, h, func(p *user.UserPreferences) { p.SetSessionRecap(tt.recap) })
+			session := startTestQuestion(h, u)
+			session.SessionStart = time.Now().Add(-12 * time.Minute)
+			for i, correct := range tt.answers {
+				// The first two words are new, the rest reviews
+				if i == 2 {
+					session.Progress.FSRSCard().SetState(learning.StateReview)
+				}
+				session.RecordAnswer(correct)
+			}
+
+			h.handleFinishSession(ctx, newTestCallback("finish_session"), u)
+
+			if _, active := h.activeSessions[int64(u.ID())]; active {
+				t.Error("session still active after finishing")
+			}
+			edits := fake.callsTo("editMessageText")
+			if len(edits) != 1 {
+				t.Fatalf("message edited %d times, want 1", len(edits))
+			}
+			text := edits[0].params.Get("text")
+			if gotRecap := strings.Contains(text, "Session complete"); gotRecap != (tt.wantRecap != nil) {
+				t.Fatalf("showed a recap = %v, want %v (text %q)", gotRecap, tt.wantRecap != nil, text)
+			}
+			for _, line := range tt.wantRecap {
+				if !strings.Contains(text, line) {
+					t.Errorf("recap %q is missing %q", text, line)
+				}
+			}
+		})
+	}
+}
+
+func TestChoiceToast(t *testing.T) {
+	tests := []struct {
+		name               string
+		isCorrect          bool
+		awaitingConfidence bool
+		want               string
+	}{
+		{"correct", true, false, "✅ Correct!"},
+		{"wrong", false, false, "❌ Oops"},
+		// The verdict waits until the confidence rating is in
+		{"correct, confidence pending", true, true, ""},
+		{"wrong, confidence pending", false, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := choiceToast(tt.isCorrect, tt.awaitingConfidence); got != tt.want {
+				t.Errorf("choiceToast(%v, %v) = %q, want %q", tt.isCorrect, tt.awaitingConfidence, got, tt.want)
+			}
+		})
+	}
+}
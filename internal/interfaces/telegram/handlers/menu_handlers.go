@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"dutch-learning-bot/internal/application/usecases"
 	"dutch-learning-bot/internal/domain/user"
 	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
 )
@@ -23,6 +25,10 @@ func (h *BotHandler) handleMenuSelection(ctx context.Context, callback *tgbotapi
 		h.handleMenuHelp(ctx, callback, user)
 	case "menu_settings":
 		h.handleMenuSettings(ctx, callback, user)
+	case "menu_categories":
+		h.handleMenuCategories(ctx, callback, user)
+	case "menu_category_stats":
+		h.handleMenuCategoryStats(ctx, callback, user)
 	default:
 		log.Printf("Unknown menu selection: %s", selection)
 	}
@@ -44,15 +50,27 @@ func (h *BotHandler) handleMenuStats(ctx context.Context, callback *tgbotapi.Cal
 	h.handleStatsFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
 }
 
+// handleMenuCategoryStats shows the per-category breakdown from the stats view
+func (h *BotHandler) handleMenuCategoryStats(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	stats, err := h.learningUseCase.GetUserStatsByCategory(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get category stats: %v", err)
+		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID, "Sorry, there was an error getting your statistics.")
+		return
+	}
+
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID,
+		shared.FormatCategoryStatsText(stats), shared.CreateCategoryStatsKeyboard())
+}
+
 // handleMenuHelp shows help from menu
 func (h *BotHandler) handleMenuHelp(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
 	h.handleHelpFlow(ctx, callback.Message.Chat.ID, callback.Message.MessageID, user, true)
 }
 
-// handleMenuSettings shows settings from menu
+// handleMenuSettings shows settings from menu, editing the menu message in place
 func (h *BotHandler) handleMenuSettings(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
-	// Get user preferences
-	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	settingsText, keyboard, err := h.buildSettingsView(ctx, user)
 	if err != nil {
 		log.Printf("Failed to get user preferences: %v", err)
 		h.bot.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID,
@@ -60,6 +78,29 @@ func (h *BotHandler) handleMenuSettings(ctx context.Context, callback *tgbotapi.
 		return
 	}
 
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, settingsText, keyboard)
+}
+
+// handleSettings processes the /settings command, sending the settings as a new message
+func (h *BotHandler) handleSettings(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	settingsText, keyboard, err := h.buildSettingsView(ctx, user)
+	if err != nil {
+		log.Printf("Failed to get user preferences: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessageWithKeyboard(message.Chat.ID, settingsText, keyboard)
+}
+
+// buildSettingsView renders the settings text and keyboard for a user
+func (h *BotHandler) buildSettingsView(ctx context.Context, user *user.User) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	// Get user preferences
+	prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
 	// Get current settings status
 	grammarTipsStatus := "❌ **DISABLED**"
 	grammarTipsAction := "Enable"
@@ -75,16 +116,157 @@ func (h *BotHandler) handleMenuSettings(ctx context.Context, callback *tgbotapi.
 		smartRemindersAction = "Disable"
 	}
 
+	sessionProgressStatus := "❌ **DISABLED**"
+	sessionProgressAction := "Enable"
+	if prefs.ShowSessionProgress() {
+		sessionProgressStatus = "✅ **ENABLED**"
+		sessionProgressAction = "Disable"
+	}
+
+	sessionRecapStatus := "❌ **DISABLED**"
+	sessionRecapAction := "Enable"
+	if prefs.SessionRecap() {
+		sessionRecapStatus = "✅ **ENABLED**"
+		sessionRecapAction = "Disable"
+	}
+
+	ignoreArticlesStatus := "❌ **DISABLED**"
+	ignoreArticlesAction := "Enable"
+	if prefs.IgnoreArticles() {
+		ignoreArticlesStatus = "✅ **ENABLED**"
+		ignoreArticlesAction = "Disable"
+	}
+
+	reviewsOnlyStatus := "❌ **DISABLED**"
+	reviewsOnlyAction := "Enable"
+	if prefs.ReviewsOnly() {
+		reviewsOnlyStatus = "✅ **ENABLED**"
+		reviewsOnlyAction = "Disable"
+	}
+
+	strictAccentsStatus := "❌ **DISABLED**"
+	strictAccentsAction := "Enable"
+	if prefs.StrictAccents() {
+		strictAccentsStatus = "✅ **ENABLED**"
+		strictAccentsAction = "Disable"
+	}
+
+	spellingVariantsStatus := "❌ **DISABLED**"
+	spellingVariantsAction := "Enable"
+	if prefs.SpellingVariants() {
+		spellingVariantsStatus = "✅ **ENABLED**"
+		spellingVariantsAction = "Disable"
+	}
+
+	intervalFuzzStatus := "❌ **DISABLED**"
+	intervalFuzzAction := "Enable"
+	if prefs.IntervalFuzz() {
+		intervalFuzzStatus = "✅ **ENABLED**"
+		intervalFuzzAction = "Disable"
+	}
+
+	communityDifficultyStatus := "❌ **DISABLED**"
+	communityDifficultyAction := "Enable"
+	if prefs.CommunityDifficulty() {
+		communityDifficultyStatus = "✅ **ENABLED**"
+		communityDifficultyAction = "Disable"
+	}
+
+	stagedRevealStatus := "❌ **DISABLED**"
+	stagedRevealAction := "Enable"
+	if prefs.StagedReveal() {
+		stagedRevealStatus = "✅ **ENABLED**"
+		stagedRevealAction = "Disable"
+	}
+
+	autoEasyStatus := "❌ **DISABLED**"
+	autoEasyAction := "Enable"
+	if prefs.AutoEasyFast() {
+		autoEasyStatus = "✅ **ENABLED**"
+		autoEasyAction = "Disable"
+	}
+
+	rateConfidenceStatus := "❌ **DISABLED**"
+	rateConfidenceAction := "Enable"
+	if prefs.RateConfidence() {
+		rateConfidenceStatus = "✅ **ENABLED**"
+		rateConfidenceAction = "Disable"
+	}
+
+	recognitionFirstStatus := "❌ **DISABLED**"
+	recognitionFirstAction := "Enable"
+	if prefs.RecognitionFirst() {
+		recognitionFirstStatus = "✅ **ENABLED**"
+		recognitionFirstAction = "Disable"
+	}
+
+	shuffleRatingsStatus := "❌ **DISABLED**"
+	shuffleRatingsAction := "Enable"
+	if prefs.ShuffleRatings() {
+		shuffleRatingsStatus = "✅ **ENABLED**"
+		shuffleRatingsAction = "Disable"
+	}
+
+	wordSenseStatus := "❌ **DISABLED**"
+	wordSenseAction := "Show"
+	if prefs.ShowWordSense() {
+		wordSenseStatus = "✅ **ENABLED**"
+		wordSenseAction = "Hide"
+	}
+
+	studyPriority := formatStudyPriority(prefs.GetStudyPriority())
+	studyPriorityNext := formatStudyPriority(nextStudyPriority(prefs.GetStudyPriority()))
+
+	newWordOrder := formatNewWordOrder(prefs.GetNewWordOrder())
+	newWordOrderNext := formatNewWordOrder(nextNewWordOrder(prefs.GetNewWordOrder()))
+
+	answerMode := formatAnswerMode(prefs.GetAnswerMode())
+	answerModeNext := formatAnswerMode(nextAnswerMode(prefs.GetAnswerMode()))
+
+	choiceGrading := formatChoiceGrading(prefs.GetChoiceGrading())
+	questionDirection := formatQuestionDirectionSetting(prefs.GetQuestionDirection())
+
+	hintType := formatHintType(prefs.GetHintType())
 	reminderInterval := prefs.GetReminderInterval()
+	reminderMode := formatReminderMode(prefs)
+	timezone := strings.ReplaceAll(prefs.Location().String(), "_", "\\_")
+	reviewAhead := formatReviewAhead(prefs.GetReviewAheadMinutes())
+	sessionLimit := formatSessionLimit(prefs.GetMaxSessionMinutes())
+	dailyNewLimit := formatDailyNewLimit(prefs.GetDailyNewLimit())
 
 	// Build settings message
 	settingsText := fmt.Sprintf(
 		"⚙️ **Settings**\n\n"+
 			"🔤 Grammar Tips: %s\n"+
 			"⏰ Smart Reminders: %s\n"+
-			"⌛️ Reminder Interval: **%d minutes**\n\n"+
+			"📈 Session Scoreboard: %s\n"+
+			"🏁 Recap When Finishing: %s\n"+
+			"📰 Ignore Articles (de/het/een): %s\n"+
+			"🔠 Strict Accents (één ≠ een): %s\n"+
+			"🇬🇧 British/American Spellings (colour = color): %s\n"+
+			"👀 Two-Step Reveal: %s\n"+
+			"⚡ Auto-Easy for Fast Correct Answers: %s\n"+
+			"🤔 Rate Confidence (sure answers wait longer): %s\n"+
+			"🎲 Spread Due Dates (avoid review pile-ups): %s\n"+
+			"👥 Start New Words at Community Difficulty: %s\n"+
+			"🎯 Study Priority: **%s**\n"+
+			"🆕 New Word Order: **%s**\n"+
+			"📋 Reviews Only (no new words): %s\n"+
+			"✍️ Answer By: **%s**\n"+
+			"🎓 Rating After Correct Choice: **%s**\n"+
+			"🔁 Question Direction: **%s**\n"+
+			"🇳🇱 Recognition First for New Words: %s\n"+
+			"🔀 Shuffle Rating Buttons: %s\n"+
+			"🧭 Meaning of Ambiguous Words: %s\n"+
+			"💡 Question Hint: **%s** (change with /hint)\n"+
+			"🗞 Reminder Style: **%s** (change with /digest)\n"+
+			"🌍 Time Zone: **%s** (change with /set\\_timezone)\n"+
+			"⌛️ Reminder Interval: **%d minutes**\n"+
+			"⏩ Review Ahead: **%s**\n"+
+			"⏱ Session Limit: **%s**\n"+
+			"🌱 New Words per Day: **%s**\n\n"+
 			"_Use the buttons below to adjust settings:_",
-		grammarTipsStatus, smartRemindersStatus, reminderInterval)
+		grammarTipsStatus, smartRemindersStatus, sessionProgressStatus, sessionRecapStatus, ignoreArticlesStatus, strictAccentsStatus, spellingVariantsStatus, stagedRevealStatus, autoEasyStatus, rateConfidenceStatus, intervalFuzzStatus, communityDifficultyStatus, studyPriority, newWordOrder, reviewsOnlyStatus, answerMode, choiceGrading, questionDirection, recognitionFirstStatus, shuffleRatingsStatus, wordSenseStatus, hintType, reminderMode, timezone, reminderInterval, reviewAhead, sessionLimit, dailyNewLimit)
 
 	// Create settings keyboard
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -97,14 +279,248 @@ func (h *BotHandler) handleMenuSettings(ctx context.Context, callback *tgbotapi.
 				"toggle_smart_reminders"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➖ 15min", "set_interval_minus-15"),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📈 %s Session Scoreboard", sessionProgressAction),
+				"toggle_session_progress"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🏁 %s Session Recap", sessionRecapAction),
+				"toggle_session_recap"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📰 %s Ignore Articles", ignoreArticlesAction),
+				"toggle_ignore_articles"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🔠 %s Strict Accents", strictAccentsAction),
+				"toggle_strict_accents"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🇬🇧 %s Either Spelling", spellingVariantsAction),
+				"toggle_spelling_variants"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("👀 %s Two-Step Reveal", stagedRevealAction),
+				"toggle_staged_reveal"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⚡ %s Auto-Easy", autoEasyAction),
+				"toggle_auto_easy"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🤔 %s Confidence Rating", rateConfidenceAction),
+				"toggle_rate_confidence"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🎲 %s Spread Due Dates", intervalFuzzAction),
+				"toggle_interval_fuzz"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("👥 %s Community Difficulty", communityDifficultyAction),
+				"toggle_community_difficulty"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🎯 Switch to %s", studyPriorityNext),
+				"toggle_study_priority"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🆕 Introduce New Words %s", newWordOrderNext),
+				"toggle_new_word_order"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📋 %s Reviews Only", reviewsOnlyAction),
+				"toggle_reviews_only"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✍️ Answer By %s", answerModeNext),
+				"toggle_answer_mode"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎓 Change Rating After Correct Choice", "toggle_choice_grading"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Change Question Direction", "toggle_question_direction"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🇳🇱 %s Recognition First", recognitionFirstAction),
+				"toggle_recognition_first"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🔀 %s Rating Shuffle", shuffleRatingsAction),
+				"toggle_shuffle_ratings"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🧭 %s Word Meanings", wordSenseAction),
+				"toggle_word_sense"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➖ 15min", "set_interval_-15"),
 			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⏰ %dmin", reminderInterval), "noop"),
-			tgbotapi.NewInlineKeyboardButtonData("➕ 15min", "set_interval_plus-15"),
+			tgbotapi.NewInlineKeyboardButtonData("➕ 15min", "set_interval_15"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➖ 1h", "set_interval_-60"),
+			tgbotapi.NewInlineKeyboardButtonData("➕ 1h", "set_interval_60"),
+		),
+		createReviewAheadRow(),
+		createSessionLimitRow(),
+		createDailyNewLimitRow(),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🏠 Back to Menu", "back_menu"),
 		),
 	)
 
-	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID, settingsText, keyboard)
+	return settingsText, keyboard, nil
+}
+
+// reviewAheadOptions are the review-ahead windows offered in settings, in minutes
+var reviewAheadOptions = []int{0, 60, 120, 240}
+
+// createReviewAheadRow creates the keyboard row for choosing a review-ahead window
+func createReviewAheadRow() []tgbotapi.InlineKeyboardButton {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, minutes := range reviewAheadOptions {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			"⏩ "+formatReviewAhead(minutes), fmt.Sprintf("set_ahead_%d", minutes)))
+	}
+	return row
+}
+
+// formatReviewAhead formats a review-ahead window for display
+func formatReviewAhead(minutes int) string {
+	switch {
+	case minutes == 0:
+		return "Off"
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dmin", minutes)
+	}
+}
+
+// sessionLimitOptions are the session time caps offered in settings, in minutes
+var sessionLimitOptions = []int{0, 10, 15, 30}
+
+// createSessionLimitRow creates the keyboard row for choosing a session time cap
+func createSessionLimitRow() []tgbotapi.InlineKeyboardButton {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, minutes := range sessionLimitOptions {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			"⏱ "+formatSessionLimit(minutes), fmt.Sprintf("set_sessionmax_%d", minutes)))
+	}
+	return row
+}
+
+// formatSessionLimit formats a session time cap for display
+func formatSessionLimit(minutes int) string {
+	if minutes == 0 {
+		return "Off"
+	}
+	return fmt.Sprintf("%dmin", minutes)
+}
+
+// dailyNewLimitOptions are the daily new-word limits offered in settings
+var dailyNewLimitOptions = []int{5, 10, 20, 0}
+
+// createDailyNewLimitRow creates the keyboard row for choosing a daily new-word limit
+func createDailyNewLimitRow() []tgbotapi.InlineKeyboardButton {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, limit := range dailyNewLimitOptions {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			"🌱 "+formatDailyNewLimit(limit), fmt.Sprintf("set_newmax_%d", limit)))
+	}
+	return row
+}
+
+// formatDailyNewLimit formats a daily new-word limit for display
+func formatDailyNewLimit(limit int) string {
+	if limit == 0 {
+		return "No limit"
+	}
+	return fmt.Sprintf("%d", limit)
+}
+
+// nextStudyPriority returns the strategy the settings toggle switches to
+func nextStudyPriority(priority user.StudyPriority) user.StudyPriority {
+	if priority == user.StudyPriorityLearningFirst {
+		return user.StudyPriorityBalanced
+	}
+	return user.StudyPriorityLearningFirst
+}
+
+// formatStudyPriority formats a study prioritization strategy for display
+func formatStudyPriority(priority user.StudyPriority) string {
+	if priority == user.StudyPriorityLearningFirst {
+		return "Learning First"
+	}
+	return "Balanced"
+}
+
+// nextNewWordOrder returns the introduction order the settings toggle switches to
+func nextNewWordOrder(order user.NewWordOrder) user.NewWordOrder {
+	if order == user.NewWordOrderSequential {
+		return user.NewWordOrderRandom
+	}
+	return user.NewWordOrderSequential
+}
+
+// formatNewWordOrder formats a new word introduction order for display
+func formatNewWordOrder(order user.NewWordOrder) string {
+	if order == user.NewWordOrderSequential {
+		return "In Order"
+	}
+	return "Randomly"
+}
+
+// nextAnswerMode returns the answer mode the settings toggle switches to
+func nextAnswerMode(mode user.AnswerMode) user.AnswerMode {
+	if mode == user.AnswerModeTyped {
+		return user.AnswerModeChoice
+	}
+	return user.AnswerModeTyped
+}
+
+// formatAnswerMode formats an answer mode for display
+func formatAnswerMode(mode user.AnswerMode) string {
+	if mode == user.AnswerModeTyped {
+		return "Typing"
+	}
+	return "Multiple Choice"
+}
+
+// formatChoiceGrading formats a multiple-choice grading mode for display
+func formatChoiceGrading(grading user.ChoiceGrading) string {
+	switch grading {
+	case user.ChoiceGradingCapped:
+		return "No Easy"
+	case user.ChoiceGradingAutoGood:
+		return "Auto Good"
+	default:
+		return "Free"
+	}
+}
+
+// formatQuestionDirectionSetting formats a question direction preference for display
+func formatQuestionDirectionSetting(direction user.QuestionDirection) string {
+	switch direction {
+	case user.QuestionDirectionToDutch:
+		return fmt.Sprintf("%s ➡️ %s", usecases.LanguageEnglish.Name, usecases.LanguageDutch.Name)
+	case user.QuestionDirectionFromDutch:
+		return fmt.Sprintf("%s ➡️ %s", usecases.LanguageDutch.Name, usecases.LanguageEnglish.Name)
+	default:
+		return "Mixed"
+	}
+}
+
+// formatHintType formats a question hint type for display
+func formatHintType(hintType user.HintType) string {
+	switch hintType {
+	case user.HintTypeFirstLetter:
+		return "First Letter"
+	case user.HintTypeLength:
+		return "Word Length"
+	case user.HintTypeNone:
+		return "None"
+	default:
+		return "Category"
+	}
 }
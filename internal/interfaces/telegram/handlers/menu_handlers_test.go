@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// newTestCommand creates a command message from the test user
+func newTestCommand(command string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		MessageID: 9,
+		From:      &tgbotapi.User{ID: 1001, FirstName: "Anna"},
+		Chat:      &tgbotapi.Chat{ID: 1001},
+		Text:      "/" + command,
+		Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command) + 1}},
+	}
+}
+
+func TestSettings_EntryPoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      func(h *BotHandler)
+		wantSends int
+		wantEdits int
+	}{
+		{
+			name:      "command sends a new message",
+			open:      func(h *BotHandler) { h.handleMessage(context.Background(), newTestCommand("settings")) },
+			wantSends: 1,
+		},
+		{
+			name:      "menu button edits the menu",
+			open:      func(h *BotHandler) { h.handleCallbackQuery(context.Background(), newTestCallback("menu_settings")) },
+			wantEdits: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestBotHandler(t, nil)
+
+			tt.open(h)
+
+			sends, edits := fake.callsTo("sendMessage"), fake.callsTo("editMessageText")
+			if len(sends) != tt.wantSends || len(edits) != tt.wantEdits {
+				t.Fatalf("got %d sends and %d edits, want %d and %d", len(sends), len(edits), tt.wantSends, tt.wantEdits)
+			}
+			shown := append(sends, edits...)[0]
+			if !strings.Contains(shown.params.Get("text"), "Settings") {
+				t.Errorf("shown text = %q, want the settings", shown.params.Get("text"))
+			}
+			if shown.params.Get("reply_markup") == "" {
+				t.Error("settings shown without a keyboard")
+			}
+		})
+	}
+}
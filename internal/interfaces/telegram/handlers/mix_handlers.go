@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// handleMix processes the /mix [category:count ...|off] command, setting the per-category daily plan
+func (h *BotHandler) handleMix(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	args := strings.TrimSpace(message.CommandArguments())
+
+	if args == "" {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+		if err != nil {
+			log.Printf("Failed to get user preferences: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading your daily mix. Please try again.")
+			return
+		}
+		h.bot.SendMessage(message.Chat.ID, formatDailyMix(prefs.GetDailyMix()))
+		return
+	}
+
+	mix, err := parseDailyMixArgs(args)
+	if err != nil {
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("%v\n\nUsage: /mix food:10 verbs:10 (or /mix off)", err))
+		return
+	}
+
+	if err := h.userUseCase.SetDailyMix(ctx, user.ID(), mix); err != nil {
+		log.Printf("Failed to set daily mix: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error saving your daily mix. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, formatDailyMix(mix))
+}
+
+// parseDailyMixArgs parses /mix arguments, accepting "off" to clear the plan
+func parseDailyMixArgs(args string) (user.DailyMix, error) {
+	if strings.EqualFold(args, "off") {
+		return nil, nil
+	}
+
+	mix, err := user.ParseDailyMix(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, quota := range mix {
+		if !vocabulary.IsValidCategory(quota.Category) {
+			return nil, fmt.Errorf("unknown category %q", quota.Category)
+		}
+	}
+
+	return mix, nil
+}
+
+// formatDailyMix formats a daily mix for display
+func formatDailyMix(mix user.DailyMix) string {
+	if len(mix) == 0 {
+		return "🥗 No daily mix set - sessions draw from all categories.\n\nSet one with /mix food:10 verbs:10"
+	}
+
+	var lines []string
+	for _, quota := range mix {
+		lines = append(lines, fmt.Sprintf("• %d %s", quota.Words, quota.Category))
+	}
+	return "🥗 Your daily mix:\n" + strings.Join(lines, "\n") +
+		"\n\nSessions work through these first each day, then continue with everything else. Clear it with /mix off"
+}
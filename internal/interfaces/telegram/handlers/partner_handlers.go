@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// partnerUsage explains the /partner subcommands
+const partnerUsage = "Usage:\n" +
+	"/partner invite - Get a code for your study partner\n" +
+	"/partner join <code> - Accept your partner's code\n" +
+	"/partner add <word> - Add a word to your shared deck\n" +
+	"/partner leave - Stop studying together\n" +
+	"/partner - See how you're both doing"
+
+// handleStudyPartner processes the /partner command and its subcommands
+func (h *BotHandler) handleStudyPartner(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		h.sendStudyPartnerOverview(ctx, message.Chat.ID, u)
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "invite":
+		h.handlePartnerInvite(ctx, message, u)
+	case "join":
+		if len(args) != 2 {
+			h.bot.SendMessage(message.Chat.ID, partnerUsage)
+			return
+		}
+		h.handlePartnerJoin(ctx, message, u, args[1])
+	case "add":
+		if len(args) < 2 {
+			h.bot.SendMessage(message.Chat.ID, partnerUsage)
+			return
+		}
+		h.handlePartnerAdd(ctx, message, u, strings.Join(args[1:], " "))
+	case "leave":
+		h.handlePartnerLeave(ctx, message, u)
+	default:
+		h.bot.SendMessage(message.Chat.ID, partnerUsage)
+	}
+}
+
+// handlePartnerInvite sends the user a fresh invite code to pass on to their partner
+func (h *BotHandler) handlePartnerInvite(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	code, err := h.learningUseCase.CreatePartnerInvite(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to create partner invite: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error creating your invite. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf(
+		"🤝 Ask your study partner to send me:\n\n/partner join %s\n\nThe code works once; a new invite replaces it.", code))
+}
+
+// handlePartnerJoin links the user with the partner who created the invite code
+func (h *BotHandler) handlePartnerJoin(ctx context.Context, message *tgbotapi.Message, u *user.User, code string) {
+	partner, err := h.learningUseCase.AcceptPartnerInvite(ctx, u.ID(), code)
+	switch {
+	case errors.Is(err, usecases.ErrInvalidPartnerInvite):
+		h.bot.SendMessage(message.Chat.ID, "🤔 That invite code isn't valid anymore. Ask your partner for a new one with /partner invite")
+		return
+	case errors.Is(err, usecases.ErrOwnPartnerInvite):
+		h.bot.SendMessage(message.Chat.ID, "🙃 That's your own invite code. Send it to your study partner instead.")
+		return
+	case errors.Is(err, learning.ErrAlreadyLinked):
+		h.bot.SendMessage(message.Chat.ID, "You or your partner already study with someone. Use /partner leave first.")
+		return
+	case err != nil:
+		log.Printf("Failed to accept partner invite: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error linking your accounts. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf(
+		"🤝 You're now study partners with %s! Add words to your shared deck with /partner add <word> "+
+			"and study them with /learn %s", partnerName(partner), learning.PartnerDeckTag))
+	h.bot.SendMessage(int64(partner.TelegramID()), fmt.Sprintf(
+		"🤝 %s accepted your invite. You're now study partners! See your progress with /partner", partnerName(u)))
+}
+
+// handlePartnerAdd adds a word to the shared deck and lets the partner know
+func (h *BotHandler) handlePartnerAdd(ctx context.Context, message *tgbotapi.Message, u *user.User, term string) {
+	word, partner, added, err := h.learningUseCase.AddToSharedDeck(ctx, u.ID(), term)
+	if errors.Is(err, usecases.ErrNoStudyPartner) {
+		h.bot.SendMessage(message.Chat.ID, "You don't have a study partner yet. Start with /partner invite")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to add %q to shared deck: %v", term, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error adding that word. Please try again.")
+		return
+	}
+
+	if word == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 No word found matching \"%s\".", shared.EscapeMarkdown(term)))
+		return
+	}
+	if !added {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("*%s* is already in your shared deck.", shared.EscapeMarkdown(word.Dutch())))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("📥 Added *%s* to your shared deck.", shared.EscapeMarkdown(word.Dutch())))
+	if partner != nil {
+		h.bot.SendMessageWithMarkdown(int64(partner.TelegramID()), fmt.Sprintf("📥 %s added *%s* to your shared deck. Study it with /learn %s",
+			shared.EscapeMarkdown(partnerName(u)), shared.EscapeMarkdown(word.Dutch()), learning.PartnerDeckTag))
+	}
+}
+
+// handlePartnerLeave ends the user's study partnership
+func (h *BotHandler) handlePartnerLeave(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	partner, err := h.learningUseCase.RemoveStudyPartner(ctx, u.ID())
+	if errors.Is(err, usecases.ErrNoStudyPartner) {
+		h.bot.SendMessage(message.Chat.ID, "You don't have a study partner.")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to remove study partner: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your partnership. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, "👋 You're no longer study partners. The words you studied keep their progress.")
+	if partner != nil {
+		h.bot.SendMessage(int64(partner.TelegramID()), fmt.Sprintf(
+			"👋 %s ended your study partnership. The words you studied keep their progress.", partnerName(u)))
+	}
+}
+
+// sendStudyPartnerOverview shows both partners' progress on the shared deck
+func (h *BotHandler) sendStudyPartnerOverview(ctx context.Context, chatID int64, u *user.User) {
+	overview, err := h.learningUseCase.GetStudyPartnerOverview(ctx, u.ID())
+	if err != nil {
+		log.Printf("Failed to get study partner overview: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error getting your partner's progress. Please try again.")
+		return
+	}
+
+	if overview == nil {
+		h.bot.SendMessage(chatID, "🤝 Study with a partner: share a deck of words and keep each other on track.\n\n"+partnerUsage)
+		return
+	}
+
+	text := fmt.Sprintf("🤝 *Study partners: you & %s*\n\n", shared.EscapeMarkdown(partnerName(overview.Partner)))
+	if overview.Mine.TotalWords == 0 {
+		text += "Your shared deck is empty. Add words with /partner add <word>"
+	} else {
+		text += fmt.Sprintf("📚 Shared deck: %d words\n\n", overview.Mine.TotalWords) +
+			formatDeckProgress("You", overview.Mine) + "\n" +
+			formatDeckProgress(shared.EscapeMarkdown(partnerName(overview.Partner)), overview.Theirs) +
+			fmt.Sprintf("\nStudy the deck with /learn %s", learning.PartnerDeckTag)
+	}
+
+	h.bot.SendMessageWithMarkdown(chatID, text)
+}
+
+// formatDeckProgress formats one partner's progress on the shared deck
+func formatDeckProgress(name string, progress *learning.DeckProgress) string {
+	return fmt.Sprintf("*%s*\n📖 Started: %d/%d\n✅ Learned: %d\n⏰ Due now: %d\n",
+		name, progress.StudiedWords, progress.TotalWords, progress.LearnedWords, progress.DueWords)
+}
+
+// partnerName returns how a study partner is addressed in messages
+func partnerName(u *user.User) string {
+	switch {
+	case u == nil:
+		return "your partner"
+	case u.FirstName() != "":
+		return u.FirstName()
+	case u.Username() != "":
+		return "@" + u.Username()
+	default:
+		return "your partner"
+	}
+}
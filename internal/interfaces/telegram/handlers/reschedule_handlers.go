@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleReschedule processes the /reschedule command, asking before any review dates are moved
+func (h *BotHandler) handleReschedule(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Recalculate", "reschedule_confirm"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Cancel", "back_menu"),
+		),
+	)
+
+	h.bot.SendMessageWithKeyboard(message.Chat.ID,
+		"🗓 Recalculate your review dates?\n\n"+
+			"Each word you've learned gets a new due date based on how well you know it and "+
+			"the current scheduling settings. Words you postponed will lose their postponement.",
+		keyboard)
+}
+
+// handleRescheduleConfirm recalculates the user's review dates once they confirmed
+func (h *BotHandler) handleRescheduleConfirm(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	moved, err := h.learningUseCase.RecalculateSchedule(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to recalculate schedule: %v", err)
+		h.bot.EditMessageWithKeyboard(chatID, messageID,
+			"Sorry, there was an error recalculating your reviews. Please try again.", shared.CreateMainMenuKeyboard())
+		return
+	}
+
+	text := "🗓 Your review dates already match the current settings."
+	if moved > 0 {
+		text = fmt.Sprintf("🗓 Done! Updated the review date of %d word(s).", moved)
+	}
+	h.bot.EditMessageWithKeyboard(chatID, messageID, text, shared.CreateMainMenuKeyboard())
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleReset processes the /reset command, asking before any progress is deleted
+func (h *BotHandler) handleReset(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗑 Yes, delete everything", "confirm_reset"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Cancel", "cancel_reset"),
+		),
+	)
+
+	h.bot.SendMessageWithKeyboard(message.Chat.ID,
+		"⚠️ Reset all your progress?\n\n"+
+			"Every word you've studied goes back to new, and your review history, streak and stats are deleted. "+
+			"Your settings and tags are kept.\n\n"+
+			"This can't be undone. To keep a copy, use /export csv first.",
+		keyboard)
+}
+
+// handleResetConfirm deletes the user's progress once they confirmed, ending any question in progress
+func (h *BotHandler) handleResetConfirm(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	if err := h.learningUseCase.ResetProgress(ctx, user.ID()); err != nil {
+		log.Printf("Failed to reset progress: %v", err)
+		h.bot.EditMessageWithKeyboard(chatID, messageID,
+			"Sorry, there was an error resetting your progress. Please try again.", shared.CreateMainMenuKeyboard())
+		return
+	}
+
+	// The active question belongs to progress that no longer exists
+	userID := int64(user.ID())
+	if session, exists := h.activeSessions[userID]; exists {
+		session.ClaimAnswer()
+		delete(h.activeSessions, userID)
+	}
+
+	h.bot.EditMessageWithKeyboard(chatID, messageID,
+		"🧹 Your progress has been reset. Every word is new again - happy learning!", shared.CreateMainMenuKeyboard())
+}
+
+// handleResetCancel leaves the user's progress untouched
+func (h *BotHandler) handleResetCancel(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User) {
+	h.bot.EditMessageWithKeyboard(callback.Message.Chat.ID, callback.Message.MessageID,
+		"👍 Reset cancelled. Your progress is safe.", shared.CreateMainMenuKeyboard())
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+	"dutch-learning-bot/internal/infrastructure/persistence"
+)
+
+// countRows counts the user's rows in a table
+func countRows(t *testing.T, db *sql.DB, table string, userID user.ID) int {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE user_id = ?`, int64(userID)).Scan(&n); err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	return n
+}
+
+func TestResetProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		confirm   bool
+		wantWiped bool
+	}{
+		{"confirmed", true, true},
+		{"cancelled", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, _, db := newTestBotHandlerWithDB(t, nil)
+			u := newTestUser(t, h, nil)
+			other, err := h.userUseCase.GetOrCreateUser(ctx, 1002, "bram", "Bram", "", "en")
+			if err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+
+			// Both users studied both words
+			learningRepo := persistence.NewLearningRepository(db)
+			vocabRepo := persistence.NewVocabularyRepository(db)
+			for _, pair := range [][2]string{{"house", "huis"}, {"tree", "boom"}} {
+				word := vocabulary.NewWord(pair[0], pair[1], vocabulary.Category("basics"))
+				if err := vocabRepo.Save(ctx, word); err != nil {
+					t.Fatalf("failed to save word: %v", err)
+				}
+				for _, userID := range []user.ID{u.ID(), other.ID()} {
+					progress := learning.NewUserProgress(userID, word.ID())
+					progress.Review(learning.Good)
+					history := learning.NewReviewHistory(userID, word.ID(), learning.Good, 2*time.Second)
+					if err := learningRepo.SaveProgressAndHistory(ctx, progress, history); err != nil {
+						t.Fatalf("failed to save review: %v", err)
+					}
+				}
+			}
+			session := startTestQuestion(h, u)
+
+			if tt.confirm {
+				h.handleResetConfirm(ctx, newTestCallback("confirm_reset"), u)
+			} else {
+				h.handleResetCancel(ctx, newTestCallback("cancel_reset"), u)
+			}
+
+			wantRows := 2
+			if tt.wantWiped {
+				wantRows = 0
+			}
+			for _, table := range []string{"user_progress", "review_history"} {
+				if got := countRows(t, db, table, u.ID()); got != wantRows {
+					t.Errorf("%d %s rows left, want %d", got, table, wantRows)
+				}
+				if got := countRows(t, db, table, other.ID()); got != 2 {
+					t.Errorf("another user has %d %s rows left, want 2", got, table)
+				}
+			}
+
+			stats, err := h.learningUseCase.GetUserStats(ctx, u.ID())
+			if err != nil {
+				t.Fatalf("GetUserStats: %v", err)
+			}
+			studied := stats.LearningWords + stats.ReviewWords
+			if (studied == 0 && stats.TotalReviews == 0 && stats.CurrentStreak == 0) != tt.wantWiped {
+				t.Errorf("stats after reset = %d studied, %d reviews, streak %d; want them zeroed: %v",
+					studied, stats.TotalReviews, stats.CurrentStreak, tt.wantWiped)
+			}
+
+			_, active := h.activeSessions[int64(u.ID())]
+			if active == tt.wantWiped {
+				t.Errorf("question still active = %v, want %v", active, !tt.wantWiped)
+			}
+			if tt.wantWiped && session.ClaimAnswer() {
+				t.Error("the cleared question could still be answered")
+			}
+		})
+	}
+}
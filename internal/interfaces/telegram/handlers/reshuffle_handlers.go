@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// handleReshuffle processes the /reshuffle command, picking a fresh order for words not studied yet
+func (h *BotHandler) handleReshuffle(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	if err := h.userUseCase.ReshuffleNewWords(ctx, user.ID()); err != nil {
+		log.Printf("Failed to reshuffle new words: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, "🔀 New words will now be introduced in a fresh random order. "+
+		"Words you've already studied keep their progress and schedule.")
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import "sync"
+
+// reviewWorkers runs background review work in order for each user while capping
+// how many jobs run at once across all users
+type reviewWorkers struct {
+	slots      chan struct{} // One token per running job
+	maxPending int           // Jobs a user may have queued or running
+
+	mu     sync.Mutex
+	queues map[int64][]func() // Per-user FIFO; the head is the job being run
+}
+
+// newReviewWorkers creates review workers running at most maxConcurrent jobs at once
+// and holding at most maxPending jobs per user
+func newReviewWorkers(maxConcurrent, maxPending int) *reviewWorkers {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxPending < 1 {
+		maxPending = 1
+	}
+
+	return &reviewWorkers{
+		slots:      make(chan struct{}, maxConcurrent),
+		maxPending: maxPending,
+		queues:     make(map[int64][]func()),
+	}
+}
+
+// submit queues a job behind the user's earlier jobs. It returns false, dropping the job,
+// when the user already has maxPending jobs waiting.
+func (w *reviewWorkers) submit(userID int64, job func()) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	queue := w.queues[userID]
+	if len(queue) >= w.maxPending {
+		return false
+	}
+
+	w.queues[userID] = append(queue, job)
+	if len(queue) == 0 {
+		// Nobody is draining this user's queue yet
+		go w.drain(userID)
+	}
+	return true
+}
+
+// drain runs the user's jobs one at a time until their queue is empty
+func (w *reviewWorkers) drain(userID int64) {
+	for {
+		w.mu.Lock()
+		job := w.queues[userID][0]
+		w.mu.Unlock()
+
+		w.slots <- struct{}{}
+		job()
+		<-w.slots
+
+		w.mu.Lock()
+		queue := w.queues[userID][1:]
+		if len(queue) == 0 {
+			delete(w.queues, userID)
+			w.mu.Unlock()
+			return
+		}
+		w.queues[userID] = queue
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReviewWorkers_OrderedUnderRapidTaps(t *testing.T) {
+	const (
+		users         = 4
+		tapsPerUser   = 50
+		maxConcurrent = 2
+	)
+	workers := newReviewWorkers(maxConcurrent, tapsPerUser)
+
+	var (
+		mu      sync.Mutex
+		order   = make(map[int64][]int)
+		running int32
+		peak    int32
+		wg      sync.WaitGroup
+	)
+	for userID := int64(1); userID <= users; userID++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			var jobs sync.WaitGroup
+			for tap := 0; tap < tapsPerUser; tap++ {
+				tap := tap
+				jobs.Add(1)
+				submitted := workers.submit(userID, func() {
+					defer jobs.Done()
+					now := atomic.AddInt32(&running, 1)
+					defer atomic.AddInt32(&running, -1)
+					for {
+						seen := atomic.LoadInt32(&peak)
+						if now <= seen || atomic.CompareAndSwapInt32(&peak, seen, now) {
+							break
+						}
+					}
+
+					mu.Lock()
+					order[userID] = append(order[userID], tap)
+					mu.Unlock()
+				})
+				if !submitted {
+					t.Errorf("user %d: tap %d was dropped", userID, tap)
+					jobs.Done()
+				}
+			}
+			jobs.Wait()
+		}(userID)
+	}
+	wg.Wait()
+
+	if peak > maxConcurrent {
+		t.Errorf("%d jobs ran at once, want at most %d", peak, maxConcurrent)
+	}
+	for userID := int64(1); userID <= users; userID++ {
+		taps := order[userID]
+		if len(taps) != tapsPerUser {
+			t.Fatalf("user %d: ran %d jobs, want %d", userID, len(taps), tapsPerUser)
+		}
+		for i, tap := range taps {
+			if tap != i {
+				t.Fatalf("user %d: job %d ran in position %d", userID, tap, i)
+			}
+		}
+	}
+}
+
+func TestReviewWorkers_DropsBeyondMaxPending(t *testing.T) {
+	workers := newReviewWorkers(1, 2)
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	if !workers.submit(1, func() { <-release }) {
+		t.Fatal("first job was dropped")
+	}
+	if !workers.submit(1, func() { close(done) }) {
+		t.Fatal("second job was dropped")
+	}
+	if workers.submit(1, func() { t.Error("job beyond the pending limit ran") }) {
+		t.Error("third job was queued past the pending limit")
+	}
+
+	close(release)
+	<-done
+
+	// Once the queue drains the user can submit again
+	again := make(chan struct{})
+	for !workers.submit(1, func() { close(again) }) {
+		time.Sleep(time.Millisecond)
+	}
+	<-again
+}
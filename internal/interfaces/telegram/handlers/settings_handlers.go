@@ -152,7 +152,7 @@ func (h *SettingsHandlers) handleSetReminderInterval(ctx context.Context, update
 
 	// Parse interval
 	interval, err := strconv.Atoi(args[1])
-	if err != nil || interval < 1 {
+	if err != nil || interval < user.MinReminderInterval {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 			"Please provide a valid number of minutes (minimum 1).\n"+
 				"Example: /set\\_reminder\\_interval 30")
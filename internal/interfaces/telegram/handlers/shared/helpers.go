@@ -2,9 +2,14 @@ package shared
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/vocabulary"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -16,6 +21,9 @@ func CreateMainMenuKeyboard() tgbotapi.InlineKeyboardMarkup {
 			tgbotapi.NewInlineKeyboardButtonData("📚 Start Learning", "menu_learn"),
 			tgbotapi.NewInlineKeyboardButtonData("📊 View Stats", "menu_stats"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📂 Study a Category", "menu_categories"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❓ Help", "menu_help"),
 			tgbotapi.NewInlineKeyboardButtonData("⚙️ Settings", "menu_settings"),
@@ -23,9 +31,37 @@ func CreateMainMenuKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
+// CreateCategoryKeyboard creates a keyboard for picking a vocabulary category to study, two per row
+func CreateCategoryKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, category := range vocabulary.Categories() {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(FormatCategory(category), "study_"+string(category)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Back to Menu", "back_menu"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// FormatCategory formats a vocabulary category for display, such as "verbs action" for verbs_action
+func FormatCategory(category vocabulary.Category) string {
+	return strings.ReplaceAll(string(category), "_", " ")
+}
+
 // CreateStatsKeyboard creates a keyboard for stats view
 func CreateStatsKeyboard(isCallback bool) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📂 By Category", "menu_category_stats"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📚 Start Learning", "menu_learn"),
 			tgbotapi.NewInlineKeyboardButtonData("🏠 Back to Menu", "back_menu"),
@@ -33,6 +69,16 @@ func CreateStatsKeyboard(isCallback bool) tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
+// CreateCategoryStatsKeyboard creates a keyboard for the per-category stats view
+func CreateCategoryStatsKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 Back to Stats", "menu_stats"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Back to Menu", "back_menu"),
+		),
+	)
+}
+
 // CreateHelpKeyboard creates a keyboard for help view
 func CreateHelpKeyboard(isCallback bool) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
@@ -52,21 +98,129 @@ func CreateNoWordsKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
+// CreateSessionInProgressKeyboard creates a keyboard for resuming or replacing an unfinished question
+func CreateSessionInProgressKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▶️ Continue", "resume_question"),
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Start over", "restart_learning"),
+		),
+	)
+}
+
 // FormatStatsText formats user statistics into a readable message
 func FormatStatsText(stats *learning.UserStats) string {
 	return fmt.Sprintf(
 		"📊 **Your Learning Stats**\n\n"+
+			"**Ready to study**\n"+
+			"⏰ Reviews due: %d\n"+
+			"🆕 New words available: %d\n"+
+			"_/learn serves due reviews first, then introduces new words._\n\n"+
+			"**Your words**\n"+
 			"📚 Total words: %d\n"+
-			"🆕 New: %d\n"+
 			"📖 Learning: %d\n"+
-			"✅ Review: %d\n"+
-			"⏰ Due now: %d\n\n"+
-			"🎯 Average difficulty: %.1f/10\n"+
+			"✅ Review: %d\n\n"+
+			"🎯 Average difficulty: %.1f/10%s\n"+
 			"📈 Total reviews: %d\n"+
-			"✅ Correct answers: %d\n\n"+
+			"✅ Correct answers: %d\n"+
+			"⚖️ Weighted accuracy: %.0f%%\n"+
+			"🎯 Retention (%dd): %s\n"+
+			"🔥 Streak: %s (best %d)\n\n"+
 			"Keep up the great work! 🌟",
-		stats.TotalWords, stats.NewWords, stats.LearningWords, stats.ReviewWords,
-		stats.DueWords, stats.AvgDifficulty, stats.TotalReviews, stats.CorrectReviews)
+		stats.DueWords, stats.NewWords,
+		stats.TotalWords, stats.LearningWords, stats.ReviewWords,
+		stats.AvgDifficulty, formatTrend(stats.DifficultyTrend), stats.TotalReviews, stats.CorrectReviews,
+		stats.WeightedAccuracy*100, int(learning.RetentionWindow.Hours()/24), formatRetention(stats.RetentionRate),
+		formatDays(stats.CurrentStreak), stats.LongestStreak)
+}
+
+// formatRetention formats a retention rate as a percentage, or a dash when there were no reviews
+func formatRetention(rate float64) string {
+	if rate == learning.NoRetentionRate {
+		return "–"
+	}
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+// FormatCategoryStatsText formats per-category statistics as a table, the categories with the most
+// due words first. Categories without studied words are left out.
+func FormatCategoryStatsText(stats map[vocabulary.Category]*learning.CategoryStats) string {
+	var categories []vocabulary.Category
+	for category, categoryStats := range stats {
+		if categoryStats.Studied > 0 {
+			categories = append(categories, category)
+		}
+	}
+	if len(categories) == 0 {
+		return "📂 **Stats by Category**\n\nYou haven't studied any words yet. Start with /learn!"
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := stats[categories[i]], stats[categories[j]]
+		if a.Due != b.Due {
+			return a.Due > b.Due
+		}
+		return categories[i] < categories[j]
+	})
+
+	var table strings.Builder
+	fmt.Fprintf(&table, "%-16s %7s %4s %5s\n", "Category", "Studied", "Due", "Diff")
+	for _, category := range categories {
+		categoryStats := stats[category]
+		fmt.Fprintf(&table, "%-16s %7d %4d %5.1f\n", FormatCategory(category),
+			categoryStats.Studied, categoryStats.Due, categoryStats.AvgDifficulty)
+	}
+
+	return "📂 **Stats by Category**\n\n```\n" + table.String() + "```\n_Diff is the average difficulty out of 10._"
+}
+
+// formatDays formats a number of days, e.g. "1 day" or "3 days"
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// formatTrend formats a trend as an arrow suffix, or nothing when unknown
+func formatTrend(trend learning.Trend) string {
+	switch trend {
+	case learning.TrendUp:
+		return " ⬆️"
+	case learning.TrendDown:
+		return " ⬇️"
+	case learning.TrendFlat:
+		return " ➡️"
+	default:
+		return ""
+	}
+}
+
+// FormatInterval formats a duration in the most readable unit: minutes, hours, days, months or years
+func FormatInterval(d time.Duration) string {
+	const (
+		day   = 24 * time.Hour
+		month = 30 * day
+		year  = 365 * day
+	)
+
+	switch {
+	case d < time.Hour:
+		return formatUnits(d.Minutes(), "m")
+	case d < day:
+		return formatUnits(d.Hours(), "h")
+	case d < month:
+		return formatUnits(float64(d)/float64(day), "d")
+	case d < year:
+		return formatUnits(float64(d)/float64(month), "mo")
+	default:
+		return formatUnits(float64(d)/float64(year), "y")
+	}
+}
+
+// formatUnits formats a value with at most one decimal, dropping a trailing ".0"
+func formatUnits(value float64, unit string) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + unit
 }
 
 // GetHelpText returns the standard help text
@@ -76,8 +230,36 @@ func GetHelpText() string {
 **Available Commands:**
 /start - Show welcome message
 /menu - Show main menu
-/learn - Start learning session
+/learn [category|tag] - Start learning session (optionally only one category, such as food, or words with your tag)
+/focus [category|tag] - Start a learning session without grammar tips
 /stats - View your progress
+/assess - Mark words you already know
+/define <word> - Look up a word and its grammar tips
+/card <word> - Show scheduling details for a word
+/why <word> - Explain why a word is due when it is
+/tag <word> <tag> - Tag a word for focused review (/tag alone lists your tags)
+/mix <category:count ...|off> - Set a daily mix such as "food:10 verbs:10"
+/direction <category> <mixed|to\_dutch|from\_dutch|off> - Pin the question direction of a category
+/sources <category ...|all> - Only introduce new words from these categories (reviews continue as usual)
+/reshuffle - Shuffle the order of words you haven't studied yet
+/reschedule - Recalculate your review dates with the current scheduling settings
+/reset - Delete all your progress and review history to start over
+/leaderboard [hide|show] - This week's most active learners; hide or show yourself on it
+/forecast - See how many reviews come due each day this week
+/undo - Undo your last review if you tapped the wrong rating
+/partner [invite|join|add|leave] - Share a deck with a study partner and follow each other's progress
+/setdifficulty <word> <1-10> - Override a word's difficulty
+/hint <category|first\_letter|length|none> - Choose the hint shown with questions
+/fsrs\_weights [weights|reset] - Show or tune the 19 FSRS scheduling weights (for advanced users)
+/learning\_steps [1m,10m|reset] - Show or set the steps new words go through before their first review
+/retention [0.85-0.97|reset] - Show or set how likely you should be to remember a word when it comes up
+/digest <hour|off> - Get one daily summary at the given hour instead of reminders
+/set\_timezone <zone> - Set your time zone, such as Europe/Amsterdam, for quiet hours, the digest and streaks
+/export [words] - Download your learning data (add "words" to include the vocabulary)
+/export csv - Download your progress as CSV; send that file to the bot to restore it, for example on another instance
+/export\_stats - Download per-word stats as a CSV spreadsheet
+/export\_settings - Back up your settings as JSON
+/import\_settings <json> - Restore settings from /export\_settings
 /help - Show this help
 
 **How it works:**
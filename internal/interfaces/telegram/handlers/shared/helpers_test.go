@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"dutch-learning-bot/internal/domain/learning"
+)
+
+func TestFormatStatsText_DueAndNewSeparately(t *testing.T) {
+	text := FormatStatsText(&learning.UserStats{DueWords: 3, NewWords: 7, TotalWords: 12})
+
+	for _, line := range []string{"⏰ Reviews due: 3\n", "🆕 New words available: 7\n", "📚 Total words: 12\n"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("stats text lacks %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestFormatInterval(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{10 * time.Minute, "10m"},
+		{59 * time.Minute, "59m"},
+		{time.Hour, "1h"},
+		{90 * time.Minute, "1.5h"},
+		{23 * time.Hour, "23h"},
+		{day, "1d"},
+		{36 * time.Hour, "1.5d"},
+		{29 * day, "29d"},
+		{30 * day, "1mo"},
+		{45 * day, "1.5mo"},
+		{364 * day, "12.1mo"},
+		{365 * day, "1y"},
+		{730 * day, "2y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			if got := FormatInterval(tt.d); got != tt.want {
+				t.Errorf("FormatInterval(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// sourcesUsage explains the /sources command
+const sourcesUsage = "Usage: /sources food verbs (or /sources all)"
+
+// handleSources processes the /sources [category ...|all] command, limiting the categories new words come from
+func (h *BotHandler) handleSources(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	args := strings.Fields(strings.ToLower(message.CommandArguments()))
+
+	if len(args) == 0 {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, user.ID())
+		if err != nil {
+			log.Printf("Failed to get user preferences: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading your new word sources. Please try again.")
+			return
+		}
+		h.bot.SendMessage(message.Chat.ID, formatNewWordCategories(prefs.GetNewWordCategories()))
+		return
+	}
+
+	var categories []string
+	if !(len(args) == 1 && args[0] == "all") {
+		for _, arg := range args {
+			category := strings.Trim(arg, ",")
+			if !vocabulary.IsValidCategory(category) {
+				h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Unknown category %q.\n\n%s", category, sourcesUsage))
+				return
+			}
+			categories = append(categories, category)
+		}
+	}
+
+	if err := h.userUseCase.SetNewWordCategories(ctx, user.ID(), categories); err != nil {
+		log.Printf("Failed to set new word categories: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error saving your new word sources. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, formatNewWordCategories(categories))
+}
+
+// formatNewWordCategories formats the categories new words are drawn from for display
+func formatNewWordCategories(categories []string) string {
+	if len(categories) == 0 {
+		return "🌱 New words come from every category.\n\nLimit them with /sources food verbs"
+	}
+
+	return "🌱 New words only come from: " + strings.Join(categories, ", ") +
+		"\n\nWords you've already started are still reviewed whatever their category. Allow everything again with /sources all"
+}
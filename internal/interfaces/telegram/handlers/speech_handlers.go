@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/domain/vocabulary"
+)
+
+// handleSpeakWord sends the pronunciation of a word's Dutch translation as an audio message
+func (h *BotHandler) handleSpeakWord(ctx context.Context, callback *tgbotapi.CallbackQuery, user *user.User, wordIDStr string) {
+	if h.config.Speech == nil {
+		return
+	}
+
+	wordID, err := strconv.ParseInt(wordIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Invalid speak word ID: %s", wordIDStr)
+		return
+	}
+
+	word, err := h.learningUseCase.GetWord(ctx, vocabulary.ID(wordID))
+	if err != nil || word == nil {
+		log.Printf("Failed to find word %d to speak: %v", wordID, err)
+		h.bot.SendMessage(callback.Message.Chat.ID, "Sorry, that word couldn't be found.")
+		return
+	}
+
+	path, err := h.config.Speech.AudioFile(ctx, word.Dutch())
+	if err != nil {
+		log.Printf("Failed to synthesize word %d: %v", wordID, err)
+		h.bot.SendMessage(callback.Message.Chat.ID, "Sorry, the pronunciation isn't available right now. Please try again later.")
+		return
+	}
+
+	if err := h.bot.SendAudio(callback.Message.Chat.ID, path, "🔊 "+word.Dutch()); err != nil {
+		log.Printf("Failed to send pronunciation of word %d: %v", wordID, err)
+		h.bot.SendMessage(callback.Message.Chat.ID, "Sorry, there was an error sending the pronunciation. Please try again.")
+	}
+}
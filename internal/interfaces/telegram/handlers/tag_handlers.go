@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/learning"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleTag processes the /tag <term> <tag> command, or lists the user's tags when called without arguments
+func (h *BotHandler) handleTag(ctx context.Context, message *tgbotapi.Message, user *user.User) {
+	const usage = "Usage: /tag <dutch or english word> <tag>"
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		h.sendTagList(ctx, message.Chat.ID, user)
+		return
+	}
+	if len(args) < 2 {
+		h.bot.SendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	// The term may contain spaces, so the tag is always the last argument
+	tag := learning.NormalizeTag(args[len(args)-1])
+	if tag == "" {
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Tags must be a single word of up to %d characters. %s", learning.MaxTagLength, usage))
+		return
+	}
+	term := strings.Join(args[:len(args)-1], " ")
+
+	word, err := h.learningUseCase.TagWord(ctx, user.ID(), term, tag)
+	if err != nil {
+		log.Printf("Failed to tag %q: %v", term, err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error tagging that word. Please try again.")
+		return
+	}
+
+	if word == nil {
+		h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🤷 No word found matching \"%s\".", shared.EscapeMarkdown(term)))
+		return
+	}
+
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, fmt.Sprintf("🏷 Tagged *%s* with #%s. Review it with /learn %s",
+		shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(tag), shared.EscapeMarkdown(tag)))
+}
+
+// sendTagList sends the user's tags
+func (h *BotHandler) sendTagList(ctx context.Context, chatID int64, user *user.User) {
+	tags, err := h.learningUseCase.GetUserTags(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get tags: %v", err)
+		h.bot.SendMessage(chatID, "Sorry, there was an error getting your tags. Please try again.")
+		return
+	}
+
+	if len(tags) == 0 {
+		h.bot.SendMessage(chatID, "You haven't tagged any words yet. Usage: /tag <dutch or english word> <tag>")
+		return
+	}
+
+	h.bot.SendMessage(chatID, "🏷 Your tags: #"+strings.Join(tags, ", #")+"\n\nStart a focused session with /learn <tag>")
+}
+
+// handleTaggedLearning starts a learning session limited to words carrying the user's tag;
+// a focus session shows no grammar tips
+func (h *BotHandler) handleTaggedLearning(ctx context.Context, message *tgbotapi.Message, user *user.User, input string, focus bool) {
+	tag := learning.NormalizeTag(input)
+	if tag == "" {
+		h.bot.SendMessage(message.Chat.ID, "Usage: /learn [tag]")
+		return
+	}
+
+	if h.confirmActiveSession(message.Chat.ID, message.MessageID, user, false) {
+		return
+	}
+
+	session, err := h.learningUseCase.GetNextTaggedWord(ctx, user.ID(), tag, focus)
+	if errors.Is(err, usecases.ErrTakeBreak) {
+		h.bot.SendMessageWithKeyboard(message.Chat.ID, takeBreakText, shared.CreateNoWordsKeyboard())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get next tagged word: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error getting your words. Please try again.")
+		return
+	}
+
+	if session == nil {
+		if !h.hasTag(ctx, user, tag) {
+			h.bot.SendMessageWithKeyboard(message.Chat.ID, fmt.Sprintf("🤷 \"%s\" isn't a category or one of your tags.\n\n%s",
+				shared.EscapeMarkdown(input), categoryPickerText), shared.CreateCategoryKeyboard())
+			return
+		}
+		h.bot.SendMessageWithKeyboard(message.Chat.ID,
+			fmt.Sprintf("🎉 No words tagged #%s are due right now.", shared.EscapeMarkdown(tag)), shared.CreateNoWordsKeyboard())
+		return
+	}
+
+	h.activeSessions[int64(user.ID())] = session
+	h.sendQuestion(message.Chat.ID, session)
+	h.startQuestionTimeout(message.Chat.ID, user, session)
+}
+
+// hasTag checks if the user has tagged any word with the tag, assuming they have when the lookup fails
+func (h *BotHandler) hasTag(ctx context.Context, user *user.User, tag string) bool {
+	tags, err := h.learningUseCase.GetUserTags(ctx, user.ID())
+	if err != nil {
+		log.Printf("Failed to get tags: %v", err)
+		return true
+	}
+	return slices.Contains(tags, tag)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/domain/user"
+)
+
+// timezoneUsage explains the /set_timezone command
+const timezoneUsage = "Usage: /set_timezone <time zone>, for example /set_timezone Europe/Amsterdam"
+
+// handleSetTimezone processes the /set_timezone [zone] command, setting the time zone quiet hours,
+// the daily digest and streaks follow
+func (h *BotHandler) handleSetTimezone(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	name := strings.TrimSpace(message.CommandArguments())
+	if name == "" {
+		prefs, err := h.userUseCase.GetUserPreferences(ctx, u.ID())
+		if err != nil {
+			log.Printf("Failed to get preferences: %v", err)
+			h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error loading your settings. Please try again.")
+			return
+		}
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🌍 Time zone: %s\n\n%s", prefs.Location(), timezoneUsage))
+		return
+	}
+
+	// "Local" would silently mean the server's zone
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("Unknown time zone %q.\n\n%s", name, timezoneUsage))
+		return
+	}
+
+	if err := h.userUseCase.SetTimezone(ctx, u.ID(), location); err != nil {
+		log.Printf("Failed to set timezone: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error updating your settings. Please try again.")
+		return
+	}
+
+	h.bot.SendMessage(message.Chat.ID, fmt.Sprintf("🌍 Time zone set to %s. It's %s there now.",
+		location, time.Now().In(location).Format("15:04")))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"dutch-learning-bot/internal/application/usecases"
+	"dutch-learning-bot/internal/domain/user"
+	"dutch-learning-bot/internal/interfaces/telegram/handlers/shared"
+)
+
+// handleUndo processes the /undo command, reverting the user's last review
+func (h *BotHandler) handleUndo(ctx context.Context, message *tgbotapi.Message, u *user.User) {
+	word, err := h.learningUseCase.UndoLastReview(ctx, u.ID())
+	if errors.Is(err, usecases.ErrNothingToUndo) {
+		h.bot.SendMessage(message.Chat.ID, "There's no review to undo yet.")
+		return
+	}
+	if errors.Is(err, usecases.ErrReviewNotUndoable) {
+		h.bot.SendMessage(message.Chat.ID, "Sorry, your last review was saved before /undo existed, so it can't be reverted.")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to undo last review: %v", err)
+		h.bot.SendMessage(message.Chat.ID, "Sorry, there was an error undoing your last review. Please try again.")
+		return
+	}
+
+	// A question about the same word would still hold the card as it was after the undone review
+	userID := int64(u.ID())
+	if session, exists := h.activeSessions[userID]; exists && word != nil && session.Word.ID() == word.ID() {
+		delete(h.activeSessions, userID)
+	}
+
+	text := "↩️ Your last review was undone."
+	if word != nil {
+		text = fmt.Sprintf("↩️ Undid your last review of *%s* (%s). It's scheduled as it was before.",
+			shared.EscapeMarkdown(word.Dutch()), shared.EscapeMarkdown(word.English()))
+	}
+	h.bot.SendMessageWithMarkdown(message.Chat.ID, text+"\n\nContinue with /learn")
+}